@@ -107,6 +107,15 @@ func setDefaultValue(v *viper.Viper) {
 	v.SetDefault("RESUME_PIPELINES", true)
 	// v.SetDefault("CORS_ALLOW_ORIGIN", "*")
 	v.SetDefault("CONSUME_PIPELINES", true)
+	v.SetDefault("PIPELINE_HEARTBEAT_INTERVAL", 10)
+	v.SetDefault("PIPELINE_LEASE_TIMEOUT", 60)
+	v.SetDefault("WORKER_METRICS_PORT", "9091")
+	v.SetDefault("PIPELINE_TASK_MAX_PARALLEL", 10)
+	v.SetDefault("NOTIFICATION_MAX_ATTEMPTS", 3)
+	v.SetDefault("NOTIFICATION_RETRY_INTERVAL", 10)
+	v.SetDefault("SMTP_PORT", 587)
+	v.SetDefault("RAW_DATA_PURGE_CRON", "0 3 * * *")
+	v.SetDefault("SECRET_ENV_PREFIX", "DEVLAKE_SECRET_")
 }
 
 func init() {
@@ -18,6 +18,7 @@ limitations under the License.
 package dal
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -109,6 +110,8 @@ type ColumnMeta interface {
 type SessionConfig struct {
 	PrepareStmt            bool
 	SkipDefaultTransaction bool
+	// Context is passed to the queries of the session, i.e. to trace them
+	Context context.Context
 }
 
 // Dal aims to facilitate an isolation between DBS and our System by defining a set of operations should a DBS provide
@@ -143,6 +146,8 @@ type Dal interface {
 	UpdateColumn(entityOrTable interface{}, columnName string, value interface{}, clauses ...Clause) errors.Error
 	// UpdateColumns allows you to update multiple columns of multiple records
 	UpdateColumns(entityOrTable interface{}, set []DalSet, clauses ...Clause) errors.Error
+	// UpdateColumnsAffected is the same as UpdateColumns but also returns the number of records updated
+	UpdateColumnsAffected(entityOrTable interface{}, set []DalSet, clauses ...Clause) (int64, errors.Error)
 	// UpdateAllColumn updated all Columns of entity
 	UpdateAllColumn(entity interface{}, clauses ...Clause) errors.Error
 	// CreateOrUpdate tries to create the record, or fallback to update all if failed
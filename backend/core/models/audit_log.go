@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+const (
+	AUDIT_ACTION_CREATE = "create"
+	AUDIT_ACTION_UPDATE = "update"
+	AUDIT_ACTION_DELETE = "delete"
+	AUDIT_ACTION_ROTATE = "rotate"
+	// the collected data of the target is deleted while the target is kept
+	AUDIT_ACTION_DELETE_DATA = "delete-data"
+)
+
+const (
+	AUDIT_TARGET_BLUEPRINT    = "blueprint"
+	AUDIT_TARGET_PROJECT      = "project"
+	AUDIT_TARGET_CONNECTION   = "connection"
+	AUDIT_TARGET_SCOPE        = "scope"
+	AUDIT_TARGET_SCOPE_CONFIG = "scope-config"
+	AUDIT_TARGET_API_KEY      = "api-key"
+)
+
+// AuditLog records who changed what, Diff holds the changed fields as `{"field": {"old": ..., "new": ...}}` with the
+// secrets sanitized
+type AuditLog struct {
+	ID           uint64          `json:"id" gorm:"primaryKey"`
+	CreatedAt    time.Time       `json:"createdAt" gorm:"index"`
+	User         string          `json:"user" gorm:"type:varchar(255);index"`
+	UserEmail    string          `json:"userEmail" gorm:"type:varchar(255)"`
+	Action       string          `json:"action" gorm:"type:varchar(40)"`
+	TargetType   string          `json:"targetType" gorm:"type:varchar(40);index:idx_audit_log_target"`
+	TargetPlugin string          `json:"targetPlugin" gorm:"type:varchar(100);index:idx_audit_log_target"`
+	TargetId     string          `json:"targetId" gorm:"type:varchar(255);index:idx_audit_log_target"`
+	Diff         json.RawMessage `json:"diff" gorm:"type:json"`
+}
+
+func (AuditLog) TableName() string {
+	return "_devlake_audit_logs"
+}
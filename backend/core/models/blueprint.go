@@ -28,6 +28,13 @@ const (
 	BLUEPRINT_MODE_ADVANCED = "ADVANCED"
 )
 
+const (
+	// PLAN_FORMAT_STAGES executes the stages of the generated plan one after another
+	PLAN_FORMAT_STAGES = "stages"
+	// PLAN_FORMAT_DAG generates plans with task dependencies, which is the default for new blueprints
+	PLAN_FORMAT_DAG = "dag"
+)
+
 // @Description CronConfig
 type Blueprint struct {
 	Name         string                 `json:"name" validate:"required"`
@@ -42,6 +49,7 @@ type Blueprint struct {
 	Labels       []string               `json:"labels" gorm:"-"`
 	Connections  []*BlueprintConnection `json:"connections" gorm:"-"`
 	Priority     int                    `json:"priority"` // greater is higher
+	PlanFormat   string                 `json:"planFormat" gorm:"type:varchar(20)" validate:"omitempty,oneof=stages dag"`
 	SyncPolicy   `gorm:"embedded"`
 	common.Model `swaggerignore:"true"`
 }
@@ -0,0 +1,66 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.Scope = (*ChatChannel)(nil)
+
+const (
+	CHANNEL_TYPE_PUBLIC  = "PUBLIC"
+	CHANNEL_TYPE_PRIVATE = "PRIVATE"
+	CHANNEL_TYPE_DIRECT  = "DIRECT"
+	CHANNEL_TYPE_GROUP   = "GROUP"
+)
+
+// ChatChannel is a conversation of an instant messaging tool, e.g. a slack channel or a feishu chat
+type ChatChannel struct {
+	domainlayer.DomainEntity
+	Name        string `gorm:"type:varchar(255)"`
+	Description string
+	Url         string `gorm:"type:varchar(255)"`
+	Type        string `gorm:"type:varchar(100)"`
+	IsArchived  bool
+	CreatorId   string `gorm:"type:varchar(255)"`
+	MemberCount int
+	CreatedDate *time.Time
+}
+
+func (ChatChannel) TableName() string {
+	return "chat_channels"
+}
+
+func (c *ChatChannel) ScopeId() string {
+	return c.Id
+}
+
+func (c *ChatChannel) ScopeName() string {
+	return c.Name
+}
+
+func NewChatChannel(id string, name string) *ChatChannel {
+	return &ChatChannel{
+		DomainEntity: domainlayer.NewDomainEntity(id),
+		Name:         name,
+	}
+}
@@ -0,0 +1,41 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package chat
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+)
+
+// ChatMessage is a message posted to a ChatChannel, replies of a thread refer to the thread root by ParentMessageId
+type ChatMessage struct {
+	domainlayer.DomainEntity
+	ChatChannelId   string `gorm:"index;type:varchar(255)"`
+	ParentMessageId string `gorm:"index;type:varchar(255)"`
+	Type            string `gorm:"type:varchar(100)"`
+	OriginalType    string `gorm:"type:varchar(100)"`
+	Content         string
+	AuthorId        string `gorm:"type:varchar(255)"`
+	ReplyCount      int
+	CreatedDate     *time.Time
+}
+
+func (ChatMessage) TableName() string {
+	return "chat_messages"
+}
@@ -0,0 +1,58 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package code
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+)
+
+// FileOwnership is the number of lines last modified by an author in a file at the HEAD of a repo, according to git
+// blame
+type FileOwnership struct {
+	domainlayer.DomainEntity
+	RepoId        string `gorm:"index;type:varchar(255)"`
+	FilePath      string `gorm:"type:text"`
+	ComponentName string `gorm:"type:varchar(255)"`
+	AuthorId      string `gorm:"type:varchar(255)"`
+	AuthorName    string `gorm:"type:varchar(255)"`
+	Lines         int
+	TotalLines    int `gorm:"comment:lines of the file"`
+	Rank          int `gorm:"comment:rank of the author by lines in the file, starting from 1"`
+}
+
+func (FileOwnership) TableName() string {
+	return "file_ownership"
+}
+
+// FileChurn is the number of commits changing a file at the HEAD of a repo within the last 30 and 90 days
+type FileChurn struct {
+	domainlayer.DomainEntity
+	RepoId          string `gorm:"index;type:varchar(255)"`
+	FilePath        string `gorm:"type:text"`
+	ComponentName   string `gorm:"type:varchar(255)"`
+	Changes30d      int
+	Changes90d      int
+	Authors90d      int
+	LastChangedDate *time.Time
+}
+
+func (FileChurn) TableName() string {
+	return "file_churn"
+}
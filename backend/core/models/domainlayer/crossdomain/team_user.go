@@ -18,15 +18,29 @@ limitations under the License.
 package crossdomain
 
 import (
+	"time"
+
 	"github.com/apache/incubator-devlake/core/models/common"
 )
 
+// TeamUserUnboundedStart is the StartDate of the memberships which are effective since ever
+var TeamUserUnboundedStart = time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// TeamUser is a membership of the user in the team, effective from StartDate (inclusive) until EndDate (exclusive),
+// a nil EndDate means the user is still a member of the team
 type TeamUser struct {
-	TeamId string `gorm:"primaryKey;type:varchar(255)"`
-	UserId string `gorm:"primaryKey;type:varchar(255)"`
+	TeamId    string    `gorm:"primaryKey;type:varchar(255)"`
+	UserId    string    `gorm:"primaryKey;type:varchar(255)"`
+	StartDate time.Time `gorm:"primaryKey"`
+	EndDate   *time.Time
 	common.NoPKModel
 }
 
+// IsEffectiveAt tells whether the user is a member of the team at the given time
+func (tu *TeamUser) IsEffectiveAt(t time.Time) bool {
+	return !tu.StartDate.After(t) && (tu.EndDate == nil || t.Before(*tu.EndDate))
+}
+
 func (TeamUser) TableName() string {
 	return "team_users"
 }
@@ -19,6 +19,7 @@ package domaininfo
 
 import (
 	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/codequality"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
@@ -29,6 +30,11 @@ import (
 
 func GetDomainTablesInfo() []dal.Tabler {
 	return []dal.Tabler{
+		// chat
+		&chat.ChatChannel{},
+		&chat.ChatMessage{},
+		&chat.ChatMessageReaction{},
+		&chat.MeetingAccountStat{},
 		// code
 		&code.Commit{},
 		&code.CommitFile{},
@@ -36,6 +42,8 @@ func GetDomainTablesInfo() []dal.Tabler {
 		&code.CommitParent{},
 		&code.Component{},
 		&code.CommitLineChange{},
+		&code.FileChurn{},
+		&code.FileOwnership{},
 		&code.PullRequest{},
 		&code.PullRequestComment{},
 		&code.PullRequestCommit{},
@@ -67,6 +75,7 @@ func GetDomainTablesInfo() []dal.Tabler {
 		&crossdomain.PullRequestIssue{},
 		&crossdomain.RefsIssuesDiffs{},
 		&crossdomain.Team{},
+		&crossdomain.TeamClosure{},
 		&crossdomain.TeamUser{},
 		&crossdomain.User{},
 		&crossdomain.UserAccount{},
@@ -0,0 +1,63 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addAuditLogTables)(nil)
+
+type addAuditLogTables struct{}
+
+type auditLog20261018 struct {
+	ID           uint64          `gorm:"primaryKey"`
+	CreatedAt    time.Time       `gorm:"index"`
+	User         string          `gorm:"type:varchar(255);index"`
+	UserEmail    string          `gorm:"type:varchar(255)"`
+	Action       string          `gorm:"type:varchar(40)"`
+	TargetType   string          `gorm:"type:varchar(40);index:idx_audit_log_target"`
+	TargetPlugin string          `gorm:"type:varchar(100);index:idx_audit_log_target"`
+	TargetId     string          `gorm:"type:varchar(255);index:idx_audit_log_target"`
+	Diff         json.RawMessage `gorm:"type:json"`
+}
+
+func (auditLog20261018) TableName() string {
+	return "_devlake_audit_logs"
+}
+
+func (*addAuditLogTables) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&auditLog20261018{},
+	)
+}
+
+func (*addAuditLogTables) Version() uint64 {
+	return 20261018000004
+}
+
+func (*addAuditLogTables) Name() string {
+	return "add audit log tables"
+}
@@ -0,0 +1,55 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addBlueprintPlanFormat)(nil)
+
+type addBlueprintPlanFormat struct{}
+
+type blueprint20261018 struct {
+	PlanFormat string `gorm:"type:varchar(20)"`
+}
+
+func (blueprint20261018) TableName() string {
+	return "_devlake_blueprints"
+}
+
+func (*addBlueprintPlanFormat) Up(basicRes context.BasicRes) errors.Error {
+	err := migrationhelper.AutoMigrateTables(basicRes, new(blueprint20261018))
+	if err != nil {
+		return err
+	}
+	// existing blueprints keep executing their plans stage by stage
+	return basicRes.GetDal().UpdateColumn(&blueprint20261018{}, "plan_format", "stages", dal.Where("plan_format IS NULL OR plan_format = ''"))
+}
+
+func (*addBlueprintPlanFormat) Version() uint64 {
+	return 20261018000007
+}
+
+func (*addBlueprintPlanFormat) Name() string {
+	return "add plan format to blueprints"
+}
@@ -0,0 +1,79 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addFileOwnershipAndChurn)(nil)
+
+type addFileOwnershipAndChurn struct{}
+
+type fileOwnership20261018 struct {
+	archived.DomainEntity
+	RepoId        string `gorm:"index;type:varchar(255)"`
+	FilePath      string `gorm:"type:text"`
+	ComponentName string `gorm:"type:varchar(255)"`
+	AuthorId      string `gorm:"type:varchar(255)"`
+	AuthorName    string `gorm:"type:varchar(255)"`
+	Lines         int
+	TotalLines    int
+	Rank          int
+}
+
+func (fileOwnership20261018) TableName() string {
+	return "file_ownership"
+}
+
+type fileChurn20261018 struct {
+	archived.DomainEntity
+	RepoId          string `gorm:"index;type:varchar(255)"`
+	FilePath        string `gorm:"type:text"`
+	ComponentName   string `gorm:"type:varchar(255)"`
+	Changes30d      int
+	Changes90d      int
+	Authors90d      int
+	LastChangedDate *time.Time
+}
+
+func (fileChurn20261018) TableName() string {
+	return "file_churn"
+}
+
+func (*addFileOwnershipAndChurn) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&fileOwnership20261018{},
+		&fileChurn20261018{},
+	)
+}
+
+func (*addFileOwnershipAndChurn) Version() uint64 {
+	return 20261018000013
+}
+
+func (*addFileOwnershipAndChurn) Name() string {
+	return "add file_ownership and file_churn tables"
+}
@@ -0,0 +1,86 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addNotificationChannels)(nil)
+
+type addNotificationChannels struct{}
+
+type notificationChannel20261018 struct {
+	archived.Model
+	archived.Creator
+	Name                     string `gorm:"type:varchar(255)"`
+	Type                     string `gorm:"type:varchar(20)"`
+	ProjectName              string `gorm:"type:varchar(255);index"`
+	BlueprintId              uint64 `gorm:"index"`
+	Endpoint                 string
+	Secret                   string
+	Events                   string `gorm:"type:json"`
+	DurationThresholdMinutes int
+	StaleDays                int
+	Enable                   bool
+}
+
+func (notificationChannel20261018) TableName() string {
+	return "_devlake_notification_channels"
+}
+
+type notification20261018 struct {
+	ChannelId   uint64 `gorm:"index"`
+	BlueprintId uint64 `gorm:"index"`
+	PipelineId  uint64 `gorm:"index"`
+	Status      string `gorm:"type:varchar(20)"`
+	Attempts    int
+	Error       string
+}
+
+func (notification20261018) TableName() string {
+	return "_devlake_notifications"
+}
+
+func (*addNotificationChannels) Up(basicRes context.BasicRes) errors.Error {
+	err := migrationhelper.AutoMigrateTables(
+		basicRes,
+		&notificationChannel20261018{},
+		&notification20261018{},
+	)
+	if err != nil {
+		return err
+	}
+	// the notifications sent before were delivered to the global endpoint once
+	return basicRes.GetDal().Exec(
+		"UPDATE _devlake_notifications SET channel_id = 0, blueprint_id = 0, pipeline_id = 0, status = ?, attempts = 1, error = '' WHERE status IS NULL",
+		"SENT",
+	)
+}
+
+func (*addNotificationChannels) Version() uint64 {
+	return 20261018000006
+}
+
+func (*addNotificationChannels) Name() string {
+	return "add notification channels"
+}
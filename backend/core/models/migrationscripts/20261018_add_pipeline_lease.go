@@ -0,0 +1,64 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addPipelineLease)(nil)
+
+type addPipelineLease struct{}
+
+type pipeline20261018 struct {
+	WorkerId        string     `gorm:"type:varchar(255);index"`
+	HeartbeatAt     *time.Time `gorm:"index"`
+	CancelRequested bool
+}
+
+func (pipeline20261018) TableName() string {
+	return "_devlake_pipelines"
+}
+
+func (script *addPipelineLease) Up(basicRes context.BasicRes) errors.Error {
+	err := migrationhelper.AutoMigrateTables(basicRes, new(pipeline20261018))
+	if err != nil {
+		return err
+	}
+	// existing rows would get NULL values which can not be scanned into the model
+	db := basicRes.GetDal()
+	err = db.UpdateColumn(&pipeline20261018{}, "worker_id", "", dal.Where("worker_id IS NULL"))
+	if err != nil {
+		return err
+	}
+	return db.UpdateColumn(&pipeline20261018{}, "cancel_requested", false, dal.Where("cancel_requested IS NULL"))
+}
+
+func (*addPipelineLease) Version() uint64 {
+	return 20261018000005
+}
+
+func (*addPipelineLease) Name() string {
+	return "add worker lease to pipelines"
+}
@@ -0,0 +1,74 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addRawDataRetentionPolicies)(nil)
+
+type addRawDataRetentionPolicies struct{}
+
+type rawDataRetentionPolicy20261018 struct {
+	archived.Model
+	archived.Creator
+	Plugin                string `gorm:"type:varchar(255);index"`
+	ConnectionId          uint64
+	Mode                  string `gorm:"type:varchar(20)"`
+	Days                  int
+	IncludeFullExtraction bool
+	Enable                bool
+	LastPurgedAt          *time.Time
+	LastDeletedRows       int64
+	LastReclaimedBytes    int64
+}
+
+func (rawDataRetentionPolicy20261018) TableName() string {
+	return "_devlake_raw_data_retention_policies"
+}
+
+type subtaskState20261018 struct {
+	ExtractedTable string `gorm:"type:varchar(255)"`
+}
+
+func (subtaskState20261018) TableName() string {
+	return "_devlake_subtask_states"
+}
+
+func (*addRawDataRetentionPolicies) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&rawDataRetentionPolicy20261018{},
+		&subtaskState20261018{},
+	)
+}
+
+func (*addRawDataRetentionPolicies) Version() uint64 {
+	return 20261018000010
+}
+
+func (*addRawDataRetentionPolicies) Name() string {
+	return "add raw data retention policies"
+}
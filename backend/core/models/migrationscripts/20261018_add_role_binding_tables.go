@@ -0,0 +1,57 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addRoleBindingTables)(nil)
+
+type addRoleBindingTables struct{}
+
+type roleBinding20261018 struct {
+	archived.Model
+	archived.Creator
+	Subject     string `gorm:"type:varchar(255);uniqueIndex:idx_role_binding"`
+	ProjectName string `gorm:"type:varchar(255);uniqueIndex:idx_role_binding"`
+	Role        string `gorm:"type:varchar(40)"`
+}
+
+func (roleBinding20261018) TableName() string {
+	return "_devlake_role_bindings"
+}
+
+func (*addRoleBindingTables) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&roleBinding20261018{},
+	)
+}
+
+func (*addRoleBindingTables) Version() uint64 {
+	return 20261018000003
+}
+
+func (*addRoleBindingTables) Name() string {
+	return "add role binding tables"
+}
@@ -0,0 +1,64 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addSubtaskCounters)(nil)
+
+type addSubtaskCounters struct{}
+
+type subtask20261018 struct {
+	RecordsWritten  int64
+	HttpRequests    int64
+	HttpRetries     int64
+	HttpRateLimited int64
+}
+
+func (subtask20261018) TableName() string {
+	return "_devlake_subtasks"
+}
+
+func (script *addSubtaskCounters) Up(basicRes context.BasicRes) errors.Error {
+	err := migrationhelper.AutoMigrateTables(basicRes, new(subtask20261018))
+	if err != nil {
+		return err
+	}
+	db := basicRes.GetDal()
+	for _, column := range []string{"records_written", "http_requests", "http_retries", "http_rate_limited"} {
+		err = db.UpdateColumn(&subtask20261018{}, column, 0, dal.Where(column+" IS NULL"))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (*addSubtaskCounters) Version() uint64 {
+	return 20261018000009
+}
+
+func (*addSubtaskCounters) Name() string {
+	return "add counters to subtasks"
+}
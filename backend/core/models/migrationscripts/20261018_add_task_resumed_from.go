@@ -0,0 +1,55 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addTaskResumedFrom)(nil)
+
+type addTaskResumedFrom struct{}
+
+type task20261018 struct {
+	ResumedFromTaskId uint64
+}
+
+func (task20261018) TableName() string {
+	return "_devlake_tasks"
+}
+
+func (script *addTaskResumedFrom) Up(basicRes context.BasicRes) errors.Error {
+	err := migrationhelper.AutoMigrateTables(basicRes, new(task20261018))
+	if err != nil {
+		return err
+	}
+	// existing rows would get NULL values which can not be scanned into the model
+	return basicRes.GetDal().UpdateColumn(&task20261018{}, "resumed_from_task_id", 0, dal.Where("resumed_from_task_id IS NULL"))
+}
+
+func (*addTaskResumedFrom) Version() uint64 {
+	return 20261018000008
+}
+
+func (*addTaskResumedFrom) Name() string {
+	return "add resumed_from_task_id to tasks"
+}
@@ -0,0 +1,122 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addTeamMembershipDates)(nil)
+
+type addTeamMembershipDates struct{}
+
+type teamUser20261018Before struct {
+	TeamId string `gorm:"primaryKey;type:varchar(255)"`
+	UserId string `gorm:"primaryKey;type:varchar(255)"`
+	archived.NoPKModel
+}
+
+type teamUser20261018After struct {
+	TeamId    string    `gorm:"primaryKey;type:varchar(255)"`
+	UserId    string    `gorm:"primaryKey;type:varchar(255)"`
+	StartDate time.Time `gorm:"primaryKey"`
+	EndDate   *time.Time
+	archived.NoPKModel
+}
+
+type teamClosure20261018 struct {
+	AncestorId   string `gorm:"primaryKey;type:varchar(255)"`
+	DescendantId string `gorm:"primaryKey;type:varchar(255)"`
+	Depth        int
+	archived.NoPKModel
+}
+
+func (teamClosure20261018) TableName() string {
+	return "team_closures"
+}
+
+type team20261018 struct {
+	Id       string
+	ParentId string
+}
+
+func (script *addTeamMembershipDates) Up(basicRes context.BasicRes) errors.Error {
+	// the existing memberships are effective since ever
+	err := migrationhelper.TransformTable(
+		basicRes,
+		script,
+		"team_users",
+		func(s *teamUser20261018Before) (*teamUser20261018After, errors.Error) {
+			return &teamUser20261018After{
+				TeamId:    s.TeamId,
+				UserId:    s.UserId,
+				StartDate: time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC),
+				NoPKModel: s.NoPKModel,
+			}, nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+	err = migrationhelper.AutoMigrateTables(basicRes, &teamClosure20261018{})
+	if err != nil {
+		return err
+	}
+
+	db := basicRes.GetDal()
+	var teams []team20261018
+	err = db.All(&teams, dal.Select("id, parent_id"), dal.From("teams"))
+	if err != nil {
+		return err
+	}
+	parents := make(map[string]string, len(teams))
+	for _, team := range teams {
+		parents[team.Id] = team.ParentId
+	}
+	closures := make([]*teamClosure20261018, 0, len(teams))
+	for _, team := range teams {
+		// the visited guard stops at the cycles and the missing parents
+		visited := map[string]bool{}
+		for id, depth := team.Id, 0; id != "" && !visited[id]; id, depth = parents[id], depth+1 {
+			if _, ok := parents[id]; !ok {
+				break
+			}
+			visited[id] = true
+			closures = append(closures, &teamClosure20261018{AncestorId: id, DescendantId: team.Id, Depth: depth})
+		}
+	}
+	if len(closures) == 0 {
+		return nil
+	}
+	return db.CreateOrUpdate(closures)
+}
+
+func (*addTeamMembershipDates) Version() uint64 {
+	return 20261018000011
+}
+
+func (*addTeamMembershipDates) Name() string {
+	return "add effective dates to team_users and team_closures"
+}
@@ -141,5 +141,19 @@ func All() []plugin.MigrationScript {
 		new(addIssueFixVerion),
 		new(addPipelinePriority),
 		new(fixNullPriority),
+		new(addChatTables),
+		new(addMeetingAccountStats),
+		new(addRoleBindingTables),
+		new(addAuditLogTables),
+		new(addPipelineLease),
+		new(addNotificationChannels),
+		new(addBlueprintPlanFormat),
+		new(addTaskResumedFrom),
+		new(addSubtaskCounters),
+		new(addRawDataRetentionPolicies),
+		new(addTeamMembershipDates),
+		new(addCheckpointToSubtaskStates),
+		new(addFileOwnershipAndChurn),
+		new(addRawDataPurgeWatermark),
 	}
 }
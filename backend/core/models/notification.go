@@ -18,6 +18,10 @@ limitations under the License.
 package models
 
 import (
+	"fmt"
+	"net/url"
+	"strings"
+
 	"github.com/apache/incubator-devlake/core/models/common"
 )
 
@@ -25,6 +29,23 @@ type NotificationType string
 
 const (
 	NotificationPipelineStatusChanged NotificationType = "PipelineStatusChanged"
+	NotificationPipelineFailed        NotificationType = "PipelineFailed"
+	NotificationTaskFailed            NotificationType = "TaskFailed"
+	NotificationPipelineTookTooLong   NotificationType = "PipelineTookTooLong"
+	NotificationBlueprintNotSucceeded NotificationType = "BlueprintNotSucceeded"
+)
+
+const (
+	NOTIFICATION_CHANNEL_WEBHOOK = "webhook"
+	NOTIFICATION_CHANNEL_SLACK   = "slack"
+	NOTIFICATION_CHANNEL_EMAIL   = "email"
+	NOTIFICATION_CHANNEL_TEAMS   = "teams"
+)
+
+const (
+	NOTIFICATION_STATUS_PENDING = "PENDING"
+	NOTIFICATION_STATUS_SENT    = "SENT"
+	NOTIFICATION_STATUS_FAILED  = "FAILED"
 )
 
 // Notification records notifications sent by lake
@@ -36,8 +57,68 @@ type Notification struct {
 	ResponseCode int
 	Response     string
 	Data         string
+	// the following fields are set for the notifications sent through the notification channels
+	ChannelId   uint64 `gorm:"index"`
+	BlueprintId uint64 `gorm:"index"`
+	PipelineId  uint64 `gorm:"index"`
+	Status      string `gorm:"type:varchar(20)"`
+	Attempts    int
+	Error       string
 }
 
 func (Notification) TableName() string {
 	return "_devlake_notifications"
 }
+
+// NotificationChannel delivers the subscribed events of a blueprint, or all blueprints of a project, to an endpoint.
+// Endpoint is the url of the webhook, slack or teams incoming webhook, or the comma separated recipients of the email
+type NotificationChannel struct {
+	common.Model
+	common.Creator
+	Name        string             `json:"name" gorm:"type:varchar(255)" validate:"required"`
+	Type        string             `json:"type" gorm:"type:varchar(20)" validate:"required,oneof=webhook slack email teams"`
+	ProjectName string             `json:"projectName" gorm:"type:varchar(255);index"`
+	BlueprintId uint64             `json:"blueprintId" gorm:"index"`
+	Endpoint    string             `json:"endpoint" gorm:"serializer:encdec" validate:"required"`
+	Secret      string             `json:"secret" gorm:"serializer:encdec"`
+	Events      []NotificationType `json:"events" gorm:"type:json;serializer:json"`
+	// PipelineTookTooLong is sent when a pipeline runs longer than the given minutes
+	DurationThresholdMinutes int `json:"durationThresholdMinutes"`
+	// BlueprintNotSucceeded is sent when a blueprint has no successful pipeline in the given days
+	StaleDays int  `json:"staleDays"`
+	Enable    bool `json:"enable"`
+}
+
+func (NotificationChannel) TableName() string {
+	return "_devlake_notification_channels"
+}
+
+// Subscribes returns true if the channel subscribes to the event
+func (channel *NotificationChannel) Subscribes(event NotificationType) bool {
+	for _, e := range channel.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Sanitize hides the secret and the endpoint which usually contains a token
+func (channel NotificationChannel) Sanitize() NotificationChannel {
+	if channel.Secret != "" {
+		channel.Secret = strings.Repeat("*", len(channel.Secret))
+	}
+	if channel.Type != NOTIFICATION_CHANNEL_EMAIL {
+		channel.Endpoint = sanitizeUrl(channel.Endpoint)
+	}
+	return channel
+}
+
+// sanitizeUrl keeps the host of the url only, incoming webhooks of slack and teams carry the token in their path
+func sanitizeUrl(rawUrl string) string {
+	u, err := url.Parse(rawUrl)
+	if err != nil || u.Host == "" {
+		return strings.Repeat("*", len(rawUrl))
+	}
+	return fmt.Sprintf("%s://%s/%s", u.Scheme, u.Host, strings.Repeat("*", 8))
+}
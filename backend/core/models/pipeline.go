@@ -18,8 +18,11 @@ limitations under the License.
 package models
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/apache/incubator-devlake/core/errors"
+
 	"github.com/apache/incubator-devlake/core/models/common"
 )
 
@@ -27,6 +30,9 @@ type GenericPipelineTask[T any] struct {
 	Plugin   string   `json:"plugin" binding:"required"`
 	Subtasks []string `json:"subtasks"`
 	Options  T        `json:"options"`
+	// Id and DependsOn turn the plan into a DAG, the task starts once all tasks it depends on are finished
+	Id        string   `json:"id,omitempty"`
+	DependsOn []string `json:"dependsOn,omitempty"`
 }
 
 // PipelineTask represents a smallest unit of execution inside a PipelinePlan
@@ -51,6 +57,80 @@ func (plan PipelinePlan) IsEmpty() bool {
 	return true
 }
 
+// IsDag checks if the tasks of the PipelinePlan declare their dependencies, in which case the stages are ignored
+// by the runner
+func (plan PipelinePlan) IsDag() bool {
+	for _, stage := range plan {
+		for _, task := range stage {
+			if task != nil && (task.Id != "" || len(task.DependsOn) > 0) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DagTaskId returns the id of the task at the given position, the position is used if the task has no id
+func (plan PipelinePlan) DagTaskId(row, col int) string {
+	if task := plan[row][col]; task.Id != "" {
+		return task.Id
+	}
+	return fmt.Sprintf("%d-%d", row+1, col+1)
+}
+
+// ValidateDag makes sure the task ids are unique, dependencies exist and there is no cycle
+func (plan PipelinePlan) ValidateDag() errors.Error {
+	if !plan.IsDag() {
+		return nil
+	}
+	dependencies := make(map[string][]string)
+	for i, stage := range plan {
+		for j, task := range stage {
+			if task == nil {
+				return errors.BadInput.New(fmt.Sprintf("task at [%d][%d] is empty", i, j))
+			}
+			id := plan.DagTaskId(i, j)
+			if _, ok := dependencies[id]; ok {
+				return errors.BadInput.New(fmt.Sprintf("duplicated task id %s", id))
+			}
+			dependencies[id] = task.DependsOn
+		}
+	}
+	for id, dependsOn := range dependencies {
+		for _, dependency := range dependsOn {
+			if _, ok := dependencies[dependency]; !ok {
+				return errors.BadInput.New(fmt.Sprintf("task %s depends on unknown task %s", id, dependency))
+			}
+		}
+	}
+	// depth-first search for cycles
+	const visiting, visited = 1, 2
+	states := make(map[string]int)
+	var visit func(id string) bool
+	visit = func(id string) bool {
+		switch states[id] {
+		case visiting:
+			return false
+		case visited:
+			return true
+		}
+		states[id] = visiting
+		for _, dependency := range dependencies[id] {
+			if !visit(dependency) {
+				return false
+			}
+		}
+		states[id] = visited
+		return true
+	}
+	for id := range dependencies {
+		if !visit(id) {
+			return errors.BadInput.New(fmt.Sprintf("task %s is part of a dependency cycle", id))
+		}
+	}
+	return nil
+}
+
 type Pipeline struct {
 	common.Model
 	Name          string       `json:"name" gorm:"index"`
@@ -67,7 +147,11 @@ type Pipeline struct {
 	Stage         int          `json:"stage"`
 	Labels        []string     `json:"labels" gorm:"-"`
 	Priority      int          `json:"priority"` // greater is higher
-	SyncPolicy    `gorm:"embedded"`
+	// the worker holding the lease of the running pipeline, the lease expires when the heartbeat stops
+	WorkerId        string     `json:"workerId" gorm:"type:varchar(255);index"`
+	HeartbeatAt     *time.Time `json:"heartbeatAt" gorm:"index"`
+	CancelRequested bool       `json:"-"`
+	SyncPolicy      `gorm:"embedded"`
 }
 
 // We use a 2D array because the request body must be an array of a set of tasks
@@ -52,3 +52,63 @@ func TestPipelinePlan_IsEmpty(t *testing.T) {
 		})
 	}
 }
+
+func TestPipelinePlan_ValidateDag(t *testing.T) {
+	tests := []struct {
+		name    string
+		plan    PipelinePlan
+		wantErr bool
+	}{
+		{
+			name: "stages",
+			plan: PipelinePlan{{{Plugin: "github"}}, {{Plugin: "dora"}}},
+		},
+		{
+			name: "dag",
+			plan: PipelinePlan{
+				{{Plugin: "github", Id: "github"}, {Plugin: "jira", Id: "jira"}},
+				{{Plugin: "dora", Id: "dora", DependsOn: []string{"github"}}},
+			},
+		},
+		{
+			name: "position as id",
+			plan: PipelinePlan{
+				{{Plugin: "github"}, {Plugin: "jira", Id: "jira"}},
+				{{Plugin: "dora", DependsOn: []string{"1-1"}}},
+			},
+		},
+		{
+			name: "duplicated id",
+			plan: PipelinePlan{
+				{{Plugin: "github", Id: "a"}, {Plugin: "jira", Id: "a"}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown dependency",
+			plan: PipelinePlan{
+				{{Plugin: "dora", Id: "dora", DependsOn: []string{"github"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "cycle",
+			plan: PipelinePlan{
+				{{Plugin: "a", Id: "a", DependsOn: []string{"c"}}},
+				{{Plugin: "b", Id: "b", DependsOn: []string{"a"}}},
+				{{Plugin: "c", Id: "c", DependsOn: []string{"b"}}},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.plan.ValidateDag()
+			if tt.wantErr {
+				assert.NotNil(t, err)
+			} else {
+				assert.Nil(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+const (
+	// RAW_DATA_RETENTION_KEEP_DAYS deletes the raw rows collected more than Days ago
+	RAW_DATA_RETENTION_KEEP_DAYS = "KEEP_DAYS"
+	// RAW_DATA_RETENTION_KEEP_LATEST deletes the raw rows superseded by a later response to the same input
+	RAW_DATA_RETENTION_KEEP_LATEST = "KEEP_LATEST"
+	// RAW_DATA_RETENTION_AFTER_CONVERSION deletes the raw rows once they were extracted and converted by the stateful
+	// extractors and convertors
+	RAW_DATA_RETENTION_AFTER_CONVERSION = "AFTER_CONVERSION"
+)
+
+// RawDataRetentionPolicy purges the `_raw_<plugin>_*` tables of a plugin, or of one connection of the plugin.
+// The raw rows not yet extracted by the stateful extractors are never purged, and the scopes whose raw rows are
+// extracted all over again by each run are skipped unless IncludeFullExtraction is set, in which case their tool
+// layer records of the purged rows are removed by the next run unless the data gets collected again. Once the raw
+// rows of a stateful extractor are purged, its next full extraction, i.e. after a config change, waits for the data
+// to be collected all over again.
+type RawDataRetentionPolicy struct {
+	common.Model
+	common.Creator
+	Plugin string `json:"plugin" gorm:"type:varchar(255);index" validate:"required"`
+	// ConnectionId limits the policy to the raw rows of the connection, 0 for all connections
+	ConnectionId          uint64 `json:"connectionId"`
+	Mode                  string `json:"mode" gorm:"type:varchar(20)" validate:"required,oneof=KEEP_DAYS KEEP_LATEST AFTER_CONVERSION"`
+	Days                  int    `json:"days"`
+	IncludeFullExtraction bool   `json:"includeFullExtraction"`
+	Enable                bool   `json:"enable"`
+	// the result of the latest scheduled or manual purge
+	LastPurgedAt       *time.Time `json:"lastPurgedAt"`
+	LastDeletedRows    int64      `json:"lastDeletedRows"`
+	LastReclaimedBytes int64      `json:"lastReclaimedBytes"`
+}
+
+func (RawDataRetentionPolicy) TableName() string {
+	return "_devlake_raw_data_retention_policies"
+}
@@ -0,0 +1,53 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import "github.com/apache/incubator-devlake/core/models/common"
+
+const (
+	ROLE_ADMIN              = "admin"
+	ROLE_PROJECT_MAINTAINER = "project-maintainer"
+	ROLE_VIEWER             = "viewer"
+)
+
+// RoleLevels ranks the roles, a role grants the permissions of the roles with lower levels
+var RoleLevels = map[string]int{
+	ROLE_VIEWER:             1,
+	ROLE_PROJECT_MAINTAINER: 2,
+	ROLE_ADMIN:              3,
+}
+
+// RoleBinding grants a role to a user (matched by name or email), globally when ProjectName is empty or only on
+// the project otherwise, which makes the user a member of the project
+type RoleBinding struct {
+	common.Model
+	common.Creator
+	Subject     string `json:"subject" gorm:"type:varchar(255);uniqueIndex:idx_role_binding"`
+	ProjectName string `json:"projectName" gorm:"type:varchar(255);uniqueIndex:idx_role_binding"`
+	Role        string `json:"role" gorm:"type:varchar(40)"`
+}
+
+func (RoleBinding) TableName() string {
+	return "_devlake_role_bindings"
+}
+
+type ApiInputRoleBinding struct {
+	Subject     string `json:"subject" validate:"required,max=255"`
+	ProjectName string `json:"projectName" validate:"max=255"`
+	Role        string `json:"role" validate:"required,oneof=admin project-maintainer viewer"`
+}
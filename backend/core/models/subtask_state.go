@@ -31,8 +31,17 @@ type SubtaskState struct {
 	// TimeAfter stores the previous timeAfter specified by the user for determining should subtask run in Incremntal or FullSync mode
 	TimeAfter     *time.Time `json:"timeAfter"`
 	PrevStartedAt *time.Time `json:"prevStartedAt"`
-	CreatedAt     time.Time  `json:"createdAt"`
-	UpdatedAt     time.Time  `json:"updatedAt"`
+	// ExtractedTable is the raw table read by the stateful extractor, whose rows created before PrevStartedAt were extracted
+	ExtractedTable string `gorm:"type:varchar(255)" json:"extractedTable"`
+	// ConvertedTable is the raw table whose extracted records are converted by the stateful convertor
+	ConvertedTable string `gorm:"type:varchar(255)" json:"convertedTable"`
+	// PurgedBefore is set once the raw rows of ExtractedTable created before it were purged by the retention policies,
+	// the rows have to be collected all over again before the next full extraction. It is cleared by a full collection
+	PurgedBefore *time.Time `json:"purgedBefore"`
+	// Checkpoint is a json string recording the progress which can not be tracked by time, i.e. the tips of the refs of a git repo
+	Checkpoint string    `gorm:"type:text" json:"checkpoint"`
+	CreatedAt  time.Time `json:"createdAt"`
+	UpdatedAt  time.Time `json:"updatedAt"`
 }
 
 func (SubtaskState) TableName() string {
@@ -57,6 +57,8 @@ type NewTask struct {
 	PipelineRow int    `json:"-"`
 	PipelineCol int    `json:"-"`
 	IsRerun     bool   `json:"-"`
+	// ResumedFromTaskId is the previous attempt whose finished subtasks are skipped by the new task
+	ResumedFromTaskId uint64 `json:"-"`
 }
 
 type Task struct {
@@ -77,6 +79,8 @@ type Task struct {
 	BeganAt       *time.Time `json:"beganAt"`
 	FinishedAt    *time.Time `json:"finishedAt" gorm:"index"`
 	SpentSeconds  int        `json:"spentSeconds"`
+	// ResumedFromTaskId is the previous attempt of the task, subtasks finished successfully in it are not executed again
+	ResumedFromTaskId uint64 `json:"resumedFromTaskId"`
 }
 
 func (Task) TableName() string {
@@ -96,6 +100,15 @@ type Subtask struct {
 	IsCollector     bool       `json:"isCollector"`
 	IsFailed        bool       `json:"isFailed"`
 	Message         string     `json:"message"`
+	SubtaskCounters
+}
+
+// SubtaskCounters are collected by the api helpers while the subtask is running
+type SubtaskCounters struct {
+	RecordsWritten  int64 `json:"recordsWritten"`
+	HttpRequests    int64 `json:"httpRequests"`
+	HttpRetries     int64 `json:"httpRetries"`
+	HttpRateLimited int64 `json:"httpRateLimited"`
 }
 
 func (Subtask) TableName() string {
@@ -117,6 +130,7 @@ type SubtaskDetails struct {
 	IsCollector     bool       `json:"isCollector"`
 	IsFailed        bool       `json:"isFailed"`
 	Message         string     `json:"message"`
+	SubtaskCounters
 }
 
 type SubtasksInfo struct {
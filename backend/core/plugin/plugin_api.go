@@ -30,6 +30,7 @@ type ApiResourceInput struct {
 	Params  map[string]string      // path variables
 	Query   url.Values             // query string
 	Body    map[string]interface{} // json body
+	RawBody []byte                 // raw json body, e.g. for verifying the signature of a webhook payload
 	Request *http.Request
 
 	User *common.User
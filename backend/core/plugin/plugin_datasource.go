@@ -23,6 +23,7 @@ import (
 
 	"net/http"
 
+	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/dal"
 
 	"github.com/apache/incubator-devlake/core/errors"
@@ -134,6 +135,12 @@ type ApiScope interface {
 	ConvertApiScope() ToolLayerScope
 }
 
+// RepoDataCleaner is implemented by the plugins keeping the data of the repos outside the database, i.e. the clones
+// cached by gitextractor, the data is removed once the repos are deleted along with their scopes
+type RepoDataCleaner interface {
+	DeleteRepoData(basicRes context.BasicRes, repoId string) errors.Error
+}
+
 type ApiGroup interface {
 	GroupId() string
 	GroupName() string
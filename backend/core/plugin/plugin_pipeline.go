@@ -0,0 +1,50 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"sync"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+)
+
+// PipelineEnqueuer saves a pipeline to be picked up by the pipeline runner
+type PipelineEnqueuer func(newPipeline *models.NewPipeline) (*models.Pipeline, errors.Error)
+
+var pipelineEnqueuer PipelineEnqueuer
+var pipelineEnqueuerLock sync.RWMutex
+
+// RegisterPipelineEnqueuer registers the PipelineEnqueuer of the server, so plugins can schedule work to run
+// in pipelines instead of the api process
+func RegisterPipelineEnqueuer(enqueuer PipelineEnqueuer) {
+	pipelineEnqueuerLock.Lock()
+	defer pipelineEnqueuerLock.Unlock()
+	pipelineEnqueuer = enqueuer
+}
+
+// EnqueuePipeline saves the pipeline with the registered PipelineEnqueuer
+func EnqueuePipeline(newPipeline *models.NewPipeline) (*models.Pipeline, errors.Error) {
+	pipelineEnqueuerLock.RLock()
+	enqueuer := pipelineEnqueuer
+	pipelineEnqueuerLock.RUnlock()
+	if enqueuer == nil {
+		return nil, errors.Default.New("no pipeline enqueuer is registered")
+	}
+	return enqueuer(newPipeline)
+}
@@ -0,0 +1,134 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// SecretProvider resolves a secret stored outside of DevLake, a secret field of a connection holding
+// `<scheme>://<path>` is replaced with the value returned by the provider registered for the scheme
+type SecretProvider interface {
+	Scheme() string
+	GetSecret(path string) (string, errors.Error)
+}
+
+var secretProviders = map[string]SecretProvider{}
+var secretProvidersLock sync.RWMutex
+
+// RegisterSecretProvider registers a SecretProvider for its scheme, the previous one would be replaced
+func RegisterSecretProvider(provider SecretProvider) {
+	secretProvidersLock.Lock()
+	defer secretProvidersLock.Unlock()
+	secretProviders[provider.Scheme()] = provider
+}
+
+// GetSecretProvider returns the SecretProvider registered for the scheme
+func GetSecretProvider(scheme string) SecretProvider {
+	secretProvidersLock.RLock()
+	defer secretProvidersLock.RUnlock()
+	return secretProviders[scheme]
+}
+
+// ParseSecretReference returns the scheme and path of the value if it references a registered SecretProvider
+func ParseSecretReference(value string) (SecretProvider, string, bool) {
+	scheme, path, found := strings.Cut(value, "://")
+	if !found {
+		return nil, "", false
+	}
+	provider := GetSecretProvider(scheme)
+	if provider == nil {
+		return nil, "", false
+	}
+	return provider, path, true
+}
+
+// ResolveSecret returns the secret referenced by the value, or the value itself if it is not a reference
+func ResolveSecret(value string) (string, errors.Error) {
+	provider, path, ok := ParseSecretReference(value)
+	if !ok {
+		return value, nil
+	}
+	secret, err := provider.GetSecret(path)
+	if err != nil {
+		return "", errors.Default.Wrap(err, fmt.Sprintf("failed to resolve the secret from %s provider", provider.Scheme()))
+	}
+	return secret, nil
+}
+
+// ResolvedCopy returns a shallow copy of the connection, a pointer to struct, with the secret references resolved by
+// ResolveSecrets. The connection itself keeps the references, so it stays safe to be saved, while the copy must never
+// be saved
+func ResolvedCopy(connection interface{}) (interface{}, errors.Error) {
+	v := reflect.ValueOf(connection)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return nil, errors.Default.New("connection must be a pointer to struct")
+	}
+	copied := reflect.New(v.Elem().Type())
+	copied.Elem().Set(v.Elem())
+	err := resolveSecrets(copied.Elem())
+	if err != nil {
+		return nil, err
+	}
+	return copied.Interface(), nil
+}
+
+// ResolveSecrets replaces the secret references in the secret fields of the connection, the string fields tagged with
+// `encrypt:"yes"` including the ones of the embedded structs, with the secrets. The other fields like the endpoint are
+// left untouched. The connection is modified in place and must not be saved afterward, so the secrets only live in
+// memory while the references stay in the database, use ResolvedCopy unless the connection is a copy already
+func ResolveSecrets(connection interface{}) errors.Error {
+	v := reflect.ValueOf(connection)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return errors.Default.New("connection must be a pointer to struct")
+	}
+	return resolveSecrets(v.Elem())
+}
+
+func resolveSecrets(v reflect.Value) errors.Error {
+	t := v.Type()
+	for i := 0; i < v.NumField(); i++ {
+		fieldType := t.Field(i)
+		field := v.Field(i)
+		// exported fields of the embedded structs are promoted even if the struct type is unexported
+		if !fieldType.IsExported() && !fieldType.Anonymous {
+			continue
+		}
+		switch field.Kind() {
+		case reflect.Struct:
+			if err := resolveSecrets(field); err != nil {
+				return err
+			}
+		case reflect.String:
+			if !field.CanSet() || fieldType.Tag.Get("encrypt") != "yes" {
+				continue
+			}
+			secret, err := ResolveSecret(field.String())
+			if err != nil {
+				return errors.Default.Wrap(err, fmt.Sprintf("failed to resolve field %s", fieldType.Name))
+			}
+			field.SetString(secret)
+		}
+	}
+	return nil
+}
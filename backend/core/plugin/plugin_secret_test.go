@@ -0,0 +1,116 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type mockSecretProvider struct {
+	secrets map[string]string
+}
+
+func (p *mockSecretProvider) Scheme() string {
+	return "mock"
+}
+
+func (p *mockSecretProvider) GetSecret(path string) (string, errors.Error) {
+	secret, ok := p.secrets[path]
+	if !ok {
+		return "", errors.NotFound.New(path + " not found")
+	}
+	return secret, nil
+}
+
+func TestResolveSecrets(t *testing.T) {
+	RegisterSecretProvider(&mockSecretProvider{secrets: map[string]string{
+		"token":    "ghp_secret",
+		"password": "p@ss",
+	}})
+	type conn struct {
+		Endpoint string
+		Token    string `encrypt:"yes"`
+	}
+	connection := &struct {
+		conn
+		Name     string
+		Password string `encrypt:"yes"`
+		Inner    conn
+	}{
+		conn:     conn{Endpoint: "https://api.github.com/", Token: "mock://token"},
+		Name:     "github",
+		Password: "mock://password",
+		Inner:    conn{Token: "plain"},
+	}
+	err := ResolveSecrets(connection)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://api.github.com/", connection.Endpoint)
+	assert.Equal(t, "ghp_secret", connection.Token)
+	assert.Equal(t, "p@ss", connection.Password)
+	assert.Equal(t, "plain", connection.Inner.Token)
+
+	exported := &struct {
+		Conn struct {
+			Token string `encrypt:"yes"`
+		}
+	}{}
+	exported.Conn.Token = "mock://token"
+	assert.Nil(t, ResolveSecrets(exported))
+	assert.Equal(t, "ghp_secret", exported.Conn.Token)
+
+	exported.Conn.Token = "mock://missing"
+	assert.NotNil(t, ResolveSecrets(exported))
+
+	// the fields other than the secrets are never resolved
+	untagged := &struct {
+		Endpoint string
+		Proxy    string
+		Token    string `encrypt:"no"`
+	}{Endpoint: "mock://token", Proxy: "mock://password", Token: "mock://token"}
+	assert.Nil(t, ResolveSecrets(untagged))
+	assert.Equal(t, "mock://token", untagged.Endpoint)
+	assert.Equal(t, "mock://password", untagged.Proxy)
+	assert.Equal(t, "mock://token", untagged.Token)
+
+	unknown, err := ResolveSecret("unknown://token")
+	assert.Nil(t, err)
+	assert.Equal(t, "unknown://token", unknown)
+}
+
+func TestResolvedCopy(t *testing.T) {
+	RegisterSecretProvider(&mockSecretProvider{secrets: map[string]string{"token": "ghp_secret"}})
+	type conn struct {
+		Endpoint string
+		Token    string `encrypt:"yes"`
+	}
+	connection := &conn{Endpoint: "https://api.github.com/", Token: "mock://token"}
+	resolved, err := ResolvedCopy(connection)
+	assert.Nil(t, err)
+	assert.Equal(t, &conn{Endpoint: "https://api.github.com/", Token: "ghp_secret"}, resolved)
+	// the references are kept by the connection so it can be saved
+	assert.Equal(t, "mock://token", connection.Token)
+
+	connection.Token = "mock://missing"
+	_, err = ResolvedCopy(connection)
+	assert.NotNil(t, err)
+	_, err = ResolvedCopy(*connection)
+	assert.NotNil(t, err)
+}
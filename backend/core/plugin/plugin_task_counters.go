@@ -0,0 +1,105 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type subtaskCountersKey struct{}
+
+// SubtaskCounters counts the work done by the running subtask of a task, they are increased by the api helpers and
+// persisted into `_devlake_subtasks` by the runner once the subtask finished. All methods are safe to call on nil
+type SubtaskCounters struct {
+	recordsWritten  atomic.Int64
+	httpRequests    atomic.Int64
+	httpRetries     atomic.Int64
+	httpRateLimited atomic.Int64
+}
+
+// SubtaskCountersSnapshot is the values of the counters at some point
+type SubtaskCountersSnapshot struct {
+	RecordsWritten  int64
+	HttpRequests    int64
+	HttpRetries     int64
+	HttpRateLimited int64
+}
+
+// WithSubtaskCounters returns a copy of ctx carrying the counters
+func WithSubtaskCounters(ctx context.Context, counters *SubtaskCounters) context.Context {
+	return context.WithValue(ctx, subtaskCountersKey{}, counters)
+}
+
+// GetSubtaskCounters returns the counters carried by ctx, or nil if there is none
+func GetSubtaskCounters(ctx context.Context) *SubtaskCounters {
+	if ctx == nil {
+		return nil
+	}
+	counters, _ := ctx.Value(subtaskCountersKey{}).(*SubtaskCounters)
+	return counters
+}
+
+// GetSubtaskCountersOf returns the counters of the resource if it is an ExecContext, or nil otherwise
+func GetSubtaskCountersOf(res interface{}) *SubtaskCounters {
+	if execCtx, ok := res.(ExecContext); ok {
+		return GetSubtaskCounters(execCtx.GetContext())
+	}
+	return nil
+}
+
+// AddRecordsWritten increases the number of records saved into the database
+func (c *SubtaskCounters) AddRecordsWritten(n int) {
+	if c != nil {
+		c.recordsWritten.Add(int64(n))
+	}
+}
+
+// IncHttpRequests increases the number of http requests sent, including the retries
+func (c *SubtaskCounters) IncHttpRequests() {
+	if c != nil {
+		c.httpRequests.Add(1)
+	}
+}
+
+// IncHttpRetries increases the number of http requests retried
+func (c *SubtaskCounters) IncHttpRetries() {
+	if c != nil {
+		c.httpRetries.Add(1)
+	}
+}
+
+// IncHttpRateLimited increases the number of http responses with the status 429
+func (c *SubtaskCounters) IncHttpRateLimited() {
+	if c != nil {
+		c.httpRateLimited.Add(1)
+	}
+}
+
+// Reset sets all counters to zero and returns their previous values
+func (c *SubtaskCounters) Reset() SubtaskCountersSnapshot {
+	if c == nil {
+		return SubtaskCountersSnapshot{}
+	}
+	return SubtaskCountersSnapshot{
+		RecordsWritten:  c.recordsWritten.Swap(0),
+		HttpRequests:    c.httpRequests.Swap(0),
+		HttpRetries:     c.httpRetries.Swap(0),
+		HttpRateLimited: c.httpRateLimited.Swap(0),
+	}
+}
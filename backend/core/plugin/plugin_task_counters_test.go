@@ -0,0 +1,39 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugin
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubtaskCounters(t *testing.T) {
+	// counting without counters in the context is a no-op
+	GetSubtaskCounters(context.Background()).IncHttpRequests()
+
+	counters := &SubtaskCounters{}
+	ctx := WithSubtaskCounters(context.Background(), counters)
+	GetSubtaskCounters(ctx).AddRecordsWritten(10)
+	GetSubtaskCounters(ctx).IncHttpRequests()
+	GetSubtaskCounters(ctx).IncHttpRetries()
+	GetSubtaskCounters(ctx).IncHttpRateLimited()
+	assert.Equal(t, SubtaskCountersSnapshot{RecordsWritten: 10, HttpRequests: 1, HttpRetries: 1, HttpRateLimited: 1}, counters.Reset())
+	assert.Equal(t, SubtaskCountersSnapshot{}, counters.Reset())
+}
@@ -23,9 +23,12 @@ import (
 	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/log"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
 	contextimpl "github.com/apache/incubator-devlake/impls/context"
 	"github.com/apache/incubator-devlake/impls/dalgorm"
 	"github.com/apache/incubator-devlake/impls/logruslog"
+	"github.com/apache/incubator-devlake/impls/secretprovider"
+	"github.com/apache/incubator-devlake/impls/tracing"
 	"gorm.io/gorm"
 	"sync"
 )
@@ -45,11 +48,25 @@ func CreateAppBasicRes() context.BasicRes {
 	app_lock.Unlock()
 	cfg := config.GetConfig()
 	logger := logruslog.Global
+	err := tracing.Init(cfg)
+	if err != nil {
+		panic(err)
+	}
 	db, err := NewGormDb(cfg, logger)
 	if err != nil {
 		panic(err)
 	}
+	err = dalgorm.RegisterTracing(db)
+	if err != nil {
+		panic(err)
+	}
+	sqlDB, e := db.DB()
+	if e != nil {
+		panic(e)
+	}
+	metricshelper.RegisterDbStats(sqlDB)
 	dalgorm.Init(cfg.GetString(plugin.EncodeKeyEnvStr))
+	secretprovider.Init(cfg)
 	return CreateBasicRes(cfg, logger, db)
 }
 
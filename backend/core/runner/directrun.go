@@ -31,7 +31,10 @@ import (
 	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/models/migrationscripts"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/impls/tracing"
 	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // RunCmd FIXME ...
@@ -51,6 +54,7 @@ func RunCmd(cmd *cobra.Command) {
 // options: plugin config
 func DirectRun(cmd *cobra.Command, args []string, pluginTask plugin.PluginTask, options map[string]interface{}, timeAfter string) {
 	basicRes := CreateAppBasicRes()
+	defer tracing.Shutdown()
 	tasks, err := cmd.Flags().GetStringSlice("subtasks")
 	if err != nil {
 		panic(err)
@@ -84,7 +88,9 @@ func DirectRun(cmd *cobra.Command, args []string, pluginTask plugin.PluginTask,
 	if err != nil {
 		panic(err)
 	}
-	ctx := createContext()
+	ctx, span := tracing.Tracer().Start(createContext(), "task", trace.WithAttributes(
+		attribute.String("devlake.plugin", cmd.Use),
+	))
 	task := &models.Task{
 		Plugin:   cmd.Use,
 		Options:  options,
@@ -109,6 +115,7 @@ func DirectRun(cmd *cobra.Command, args []string, pluginTask plugin.PluginTask,
 		nil,
 		&syncPolicy,
 	)
+	tracing.EndSpan(span, err)
 	if err != nil {
 		panic(err)
 	}
@@ -19,6 +19,7 @@ package runner
 
 import (
 	gocontext "context"
+	"fmt"
 	"time"
 
 	"github.com/apache/incubator-devlake/core/context"
@@ -44,6 +45,14 @@ func RunPipeline(
 	if err != nil {
 		return err
 	}
+	dbPipeline := &models.Pipeline{}
+	err = db.First(dbPipeline, dal.Where("id = ?", pipelineId))
+	if err != nil {
+		return err
+	}
+	if dbPipeline.Plan.IsDag() {
+		return runPipelineDag(basicRes, dbPipeline, tasks, runTasks)
+	}
 	taskIds := make([][]uint64, 0)
 	for _, task := range tasks {
 		for len(taskIds) < task.PipelineRow {
@@ -103,3 +112,129 @@ func runPipelineTasks(
 	}
 	return err
 }
+
+// runPipelineDag executes the tasks as soon as their dependencies are finished, at most PIPELINE_TASK_MAX_PARALLEL
+// tasks at a time. The dependencies which are not pending are finished in the previous runs of the pipeline.
+func runPipelineDag(
+	basicRes context.BasicRes,
+	dbPipeline *models.Pipeline,
+	tasks []models.Task,
+	runTasks func([]uint64) errors.Error,
+) errors.Error {
+	db := basicRes.GetDal()
+	log := basicRes.GetLogger()
+	// if pipeline has been cancelled, just return.
+	if dbPipeline.Status == models.TASK_CANCELLED {
+		return nil
+	}
+	err := dbPipeline.Plan.ValidateDag()
+	if err != nil {
+		return err
+	}
+	nodes, err := buildDagNodes(dbPipeline.Plan, tasks)
+	if err != nil {
+		return err
+	}
+	maxParallel := basicRes.GetConfigReader().GetInt("PIPELINE_TASK_MAX_PARALLEL")
+	if maxParallel <= 0 {
+		maxParallel = len(nodes)
+	}
+
+	type taskResult struct {
+		node *dagNode
+		err  errors.Error
+	}
+	results := make(chan taskResult)
+	ready := make([]*dagNode, 0)
+	for _, node := range nodes {
+		if node.pending == 0 {
+			ready = append(ready, node)
+		}
+	}
+	running, finished, stage := 0, 0, 0
+	var lastErr errors.Error
+	stopped := false
+	for finished < len(nodes) {
+		for !stopped && running < maxParallel && len(ready) > 0 {
+			node := ready[0]
+			ready = ready[1:]
+			if node.task.PipelineRow > stage {
+				stage = node.task.PipelineRow
+				err = db.UpdateColumns(dbPipeline, []dal.DalSet{
+					{ColumnName: "status", Value: models.TASK_RUNNING},
+					{ColumnName: "stage", Value: stage},
+				})
+				if err != nil {
+					log.Error(err, "update pipeline state failed")
+				}
+			}
+			running++
+			go func(node *dagNode) {
+				results <- taskResult{node: node, err: runTasks([]uint64{node.task.ID})}
+			}(node)
+		}
+		if running == 0 {
+			if !stopped {
+				lastErr = errors.Default.New("no runnable task left, the plan might contain a dependency cycle")
+			}
+			break
+		}
+		result := <-results
+		running--
+		finished++
+		if result.err != nil {
+			log.Error(result.err, "run task %s failed", result.node.id)
+			lastErr = result.err
+			if errors.Is(result.err, gocontext.Canceled) || !dbPipeline.SkipOnFail {
+				// wait for the running tasks and leave the rest untouched
+				stopped = true
+				continue
+			}
+		}
+		for _, dependent := range result.node.dependents {
+			dependent.pending--
+			if dependent.pending == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	if dbPipeline.BeganAt != nil {
+		log.Info("pipeline finished in %d ms: %v", time.Now().UnixMilli()-dbPipeline.BeganAt.UnixMilli(), lastErr)
+	} else {
+		log.Info("pipeline finished at %d ms: %v", time.Now().UnixMilli(), lastErr)
+	}
+	return lastErr
+}
+
+type dagNode struct {
+	id         string
+	task       *models.Task
+	pending    int
+	dependents []*dagNode
+}
+
+// buildDagNodes links the pending tasks by the dependencies declared in the plan, tasks are matched with the plan by
+// their position
+func buildDagNodes(plan models.PipelinePlan, tasks []models.Task) ([]*dagNode, errors.Error) {
+	nodes := make([]*dagNode, 0, len(tasks))
+	nodeById := make(map[string]*dagNode)
+	for i := range tasks {
+		task := &tasks[i]
+		row, col := task.PipelineRow-1, task.PipelineCol-1
+		if row < 0 || row >= len(plan) || col < 0 || col >= len(plan[row]) {
+			return nil, errors.Default.New(fmt.Sprintf("task #%d at [%d][%d] is not found in the plan", task.ID, task.PipelineRow, task.PipelineCol))
+		}
+		node := &dagNode{id: plan.DagTaskId(row, col), task: task}
+		nodes = append(nodes, node)
+		nodeById[node.id] = node
+	}
+	for _, node := range nodes {
+		for _, dependency := range plan[node.task.PipelineRow-1][node.task.PipelineCol-1].DependsOn {
+			if dependencyNode, ok := nodeById[dependency]; ok {
+				dependencyNode.dependents = append(dependencyNode.dependents, node)
+				node.pending++
+			}
+		}
+	}
+	return nodes, nil
+}
@@ -0,0 +1,105 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	mockconfig "github.com/apache/incubator-devlake/mocks/core/config"
+	mockcontext "github.com/apache/incubator-devlake/mocks/core/context"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mocklog "github.com/apache/incubator-devlake/mocks/core/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockDagBasicRes(maxParallel int) *mockcontext.BasicRes {
+	mockDal := new(mockdal.Dal)
+	mockDal.On("UpdateColumns", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockLog := new(mocklog.Logger)
+	mockLog.On("Info", mock.Anything, mock.Anything).Maybe()
+	mockLog.On("Error", mock.Anything, mock.Anything, mock.Anything).Maybe()
+	mockCfg := new(mockconfig.ConfigReader)
+	mockCfg.On("GetInt", "PIPELINE_TASK_MAX_PARALLEL").Return(maxParallel)
+	basicRes := new(mockcontext.BasicRes)
+	basicRes.On("GetDal").Return(mockDal)
+	basicRes.On("GetLogger").Return(mockLog)
+	basicRes.On("GetConfigReader").Return(mockCfg)
+	return basicRes
+}
+
+func TestRunPipelineDag(t *testing.T) {
+	pipeline := &models.Pipeline{
+		Plan: models.PipelinePlan{
+			{
+				{Plugin: "github", Id: "github"},
+				{Plugin: "jira", Id: "jira"},
+			},
+			{
+				{Plugin: "dora", Id: "dora", DependsOn: []string{"github"}},
+			},
+		},
+	}
+	tasks := []models.Task{
+		{Model: common.Model{ID: 1}, PipelineRow: 1, PipelineCol: 1},
+		{Model: common.Model{ID: 2}, PipelineRow: 1, PipelineCol: 2},
+		{Model: common.Model{ID: 3}, PipelineRow: 2, PipelineCol: 1},
+	}
+	// jira does not finish until dora is done, which would dead lock in the stages format
+	doraDone := make(chan struct{})
+	var lock sync.Mutex
+	finished := make([]uint64, 0)
+	err := runPipelineDag(mockDagBasicRes(2), pipeline, tasks, func(taskIds []uint64) errors.Error {
+		switch taskIds[0] {
+		case 2:
+			<-doraDone
+		case 3:
+			close(doraDone)
+		}
+		lock.Lock()
+		finished = append(finished, taskIds[0])
+		lock.Unlock()
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 3, 2}, finished)
+}
+
+func TestRunPipelineDagStopsOnFailure(t *testing.T) {
+	pipeline := &models.Pipeline{
+		Plan: models.PipelinePlan{
+			{{Plugin: "github", Id: "github"}},
+			{{Plugin: "dora", Id: "dora", DependsOn: []string{"github"}}},
+		},
+	}
+	tasks := []models.Task{
+		{Model: common.Model{ID: 1}, PipelineRow: 1, PipelineCol: 1},
+		{Model: common.Model{ID: 2}, PipelineRow: 2, PipelineCol: 1},
+	}
+	executed := make([]uint64, 0)
+	err := runPipelineDag(mockDagBasicRes(0), pipeline, tasks, func(taskIds []uint64) errors.Error {
+		executed = append(executed, taskIds...)
+		return errors.Default.New("failed")
+	})
+	assert.NotNil(t, err)
+	assert.Equal(t, []uint64{1}, executed)
+}
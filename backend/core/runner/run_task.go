@@ -32,9 +32,13 @@ import (
 	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/core/utils"
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	contextimpl "github.com/apache/incubator-devlake/impls/context"
 	"github.com/apache/incubator-devlake/impls/logruslog"
+	"github.com/apache/incubator-devlake/impls/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // RunTask FIXME ...
@@ -54,10 +58,17 @@ func RunTask(
 		return err
 	}
 
+	ctx, span := tracing.Tracer().Start(ctx, "task", trace.WithAttributes(
+		attribute.Int64("devlake.task_id", int64(task.ID)),
+		attribute.Int64("devlake.pipeline_id", int64(task.PipelineId)),
+		attribute.String("devlake.plugin", task.Plugin),
+	))
 	logger, err := getTaskLogger(basicRes.GetLogger(), task)
 	if err != nil {
+		tracing.EndSpan(span, err)
 		return err
 	}
+	logger = logruslog.WithTraceContext(logger, ctx)
 	beganAt := time.Now()
 	if task.BeganAt != nil {
 		beganAt = *task.BeganAt
@@ -76,45 +87,16 @@ func RunTask(
 			logger.Error(err, "run task failed with panic")
 		}
 		finishedAt := time.Now()
-		spentSeconds := finishedAt.Unix() - beganAt.Unix()
+		status := models.TASK_COMPLETED
 		if err != nil {
-			lakeErr := errors.AsLakeErrorType(err)
-			subTaskName := "unknown"
-			if lakeErr = lakeErr.As(errors.SubtaskErr); lakeErr != nil {
-				if meta, ok := lakeErr.GetData().(*plugin.SubTaskMeta); ok {
-					subTaskName = meta.Name
-				}
-			} else {
-				lakeErr = errors.Convert(err)
-			}
-			dbe := db.UpdateColumns(task, []dal.DalSet{
-				{ColumnName: "status", Value: models.TASK_FAILED},
-				{ColumnName: "message", Value: lakeErr.Error()},
-				{ColumnName: "error_name", Value: lakeErr.Messages().Format()},
-				{ColumnName: "finished_at", Value: finishedAt},
-				{ColumnName: "spent_seconds", Value: spentSeconds},
-				{ColumnName: "failed_sub_task", Value: subTaskName},
-			})
-			if dbe != nil {
-				logger.Error(dbe, "failed to finalize task status into db (task failed)")
-			}
-		} else {
-			dbe := db.UpdateColumns(task, []dal.DalSet{
-				{ColumnName: "status", Value: models.TASK_COMPLETED},
-				{ColumnName: "message", Value: ""},
-				{ColumnName: "finished_at", Value: finishedAt},
-				{ColumnName: "spent_seconds", Value: spentSeconds},
-			})
-			if dbe != nil {
-				logger.Error(dbe, "failed to finalize task status into db (task succeeded)")
-			}
+			status = models.TASK_FAILED
+		}
+		metricshelper.TaskDuration.WithLabelValues(task.Plugin, status).Observe(finishedAt.Sub(beganAt).Seconds())
+		tracing.EndSpan(span, err)
+		if !finalizeTask(db, logger, task, dbPipeline, err, beganAt, finishedAt) {
+			logger.Warn(err, "pipeline #%d was taken over by another worker, leaving task #%d to it", task.PipelineId, task.ID)
+			return
 		}
-		// update finishedTasks
-		errors.Must(db.UpdateColumn(
-			&models.Pipeline{},
-			"finished_tasks", dal.Expr("finished_tasks + 1"),
-			dal.Where("id=?", task.PipelineId),
-		))
 		// not return err if the `SkipOnFail` is true and the error is not canceled
 		if dbPipeline.SkipOnFail && !errors.Is(err, gocontext.Canceled) {
 			err = nil
@@ -146,6 +128,69 @@ func RunTask(
 	return err
 }
 
+// finalizeTask records the result of the task and counts it as finished in the pipeline. The pipeline is requeued and
+// leased by another worker if this one stopped sending heartbeats, its rows belong to the new lease holder then and
+// are left untouched, in which case false is returned
+func finalizeTask(
+	db dal.Dal,
+	logger log.Logger,
+	task *models.Task,
+	dbPipeline *models.Pipeline,
+	err errors.Error,
+	beganAt time.Time,
+	finishedAt time.Time,
+) bool {
+	spentSeconds := finishedAt.Unix() - beganAt.Unix()
+	leaseHeld := dal.Where(
+		fmt.Sprintf("pipeline_id IN (SELECT id FROM %s WHERE id = ? AND worker_id = ?)", dbPipeline.TableName()),
+		task.PipelineId, dbPipeline.WorkerId,
+	)
+	var updated int64
+	var dbe errors.Error
+	if err != nil {
+		lakeErr := errors.AsLakeErrorType(err)
+		subTaskName := "unknown"
+		if lakeErr = lakeErr.As(errors.SubtaskErr); lakeErr != nil {
+			if meta, ok := lakeErr.GetData().(*plugin.SubTaskMeta); ok {
+				subTaskName = meta.Name
+			}
+		} else {
+			lakeErr = errors.Convert(err)
+		}
+		updated, dbe = db.UpdateColumnsAffected(task, []dal.DalSet{
+			{ColumnName: "status", Value: models.TASK_FAILED},
+			{ColumnName: "message", Value: lakeErr.Error()},
+			{ColumnName: "error_name", Value: lakeErr.Messages().Format()},
+			{ColumnName: "finished_at", Value: finishedAt},
+			{ColumnName: "spent_seconds", Value: spentSeconds},
+			{ColumnName: "failed_sub_task", Value: subTaskName},
+		}, leaseHeld)
+		if dbe != nil {
+			logger.Error(dbe, "failed to finalize task status into db (task failed)")
+		}
+	} else {
+		updated, dbe = db.UpdateColumnsAffected(task, []dal.DalSet{
+			{ColumnName: "status", Value: models.TASK_COMPLETED},
+			{ColumnName: "message", Value: ""},
+			{ColumnName: "finished_at", Value: finishedAt},
+			{ColumnName: "spent_seconds", Value: spentSeconds},
+		}, leaseHeld)
+		if dbe != nil {
+			logger.Error(dbe, "failed to finalize task status into db (task succeeded)")
+		}
+	}
+	if dbe == nil && updated == 0 {
+		return false
+	}
+	// update finishedTasks
+	errors.Must(db.UpdateColumn(
+		&models.Pipeline{},
+		"finished_tasks", dal.Expr("finished_tasks + 1"),
+		dal.Where("id = ? AND worker_id = ?", task.PipelineId, dbPipeline.WorkerId),
+	))
+	return true
+}
+
 // RunPluginTask FIXME ...
 func RunPluginTask(
 	ctx gocontext.Context,
@@ -172,19 +217,9 @@ func RunPluginTask(
 	)
 }
 
-// RunPluginSubTasks FIXME ...
-func RunPluginSubTasks(
-	ctx gocontext.Context,
-	basicRes context.BasicRes,
-	task *models.Task,
-	pluginTask plugin.PluginTask,
-	progress chan plugin.RunningProgress,
-	syncPolicy *models.SyncPolicy,
-) errors.Error {
-	logger := basicRes.GetLogger()
-	logger.Info("start plugin")
-	// find out all possible subtasks this plugin can offer
-	subtaskMetas := pluginTask.SubTaskMetas()
+// GetSubtasksFlag returns whether each subtask of the plugin is enabled, according to the subtasks specified by the
+// user and the sync policy
+func GetSubtasksFlag(subtaskMetas []plugin.SubTaskMeta, subtasks []string, syncPolicy *models.SyncPolicy) (map[string]bool, errors.Error) {
 	subtasksFlag := make(map[string]bool)
 	for _, subtaskMeta := range subtaskMetas {
 		subtasksFlag[subtaskMeta.Name] = subtaskMeta.EnabledByDefault
@@ -198,12 +233,12 @@ func RunPluginSubTasks(
 	*/
 
 	// user specifies what subtasks to run
-	if len(task.Subtasks) != 0 {
+	if len(subtasks) != 0 {
 		// decode user specified subtasks
 		var specifiedTasks []string
-		err := api.Decode(task.Subtasks, &specifiedTasks, nil)
+		err := api.Decode(subtasks, &specifiedTasks, nil)
 		if err != nil {
-			return errors.Default.Wrap(err, "subtasks could not be decoded")
+			return nil, errors.Default.Wrap(err, "subtasks could not be decoded")
 		}
 		if len(specifiedTasks) > 0 {
 			// first, disable all subtasks
@@ -215,7 +250,7 @@ func RunPluginSubTasks(
 				if _, ok := subtasksFlag[task]; ok {
 					subtasksFlag[task] = true
 				} else {
-					return errors.Default.New(fmt.Sprintf("subtask %s does not exist", task))
+					return nil, errors.Default.New(fmt.Sprintf("subtask %s does not exist", task))
 				}
 			}
 		}
@@ -231,6 +266,26 @@ func RunPluginSubTasks(
 			subtasksFlag[subtaskMeta.Name] = true
 		}
 	}
+	return subtasksFlag, nil
+}
+
+// RunPluginSubTasks FIXME ...
+func RunPluginSubTasks(
+	ctx gocontext.Context,
+	basicRes context.BasicRes,
+	task *models.Task,
+	pluginTask plugin.PluginTask,
+	progress chan plugin.RunningProgress,
+	syncPolicy *models.SyncPolicy,
+) errors.Error {
+	logger := basicRes.GetLogger()
+	logger.Info("start plugin")
+	// find out all possible subtasks this plugin can offer
+	subtaskMetas := pluginTask.SubTaskMetas()
+	subtasksFlag, err := GetSubtasksFlag(subtaskMetas, task.Subtasks, syncPolicy)
+	if err != nil {
+		return err
+	}
 
 	// calculate total step(number of task to run)
 	steps := 0
@@ -240,6 +295,17 @@ func RunPluginSubTasks(
 		}
 	}
 
+	// counters are shared by the subtasks, they are reset when a subtask begins
+	ctx = plugin.WithSubtaskCounters(ctx, &plugin.SubtaskCounters{})
+	// the spans of the http requests and the queries are attached to the running subtask
+	ctx = tracing.WithActiveSpan(ctx)
+	if tracing.Enabled() {
+		basicRes = contextimpl.NewDefaultBasicRes(
+			basicRes.GetConfigReader(),
+			logger,
+			basicRes.GetDal().Session(dal.SessionConfig{Context: tracing.Detach(ctx)}),
+		)
+	}
 	taskCtx := contextimpl.NewDefaultTaskContext(ctx, basicRes, task.Plugin, subtasksFlag, progress)
 	if closeablePlugin, ok := pluginTask.(plugin.CloseablePluginTask); ok {
 		defer closeablePlugin.Close(taskCtx)
@@ -321,6 +387,12 @@ func RunPluginSubTasks(
 				)
 				subtaskFinished = sfc > 0
 			}
+			if !subtaskFinished && task.ResumedFromTaskId > 0 {
+				subtaskFinished, err = resumeSubtask(basicRes, task, subtaskMeta.Name)
+				if err != nil {
+					return err
+				}
+			}
 		}
 		if subtaskFinished {
 			logger.Info("subtask %s already finished previously", subtaskMeta.Name)
@@ -405,7 +477,7 @@ func runSubtask(
 	parentID uint64,
 	subtaskNumber int,
 	entryPoint plugin.SubTaskEntryPoint,
-) errors.Error {
+) (err errors.Error) {
 	beginAt := time.Now()
 	subtask := &models.Subtask{
 		Name:    ctx.GetName(),
@@ -413,18 +485,58 @@ func runSubtask(
 		Number:  subtaskNumber,
 		BeganAt: &beginAt,
 	}
+	counters := plugin.GetSubtaskCounters(ctx.GetContext())
+	counters.Reset()
+	_, span := tracing.Tracer().Start(ctx.GetContext(), "subtask", trace.WithAttributes(
+		attribute.String("devlake.subtask", subtask.Name),
+		attribute.Int("devlake.subtask_number", subtaskNumber),
+	))
+	tracing.SetActiveSpan(ctx.GetContext(), span)
 	recordSubtask(basicRes, subtask)
 	// defer to record subtask status
 	defer func() {
+		tracing.SetActiveSpan(ctx.GetContext(), nil)
+		tracing.EndSpan(span, err)
 		finishedAt := time.Now()
 		subtask.FinishedAt = &finishedAt
 		subtask.SpentSeconds = finishedAt.Unix() - beginAt.Unix()
+		snapshot := counters.Reset()
+		subtask.RecordsWritten = snapshot.RecordsWritten
+		subtask.HttpRequests = snapshot.HttpRequests
+		subtask.HttpRetries = snapshot.HttpRetries
+		subtask.HttpRateLimited = snapshot.HttpRateLimited
 
 		recordSubtask(basicRes, subtask)
 	}()
 	return entryPoint(ctx)
 }
 
+// resumeSubtask returns true if the subtask was finished successfully by the previous attempt of the task, in which case
+// the result is copied to the task so that it would be skipped when the task is resumed again
+func resumeSubtask(basicRes context.BasicRes, task *models.Task, name string) (bool, errors.Error) {
+	db := basicRes.GetDal()
+	previous := &models.Subtask{}
+	err := db.First(previous, dal.Where(
+		"task_id = ? AND name = ? AND finished_at IS NOT NULL AND is_failed = ?", task.ResumedFromTaskId, name, false,
+	))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Default.Wrap(err, fmt.Sprintf("error getting subtask %s of task %d", name, task.ResumedFromTaskId))
+	}
+	recordSubtask(basicRes, &models.Subtask{
+		TaskID:          task.ID,
+		Name:            name,
+		Number:          previous.Number,
+		BeganAt:         previous.BeganAt,
+		FinishedAt:      previous.FinishedAt,
+		SpentSeconds:    previous.SpentSeconds,
+		SubtaskCounters: previous.SubtaskCounters,
+	})
+	return true, nil
+}
+
 func recordSubtask(basicRes context.BasicRes, subtask *models.Subtask) {
 	where := dal.Where("task_id = ? and name = ?", subtask.TaskID, subtask.Name)
 	if err := basicRes.GetDal().UpdateColumns(subtask, []dal.DalSet{
@@ -433,6 +545,10 @@ func recordSubtask(basicRes context.BasicRes, subtask *models.Subtask) {
 		{ColumnName: "spent_seconds", Value: subtask.SpentSeconds},
 		//{ColumnName: "finished_records", Value: subtask.FinishedRecords}, // FinishedRecords is zero always.
 		{ColumnName: "number", Value: subtask.Number},
+		{ColumnName: "records_written", Value: subtask.RecordsWritten},
+		{ColumnName: "http_requests", Value: subtask.HttpRequests},
+		{ColumnName: "http_retries", Value: subtask.HttpRetries},
+		{ColumnName: "http_rate_limited", Value: subtask.HttpRateLimited},
 	}, where); err != nil {
 		basicRes.GetLogger().Error(err, "error writing subtask %d status to DB: %v", subtask.ID)
 	}
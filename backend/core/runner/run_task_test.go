@@ -0,0 +1,108 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package runner
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	mockcontext "github.com/apache/incubator-devlake/mocks/core/context"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResumeSubtask(t *testing.T) {
+	finishedAt := time.Now()
+	notFound := errors.NotFound.New("record not found")
+	mockDal := new(mockdal.Dal)
+	mockDal.On("First", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*args.Get(0).(*models.Subtask) = models.Subtask{TaskID: 1, Name: "collectIssues", Number: 2, FinishedAt: &finishedAt, SpentSeconds: 3}
+	}).Once()
+	mockDal.On("First", mock.Anything, mock.Anything).Return(notFound).Once()
+	mockDal.On("IsErrorNotFound", notFound).Return(true)
+	mockDal.On("UpdateColumns", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	basicRes := new(mockcontext.BasicRes)
+	basicRes.On("GetDal").Return(mockDal)
+
+	task := &models.Task{Model: common.Model{ID: 2}, ResumedFromTaskId: 1}
+	finished, err := resumeSubtask(basicRes, task, "collectIssues")
+	assert.Nil(t, err)
+	assert.True(t, finished)
+	// the previous result is recorded to the new task
+	recorded := mockDal.Calls[1].Arguments.Get(0).(*models.Subtask)
+	assert.Equal(t, uint64(2), recorded.TaskID)
+	assert.Equal(t, "collectIssues", recorded.Name)
+	assert.Equal(t, &finishedAt, recorded.FinishedAt)
+	assert.Equal(t, int64(3), recorded.SpentSeconds)
+
+	finished, err = resumeSubtask(basicRes, task, "extractIssues")
+	assert.Nil(t, err)
+	assert.False(t, finished)
+	mockDal.AssertNumberOfCalls(t, "UpdateColumns", 1)
+}
+
+func TestGetSubtasksFlag(t *testing.T) {
+	subtaskMetas := []plugin.SubTaskMeta{
+		{Name: "collectIssues", EnabledByDefault: true},
+		{Name: "extractIssues", EnabledByDefault: true},
+		{Name: "convertIssues", EnabledByDefault: false},
+		{Name: "convertAccounts", EnabledByDefault: false, Required: true},
+	}
+	flags, err := GetSubtasksFlag(subtaskMetas, nil, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"collectIssues": true, "extractIssues": true, "convertIssues": false, "convertAccounts": true}, flags)
+
+	flags, err = GetSubtasksFlag(subtaskMetas, []string{"collectIssues", "convertIssues"}, &models.SyncPolicy{
+		TriggerSyncPolicy: models.TriggerSyncPolicy{SkipCollectors: true},
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]bool{"collectIssues": false, "extractIssues": false, "convertIssues": true, "convertAccounts": true}, flags)
+
+	_, err = GetSubtasksFlag(subtaskMetas, []string{"collectPrs"}, nil)
+	assert.NotNil(t, err)
+}
+
+func TestFinalizeTask(t *testing.T) {
+	beganAt := time.Now().Add(-time.Minute)
+	task := &models.Task{Model: common.Model{ID: 3}, PipelineId: 2}
+	dbPipeline := &models.Pipeline{Model: common.Model{ID: 2}, WorkerId: "worker-1"}
+
+	mockDal := new(mockdal.Dal)
+	mockDal.On("UpdateColumnsAffected", task, mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+	mockDal.On("UpdateColumn", mock.Anything, "finished_tasks", mock.Anything, mock.Anything).Return(nil).Once()
+	assert.True(t, finalizeTask(mockDal, unithelper.DummyLogger(), task, dbPipeline, errors.Default.New("failed"), beganAt, time.Now()))
+	// both updates are guarded by the lease of the worker
+	taskClause := mockDal.Calls[0].Arguments.Get(2).([]dal.Clause)[0].Data.(dal.DalClause)
+	assert.Equal(t, []interface{}{uint64(2), "worker-1"}, taskClause.Params)
+	pipelineClause := mockDal.Calls[1].Arguments.Get(3).([]dal.Clause)[0].Data.(dal.DalClause)
+	assert.Equal(t, "id = ? AND worker_id = ?", pipelineClause.Expr)
+	assert.Equal(t, []interface{}{uint64(2), "worker-1"}, pipelineClause.Params)
+
+	// the pipeline was taken over by another worker, the task and the pipeline are left to it
+	mockDal = new(mockdal.Dal)
+	mockDal.On("UpdateColumnsAffected", task, mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+	assert.False(t, finalizeTask(mockDal, unithelper.DummyLogger(), task, dbPipeline, nil, beganAt, time.Now()))
+	mockDal.AssertNotCalled(t, "UpdateColumn", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,166 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audithelper
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+// fields changed on every update which are not worth recording
+var ignoredFields = map[string]bool{
+	"createdAt": true,
+	"updatedAt": true,
+}
+
+type AuditHelper struct {
+	basicRes context.BasicRes
+}
+
+func NewAuditHelper(basicRes context.BasicRes) *AuditHelper {
+	return &AuditHelper{
+		basicRes: basicRes,
+	}
+}
+
+// AuditTarget identifies the entity being changed
+type AuditTarget struct {
+	Type   string
+	Plugin string
+	Id     string
+}
+
+// Record writes an audit log of the action taken by the user, `before` and `after` are the entity before and after
+// the change (nil for creation and deletion) and must be sanitized by the caller. tx is optional
+func (h *AuditHelper) Record(tx dal.Dal, user *common.User, action string, target AuditTarget, before, after interface{}) errors.Error {
+	if tx == nil {
+		tx = h.basicRes.GetDal()
+	}
+	diff, err := Diff(before, after)
+	if err != nil {
+		return err
+	}
+	auditLog := &models.AuditLog{
+		Action:       action,
+		TargetType:   target.Type,
+		TargetPlugin: target.Plugin,
+		TargetId:     target.Id,
+		Diff:         diff,
+	}
+	if user != nil {
+		auditLog.User = user.Name
+		auditLog.UserEmail = user.Email
+	}
+	err = tx.Create(auditLog)
+	if err != nil {
+		return errors.Default.Wrap(err, "error writing audit log")
+	}
+	return nil
+}
+
+// Diff returns the fields which differ between the json representations of before and after, in the form of
+// `{"field": {"old": ..., "new": ...}}`
+func Diff(before, after interface{}) (json.RawMessage, errors.Error) {
+	oldFields, err := toFields(before)
+	if err != nil {
+		return nil, err
+	}
+	newFields, err := toFields(after)
+	if err != nil {
+		return nil, err
+	}
+	diff := map[string]map[string]interface{}{}
+	for name, oldValue := range oldFields {
+		newValue, ok := newFields[name]
+		if !ok || !reflect.DeepEqual(oldValue, newValue) {
+			diff[name] = map[string]interface{}{"old": oldValue, "new": newValue}
+		}
+	}
+	for name, newValue := range newFields {
+		if _, ok := oldFields[name]; !ok {
+			diff[name] = map[string]interface{}{"old": nil, "new": newValue}
+		}
+	}
+	result, e := json.Marshal(diff)
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	return result, nil
+}
+
+// Snapshot returns the json representation of the entity as a map, so that it will not be affected by the changes
+// made to the entity afterward
+func Snapshot(v interface{}) (map[string]interface{}, errors.Error) {
+	return toFields(v)
+}
+
+func toFields(v interface{}) (map[string]interface{}, errors.Error) {
+	fields := map[string]interface{}{}
+	if v == nil || (reflect.ValueOf(v).Kind() == reflect.Ptr && reflect.ValueOf(v).IsNil()) {
+		return fields, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	err = json.Unmarshal(data, &fields)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("%T is not a json object", v))
+	}
+	for name := range ignoredFields {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+// Sanitize returns the result of the `Sanitize()` method of the entity if it has one, i.e. connections
+func Sanitize(v interface{}) interface{} {
+	if v == nil {
+		return nil
+	}
+	value := reflect.ValueOf(v)
+	method := value.MethodByName("Sanitize")
+	if !method.IsValid() && value.Kind() == reflect.Ptr && !value.IsNil() {
+		method = value.Elem().MethodByName("Sanitize")
+	}
+	if !method.IsValid() || method.Type().NumIn() != 0 || method.Type().NumOut() != 1 {
+		return v
+	}
+	return method.Call(nil)[0].Interface()
+}
+
+// TargetId returns the id of the connection, scope or scope config
+func TargetId(v interface{}) string {
+	switch m := v.(type) {
+	case plugin.ToolLayerScope:
+		return fmt.Sprintf("%d:%s", m.ScopeConnectionId(), m.ScopeId())
+	case plugin.ToolLayerScopeConfig:
+		return fmt.Sprintf("%d", m.ScopeConfigId())
+	case plugin.ToolLayerConnection:
+		return fmt.Sprintf("%d", m.ConnectionId())
+	}
+	return ""
+}
@@ -0,0 +1,70 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audithelper
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type testConnection struct {
+	ID        uint64 `json:"id"`
+	Name      string `json:"name"`
+	Token     string `json:"token"`
+	UpdatedAt string `json:"updatedAt"`
+}
+
+func (c testConnection) Sanitize() testConnection {
+	c.Token = "***"
+	return c
+}
+
+func TestDiff(t *testing.T) {
+	before := &testConnection{ID: 1, Name: "github", Token: "old", UpdatedAt: "yesterday"}
+	after := &testConnection{ID: 1, Name: "github-cloud", Token: "new", UpdatedAt: "today"}
+
+	diff, err := Diff(Sanitize(before), Sanitize(after))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"name":{"old":"github","new":"github-cloud"}}`, string(diff))
+
+	diff, err = Diff(nil, Sanitize(after))
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"id":{"old":null,"new":1},
+		"name":{"old":null,"new":"github-cloud"},
+		"token":{"old":null,"new":"***"}
+	}`, string(diff))
+
+	diff, err = Diff(before, (*testConnection)(nil))
+	assert.Nil(t, err)
+	changes := map[string]map[string]interface{}{}
+	assert.Nil(t, json.Unmarshal(diff, &changes))
+	assert.Equal(t, "old", changes["token"]["old"])
+	assert.Nil(t, changes["token"]["new"])
+}
+
+func TestSnapshot(t *testing.T) {
+	connection := &testConnection{ID: 1, Name: "github"}
+	snapshot, err := Snapshot(connection)
+	assert.Nil(t, err)
+	connection.Name = "changed"
+	assert.Equal(t, "github", snapshot["name"])
+	assert.NotContains(t, snapshot, "updatedAt")
+}
@@ -0,0 +1,102 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metricshelper
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "devlake"
+
+// Registry holds the metrics exposed by the /metrics endpoint
+var Registry = prometheus.NewRegistry()
+
+var (
+	// TaskDuration observes the duration of the finished tasks
+	TaskDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "task_duration_seconds",
+		Help:      "Duration of the finished tasks.",
+		Buckets:   []float64{10, 30, 60, 300, 600, 1800, 3600, 3 * 3600, 6 * 3600, 12 * 3600, 24 * 3600},
+	}, []string{"plugin", "status"})
+	// ApiInflightRequests is the number of the requests being sent by the ApiAsyncClient
+	ApiInflightRequests = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "api_client_inflight_requests",
+		Help:      "Number of requests being sent by the api async clients.",
+	}, []string{"plugin", "connection_id"})
+	// ApiRequests counts the requests sent by the ApiAsyncClient
+	ApiRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_client_requests_total",
+		Help:      "Number of requests sent by the api async clients.",
+	}, []string{"plugin", "connection_id"})
+	// ApiRetries counts the requests retried by the ApiAsyncClient
+	ApiRetries = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_client_retries_total",
+		Help:      "Number of requests retried by the api async clients.",
+	}, []string{"plugin", "connection_id"})
+	// ApiRateLimited counts the responses with status 429 received by the ApiAsyncClient
+	ApiRateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_client_rate_limited_total",
+		Help:      "Number of responses with status 429 received by the api async clients.",
+	}, []string{"plugin", "connection_id"})
+	// ApiRateLimitWait accumulates the time the requests of the ApiAsyncClient waited for the rate limiter
+	ApiRateLimitWait = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "api_client_rate_limit_wait_seconds_total",
+		Help:      "Time the requests of the api async clients waited to be scheduled by the rate limiter.",
+	}, []string{"plugin", "connection_id"})
+	// BatchSaveRows counts the rows written by BatchSave, which is used by the BatchSaveDivider
+	BatchSaveRows = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "batch_save_rows_total",
+		Help:      "Number of rows written by batch save.",
+	}, []string{"table"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		TaskDuration,
+		ApiInflightRequests,
+		ApiRequests,
+		ApiRetries,
+		ApiRateLimited,
+		ApiRateLimitWait,
+		BatchSaveRows,
+	)
+}
+
+// RegisterDbStats exposes the connection pool stats of the database
+func RegisterDbStats(db *sql.DB) {
+	Registry.MustRegister(collectors.NewDBStatsCollector(db, namespace))
+}
+
+// Handler serves the metrics in the Prometheus/OpenMetrics text format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{EnableOpenMetrics: true})
+}
@@ -30,6 +30,9 @@ import (
 	"github.com/apache/incubator-devlake/core/log"
 	plugin "github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/core/utils"
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
+	"github.com/prometheus/client_golang/prometheus"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 // HttpMinStatusRetryCode is which status will retry
@@ -44,6 +47,8 @@ type ApiAsyncClient struct {
 	maxRetry     int
 	numOfWorkers int
 	logger       log.Logger
+	counters     *plugin.SubtaskCounters
+	pluginName   string
 }
 
 const defaultTimeout = 120 * time.Second
@@ -129,6 +134,8 @@ func CreateAsyncApiClient(
 		retry,
 		numOfWorkers,
 		logger,
+		plugin.GetSubtaskCounters(taskCtx.GetContext()),
+		taskCtx.GetName(),
 	}, nil
 }
 
@@ -154,14 +161,29 @@ func (apiClient *ApiAsyncClient) DoAsync(
 	handler plugin.ApiAsyncCallback,
 	retry int,
 ) {
+	labels := prometheus.Labels{"plugin": apiClient.pluginName, "connection_id": apiClient.connectionId}
+	var submittedAt time.Time
 	var request func() errors.Error
 	request = func() errors.Error {
 		var err error
 		var res *http.Response
 		var respBody []byte
 
+		wait := time.Since(submittedAt).Seconds()
+		metricshelper.ApiRateLimitWait.With(labels).Add(wait)
 		apiClient.logger.Debug("endpoint: %s  method: %s  header: %s  body: %s query: %s", path, method, header, body, query)
-		res, err = apiClient.Do(method, path, query, body, header)
+		inflight := metricshelper.ApiInflightRequests.With(labels)
+		inflight.Inc()
+		res, err = apiClient.do(
+			method, path, query, body, header,
+			attribute.Int("devlake.retry", retry),
+			attribute.Float64("devlake.rate_limit_wait_seconds", wait),
+		)
+		inflight.Dec()
+		metricshelper.ApiRequests.With(labels).Inc()
+		if err == nil && res.StatusCode == http.StatusTooManyRequests {
+			metricshelper.ApiRateLimited.With(labels).Inc()
+		}
 		if err == ErrIgnoreAndContinue {
 			// make sure defer func got be executed
 			err = nil //nolint
@@ -196,8 +218,11 @@ func (apiClient *ApiAsyncClient) DoAsync(
 			// check whether we still have retry times and not error from handler and canceled error
 			if retry < apiClient.maxRetry && err != context.Canceled {
 				apiClient.logger.Warn(err, "retry #%d calling %s", retry, path)
+				apiClient.counters.IncHttpRetries()
+				metricshelper.ApiRetries.With(labels).Inc()
 				retry++
 				apiClient.NextTick(func() errors.Error {
+					submittedAt = time.Now()
 					apiClient.SubmitBlocking(request)
 					return nil
 				})
@@ -215,6 +240,7 @@ func (apiClient *ApiAsyncClient) DoAsync(
 		// when error occurs
 		return handler(res)
 	}
+	submittedAt = time.Now()
 	apiClient.SubmitBlocking(request)
 }
 
@@ -30,6 +30,7 @@ import (
 	"net/url"
 	"reflect"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -41,6 +42,11 @@ import (
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
 	"github.com/apache/incubator-devlake/core/utils"
+	"github.com/apache/incubator-devlake/impls/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ErrIgnoreAndContinue is a error which should be ignored
@@ -67,13 +73,35 @@ type ApiClient struct {
 	afterResponse plugin.ApiClientAfterResponse
 	ctx           gocontext.Context
 	logger        log.Logger
+	// connectionId labels the metrics of the requests, it is empty if the client is not created from a connection
+	connectionId string
 }
 
 // NewApiClientFromConnection creates ApiClient based on given connection.
+// The secret references of the connection, i.e. env://GITHUB_TOKEN, are resolved on a copy which is then used by
+// the ApiClient, the connection itself is left untouched so it stays safe to be saved.
 func NewApiClientFromConnection(
 	ctx gocontext.Context,
 	br context.BasicRes,
 	connection plugin.ApiConnection,
+) (*ApiClient, errors.Error) {
+	if reflect.ValueOf(connection).Kind() != reflect.Ptr {
+		panic(fmt.Errorf("connection is not a pointer"))
+	}
+	resolved, err := plugin.ResolvedCopy(connection)
+	if err != nil {
+		return nil, err
+	}
+	return NewApiClientFromResolvedConnection(ctx, br, resolved.(plugin.ApiConnection))
+}
+
+// NewApiClientFromResolvedConnection creates ApiClient based on given connection, which must be returned by
+// plugin.ResolvedCopy. The connection is prepared in place, so the values set by PrepareApiClient, i.e. the
+// installation token of a GitHub App, can be read afterward, and it must never be saved.
+func NewApiClientFromResolvedConnection(
+	ctx gocontext.Context,
+	br context.BasicRes,
+	connection plugin.ApiConnection,
 ) (*ApiClient, errors.Error) {
 	if reflect.ValueOf(connection).Kind() != reflect.Ptr {
 		panic(fmt.Errorf("connection is not a pointer"))
@@ -82,6 +110,9 @@ func NewApiClientFromConnection(
 	if err != nil {
 		return nil, err
 	}
+	if id := reflectField(connection, "ID"); id.IsValid() && id.CanUint() {
+		apiClient.connectionId = strconv.FormatUint(id.Uint(), 10)
+	}
 
 	// if connection needs to prepare the ApiClient, i.e. fetch token for future requests
 	if prepareApiClient, ok := connection.(plugin.PrepareApiClient); ok {
@@ -323,6 +354,18 @@ func (apiClient *ApiClient) Do(
 	query url.Values,
 	body interface{},
 	headers http.Header,
+) (*http.Response, errors.Error) {
+	return apiClient.do(method, path, query, body, headers)
+}
+
+// do sends the request in a span of the subtask, the attributes are added to the span
+func (apiClient *ApiClient) do(
+	method string,
+	path string,
+	query url.Values,
+	body interface{},
+	headers http.Header,
+	attributes ...attribute.KeyValue,
 ) (*http.Response, errors.Error) {
 	uri, err := GetURIStringPointer(apiClient.endpoint, path, query)
 	if err != nil {
@@ -378,11 +421,25 @@ func (apiClient *ApiClient) Do(
 		}
 	}
 	apiClient.logDebug("[api-client] %v %v", method, *uri)
+	counters := plugin.GetSubtaskCounters(apiClient.ctx)
+	counters.IncHttpRequests()
+	span := apiClient.startSpan(req, attributes)
 	res, err = errors.Convert01(apiClient.client.Do(req))
 	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
 		apiClient.logError(err, "[api-client] failed to request %s with error", req.URL.String())
 		return nil, err
 	}
+	span.SetAttributes(semconv.HTTPStatusCodeKey.Int(res.StatusCode))
+	if res.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, res.Status)
+	}
+	span.End()
+	if res.StatusCode == http.StatusTooManyRequests {
+		counters.IncHttpRateLimited()
+	}
 	// after receive
 	if apiClient.afterResponse != nil {
 		err = apiClient.afterResponse(res)
@@ -399,6 +456,26 @@ func (apiClient *ApiClient) Do(
 	return res, nil
 }
 
+// startSpan starts the span of the request, the query is left out of the url since it might carry credentials
+func (apiClient *ApiClient) startSpan(req *http.Request, attributes []attribute.KeyValue) trace.Span {
+	ctx := apiClient.ctx
+	if ctx == nil {
+		ctx = gocontext.Background()
+	}
+	attributes = append(
+		attributes,
+		semconv.HTTPMethodKey.String(req.Method),
+		semconv.HTTPURLKey.String(fmt.Sprintf("%s://%s%s", req.URL.Scheme, req.URL.Host, req.URL.Path)),
+	)
+	_, span := tracing.StartSpan(
+		ctx,
+		fmt.Sprintf("HTTP %s", req.Method),
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attributes...),
+	)
+	return span
+}
+
 // Get FIXME ...
 func (apiClient *ApiClient) Get(
 	path string,
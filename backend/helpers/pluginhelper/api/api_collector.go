@@ -169,9 +169,9 @@ func (collector *ApiCollector) Execute() errors.Error {
 	}
 	// flush data if not incremental collection
 	if !isIncremental {
-		err = db.Delete(&RawData{}, dal.From(collector.table), dal.Where("params = ?", collector.params))
+		err = flushRawData(db, collector.table, collector.params)
 		if err != nil {
-			return errors.Default.Wrap(err, "error deleting data from collector")
+			return err
 		}
 	}
 
@@ -486,6 +486,7 @@ func (collector *ApiCollector) fetchAsync(reqData *RequestData, handler func(int
 			return errors.Default.Wrap(err, fmt.Sprintf("error inserting raw rows into %s", collector.table))
 		}
 		logger.Debug("fetchAsync === total %d rows were saved into database", count)
+		plugin.GetSubtaskCountersOf(collector.args.Ctx).AddRecordsWritten(count)
 		// increase progress only when it was not nested
 		collector.args.Ctx.IncProgress(1)
 		if handler != nil {
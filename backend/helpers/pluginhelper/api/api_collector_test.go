@@ -46,6 +46,7 @@ func TestFetchPageUndetermined(t *testing.T) {
 	mockDal := new(mockdal.Dal)
 	mockDal.On("AutoMigrate", mock.Anything, mock.Anything).Return(nil).Once()
 	mockDal.On("Delete", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	mockDal.On("UpdateColumn", mock.Anything, "purged_before", nil, mock.Anything).Return(nil).Once()
 	mockDal.On("Create", mock.Anything, mock.Anything).Return(nil).Once()
 
 	mockCtx := unithelper.DummySubTaskContext(mockDal)
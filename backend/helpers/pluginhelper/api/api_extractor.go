@@ -82,6 +82,10 @@ func (extractor *ApiExtractor) Execute() errors.Error {
 		dal.Where("params = ?", extractor.params),
 		dal.Orderby("id ASC"),
 	}
+	rawDataIds, rawDataFiltered := getRawDataIdsFilter(extractor.args.Ctx, extractor.table)
+	if rawDataFiltered {
+		clauses = append(clauses, dal.Where("id IN ?", rawDataIds))
+	}
 
 	count, err := db.Count(clauses...)
 	if err != nil {
@@ -95,6 +99,8 @@ func (extractor *ApiExtractor) Execute() errors.Error {
 	defer cursor.Close()
 	// batch save divider
 	divider := NewBatchSaveDivider(extractor.args.Ctx, extractor.args.BatchSize, extractor.table, extractor.params)
+	// the records extracted from the other rows must be kept when only some rows are extracted
+	divider.SetIncrementalMode(rawDataFiltered)
 
 	// progress
 	extractor.args.Ctx.SetProgress(0, -1)
@@ -20,9 +20,11 @@ package api
 import (
 	"encoding/json"
 	"reflect"
+	"time"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/models/common"
 	plugin "github.com/apache/incubator-devlake/core/plugin"
 )
@@ -78,6 +80,8 @@ type StatefulApiExtractorArgs[InputType any] struct {
 type StatefulApiExtractor[InputType any] struct {
 	*StatefulApiExtractorArgs[InputType]
 	*SubtaskStateManager
+	rawDataIds      []uint64
+	rawDataFiltered bool
 }
 
 // NewStatefulApiExtractor creates a new StatefulApiExtractor
@@ -86,9 +90,18 @@ func NewStatefulApiExtractor[InputType any](args *StatefulApiExtractorArgs[Input
 	if err != nil {
 		return nil, err
 	}
+	// tells the raw data retention policies which raw rows are still needed by the next incremental run
+	stateManager.state.ExtractedTable = args.GetRawDataTable()
+	// extracting some rows is an incremental run, the children of the extracted records must be replaced as well
+	rawDataIds, rawDataFiltered := getRawDataIdsFilter(args.SubTaskContext, args.GetRawDataTable())
+	if rawDataFiltered {
+		stateManager.isIncremental = true
+	}
 	return &StatefulApiExtractor[InputType]{
 		StatefulApiExtractorArgs: args,
 		SubtaskStateManager:      stateManager,
+		rawDataIds:               rawDataIds,
+		rawDataFiltered:          rawDataFiltered,
 	}, nil
 }
 
@@ -102,6 +115,10 @@ func (extractor *StatefulApiExtractor[InputType]) Execute() errors.Error {
 	if !db.HasTable(table) {
 		return nil
 	}
+	// extracting the rows left by the raw data retention policies would lose the records of the purged ones
+	if !extractor.IsIncremental() && extractor.state.PurgedBefore != nil {
+		return extractor.requireFullCollection()
+	}
 
 	clauses := []dal.Clause{
 		dal.Select("id"),
@@ -110,13 +127,17 @@ func (extractor *StatefulApiExtractor[InputType]) Execute() errors.Error {
 		dal.Orderby("id ASC"),
 	}
 
-	if extractor.IsIncremental() {
-		since := extractor.GetSince()
-		if since != nil {
-			clauses = append(clauses, dal.Where("created_at >= ? ", since))
+	if extractor.rawDataFiltered {
+		clauses = append(clauses, dal.Where("id IN ?", extractor.rawDataIds))
+	} else {
+		if extractor.IsIncremental() {
+			since := extractor.GetSince()
+			if since != nil {
+				clauses = append(clauses, dal.Where("created_at >= ? ", since))
+			}
 		}
+		clauses = append(clauses, dal.Where("created_at < ? ", extractor.GetUntil()))
 	}
-	clauses = append(clauses, dal.Where("created_at < ? ", extractor.GetUntil()))
 
 	// first get total count for progress tracking
 	count, err := db.Count(clauses...)
@@ -199,8 +220,33 @@ func (extractor *StatefulApiExtractor[InputType]) Execute() errors.Error {
 	if err != nil {
 		return err
 	}
+	// the state is left for the next regular run, which has to extract the rows saved in the meantime
+	if extractor.rawDataFiltered {
+		return nil
+	}
 	// save the incremental state
 	return extractor.SubtaskStateManager.Close()
 }
 
+// requireFullCollection resets the state of the collectors of the raw table so the next run collects all the rows
+// again, the extraction is postponed until then and the existing records are kept
+func (extractor *StatefulApiExtractor[InputType]) requireFullCollection() errors.Error {
+	table := extractor.GetRawDataTable()
+	params := extractor.GetRawDataParams()
+	err := extractor.GetDal().UpdateColumn(
+		&models.CollectorLatestState{},
+		"latest_success_start", nil,
+		dal.Where("raw_data_table = ? AND raw_data_params = ?", table, params),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "error resetting the collector state")
+	}
+	extractor.GetLogger().Warn(
+		nil,
+		"raw rows of %s created before %s were purged, the full extraction is postponed until they are collected again by the next run",
+		table, extractor.state.PurgedBefore.Format(time.RFC3339),
+	)
+	return nil
+}
+
 var _ plugin.SubTask = (*StatefulApiExtractor[any])(nil)
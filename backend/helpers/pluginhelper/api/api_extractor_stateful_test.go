@@ -0,0 +1,104 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestStatefulApiExtractorRequiresFullCollectionAfterPurge(t *testing.T) {
+	time1 := errors.Must1(time.Parse(time.RFC3339, "2021-01-01T00:00:00Z"))
+	time2 := errors.Must1(time.Parse(time.RFC3339, "2022-01-01T00:00:00Z"))
+	for _, tc := range []struct {
+		name                   string
+		state                  *models.SubtaskState
+		config                 string
+		expectedFullCollection bool
+	}{
+		{
+			name:   "config unchanged - incremental extraction of the remaining rows",
+			state:  &models.SubtaskState{PrevStartedAt: &time2, PrevConfig: `"hello"`, PurgedBefore: &time1},
+			config: "hello",
+		},
+		{
+			name:                   "config changed - full extraction waits for a full collection",
+			state:                  &models.SubtaskState{PrevStartedAt: &time2, PrevConfig: `"hello"`, PurgedBefore: &time1},
+			config:                 "world",
+			expectedFullCollection: true,
+		},
+		{
+			name:   "config changed without purge - full extraction",
+			state:  &models.SubtaskState{PrevStartedAt: &time2, PrevConfig: `"hello"`},
+			config: "world",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDal := mockdal.NewDal(t)
+			mockDal.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				dst := args.Get(0).(*models.SubtaskState)
+				*dst = *tc.state
+			}).Return(nil).Once()
+			mockDal.On("HasTable", "_raw_test_table").Return(true).Once()
+			if tc.expectedFullCollection {
+				mockDal.On("UpdateColumn", mock.AnythingOfType("*models.CollectorLatestState"), "latest_success_start", nil, mock.Anything).Return(nil).Once()
+			} else {
+				mockDal.On("Count", mock.Anything).Return(int64(0), nil).Once()
+				mockDal.On("Pluck", "id", mock.Anything, mock.Anything).Return(nil).Once()
+				mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil).Once()
+			}
+
+			mockTaskCtx := new(mockplugin.TaskContext)
+			mockTaskCtx.On("SyncPolicy").Return(&models.SyncPolicy{})
+			mockTaskCtx.On("GetName").Return("test-plugin")
+			mockSubtaskCtx := new(mockplugin.SubTaskContext)
+			mockSubtaskCtx.On("TaskContext").Return(mockTaskCtx)
+			mockSubtaskCtx.On("GetName").Return("test-subtask")
+			mockSubtaskCtx.On("GetDal").Return(mockDal)
+			mockSubtaskCtx.On("GetData").Return(nil)
+			mockSubtaskCtx.On("GetLogger").Return(unithelper.DummyLogger())
+			mockSubtaskCtx.On("SetProgress", mock.Anything, mock.Anything)
+			mockSubtaskCtx.On("GetContext").Return(context.Background())
+
+			extractor, err := NewStatefulApiExtractor(&StatefulApiExtractorArgs[any]{
+				SubtaskCommonArgs: &SubtaskCommonArgs{
+					SubTaskContext: mockSubtaskCtx,
+					Table:          "test_table",
+					Params:         "whatever",
+					SubtaskConfig:  tc.config,
+				},
+				Extract: func(body *any, row *RawData) ([]interface{}, errors.Error) {
+					return nil, nil
+				},
+			})
+			assert.Nil(t, err)
+			assert.Nil(t, extractor.Execute())
+			// the state is kept as is for the extraction following the full collection
+			assert.Equal(t, tc.expectedFullCollection, extractor.state.PrevStartedAt.Equal(time2))
+		})
+	}
+}
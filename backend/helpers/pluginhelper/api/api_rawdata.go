@@ -23,7 +23,9 @@ import (
 	"reflect"
 	"time"
 
+	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
 	plugin "github.com/apache/incubator-devlake/core/plugin"
 )
 
@@ -63,6 +65,24 @@ type RawDataSubTask struct {
 	params string
 }
 
+// flushRawData deletes the raw rows of the params before a full collection, the purge watermark of the stateful
+// extractors reading them is lifted as well since the rows are collected all over again
+func flushRawData(db dal.Dal, table, params string) errors.Error {
+	err := db.Delete(&RawData{}, dal.From(table), dal.Where("params = ?", params))
+	if err != nil {
+		return errors.Default.Wrap(err, "error deleting data from collector")
+	}
+	err = db.UpdateColumn(
+		&models.SubtaskState{},
+		"purged_before", nil,
+		dal.Where("extracted_table = ? AND params = ? AND purged_before IS NOT NULL", table, params),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "error lifting the purge watermark")
+	}
+	return nil
+}
+
 // NewRawDataSubTask constructor for RawDataSubTask
 func NewRawDataSubTask(args RawDataSubTaskArgs) (*RawDataSubTask, errors.Error) {
 	if args.Ctx == nil {
@@ -27,6 +27,8 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
 )
 
 // BatchSave performs multiple records persistence of a specific type in one sql query to improve the performance
@@ -45,6 +47,7 @@ type BatchSave struct {
 	tableName  string
 	mutex      sync.Mutex
 	lastErr    errors.Error
+	counters   *plugin.SubtaskCounters
 }
 
 // NewBatchSave creates a new BatchSave instance
@@ -74,6 +77,7 @@ func NewBatchSave(basicRes context.BasicRes, slotType reflect.Type, size int, ta
 		valueIndex: make(map[string]int),
 		primaryKey: primaryKey,
 		tableName:  tn,
+		counters:   plugin.GetSubtaskCountersOf(basicRes),
 	}, nil
 }
 
@@ -134,6 +138,8 @@ func (c *BatchSave) flushWithoutLocking() errors.Error {
 		return err
 	}
 	c.log.Debug("batch save flush total %d records to database", c.current)
+	c.counters.AddRecordsWritten(c.current)
+	metricshelper.BatchSaveRows.WithLabelValues(c.tableName).Add(float64(c.current))
 	c.current = 0
 	c.valueIndex = make(map[string]int)
 	return nil
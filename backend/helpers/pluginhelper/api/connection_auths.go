@@ -32,7 +32,7 @@ import (
 // BasicAuth implements HTTP Basic Authentication
 type BasicAuth struct {
 	Username string `mapstructure:"username" validate:"required" json:"username"`
-	Password string `mapstructure:"password" validate:"required" json:"password" gorm:"serializer:encdec"`
+	Password string `mapstructure:"password" validate:"required" json:"password" gorm:"serializer:encdec" encrypt:"yes"`
 }
 
 // GetEncodedToken returns encoded bearer token for HTTP Basic Authentication
@@ -56,7 +56,7 @@ func (ba *BasicAuth) GetBasicAuthenticator() plugin.ApiAuthenticator {
 
 // AccessToken implements HTTP Bearer Authentication with Access Token
 type AccessToken struct {
-	Token string `mapstructure:"token" validate:"required" json:"token" gorm:"serializer:encdec"`
+	Token string `mapstructure:"token" validate:"required" json:"token" gorm:"serializer:encdec" encrypt:"yes"`
 }
 
 // SetupAuthentication sets up the request headers for authentication
@@ -73,7 +73,7 @@ func (at *AccessToken) GetAccessTokenAuthenticator() plugin.ApiAuthenticator {
 // AppKey implements the API Key and Secret authentication mechanism
 type AppKey struct {
 	AppId     string `mapstructure:"appId" validate:"required" json:"appId"`
-	SecretKey string `mapstructure:"secretKey" validate:"required" json:"secretKey" gorm:"serializer:encdec"`
+	SecretKey string `mapstructure:"secretKey" validate:"required" json:"secretKey" gorm:"serializer:encdec" encrypt:"yes"`
 }
 
 // SetupAuthentication sets up the request headers for authentication
@@ -31,6 +31,8 @@ import (
 	"github.com/apache/incubator-devlake/core/log"
 	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/audithelper"
+	"github.com/apache/incubator-devlake/helpers/dbhelper"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -38,10 +40,12 @@ import (
 type ConnectionApiHelper struct {
 	encryptionSecret string
 	log              log.Logger
+	basicRes         context.BasicRes
 	db               dal.Dal
 	validator        *validator.Validate
 	bpManager        *services.BlueprintManager
 	pluginName       string
+	auditHelper      *audithelper.AuditHelper
 }
 
 // NewConnectionHelper creates a ConnectionHelper for connection management
@@ -54,36 +58,39 @@ func NewConnectionHelper(
 		vld = validator.New()
 	}
 	return &ConnectionApiHelper{
+		basicRes:         basicRes,
 		encryptionSecret: basicRes.GetConfig(plugin.EncodeKeyEnvStr),
 		log:              basicRes.GetLogger(),
 		db:               basicRes.GetDal(),
 		validator:        vld,
 		bpManager:        services.NewBlueprintManager(basicRes.GetDal()),
 		pluginName:       pluginName,
+		auditHelper:      audithelper.NewAuditHelper(basicRes),
 	}
 }
 
 // Create a connection record based on request body
 func (c *ConnectionApiHelper) Create(connection interface{}, input *plugin.ApiResourceInput) errors.Error {
-	return c.CreateWithTx(nil, connection, input)
+	return c.inTx(func(tx dal.Transaction) errors.Error {
+		return c.CreateWithTx(tx, connection, input)
+	})
 }
 
-// Create a connection record based on request body
+// Create a connection record based on request body, the connection and its audit log are saved in the transaction
 func (c *ConnectionApiHelper) CreateWithTx(tx dal.Transaction, connection interface{}, input *plugin.ApiResourceInput) errors.Error {
-	// update fields from request body
-	db := c.db
-	if tx != nil {
-		db = tx
+	if tx == nil {
+		return c.Create(connection, input)
 	}
+	// update fields from request body
 	err := c.merge(connection, input.Body)
 	if err != nil {
 		return err
 	}
-	if err := c.save(connection, db.Create); err != nil {
+	if err := c.save(connection, tx.Create); err != nil {
 		c.log.Error(err, "create connection")
 		return err
 	}
-	return nil
+	return c.recordAudit(tx, input, models.AUDIT_ACTION_CREATE, connection, nil, audithelper.Sanitize(connection))
 }
 
 // Patch (Modify) a connection record based on request body
@@ -92,11 +99,7 @@ func (c *ConnectionApiHelper) Patch(connection interface{}, input *plugin.ApiRes
 	if err != nil {
 		return err
 	}
-	err = c.merge(connection, input.Body)
-	if err != nil {
-		return err
-	}
-	return c.save(connection, c.db.CreateOrUpdate)
+	return c.patch(connection, input)
 }
 
 // PatchByName (Modify) a connection record based on request body by connection name
@@ -105,11 +108,51 @@ func (c *ConnectionApiHelper) PatchByName(connection interface{}, input *plugin.
 	if err != nil {
 		return err
 	}
+	return c.patch(connection, input)
+}
+
+func (c *ConnectionApiHelper) patch(connection interface{}, input *plugin.ApiResourceInput) errors.Error {
+	before, err := audithelper.Snapshot(audithelper.Sanitize(connection))
+	if err != nil {
+		return err
+	}
 	err = c.merge(connection, input.Body)
 	if err != nil {
 		return err
 	}
-	return c.save(connection, c.db.CreateOrUpdate)
+	return c.inTx(func(tx dal.Transaction) errors.Error {
+		err := c.save(connection, tx.CreateOrUpdate)
+		if err != nil {
+			return err
+		}
+		return c.recordAudit(tx, input, models.AUDIT_ACTION_UPDATE, connection, before, audithelper.Sanitize(connection))
+	})
+}
+
+// inTx runs fn in a transaction, so the change of the connection and its audit log are either both saved or both
+// discarded
+func (c *ConnectionApiHelper) inTx(fn func(tx dal.Transaction) errors.Error) (err errors.Error) {
+	txHelper := dbhelper.NewTxHelper(c.basicRes, &err)
+	defer txHelper.End()
+	tx := txHelper.Begin()
+	err = fn(tx)
+	return
+}
+
+// recordAudit writes an audit log for the change of the connection, before and after must be sanitized
+func (c *ConnectionApiHelper) recordAudit(tx dal.Dal, input *plugin.ApiResourceInput, action string, connection, before, after interface{}) errors.Error {
+	return c.auditHelper.Record(
+		tx,
+		input.User,
+		action,
+		audithelper.AuditTarget{
+			Type:   models.AUDIT_TARGET_CONNECTION,
+			Plugin: c.pluginName,
+			Id:     fmt.Sprintf("%d", reflectField(connection, "ID").Uint()),
+		},
+		before,
+		after,
+	)
 }
 
 // First finds connection from db by id, parsing request input and decrypt it
@@ -147,8 +190,8 @@ func (c *ConnectionApiHelper) List(connections interface{}) errors.Error {
 	return CallDB(c.db.All, connections)
 }
 
-// Delete connection.
-func (c *ConnectionApiHelper) deleteConnection(connection interface{}) (*services.BlueprintProjectPairs, errors.Error) {
+// Delete connection in the transaction.
+func (c *ConnectionApiHelper) deleteConnection(tx dal.Transaction, connection interface{}) (*services.BlueprintProjectPairs, errors.Error) {
 	connectionId := reflectField(connection, "ID").Uint()
 	referencingBps := c.bpManager.GetBlueprintsByConnection(c.pluginName, connectionId)
 	if len(referencingBps) > 0 {
@@ -160,19 +203,19 @@ func (c *ConnectionApiHelper) deleteConnection(connection interface{}) (*service
 	}
 	if scopeModel := src.Scope(); scopeModel != nil {
 		// ensure the connection has no scopes using it
-		count := errors.Must1(c.db.Count(dal.From(scopeModel.TableName()), dal.Where("connection_id = ?", connectionId)))
+		count := errors.Must1(tx.Count(dal.From(scopeModel.TableName()), dal.Where("connection_id = ?", connectionId)))
 		if count > 0 {
 			return nil, errors.Conflict.New("Please delete all data scope(s) before you delete this Data Connection.")
 		}
 	}
 	if scopeConfigModel := src.ScopeConfig(); scopeConfigModel != nil {
 		// remove scope-configs that use this connection
-		err = CallDB(c.db.Delete, scopeConfigModel, dal.Where("connection_id = ?", connectionId))
+		err = CallDB(tx.Delete, scopeConfigModel, dal.Where("connection_id = ?", connectionId))
 		if err != nil {
 			return nil, errors.Default.Wrap(err, fmt.Sprintf("error deleting scope-configs for plugin %s using connection %d", c.pluginName, connectionId))
 		}
 	}
-	return nil, CallDB(c.db.Delete, connection)
+	return nil, CallDB(tx.Delete, connection)
 }
 
 // TODO: combine connection/scope/scopeConfig helper
@@ -182,7 +225,15 @@ func (c *ConnectionApiHelper) Delete(connection interface{}, input *plugin.ApiRe
 		return nil, err
 	}
 	var refs *services.BlueprintProjectPairs
-	refs, err = c.deleteConnection(connection)
+	// the connection and its audit log are deleted and written in the same transaction
+	err = c.inTx(func(tx dal.Transaction) errors.Error {
+		var err errors.Error
+		refs, err = c.deleteConnection(tx, connection)
+		if err != nil {
+			return err
+		}
+		return c.recordAudit(tx, input, models.AUDIT_ACTION_DELETE, connection, audithelper.Sanitize(connection), nil)
+	})
 	if err != nil {
 		return &plugin.ApiResourceOutput{Body: &shared.ApiBody{
 			Success: false,
@@ -115,6 +115,8 @@ func NewStatefulDataConverter[
 	if err != nil {
 		return nil, err
 	}
+	// tells the raw data retention policies which raw rows were converted
+	stateManager.state.ConvertedTable = args.GetRawDataTable()
 	return &StatefulDataConverter[InputType]{
 		StatefulDataConverterArgs: args,
 		SubtaskStateManager:       stateManager,
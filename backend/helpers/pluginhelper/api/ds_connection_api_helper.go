@@ -23,7 +23,9 @@ import (
 	"github.com/apache/incubator-devlake/server/api/shared"
 
 	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/srvhelper"
 )
@@ -42,8 +44,10 @@ func NewDsConnectionApiHelper[
 	connSrvHelper *srvhelper.ConnectionSrvHelper[C, S, SC],
 	sterilizer func(c C) C,
 ) *DsConnectionApiHelper[C, S, SC] {
+	modelApiHelper := NewModelApiHelper[C](basicRes, connSrvHelper.ModelSrvHelper, []string{"connectionId"}, sterilizer)
+	modelApiHelper.auditTarget = models.AUDIT_TARGET_CONNECTION
 	return &DsConnectionApiHelper[C, S, SC]{
-		ModelApiHelper:      NewModelApiHelper[C](basicRes, connSrvHelper.ModelSrvHelper, []string{"connectionId"}, sterilizer),
+		ModelApiHelper:      modelApiHelper,
 		ConnectionSrvHelper: connSrvHelper,
 	}
 }
@@ -67,7 +71,9 @@ func (connApi *DsConnectionApiHelper[C, S, SC]) Delete(input *plugin.ApiResource
 	if err != nil {
 		return nil, err
 	}
-	refs, err := connApi.ConnectionSrvHelper.DeleteConnection(conn)
+	refs, err := connApi.ConnectionSrvHelper.DeleteConnection(conn, func(tx dal.Transaction) errors.Error {
+		return connApi.recordAudit(tx, input, models.AUDIT_ACTION_DELETE, connApi.Sanitize(conn), nil)
+	})
 	if err != nil {
 		return &plugin.ApiResourceOutput{Body: &shared.ApiBody{
 			Success: false,
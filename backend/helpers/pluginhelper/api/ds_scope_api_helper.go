@@ -21,7 +21,9 @@ import (
 	"fmt"
 
 	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/models/common"
 	"github.com/apache/incubator-devlake/core/plugin"
 	serviceHelper "github.com/apache/incubator-devlake/helpers/pluginhelper/services"
@@ -50,8 +52,10 @@ func NewDsScopeApiHelper[
 	srvHelper *srvhelper.ScopeSrvHelper[C, S, SC],
 	sterilizer func(s S) S,
 ) *DsScopeApiHelper[C, S, SC] {
+	modelApiHelper := NewModelApiHelper[S](basicRes, srvHelper.ModelSrvHelper, []string{"connectionId", "scopeId"}, sterilizer)
+	modelApiHelper.auditTarget = models.AUDIT_TARGET_SCOPE
 	return &DsScopeApiHelper[C, S, SC]{
-		ModelApiHelper: NewModelApiHelper[S](basicRes, srvHelper.ModelSrvHelper, []string{"connectionId", "scopeId"}, sterilizer),
+		ModelApiHelper: modelApiHelper,
 		ScopeSrvHelper: srvHelper,
 	}
 }
@@ -138,7 +142,14 @@ func (scopeApi *DsScopeApiHelper[C, S, SC]) Delete(input *plugin.ApiResourceInpu
 	}
 	// time.Sleep(1 * time.Minute) # uncomment this line if you were to verify pipelines get blocked while deleting data
 	// check referencing blueprints
-	refs, err := scopeApi.ScopeSrvHelper.DeleteScope(scope, input.Query.Get("delete_data_only") == "true")
+	dataOnly := input.Query.Get("delete_data_only") == "true"
+	action := models.AUDIT_ACTION_DELETE
+	if dataOnly {
+		action = models.AUDIT_ACTION_DELETE_DATA
+	}
+	refs, err := scopeApi.ScopeSrvHelper.DeleteScope(scope, dataOnly, func(tx dal.Transaction) errors.Error {
+		return scopeApi.recordAudit(tx, input, action, scopeApi.Sanitize(scope), nil)
+	})
 	if err != nil {
 		return &plugin.ApiResourceOutput{Body: &shared.ApiBody{
 			Success: false,
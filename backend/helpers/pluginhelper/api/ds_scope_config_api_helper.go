@@ -19,7 +19,9 @@ package api
 
 import (
 	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/srvhelper"
 	"github.com/apache/incubator-devlake/server/api/shared"
@@ -40,8 +42,10 @@ func NewDsScopeConfigApiHelper[
 	dalHelper *srvhelper.ScopeConfigSrvHelper[C, S, SC],
 	sterilizer func(sc SC) SC,
 ) *DsScopeConfigApiHelper[C, S, SC] {
+	modelApiHelper := NewModelApiHelper[SC](basicRes, dalHelper.ModelSrvHelper, []string{"scopeConfigId"}, sterilizer)
+	modelApiHelper.auditTarget = models.AUDIT_TARGET_SCOPE_CONFIG
 	return &DsScopeConfigApiHelper[C, S, SC]{
-		ModelApiHelper:       NewModelApiHelper[SC](basicRes, dalHelper.ModelSrvHelper, []string{"scopeConfigId"}, sterilizer),
+		ModelApiHelper:       modelApiHelper,
 		ScopeConfigSrvHelper: dalHelper,
 	}
 }
@@ -95,7 +99,9 @@ func (connApi *DsScopeConfigApiHelper[C, S, SC]) Delete(input *plugin.ApiResourc
 	if err != nil {
 		return nil, err
 	}
-	refs, err := connApi.ScopeConfigSrvHelper.DeleteScopeConfig(scopeConfig)
+	refs, err := connApi.ScopeConfigSrvHelper.DeleteScopeConfig(scopeConfig, func(tx dal.Transaction) errors.Error {
+		return connApi.recordAudit(tx, input, models.AUDIT_ACTION_DELETE, connApi.Sanitize(scopeConfig), nil)
+	})
 	if err != nil {
 		return &plugin.ApiResourceOutput{Body: &shared.ApiBody{
 			Success: false,
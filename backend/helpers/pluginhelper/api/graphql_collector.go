@@ -141,9 +141,9 @@ func (collector *GraphqlCollector) Execute() errors.Error {
 	}
 	// flush data if not incremental collection
 	if !collector.args.Incremental {
-		err = db.Delete(&RawData{}, dal.From(collector.table), dal.Where("params = ?", collector.params))
+		err = flushRawData(db, collector.table, collector.params)
 		if err != nil {
-			return errors.Default.Wrap(err, "error deleting data from collector")
+			return err
 		}
 	}
 
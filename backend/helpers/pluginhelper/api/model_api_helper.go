@@ -25,7 +25,10 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/audithelper"
+	"github.com/apache/incubator-devlake/helpers/dbhelper"
 	"github.com/apache/incubator-devlake/helpers/srvhelper"
 	"github.com/apache/incubator-devlake/helpers/utils"
 	"github.com/go-playground/validator/v10"
@@ -40,6 +43,8 @@ type ModelApiHelper[M dal.Tabler] struct {
 	modelName      string
 	pkPathVarNames []string
 	sterilizers    []func(m M) M
+	auditHelper    *audithelper.AuditHelper
+	auditTarget    string // type of the audit target, changes are not audited if empty
 }
 
 func NewModelApiHelper[M dal.Tabler](
@@ -56,6 +61,7 @@ func NewModelApiHelper[M dal.Tabler](
 		log:            basicRes.GetLogger().Nested(fmt.Sprintf("%s_dal", modelName)),
 		modelName:      modelName,
 		pkPathVarNames: pkPathVarNames,
+		auditHelper:    audithelper.NewAuditHelper(basicRes),
 	}
 	if sterilizer != nil {
 		modelApiHelper.sterilizers = []func(m M) M{sterilizer}
@@ -69,11 +75,17 @@ func (self *ModelApiHelper[M]) Post(input *plugin.ApiResourceInput) (*plugin.Api
 	if err != nil {
 		return nil, err
 	}
-	err = self.dalHelper.Create(model)
+	err = self.inTx(func(tx dal.Transaction) errors.Error {
+		err := self.dalHelper.NewTx(tx).Create(model)
+		if err != nil {
+			return err
+		}
+		model = self.Sanitize(model)
+		return self.recordAudit(tx, input, models.AUDIT_ACTION_CREATE, nil, model)
+	})
 	if err != nil {
 		return nil, err
 	}
-	model = self.Sanitize(model)
 	return &plugin.ApiResourceOutput{
 		Status: http.StatusCreated,
 		Body:   model,
@@ -154,14 +166,24 @@ func (self *ModelApiHelper[M]) PatchModel(input *plugin.ApiResourceInput, zeroFi
 }
 
 func (self *ModelApiHelper[M]) Patch(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
-	model, err := self.PatchModel(input, true)
+	before, err := self.FindByPk(input)
 	if err != nil {
-		return nil, errors.Convert(err)
+		return nil, err
 	}
-	if err := self.dalHelper.Update(model); err != nil {
+	model, e := self.PatchModel(input, true)
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	err = self.inTx(func(tx dal.Transaction) errors.Error {
+		if err := self.dalHelper.NewTx(tx).Update(model); err != nil {
+			return err
+		}
+		model = self.Sanitize(model)
+		return self.recordAudit(tx, input, models.AUDIT_ACTION_UPDATE, self.Sanitize(before), model)
+	})
+	if err != nil {
 		return nil, err
 	}
-	model = self.Sanitize(model)
 	return &plugin.ApiResourceOutput{
 		Body: model,
 	}, nil
@@ -172,11 +194,17 @@ func (self *ModelApiHelper[M]) Delete(input *plugin.ApiResourceInput) (*plugin.A
 	if err != nil {
 		return nil, err
 	}
-	err = self.dalHelper.DeleteModel(model)
+	err = self.inTx(func(tx dal.Transaction) errors.Error {
+		err := self.dalHelper.NewTx(tx).DeleteModel(model)
+		if err != nil {
+			return err
+		}
+		model = self.Sanitize(model)
+		return self.recordAudit(tx, input, models.AUDIT_ACTION_DELETE, model, nil)
+	})
 	if err != nil {
 		return nil, err
 	}
-	model = self.Sanitize(model)
 	return &plugin.ApiResourceOutput{
 		Body: model,
 	}, nil
@@ -198,17 +226,33 @@ func (self *ModelApiHelper[M]) PutMultipleCb(input *plugin.ApiResourceInput, bef
 	if err != nil {
 		return nil, err
 	}
-	for i, item := range req.Data {
-		if beforeSave != nil {
-			err := beforeSave(item)
+	// the items are saved along with their audit logs in a single transaction
+	err = self.inTx(func(tx dal.Transaction) errors.Error {
+		dalHelper := self.dalHelper.NewTx(tx)
+		for i, item := range req.Data {
+			if beforeSave != nil {
+				err := beforeSave(item)
+				if err != nil {
+					return err
+				}
+			}
+			action, before, err := self.findExisting(tx, item)
 			if err != nil {
-				return nil, err
+				return err
+			}
+			err = dalHelper.CreateOrUpdate(item)
+			if err != nil {
+				return errors.BadInput.Wrap(err, fmt.Sprintf("failed to save item %d", i))
+			}
+			err = self.recordAudit(tx, input, action, before, self.Sanitize(item))
+			if err != nil {
+				return err
 			}
 		}
-		err := self.dalHelper.CreateOrUpdate(item)
-		if err != nil {
-			return nil, errors.BadInput.Wrap(err, fmt.Sprintf("failed to save item %d", i))
-		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	req.Data = self.BatchSanitize(req.Data)
 	return &plugin.ApiResourceOutput{
@@ -216,6 +260,57 @@ func (self *ModelApiHelper[M]) PutMultipleCb(input *plugin.ApiResourceInput, bef
 	}, nil
 }
 
+// findExisting loads the record sharing the primary key with the item for auditing, gorm uses the primary key of the
+// destination as the condition
+func (self *ModelApiHelper[M]) findExisting(db dal.Dal, item *M) (string, *M, errors.Error) {
+	if self.auditTarget == "" {
+		return "", nil, nil
+	}
+	existing := new(M)
+	*existing = *item
+	err := db.First(existing)
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return models.AUDIT_ACTION_CREATE, nil, nil
+		}
+		return "", nil, err
+	}
+	return models.AUDIT_ACTION_UPDATE, self.Sanitize(existing), nil
+}
+
+// inTx runs fn in a transaction, so the change and its audit log are either both saved or both discarded
+func (self *ModelApiHelper[M]) inTx(fn func(tx dal.Transaction) errors.Error) (err errors.Error) {
+	txHelper := dbhelper.NewTxHelper(self.basicRes, &err)
+	defer txHelper.End()
+	tx := txHelper.Begin()
+	err = fn(tx)
+	return
+}
+
+// recordAudit writes an audit log for the change made through the api in the transaction of the change, before and
+// after must be sanitized
+func (self *ModelApiHelper[M]) recordAudit(tx dal.Dal, input *plugin.ApiResourceInput, action string, before, after *M) errors.Error {
+	if self.auditTarget == "" {
+		return nil
+	}
+	target := after
+	if target == nil {
+		target = before
+	}
+	return self.auditHelper.Record(
+		tx,
+		input.User,
+		action,
+		audithelper.AuditTarget{
+			Type:   self.auditTarget,
+			Plugin: input.Params["plugin"],
+			Id:     audithelper.TargetId(target),
+		},
+		before,
+		after,
+	)
+}
+
 func parsePagination[P any](input *plugin.ApiResourceInput) (*P, errors.Error) {
 	if !input.Query.Has("page") {
 		input.Query.Set("page", "1")
@@ -0,0 +1,100 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/srvhelper"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	mockcontext "github.com/apache/incubator-devlake/mocks/core/context"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type auditedModel struct {
+	ID   uint64 `gorm:"primaryKey" json:"id"`
+	Name string `json:"name"`
+}
+
+func (auditedModel) TableName() string {
+	return "_tool_audited_models"
+}
+
+func mockAuditedModelApiHelper(t *testing.T) (*ModelApiHelper[auditedModel], *mockdal.Dal, *mockdal.Transaction) {
+	mockDal := mockdal.NewDal(t)
+	mockTx := mockdal.NewTransaction(t)
+	mockRes := new(mockcontext.BasicRes)
+	mockRes.On("GetDal").Return(mockDal)
+	mockRes.On("GetLogger").Return(unithelper.DummyLogger())
+	idColumn := mockdal.NewColumnMeta(t)
+	idColumn.On("Name").Return("id")
+	mockDal.On("GetColumns", mock.Anything, mock.Anything).Return([]dal.ColumnMeta{idColumn}, nil)
+	mockDal.On("Begin").Return(mockTx)
+	mockTx.On("UnlockTables").Return(nil)
+
+	helper := NewModelApiHelper[auditedModel](mockRes, srvhelper.NewModelSrvHelper[auditedModel](mockRes, nil), []string{"id"}, nil)
+	helper.auditTarget = models.AUDIT_TARGET_SCOPE
+	return helper, mockDal, mockTx
+}
+
+func TestModelApiHelperPostRecordsAuditInTransaction(t *testing.T) {
+	helper, _, mockTx := mockAuditedModelApiHelper(t)
+	mockTx.On("Create", mock.AnythingOfType("*api.auditedModel"), mock.Anything).Return(nil).Once()
+	mockTx.On("Create", mock.AnythingOfType("*models.AuditLog"), mock.Anything).Return(nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+
+	output, err := helper.Post(&plugin.ApiResourceInput{Body: map[string]interface{}{"id": 1, "name": "foo"}})
+	assert.Nil(t, err)
+	assert.Equal(t, &auditedModel{ID: 1, Name: "foo"}, output.Body)
+}
+
+func TestModelApiHelperPostRollsBackWhenAuditFails(t *testing.T) {
+	helper, _, mockTx := mockAuditedModelApiHelper(t)
+	mockTx.On("Create", mock.AnythingOfType("*api.auditedModel"), mock.Anything).Return(nil).Once()
+	mockTx.On("Create", mock.AnythingOfType("*models.AuditLog"), mock.Anything).Return(errors.Default.New("db error")).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+
+	_, err := helper.Post(&plugin.ApiResourceInput{Body: map[string]interface{}{"id": 1, "name": "foo"}})
+	assert.NotNil(t, err)
+}
+
+func TestModelApiHelperDeleteRecordsAuditInTransaction(t *testing.T) {
+	helper, mockDal, mockTx := mockAuditedModelApiHelper(t)
+	mockDal.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*auditedModel) = auditedModel{ID: 1, Name: "foo"}
+	}).Return(nil).Once()
+	mockTx.On("Delete", mock.AnythingOfType("*api.auditedModel"), mock.Anything).Return(nil).Once()
+	var auditLog *models.AuditLog
+	mockTx.On("Create", mock.AnythingOfType("*models.AuditLog"), mock.Anything).Run(func(args mock.Arguments) {
+		auditLog = args.Get(0).(*models.AuditLog)
+	}).Return(nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+
+	_, err := helper.Delete(&plugin.ApiResourceInput{Params: map[string]string{"id": "1"}})
+	assert.Nil(t, err)
+	if assert.NotNil(t, auditLog) {
+		assert.Equal(t, models.AUDIT_ACTION_DELETE, auditLog.Action)
+		assert.Equal(t, models.AUDIT_TARGET_SCOPE, auditLog.TargetType)
+	}
+}
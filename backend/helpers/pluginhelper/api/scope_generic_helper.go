@@ -34,6 +34,7 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/dbhelper"
 	serviceHelper "github.com/apache/incubator-devlake/helpers/pluginhelper/services"
+	"github.com/apache/incubator-devlake/helpers/srvhelper"
 	"github.com/go-playground/validator/v10"
 )
 
@@ -322,6 +323,16 @@ func (gs *GenericScopeApiHelper[Conn, Scope, ScopeConfig]) DeleteScope(input *pl
 			return refs, errors.Conflict.New("Found one or more references to this scope")
 		}
 	}
+	var repoIds []string
+	err = gs.db.Pluck(
+		"id",
+		&repoIds,
+		dal.From("repos"),
+		dal.Where("_raw_data_table LIKE ? AND _raw_data_params = ?", fmt.Sprintf("_raw_%s%%", gs.plugin), plugin.MarshalScopeParams((*scope).ScopeParams())),
+	)
+	if err != nil {
+		return nil, err
+	}
 	if err = gs.deleteScopeData(*scope); err != nil {
 		return nil, err
 	}
@@ -329,6 +340,7 @@ func (gs *GenericScopeApiHelper[Conn, Scope, ScopeConfig]) DeleteScope(input *pl
 		// Delete the scope itself
 		errors.Must(gs.dbHelper.DeleteScope(scope))
 	}
+	srvhelper.DeleteReposData(gs.basicRes, repoIds)
 	return nil, nil
 }
 
@@ -18,6 +18,7 @@ limitations under the License.
 package api
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"time"
@@ -105,6 +106,10 @@ func NewSubtaskStateManager(args *SubtaskCommonArgs) (stateManager *SubtaskState
 	if stateManager.since == nil {
 		stateManager.since = preState.TimeAfter
 	}
+	// the progress of the previous run is meaningless in full sync mode
+	if !isIncremental {
+		preState.Checkpoint = ""
+	}
 	return
 }
 
@@ -179,6 +184,19 @@ func (c *SubtaskStateManager) GetUntil() *time.Time {
 	return c.until
 }
 
+// GetCheckpoint decodes the checkpoint saved by the previous run into v, v is left untouched if there is none
+func (c *SubtaskStateManager) GetCheckpoint(v any) errors.Error {
+	if c.state.Checkpoint == "" {
+		return nil
+	}
+	return errors.Convert(json.Unmarshal([]byte(c.state.Checkpoint), v))
+}
+
+// SetCheckpoint records the progress of the subtask, it is saved along with the state by Close
+func (c *SubtaskStateManager) SetCheckpoint(v any) {
+	c.state.Checkpoint = utils.ToJsonString(v)
+}
+
 func (c *SubtaskStateManager) Close() errors.Error {
 	// update timeAfter in the database only for fullsync mode
 	if !c.isIncremental {
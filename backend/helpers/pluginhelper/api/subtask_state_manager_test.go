@@ -187,3 +187,53 @@ func TestSubtaskStateManager(t *testing.T) {
 		})
 	}
 }
+
+func TestSubtaskStateManagerCheckpoint(t *testing.T) {
+	time1 := errors.Must1(time.Parse(time.RFC3339, "2021-01-01T00:00:00Z"))
+	for _, tc := range []struct {
+		name               string
+		syncPolicy         *models.SyncPolicy
+		expectedCheckpoint map[string]string
+	}{
+		{
+			name:               "Incremental - checkpoint kept",
+			syncPolicy:         &models.SyncPolicy{},
+			expectedCheckpoint: map[string]string{"refs/heads/main": "abc"},
+		},
+		{
+			name:               "Full sync - checkpoint discarded",
+			syncPolicy:         &models.SyncPolicy{TriggerSyncPolicy: models.TriggerSyncPolicy{FullSync: true}},
+			expectedCheckpoint: map[string]string{},
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			mockDal := new(mockdal.Dal)
+			mockDal.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				dst := args.Get(0).(*models.SubtaskState)
+				*dst = models.SubtaskState{PrevStartedAt: &time1, Checkpoint: `{"refs/heads/main":"abc"}`}
+			}).Return(nil).Once()
+			mockDal.On("CreateOrUpdate", mock.Anything, mock.Anything).Return(nil).Once()
+			mockTaskCtx := new(mockplugin.TaskContext)
+			mockTaskCtx.On("SyncPolicy").Return(tc.syncPolicy)
+			mockTaskCtx.On("GetName").Return("test-plugin")
+			mockSubtaskCtx := new(mockplugin.SubTaskContext)
+			mockSubtaskCtx.On("TaskContext").Return(mockTaskCtx)
+			mockSubtaskCtx.On("GetName").Return("test-subtask")
+			mockSubtaskCtx.On("GetDal").Return(mockDal)
+
+			stateManager, err := NewSubtaskStateManager(&SubtaskCommonArgs{
+				SubTaskContext: mockSubtaskCtx,
+				Params:         "whatever",
+			})
+			assert.Nil(t, err)
+			checkpoint := map[string]string{}
+			assert.Nil(t, stateManager.GetCheckpoint(&checkpoint))
+			assert.Equal(t, tc.expectedCheckpoint, checkpoint)
+
+			stateManager.SetCheckpoint(map[string]string{"refs/heads/main": "def"})
+			assert.Nil(t, stateManager.Close())
+			assert.Equal(t, `{"refs/heads/main":"def"}`, stateManager.state.Checkpoint)
+			mockDal.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,224 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/core/utils"
+)
+
+// RAW_DATA_IDS_OPTION is the task option limiting the extractors to some rows of the raw tables, it is set on the tasks
+// enqueued for webhook events so only the rows saved from the events are extracted instead of the whole scope
+const RAW_DATA_IDS_OPTION = "rawDataIds"
+
+// RawDataFilter is implemented by the task data of the plugins accepting the RAW_DATA_IDS_OPTION, it returns the ids of
+// the rows to be extracted from the raw table, and false if the extraction is not limited
+type RawDataFilter interface {
+	GetRawDataIds(table string) ([]uint64, bool)
+}
+
+func getRawDataIdsFilter(ctx plugin.SubTaskContext, table string) ([]uint64, bool) {
+	filter, ok := ctx.GetData().(RawDataFilter)
+	if !ok {
+		return nil, false
+	}
+	return filter.GetRawDataIds(table)
+}
+
+// RawEvent is a webhook event payload to be stored into a raw table, Table and Params must be the same as
+// the ones used by the collector of the entity so the payload is picked up by the extractor
+type RawEvent struct {
+	Table  string
+	Params any
+	Data   json.RawMessage
+	Url    string
+	Input  any
+}
+
+// WebhookRefresh describes the subtasks to run for a scope affected by webhook events
+type WebhookRefresh struct {
+	// ScopeKey identifies the scope, the refreshes of the same scope are merged and never run in parallel
+	ScopeKey   string
+	Plugin     string
+	Options    map[string]interface{}
+	Subtasks   []string
+	RawDataIds map[string][]uint64
+}
+
+// WebhookEventHelper stores webhook event payloads as raw data and refreshes the affected scopes by enqueuing
+// pipelines running the extractors and convertors only for the saved rows. The events of the same scope arriving
+// within the batchDelay are merged into a single pipeline, and the pipelines of the same scope are labeled to be
+// run one after another by the pipeline runner.
+type WebhookEventHelper struct {
+	basicRes   context.BasicRes
+	batchDelay time.Duration
+	mu         sync.Mutex
+	pending    map[string]map[string]*WebhookRefresh
+}
+
+// NewWebhookEventHelper creates a WebhookEventHelper
+func NewWebhookEventHelper(basicRes context.BasicRes) *WebhookEventHelper {
+	return &WebhookEventHelper{
+		basicRes:   basicRes,
+		batchDelay: 10 * time.Second,
+		pending:    make(map[string]map[string]*WebhookRefresh),
+	}
+}
+
+// SaveRawEvents appends the event payloads to their raw tables, and returns the ids of the saved rows by raw table
+func (h *WebhookEventHelper) SaveRawEvents(events ...*RawEvent) (map[string][]uint64, errors.Error) {
+	db := h.basicRes.GetDal()
+	ids := make(map[string][]uint64)
+	for _, event := range events {
+		table := fmt.Sprintf("_raw_%s", event.Table)
+		err := db.AutoMigrate(&RawData{}, dal.From(table))
+		if err != nil {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to create raw table %s", table))
+		}
+		var input json.RawMessage
+		if event.Input != nil {
+			input, err = errors.Convert01(json.Marshal(event.Input))
+			if err != nil {
+				return nil, err
+			}
+		}
+		row := &RawData{
+			Params:    plugin.MarshalScopeParams(event.Params),
+			Data:      event.Data,
+			Url:       event.Url,
+			Input:     input,
+			CreatedAt: time.Now(),
+		}
+		err = db.Create(row, dal.From(table))
+		if err != nil {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("failed to save event into %s", table))
+		}
+		ids[table] = append(ids[table], row.ID)
+	}
+	return ids, nil
+}
+
+// RefreshScope schedules the refresh to be enqueued along with the other ones of the scope, it returns immediately
+func (h *WebhookEventHelper) RefreshScope(refresh *WebhookRefresh) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	refreshes := h.pending[refresh.ScopeKey]
+	if refreshes == nil {
+		refreshes = make(map[string]*WebhookRefresh)
+		h.pending[refresh.ScopeKey] = refreshes
+		go func() {
+			time.Sleep(h.batchDelay)
+			h.enqueue(refresh.ScopeKey)
+		}()
+	}
+	merged := refreshes[refresh.Plugin]
+	if merged == nil {
+		merged = &WebhookRefresh{
+			ScopeKey:   refresh.ScopeKey,
+			Plugin:     refresh.Plugin,
+			Options:    refresh.Options,
+			RawDataIds: make(map[string][]uint64),
+		}
+		refreshes[refresh.Plugin] = merged
+	}
+	for _, subtask := range refresh.Subtasks {
+		if !utils.StringsContains(merged.Subtasks, subtask) {
+			merged.Subtasks = append(merged.Subtasks, subtask)
+		}
+	}
+	for table, ids := range refresh.RawDataIds {
+		merged.RawDataIds[table] = append(merged.RawDataIds[table], ids...)
+	}
+}
+
+func (h *WebhookEventHelper) enqueue(scopeKey string) {
+	h.mu.Lock()
+	refreshes := h.pending[scopeKey]
+	delete(h.pending, scopeKey)
+	h.mu.Unlock()
+	_, err := plugin.EnqueuePipeline(newWebhookPipeline(scopeKey, refreshes))
+	if err != nil {
+		h.basicRes.GetLogger().Error(err, "failed to enqueue the pipeline for the webhook events of %s", scopeKey)
+	}
+}
+
+func newWebhookPipeline(scopeKey string, refreshes map[string]*WebhookRefresh) *models.NewPipeline {
+	plugins := make([]string, 0, len(refreshes))
+	for pluginName := range refreshes {
+		plugins = append(plugins, pluginName)
+	}
+	sort.Strings(plugins)
+	stage := make(models.PipelineStage, 0, len(plugins))
+	for _, pluginName := range plugins {
+		refresh := refreshes[pluginName]
+		options := make(map[string]interface{}, len(refresh.Options)+1)
+		for k, v := range refresh.Options {
+			options[k] = v
+		}
+		options[RAW_DATA_IDS_OPTION] = refresh.RawDataIds
+		stage = append(stage, &models.PipelineTask{
+			Plugin:   pluginName,
+			Subtasks: refresh.Subtasks,
+			Options:  options,
+		})
+	}
+	return &models.NewPipeline{
+		Name:   fmt.Sprintf("webhook events of %s", scopeKey),
+		Plan:   models.PipelinePlan{stage},
+		Labels: []string{fmt.Sprintf("parallel/webhook/%s", scopeKey)},
+	}
+}
+
+// VerifyHmacSha256Signature checks the hex encoded HMAC-SHA256 signature of the payload, an optional
+// algorithm prefix like `sha256=` is accepted
+func VerifyHmacSha256Signature(secret string, payload []byte, signature string) bool {
+	if secret == "" || signature == "" {
+		return false
+	}
+	signature = strings.TrimPrefix(signature, "sha256=")
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// VerifyWebhookToken compares a shared secret token sent along with the payload in constant time
+func VerifyWebhookToken(secret string, token string) bool {
+	if secret == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(secret), []byte(token)) == 1
+}
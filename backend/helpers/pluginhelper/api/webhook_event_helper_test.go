@@ -0,0 +1,114 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyHmacSha256Signature(t *testing.T) {
+	// example from https://docs.github.com/en/webhooks/using-webhooks/validating-webhook-deliveries
+	secret := "It's a Secret to Everybody"
+	payload := []byte("Hello, World!")
+	signature := "sha256=757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17"
+	assert.True(t, VerifyHmacSha256Signature(secret, payload, signature))
+	assert.True(t, VerifyHmacSha256Signature(secret, payload, signature[len("sha256="):]))
+	assert.False(t, VerifyHmacSha256Signature(secret, []byte("Hello, World?"), signature))
+	assert.False(t, VerifyHmacSha256Signature("another secret", payload, signature))
+	assert.False(t, VerifyHmacSha256Signature(secret, payload, "sha256=not-hex"))
+	assert.False(t, VerifyHmacSha256Signature("", payload, signature))
+	assert.False(t, VerifyHmacSha256Signature(secret, payload, ""))
+}
+
+func TestVerifyWebhookToken(t *testing.T) {
+	assert.True(t, VerifyWebhookToken("token", "token"))
+	assert.False(t, VerifyWebhookToken("token", "Token"))
+	assert.False(t, VerifyWebhookToken("token", ""))
+	assert.False(t, VerifyWebhookToken("", ""))
+}
+
+func TestWebhookEventHelperRefreshScope(t *testing.T) {
+	enqueued := make(chan *models.NewPipeline, 2)
+	plugin.RegisterPipelineEnqueuer(func(newPipeline *models.NewPipeline) (*models.Pipeline, errors.Error) {
+		enqueued <- newPipeline
+		return &models.Pipeline{}, nil
+	})
+	defer plugin.RegisterPipelineEnqueuer(nil)
+
+	helper := NewWebhookEventHelper(nil)
+	helper.batchDelay = 50 * time.Millisecond
+	options := map[string]interface{}{"connectionId": 1, "name": "apache/incubator-devlake"}
+	helper.RefreshScope(&WebhookRefresh{
+		ScopeKey:   "github:1:apache/incubator-devlake",
+		Plugin:     "github",
+		Options:    options,
+		Subtasks:   []string{"Extract Pull Requests", "Convert Pull Requests"},
+		RawDataIds: map[string][]uint64{"_raw_github_api_pull_requests": {1}},
+	})
+	helper.RefreshScope(&WebhookRefresh{
+		ScopeKey:   "github:1:apache/incubator-devlake",
+		Plugin:     "github",
+		Options:    options,
+		Subtasks:   []string{"Extract Pull Requests", "Extract Pull Request Reviews"},
+		RawDataIds: map[string][]uint64{"_raw_github_api_pull_requests": {2}, "_raw_github_api_pull_request_reviews": {3}},
+	})
+	helper.RefreshScope(&WebhookRefresh{
+		ScopeKey:   "github:1:apache/incubator-devlake",
+		Plugin:     "github_graphql",
+		Options:    options,
+		Subtasks:   []string{"Extract Deployments"},
+		RawDataIds: map[string][]uint64{"_raw_github_graphql_deployment": {4}},
+	})
+
+	var pipeline *models.NewPipeline
+	select {
+	case pipeline = <-enqueued:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the refreshes were not enqueued")
+	}
+	assert.Equal(t, "webhook events of github:1:apache/incubator-devlake", pipeline.Name)
+	assert.Equal(t, []string{"parallel/webhook/github:1:apache/incubator-devlake"}, pipeline.Labels)
+	if assert.Len(t, pipeline.Plan, 1) && assert.Len(t, pipeline.Plan[0], 2) {
+		github := pipeline.Plan[0][0]
+		assert.Equal(t, "github", github.Plugin)
+		assert.Equal(t, []string{"Extract Pull Requests", "Convert Pull Requests", "Extract Pull Request Reviews"}, github.Subtasks)
+		assert.Equal(t, "apache/incubator-devlake", github.Options["name"])
+		assert.Equal(t, map[string][]uint64{
+			"_raw_github_api_pull_requests":        {1, 2},
+			"_raw_github_api_pull_request_reviews": {3},
+		}, github.Options[RAW_DATA_IDS_OPTION])
+		graphql := pipeline.Plan[0][1]
+		assert.Equal(t, "github_graphql", graphql.Plugin)
+		assert.Equal(t, []string{"Extract Deployments"}, graphql.Subtasks)
+		assert.Equal(t, map[string][]uint64{"_raw_github_graphql_deployment": {4}}, graphql.Options[RAW_DATA_IDS_OPTION])
+	}
+	// the options of the callers are left untouched
+	assert.NotContains(t, options, RAW_DATA_IDS_OPTION)
+
+	select {
+	case <-enqueued:
+		t.Fatal("the refreshes of a scope must be merged into a single pipeline")
+	case <-time.After(200 * time.Millisecond):
+	}
+}
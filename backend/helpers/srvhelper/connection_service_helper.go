@@ -48,7 +48,9 @@ func NewConnectionSrvHelper[
 	}
 }
 
-func (connSrv *ConnectionSrvHelper[C, S, SC]) DeleteConnection(connection *C) (refs *DsRefs, err errors.Error) {
+// DeleteConnection deletes the connection along with its scope configs, onDeleted is optional and runs in the same
+// transaction, i.e. to write the audit log of the deletion
+func (connSrv *ConnectionSrvHelper[C, S, SC]) DeleteConnection(connection *C, onDeleted func(tx dal.Transaction) errors.Error) (refs *DsRefs, err errors.Error) {
 	err = connSrv.ModelSrvHelper.NoRunningPipeline(func(tx dal.Transaction) errors.Error {
 		// make sure no blueprint is using the connection
 		connectionId := (*connection).ConnectionId()
@@ -64,6 +66,9 @@ func (connSrv *ConnectionSrvHelper[C, S, SC]) DeleteConnection(connection *C) (r
 		if reflect.TypeOf(new(SC)) != reflect.TypeOf(new(NoScopeConfig)) {
 			errors.Must(connSrv.db.Delete(new(SC), dal.Where("connection_id = ?", connectionId)))
 		}
+		if onDeleted != nil {
+			return onDeleted(tx)
+		}
 		return nil
 	})
 	return
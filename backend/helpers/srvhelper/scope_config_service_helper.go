@@ -117,7 +117,9 @@ func (scopeConfigSrv *ScopeConfigSrvHelper[C, S, SC]) GetProjectsByScopeConfig(p
 	return ps, nil
 }
 
-func (scopeConfigSrv *ScopeConfigSrvHelper[C, S, SC]) DeleteScopeConfig(scopeConfig *SC) (refs []*S, err errors.Error) {
+// DeleteScopeConfig deletes the scope config if no scope is using it, onDeleted is optional and runs in the same
+// transaction, i.e. to write the audit log of the deletion
+func (scopeConfigSrv *ScopeConfigSrvHelper[C, S, SC]) DeleteScopeConfig(scopeConfig *SC, onDeleted func(tx dal.Transaction) errors.Error) (refs []*S, err errors.Error) {
 	err = scopeConfigSrv.ModelSrvHelper.NoRunningPipeline(func(tx dal.Transaction) errors.Error {
 		// make sure no scope is using the scopeConfig
 		sc := *scopeConfig
@@ -129,6 +131,9 @@ func (scopeConfigSrv *ScopeConfigSrvHelper[C, S, SC]) DeleteScopeConfig(scopeCon
 			return errors.Conflict.New("Please delete all data scope(s) before you delete this ScopeConfig.")
 		}
 		errors.Must(tx.Delete(scopeConfig))
+		if onDeleted != nil {
+			return onDeleted(tx)
+		}
 		return nil
 	})
 	return
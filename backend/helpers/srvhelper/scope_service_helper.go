@@ -170,7 +170,10 @@ func (scopeSrv *ScopeSrvHelper[C, S, SC]) GetScopesPage(pagination *ScopePaginat
 	return data, count, nil
 }
 
-func (scopeSrv *ScopeSrvHelper[C, S, SC]) DeleteScope(scope *S, dataOnly bool) (refs *DsRefs, err errors.Error) {
+// DeleteScope deletes the data of the scope, and the scope itself unless dataOnly, onDeleted is optional and runs in
+// the same transaction, i.e. to write the audit log of the deletion
+func (scopeSrv *ScopeSrvHelper[C, S, SC]) DeleteScope(scope *S, dataOnly bool, onDeleted func(tx dal.Transaction) errors.Error) (refs *DsRefs, err errors.Error) {
+	var repoIds []string
 	err = scopeSrv.ModelSrvHelper.NoRunningPipeline(func(tx dal.Transaction) errors.Error {
 		s := *scope
 		// check referencing blueprints
@@ -182,12 +185,51 @@ func (scopeSrv *ScopeSrvHelper[C, S, SC]) DeleteScope(scope *S, dataOnly bool) (
 			errors.Must(tx.Delete(scope))
 		}
 		// delete data
+		repoIds = scopeSrv.getRepoIds(s, tx)
 		scopeSrv.deleteScopeData(s, tx)
+		if onDeleted != nil {
+			return onDeleted(tx)
+		}
 		return nil
 	})
+	if err == nil {
+		DeleteReposData(scopeSrv.basicRes, repoIds)
+	}
 	return
 }
 
+// getRepoIds returns the ids of the domain layer repos of the scope
+func (scopeSrv *ScopeSrvHelper[C, S, SC]) getRepoIds(scope plugin.ToolLayerScope, tx dal.Transaction) []string {
+	var repoIds []string
+	errors.Must(tx.Pluck(
+		"id",
+		&repoIds,
+		dal.From("repos"),
+		dal.Where(
+			"_raw_data_table LIKE ? AND _raw_data_params = ?",
+			fmt.Sprintf("_raw_%s%%", scopeSrv.pluginName),
+			plugin.MarshalScopeParams(scope.ScopeParams()),
+		),
+	))
+	return repoIds
+}
+
+// DeleteReposData tells the plugins keeping the data of the repos outside the database to remove the data of the
+// deleted repos, the failures are logged only since the repos are gone anyway
+func DeleteReposData(basicRes context.BasicRes, repoIds []string) {
+	for name, meta := range plugin.AllPlugins() {
+		cleaner, ok := meta.(plugin.RepoDataCleaner)
+		if !ok {
+			continue
+		}
+		for _, repoId := range repoIds {
+			if err := cleaner.DeleteRepoData(basicRes, repoId); err != nil {
+				basicRes.GetLogger().Error(err, "failed to delete the %s data of repo %s", name, repoId)
+			}
+		}
+	}
+}
+
 func (scopeSrv *ScopeSrvHelper[C, S, SC]) getScopeConfig(scopeConfigId uint64) *SC {
 	if scopeConfigId < 1 {
 		return nil
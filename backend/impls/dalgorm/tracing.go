@@ -0,0 +1,100 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dalgorm
+
+import (
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/impls/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	spanInstanceKey = "devlake:span"
+	// long statements, i.e. batch inserts, are truncated to keep the spans small
+	maxTracedStatementLength = 2048
+)
+
+// RegisterTracing records the queries made in the context of a span, see tracing.StartSpan
+func RegisterTracing(db *gorm.DB) errors.Error {
+	callback := db.Callback()
+	errs := []error{
+		callback.Create().Before("gorm:create").Register("devlake:before_create", startQuerySpan("create")),
+		callback.Create().After("gorm:create").Register("devlake:after_create", endQuerySpan),
+		callback.Query().Before("gorm:query").Register("devlake:before_query", startQuerySpan("query")),
+		callback.Query().After("gorm:query").Register("devlake:after_query", endQuerySpan),
+		callback.Update().Before("gorm:update").Register("devlake:before_update", startQuerySpan("update")),
+		callback.Update().After("gorm:update").Register("devlake:after_update", endQuerySpan),
+		callback.Delete().Before("gorm:delete").Register("devlake:before_delete", startQuerySpan("delete")),
+		callback.Delete().After("gorm:delete").Register("devlake:after_delete", endQuerySpan),
+		callback.Row().Before("gorm:row").Register("devlake:before_row", startQuerySpan("row")),
+		callback.Row().After("gorm:row").Register("devlake:after_row", endQuerySpan),
+		callback.Raw().Before("gorm:raw").Register("devlake:before_raw", startQuerySpan("raw")),
+		callback.Raw().After("gorm:raw").Register("devlake:after_raw", endQuerySpan),
+	}
+	for _, err := range errs {
+		if err != nil {
+			return errors.Convert(err)
+		}
+	}
+	return nil
+}
+
+func startQuerySpan(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement.Context == nil {
+			return
+		}
+		_, span := tracing.StartSpan(
+			db.Statement.Context,
+			"db."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				semconv.DBSystemKey.String(db.Dialector.Name()),
+				semconv.DBSQLTableKey.String(db.Statement.Table),
+			),
+		)
+		if span.IsRecording() {
+			db.InstanceSet(spanInstanceKey, span)
+		}
+	}
+}
+
+func endQuerySpan(db *gorm.DB) {
+	value, ok := db.InstanceGet(spanInstanceKey)
+	if !ok {
+		return
+	}
+	span := value.(trace.Span)
+	statement := db.Statement.SQL.String()
+	if len(statement) > maxTracedStatementLength {
+		statement = statement[:maxTracedStatementLength]
+	}
+	span.SetAttributes(
+		semconv.DBStatementKey.String(statement),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+	if db.Error != nil && db.Error != gorm.ErrRecordNotFound {
+		span.RecordError(db.Error)
+		span.SetStatus(codes.Error, db.Error.Error())
+	}
+	span.End()
+}
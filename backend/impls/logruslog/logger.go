@@ -18,6 +18,7 @@ limitations under the License.
 package logruslog
 
 import (
+	"context"
 	"fmt"
 	"regexp"
 	"strings"
@@ -25,6 +26,7 @@ import (
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
 	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var alreadyInBracketsRegex = regexp.MustCompile(`\[.*?]+`)
@@ -32,6 +34,7 @@ var alreadyInBracketsRegex = regexp.MustCompile(`\[.*?]+`)
 type DefaultLogger struct {
 	log    *logrus.Logger
 	config *log.LoggerConfig
+	fields logrus.Fields
 }
 
 func NewDefaultLogger(logger *logrus.Logger) (log.Logger, errors.Error) {
@@ -55,7 +58,11 @@ func (l *DefaultLogger) Log(level log.LogLevel, format string, a ...interface{})
 		if l.config.Prefix != "" {
 			msg = fmt.Sprintf("%s %s", l.config.Prefix, msg)
 		}
-		l.log.Log(logrus.Level(level), msg)
+		if len(l.fields) > 0 {
+			l.log.WithFields(l.fields).Log(logrus.Level(level), msg)
+		} else {
+			l.log.Log(logrus.Level(level), msg)
+		}
 	}
 }
 
@@ -119,10 +126,32 @@ func (l *DefaultLogger) getLogger(prefix string) (log.Logger, errors.Error) {
 			Path:   l.config.Path,
 			Prefix: prefix,
 		},
+		fields: l.fields,
 	}
 	return newLogger, nil
 }
 
+// WithTraceContext returns a logger adding the trace_id and span_id of the span in ctx to each line, the logger is
+// returned as is if there is no span to refer to
+func WithTraceContext(logger log.Logger, ctx context.Context) log.Logger {
+	defaultLogger, ok := logger.(*DefaultLogger)
+	spanContext := trace.SpanContextFromContext(ctx)
+	if !ok || !spanContext.IsValid() {
+		return logger
+	}
+	fields := make(logrus.Fields, len(defaultLogger.fields)+2)
+	for key, value := range defaultLogger.fields {
+		fields[key] = value
+	}
+	fields["trace_id"] = spanContext.TraceID().String()
+	fields["span_id"] = spanContext.SpanID().String()
+	return &DefaultLogger{
+		log:    defaultLogger.log,
+		config: defaultLogger.config,
+		fields: fields,
+	}
+}
+
 func (l *DefaultLogger) createPrefix(newPrefix string) string {
 	newPrefix = strings.TrimSpace(newPrefix)
 	alreadyInBrackets := alreadyInBracketsRegex.MatchString(newPrefix)
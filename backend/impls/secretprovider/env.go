@@ -0,0 +1,50 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.SecretProvider = (*EnvSecretProvider)(nil)
+
+// EnvSecretProvider resolves `env://NAME` with the environment variable NAME, only the variables starting with Prefix
+// are readable so the configuration of DevLake itself, i.e. ENCRYPTION_SECRET or DB_URL, could not be referenced
+type EnvSecretProvider struct {
+	Prefix string
+}
+
+func (p *EnvSecretProvider) Scheme() string {
+	return "env"
+}
+
+func (p *EnvSecretProvider) GetSecret(path string) (string, errors.Error) {
+	if p.Prefix == "" || !strings.HasPrefix(path, p.Prefix) {
+		return "", errors.Forbidden.New(fmt.Sprintf("environment variable %s does not start with %s", path, p.Prefix))
+	}
+	secret, ok := os.LookupEnv(path)
+	if !ok {
+		return "", errors.NotFound.New("environment variable " + path + " is not set")
+	}
+	return secret, nil
+}
@@ -0,0 +1,70 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.SecretProvider = (*FileSecretProvider)(nil)
+
+// FileSecretProvider resolves `file://<path>` with the content of the file, and `file://<path>#<key>` with the
+// value of the key in the file which holds a json object. The path is relative to BaseDir, the absolute paths and the
+// ones escaping BaseDir are rejected, and no file is readable if BaseDir is not set
+type FileSecretProvider struct {
+	BaseDir string
+}
+
+func (p *FileSecretProvider) Scheme() string {
+	return "file"
+}
+
+func (p *FileSecretProvider) GetSecret(path string) (string, errors.Error) {
+	path, key, _ := strings.Cut(path, "#")
+	if p.BaseDir == "" {
+		return "", errors.Forbidden.New("SECRET_FILE_DIR is not set")
+	}
+	if filepath.IsAbs(path) || !filepath.IsLocal(filepath.Clean(path)) {
+		return "", errors.Forbidden.New(fmt.Sprintf("%s is not a relative path inside SECRET_FILE_DIR", path))
+	}
+	path = filepath.Join(p.BaseDir, path)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	if key == "" {
+		return strings.TrimSpace(string(content)), nil
+	}
+	secrets := map[string]string{}
+	err = json.Unmarshal(content, &secrets)
+	if err != nil {
+		return "", errors.BadInput.Wrap(err, fmt.Sprintf("%s is not a json object of secrets", path))
+	}
+	secret, ok := secrets[key]
+	if !ok {
+		return "", errors.NotFound.New(fmt.Sprintf("%s is not found in %s", key, path))
+	}
+	return secret, nil
+}
@@ -0,0 +1,38 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"github.com/apache/incubator-devlake/core/config"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+// Init registers the secret providers enabled by the configuration, `env://` reads the variables starting with
+// SECRET_ENV_PREFIX, `file://` reads the files inside SECRET_FILE_DIR, and `vault://` requires VAULT_ADDR
+func Init(cfg config.ConfigReader) {
+	plugin.RegisterSecretProvider(&EnvSecretProvider{Prefix: cfg.GetString("SECRET_ENV_PREFIX")})
+	plugin.RegisterSecretProvider(&FileSecretProvider{BaseDir: cfg.GetString("SECRET_FILE_DIR")})
+	if address := cfg.GetString("VAULT_ADDR"); address != "" {
+		plugin.RegisterSecretProvider(NewVaultSecretProvider(
+			address,
+			cfg.GetString("VAULT_TOKEN"),
+			cfg.GetString("VAULT_NAMESPACE"),
+			cfg.GetInt("VAULT_KV_VERSION"),
+		))
+	}
+}
@@ -0,0 +1,120 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secretprovider
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+)
+
+var _ plugin.SecretProvider = (*VaultSecretProvider)(nil)
+
+// VaultSecretProvider resolves `vault://<mount>/<path>#<key>` with the key of the secret stored in a Vault-compatible
+// KV secrets engine, the key defaults to `value`
+type VaultSecretProvider struct {
+	Address   string
+	Token     string
+	Namespace string
+	KvVersion int
+	Client    *http.Client
+}
+
+// NewVaultSecretProvider creates a VaultSecretProvider talking to the KV engine at the address
+func NewVaultSecretProvider(address, token, namespace string, kvVersion int) *VaultSecretProvider {
+	if kvVersion == 0 {
+		kvVersion = 2
+	}
+	return &VaultSecretProvider{
+		Address:   strings.TrimSuffix(address, "/"),
+		Token:     token,
+		Namespace: namespace,
+		KvVersion: kvVersion,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (p *VaultSecretProvider) Scheme() string {
+	return "vault"
+}
+
+func (p *VaultSecretProvider) GetSecret(path string) (string, errors.Error) {
+	path, key, _ := strings.Cut(path, "#")
+	if key == "" {
+		key = "value"
+	}
+	mount, secretPath, found := strings.Cut(strings.Trim(path, "/"), "/")
+	if !found {
+		return "", errors.BadInput.New(fmt.Sprintf("%s should be in the form of <mount>/<path>", path))
+	}
+	url := fmt.Sprintf("%s/v1/%s/%s", p.Address, mount, secretPath)
+	if p.KvVersion == 2 {
+		url = fmt.Sprintf("%s/v1/%s/data/%s", p.Address, mount, secretPath)
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	req.Header.Set("X-Vault-Token", p.Token)
+	if p.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.Namespace)
+	}
+	res, err := p.Client.Do(req)
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return "", errors.Convert(err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return "", errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("failed to read %s from vault", path))
+	}
+
+	var secrets map[string]interface{}
+	if p.KvVersion == 2 {
+		result := &struct {
+			Data struct {
+				Data map[string]interface{} `json:"data"`
+			} `json:"data"`
+		}{}
+		err = json.Unmarshal(body, result)
+		secrets = result.Data.Data
+	} else {
+		result := &struct {
+			Data map[string]interface{} `json:"data"`
+		}{}
+		err = json.Unmarshal(body, result)
+		secrets = result.Data
+	}
+	if err != nil {
+		return "", errors.Default.Wrap(errors.Convert(err), fmt.Sprintf("failed to decode %s from vault", path))
+	}
+	secret, ok := secrets[key].(string)
+	if !ok {
+		return "", errors.NotFound.New(fmt.Sprintf("%s is not found in %s", key, path))
+	}
+	return secret, nil
+}
@@ -0,0 +1,89 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	gocontext "context"
+	"sync"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type activeSpanKey struct{}
+
+// activeSpan tracks the subtask being executed, the api clients and the database sessions are created once for
+// a task, while their spans belong to the subtask
+type activeSpan struct {
+	mu   sync.RWMutex
+	span trace.Span
+}
+
+// WithActiveSpan returns a context whose active span can be changed by SetActiveSpan later
+func WithActiveSpan(ctx gocontext.Context) gocontext.Context {
+	return gocontext.WithValue(ctx, activeSpanKey{}, &activeSpan{})
+}
+
+// SetActiveSpan replaces the active span of the context created by WithActiveSpan, nil restores the span of the context
+func SetActiveSpan(ctx gocontext.Context, span trace.Span) {
+	if active, ok := ctx.Value(activeSpanKey{}).(*activeSpan); ok {
+		active.mu.Lock()
+		active.span = span
+		active.mu.Unlock()
+	}
+}
+
+// Detach returns a context holding the spans of ctx without being canceled along with it
+func Detach(ctx gocontext.Context) gocontext.Context {
+	detached := trace.ContextWithSpan(gocontext.Background(), trace.SpanFromContext(ctx))
+	if active, ok := ctx.Value(activeSpanKey{}).(*activeSpan); ok {
+		detached = gocontext.WithValue(detached, activeSpanKey{}, active)
+	}
+	return detached
+}
+
+// ParentSpan returns the active span if there is one, or the span of the context
+func ParentSpan(ctx gocontext.Context) trace.Span {
+	if active, ok := ctx.Value(activeSpanKey{}).(*activeSpan); ok {
+		active.mu.RLock()
+		defer active.mu.RUnlock()
+		if active.span != nil {
+			return active.span
+		}
+	}
+	return trace.SpanFromContext(ctx)
+}
+
+// EndSpan marks the span as failed if err is not nil and ends it
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// StartSpan starts a child span of ParentSpan, nothing is recorded if there is no parent span, so queries and
+// requests made outside of pipelines, i.e. by the api, don't create a trace each
+func StartSpan(ctx gocontext.Context, name string, opts ...trace.SpanStartOption) (gocontext.Context, trace.Span) {
+	parent := ParentSpan(ctx)
+	if !parent.SpanContext().IsValid() {
+		return ctx, parent
+	}
+	return Tracer().Start(trace.ContextWithSpan(ctx, parent), name, opts...)
+}
@@ -0,0 +1,89 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	gocontext "context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+var _ sdktrace.SpanExporter = (*FileExporter)(nil)
+
+// FileExporter writes the spans as json lines, which can be inspected offline or replayed to a collector
+type FileExporter struct {
+	mu     sync.Mutex
+	writer io.WriteCloser
+}
+
+type fileSpan struct {
+	TraceId      string                 `json:"traceId"`
+	SpanId       string                 `json:"spanId"`
+	ParentSpanId string                 `json:"parentSpanId,omitempty"`
+	Name         string                 `json:"name"`
+	StartTime    time.Time              `json:"startTime"`
+	EndTime      time.Time              `json:"endTime"`
+	Status       string                 `json:"status"`
+	Message      string                 `json:"message,omitempty"`
+	Attributes   map[string]interface{} `json:"attributes,omitempty"`
+}
+
+// NewFileExporter creates a FileExporter writing to the writer, which is closed on shutdown
+func NewFileExporter(writer io.WriteCloser) *FileExporter {
+	return &FileExporter{writer: writer}
+}
+
+func (e *FileExporter) ExportSpans(_ gocontext.Context, spans []sdktrace.ReadOnlySpan) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	encoder := json.NewEncoder(e.writer)
+	for _, span := range spans {
+		record := &fileSpan{
+			TraceId:   span.SpanContext().TraceID().String(),
+			SpanId:    span.SpanContext().SpanID().String(),
+			Name:      span.Name(),
+			StartTime: span.StartTime(),
+			EndTime:   span.EndTime(),
+			Status:    span.Status().Code.String(),
+			Message:   span.Status().Description,
+		}
+		if span.Parent().IsValid() {
+			record.ParentSpanId = span.Parent().SpanID().String()
+		}
+		if attributes := span.Attributes(); len(attributes) > 0 {
+			record.Attributes = make(map[string]interface{}, len(attributes))
+			for _, kv := range attributes {
+				record.Attributes[string(kv.Key)] = kv.Value.AsInterface()
+			}
+		}
+		if err := encoder.Encode(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (e *FileExporter) Shutdown(_ gocontext.Context) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.writer.Close()
+}
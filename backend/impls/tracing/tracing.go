@@ -0,0 +1,113 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	gocontext "context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/config"
+	"github.com/apache/incubator-devlake/core/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	EXPORTER_OTLP = "otlp"
+	EXPORTER_FILE = "file"
+)
+
+var provider *sdktrace.TracerProvider
+
+// Tracer returns the tracer of devlake, spans are dropped unless Init enabled an exporter
+func Tracer() trace.Tracer {
+	return otel.Tracer("github.com/apache/incubator-devlake")
+}
+
+// Init sets up the exporter chosen by TRACING_EXPORTER: `otlp` sends the spans to TRACING_OTLP_ENDPOINT over
+// http, and `file` appends them to TRACING_FILE_PATH as json lines for offline use
+func Init(cfg config.ConfigReader) errors.Error {
+	var exporter sdktrace.SpanExporter
+	switch exporterName := strings.ToLower(cfg.GetString("TRACING_EXPORTER")); exporterName {
+	case "":
+		return nil
+	case EXPORTER_OTLP:
+		options := []otlptracehttp.Option{}
+		if endpoint := cfg.GetString("TRACING_OTLP_ENDPOINT"); endpoint != "" {
+			options = append(options, otlptracehttp.WithEndpoint(endpoint))
+		}
+		if cfg.GetBool("TRACING_OTLP_INSECURE") {
+			options = append(options, otlptracehttp.WithInsecure())
+		}
+		otlpExporter, err := otlptracehttp.New(gocontext.Background(), options...)
+		if err != nil {
+			return errors.Default.Wrap(err, "failed to create the otlp exporter")
+		}
+		exporter = otlpExporter
+	case EXPORTER_FILE:
+		path := cfg.GetString("TRACING_FILE_PATH")
+		if path == "" {
+			return errors.BadInput.New("TRACING_FILE_PATH is required by the file exporter")
+		}
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return errors.Default.Wrap(err, fmt.Sprintf("failed to open %s", path))
+		}
+		exporter = NewFileExporter(file)
+	default:
+		return errors.BadInput.New(fmt.Sprintf("unknown TRACING_EXPORTER %s", exporterName))
+	}
+	ratio := 1.0
+	if cfg.IsSet("TRACING_SAMPLE_RATIO") {
+		ratio = cfg.GetFloat64("TRACING_SAMPLE_RATIO")
+	}
+	provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewWithAttributes(
+			semconv.SchemaURL,
+			semconv.ServiceNameKey.String("devlake"),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return nil
+}
+
+// Enabled tells whether the spans are exported
+func Enabled() bool {
+	return provider != nil
+}
+
+// Shutdown flushes the pending spans, it should be called before the process exits
+func Shutdown() {
+	if provider == nil {
+		return
+	}
+	ctx, cancel := gocontext.WithTimeout(gocontext.Background(), 10*time.Second)
+	defer cancel()
+	_ = provider.Shutdown(ctx)
+}
@@ -0,0 +1,82 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"bytes"
+	gocontext "context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+func TestStartSpan(t *testing.T) {
+	buf := &bytes.Buffer{}
+	tracerProvider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(NewFileExporter(nopCloser{buf})))
+	otel.SetTracerProvider(tracerProvider)
+	defer otel.SetTracerProvider(trace.NewNoopTracerProvider())
+	tracer := Tracer()
+
+	// no span is recorded without a parent
+	_, orphan := StartSpan(gocontext.Background(), "orphan")
+	assert.False(t, orphan.IsRecording())
+
+	ctx, task := tracer.Start(gocontext.Background(), "task")
+	ctx = WithActiveSpan(ctx)
+	_, subtask := tracer.Start(ctx, "subtask")
+	SetActiveSpan(ctx, subtask)
+	// the request is attached to the active subtask, even if the context was detached from the task
+	_, request := StartSpan(Detach(ctx), "request")
+	EndSpan(request, fmt.Errorf("failed"))
+	SetActiveSpan(ctx, nil)
+	EndSpan(subtask, nil)
+	_, query := StartSpan(ctx, "query")
+	EndSpan(query, nil)
+	EndSpan(task, nil)
+
+	spans := make(map[string]fileSpan)
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		span := fileSpan{}
+		assert.Nil(t, json.Unmarshal([]byte(line), &span))
+		spans[span.Name] = span
+	}
+	assert.Len(t, spans, 4)
+	taskSpanId := trace.SpanFromContext(ctx).SpanContext().SpanID().String()
+	assert.Equal(t, taskSpanId, spans["task"].SpanId)
+	assert.Equal(t, taskSpanId, spans["subtask"].ParentSpanId)
+	assert.Equal(t, spans["subtask"].SpanId, spans["request"].ParentSpanId)
+	assert.Equal(t, "Error", spans["request"].Status)
+	assert.Equal(t, "failed", spans["request"].Message)
+	assert.Equal(t, taskSpanId, spans["query"].ParentSpanId)
+	assert.Equal(t, spans["task"].TraceId, spans["query"].TraceId)
+}
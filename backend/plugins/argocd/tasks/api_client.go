@@ -34,7 +34,12 @@ func NewArgocdApiClient(taskCtx plugin.TaskContext, connection *models.ArgocdCon
 		return nil, err
 	}
 
-	apiClient.SetData("Token", connection.Token)
+	// the client is created from a resolved copy of the connection, the token has to be resolved as well
+	token, err := plugin.ResolveSecret(connection.Token)
+	if err != nil {
+		return nil, err
+	}
+	apiClient.SetData("Token", token)
 
 	// use standard creator to ensure scheduler initialized
 	asyncClient, err2 := api.CreateAsyncApiClient(taskCtx, apiClient, nil)
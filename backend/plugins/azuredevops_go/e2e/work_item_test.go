@@ -0,0 +1,144 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/impl"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/models"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/tasks"
+)
+
+func TestAzuredevopsWorkItemDataFlow(t *testing.T) {
+
+	var azuredevops impl.Azuredevops
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "azuredevops_go", azuredevops)
+
+	taskData := &tasks.AzuredevopsTaskData{
+		Options: &tasks.AzuredevopsOptions{
+			ConnectionId:   1,
+			ProjectId:      "test-project",
+			OrganizationId: "johndoe",
+			RepositoryId:   "0d50ba13-f9ad-49b0-9b21-d29eda50ca33",
+			ScopeConfig: &models.AzuredevopsScopeConfig{
+				TypeMappings: map[string]models.TypeMapping{
+					"Task": {
+						StandardType: ticket.TASK,
+						StatusMappings: models.StatusMappings{
+							"Ready for QA": {StandardStatus: ticket.IN_PROGRESS},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_azuredevops_go_api_iterations.csv",
+		"_raw_azuredevops_go_api_iterations")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_azuredevops_go_api_areas.csv",
+		"_raw_azuredevops_go_api_areas")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_azuredevops_go_api_work_items.csv",
+		"_raw_azuredevops_go_api_work_items")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_azuredevops_go_api_work_item_updates.csv",
+		"_raw_azuredevops_go_api_work_item_updates")
+
+	// verify extraction
+	dataflowTester.FlushTabler(&models.AzuredevopsIteration{})
+	dataflowTester.FlushTabler(&models.AzuredevopsArea{})
+	dataflowTester.FlushTabler(&models.AzuredevopsWorkItem{})
+	dataflowTester.FlushTabler(&models.AzuredevopsWorkItemRevision{})
+	dataflowTester.Subtask(tasks.ExtractApiIterationsMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractApiAreasMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractApiWorkItemsMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractApiWorkItemUpdatesMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&models.AzuredevopsIteration{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/_tool_azuredevops_go_iterations.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+	dataflowTester.VerifyTableWithOptions(&models.AzuredevopsArea{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/_tool_azuredevops_go_areas.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+	dataflowTester.VerifyTableWithOptions(&models.AzuredevopsWorkItem{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/_tool_azuredevops_go_work_items.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+	dataflowTester.VerifyTableWithOptions(&models.AzuredevopsWorkItemRevision{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/_tool_azuredevops_go_work_item_revisions.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+
+	// verify conversion
+	dataflowTester.FlushTabler(&ticket.Board{})
+	dataflowTester.Subtask(tasks.ConvertApiAreasMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&ticket.Board{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/boards.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+
+	dataflowTester.FlushTabler(&ticket.Sprint{})
+	dataflowTester.FlushTabler(&ticket.BoardSprint{})
+	dataflowTester.Subtask(tasks.ConvertApiIterationsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&ticket.Sprint{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/sprints.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+	dataflowTester.VerifyTableWithOptions(&ticket.BoardSprint{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/board_sprints.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+
+	dataflowTester.FlushTabler(&ticket.Issue{})
+	dataflowTester.FlushTabler(&ticket.BoardIssue{})
+	dataflowTester.FlushTabler(&ticket.SprintIssue{})
+	dataflowTester.FlushTabler(&ticket.IssueLabel{})
+	dataflowTester.FlushTabler(&ticket.IssueAssignee{})
+	dataflowTester.Subtask(tasks.ConvertApiWorkItemsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&ticket.Issue{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/issues.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+	dataflowTester.VerifyTableWithOptions(&ticket.BoardIssue{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/board_issues.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+	dataflowTester.VerifyTableWithOptions(&ticket.SprintIssue{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/sprint_issues.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+	dataflowTester.VerifyTableWithOptions(&ticket.IssueLabel{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/issue_labels.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+	dataflowTester.VerifyTableWithOptions(&ticket.IssueAssignee{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/issue_assignees.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+
+	dataflowTester.FlushTabler(&ticket.IssueChangelogs{})
+	dataflowTester.Subtask(tasks.ConvertApiWorkItemUpdatesMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(&ticket.IssueChangelogs{}, e2ehelper.TableOptions{
+		CSVRelPath:  "./snapshot_tables/issue_changelogs.csv",
+		IgnoreTypes: []interface{}{common.NoPKModel{}},
+	})
+}
@@ -93,6 +93,10 @@ func (p Azuredevops) GetTablesInfo() []dal.Tabler {
 		&models.AzuredevopsScopeConfig{},
 		&models.AzuredevopsTimelineRecord{},
 		&models.AzuredevopsUser{},
+		&models.AzuredevopsWorkItem{},
+		&models.AzuredevopsWorkItemRevision{},
+		&models.AzuredevopsIteration{},
+		&models.AzuredevopsArea{},
 	}
 }
 
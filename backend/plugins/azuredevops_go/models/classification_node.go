@@ -0,0 +1,73 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+type AzuredevopsIteration struct {
+	common.NoPKModel
+
+	ConnectionId  uint64 `gorm:"primaryKey"`
+	AzuredevopsId string `gorm:"primaryKey;type:varchar(255)"`
+	ProjectId     string `gorm:"type:varchar(255);index"`
+	Name          string `gorm:"type:varchar(255)"`
+	// Path uses the same format as System.IterationPath of work items, e.g. `Project\Sprint 1`
+	Path       string `gorm:"type:varchar(255)"`
+	StartDate  *time.Time
+	FinishDate *time.Time
+	Url        string `gorm:"type:varchar(255)"`
+}
+
+func (AzuredevopsIteration) TableName() string {
+	return "_tool_azuredevops_go_iterations"
+}
+
+type AzuredevopsArea struct {
+	common.NoPKModel
+
+	ConnectionId  uint64 `gorm:"primaryKey"`
+	AzuredevopsId string `gorm:"primaryKey;type:varchar(255)"`
+	ProjectId     string `gorm:"type:varchar(255);index"`
+	Name          string `gorm:"type:varchar(255)"`
+	// Path uses the same format as System.AreaPath of work items, e.g. `Project\Team A`
+	Path string `gorm:"type:varchar(255)"`
+	Url  string `gorm:"type:varchar(255)"`
+}
+
+func (AzuredevopsArea) TableName() string {
+	return "_tool_azuredevops_go_areas"
+}
+
+// AzuredevopsApiClassificationNode is a node of the iteration or area tree,
+// children are flattened into separated raw records by the collector.
+type AzuredevopsApiClassificationNode struct {
+	Id            int    `json:"id"`
+	Identifier    string `json:"identifier"`
+	Name          string `json:"name"`
+	StructureType string `json:"structureType"`
+	Path          string `json:"path"`
+	Url           string `json:"url"`
+	Attributes    struct {
+		StartDate  *time.Time `json:"startDate"`
+		FinishDate *time.Time `json:"finishDate"`
+	} `json:"attributes"`
+}
@@ -32,7 +32,7 @@ var _ plugin.AccessTokenAuthenticator = (*AzuredevopsAccessToken)(nil)
 
 // AzuredevopsAccessToken implements HTTP Bearer Authentication with Access Token
 type AzuredevopsAccessToken struct {
-	Token string `mapstructure:"token" validate:"required" json:"token" gorm:"serializer:encdec"`
+	Token string `mapstructure:"token" validate:"required" json:"token" gorm:"serializer:encdec" encrypt:"yes"`
 }
 
 // GetAccessTokenAuthenticator returns SetupAuthentication
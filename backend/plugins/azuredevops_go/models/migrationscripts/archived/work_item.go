@@ -0,0 +1,117 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package archived
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+)
+
+type AzuredevopsWorkItem struct {
+	archived.NoPKModel
+
+	ConnectionId          uint64 `gorm:"primaryKey"`
+	AzuredevopsId         int    `gorm:"primaryKey;autoIncrement:false"`
+	ProjectId             string `gorm:"type:varchar(255);index"`
+	Rev                   int
+	Title                 string
+	Description           string
+	Type                  string `gorm:"type:varchar(100)"`
+	State                 string `gorm:"type:varchar(100)"`
+	Reason                string `gorm:"type:varchar(255)"`
+	StdType               string `gorm:"type:varchar(100)"`
+	StdStatus             string `gorm:"type:varchar(100)"`
+	AreaPath              string `gorm:"type:varchar(255)"`
+	IterationPath         string `gorm:"type:varchar(255)"`
+	Tags                  string
+	Priority              string `gorm:"type:varchar(255)"`
+	Severity              string `gorm:"type:varchar(255)"`
+	StoryPoint            *float64
+	OriginalEstimateHours *float64
+	RemainingWorkHours    *float64
+	CompletedWorkHours    *float64
+	CreatorId             string `gorm:"type:varchar(255)"`
+	CreatorName           string `gorm:"type:varchar(255)"`
+	AssigneeId            string `gorm:"type:varchar(255)"`
+	AssigneeName          string `gorm:"type:varchar(255)"`
+	ParentId              int
+	Url                   string `gorm:"type:varchar(255)"`
+	CreatedDate           *time.Time
+	ChangedDate           *time.Time
+	ResolvedDate          *time.Time
+	ClosedDate            *time.Time
+	DueDate               *time.Time
+}
+
+func (AzuredevopsWorkItem) TableName() string {
+	return "_tool_azuredevops_go_work_items"
+}
+
+type AzuredevopsWorkItemRevision struct {
+	archived.NoPKModel
+
+	ConnectionId uint64 `gorm:"primaryKey"`
+	WorkItemId   int    `gorm:"primaryKey;autoIncrement:false"`
+	Rev          int    `gorm:"primaryKey;autoIncrement:false"`
+	Field        string `gorm:"primaryKey;type:varchar(255)"`
+	ProjectId    string `gorm:"type:varchar(255);index"`
+	AuthorId     string `gorm:"type:varchar(255)"`
+	AuthorName   string `gorm:"type:varchar(255)"`
+	FromValue    string
+	ToValue      string
+	FromString   string
+	ToString     string
+	ChangedDate  *time.Time
+}
+
+func (AzuredevopsWorkItemRevision) TableName() string {
+	return "_tool_azuredevops_go_work_item_revisions"
+}
+
+type AzuredevopsIteration struct {
+	archived.NoPKModel
+
+	ConnectionId  uint64 `gorm:"primaryKey"`
+	AzuredevopsId string `gorm:"primaryKey;type:varchar(255)"`
+	ProjectId     string `gorm:"type:varchar(255);index"`
+	Name          string `gorm:"type:varchar(255)"`
+	Path          string `gorm:"type:varchar(255)"`
+	StartDate     *time.Time
+	FinishDate    *time.Time
+	Url           string `gorm:"type:varchar(255)"`
+}
+
+func (AzuredevopsIteration) TableName() string {
+	return "_tool_azuredevops_go_iterations"
+}
+
+type AzuredevopsArea struct {
+	archived.NoPKModel
+
+	ConnectionId  uint64 `gorm:"primaryKey"`
+	AzuredevopsId string `gorm:"primaryKey;type:varchar(255)"`
+	ProjectId     string `gorm:"type:varchar(255);index"`
+	Name          string `gorm:"type:varchar(255)"`
+	Path          string `gorm:"type:varchar(255)"`
+	Url           string `gorm:"type:varchar(255)"`
+}
+
+func (AzuredevopsArea) TableName() string {
+	return "_tool_azuredevops_go_areas"
+}
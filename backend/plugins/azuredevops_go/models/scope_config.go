@@ -25,12 +25,26 @@ import (
 
 var _ plugin.ToolLayerScopeConfig = (*AzuredevopsScopeConfig)(nil)
 
+type StatusMapping struct {
+	StandardStatus string `json:"standardStatus"`
+}
+
+type StatusMappings map[string]StatusMapping
+
+// TypeMapping maps a work item type to a standard issue type, as well as the states
+// of the work item type to standard issue statuses
+type TypeMapping struct {
+	StandardType   string         `json:"standardType"`
+	StatusMappings StatusMappings `json:"statusMappings"`
+}
+
 type AzuredevopsScopeConfig struct {
 	common.ScopeConfig `mapstructure:",squash" json:",inline"`
 
-	DeploymentPattern string            `mapstructure:"deploymentPattern,omitempty" json:"deploymentPattern"`
-	ProductionPattern string            `mapstructure:"productionPattern,omitempty" json:"productionPattern"`
-	Refdiff           datatypes.JSONMap `mapstructure:"refdiff,omitempty" json:"refdiff" swaggertype:"object" format:"json"`
+	DeploymentPattern string                 `mapstructure:"deploymentPattern,omitempty" json:"deploymentPattern"`
+	ProductionPattern string                 `mapstructure:"productionPattern,omitempty" json:"productionPattern"`
+	Refdiff           datatypes.JSONMap      `mapstructure:"refdiff,omitempty" json:"refdiff" swaggertype:"object" format:"json"`
+	TypeMappings      map[string]TypeMapping `mapstructure:"typeMappings,omitempty" json:"typeMappings" gorm:"type:json;serializer:json"`
 }
 
 // GetConnectionId implements plugin.ToolLayerScopeConfig.
@@ -0,0 +1,61 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// AzuredevopsWorkItemRevision stores a single field change of a work item update,
+// only the fields we care about (state, assignee and iteration) are kept.
+type AzuredevopsWorkItemRevision struct {
+	common.NoPKModel
+
+	ConnectionId uint64 `gorm:"primaryKey"`
+	WorkItemId   int    `gorm:"primaryKey"`
+	Rev          int    `gorm:"primaryKey"`
+	Field        string `gorm:"primaryKey;type:varchar(255)"`
+	ProjectId    string `gorm:"type:varchar(255);index"`
+	AuthorId     string `gorm:"type:varchar(255)"`
+	AuthorName   string `gorm:"type:varchar(255)"`
+	FromValue    string
+	ToValue      string
+	FromString   string
+	ToString     string
+	ChangedDate  *time.Time
+}
+
+func (AzuredevopsWorkItemRevision) TableName() string {
+	return "_tool_azuredevops_go_work_item_revisions"
+}
+
+type AzuredevopsApiWorkItemUpdate struct {
+	Id          int                                          `json:"id"`
+	WorkItemId  int                                          `json:"workItemId"`
+	Rev         int                                          `json:"rev"`
+	RevisedBy   AzuredevopsApiIdentity                       `json:"revisedBy"`
+	RevisedDate *time.Time                                   `json:"revisedDate"`
+	Fields      map[string]AzuredevopsApiWorkItemFieldUpdate `json:"fields"`
+}
+
+type AzuredevopsApiWorkItemFieldUpdate struct {
+	OldValue any `json:"oldValue"`
+	NewValue any `json:"newValue"`
+}
@@ -0,0 +1,58 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+)
+
+func init() {
+	RegisterSubtaskMeta(&CollectIterationsMeta)
+}
+
+const RawIterationTable = "azuredevops_go_api_iterations"
+
+var CollectIterationsMeta = plugin.SubTaskMeta{
+	Name:             "collectApiIterations",
+	EntryPoint:       CollectIterations,
+	EnabledByDefault: true,
+	Description:      "Collect Iteration Paths data from Azure DevOps API",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+	ProductTables:    []string{RawIterationTable},
+}
+
+func CollectIterations(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RawIterationTable)
+
+	// The classification nodes api returns the whole tree at once without pagination
+	collector, err := api.NewApiCollector(api.ApiCollectorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		ApiClient:          data.ApiClient,
+		Incremental:        false,
+		UrlTemplate:        "{{ .Params.OrganizationId }}/{{ .Params.ProjectId }}/_apis/wit/classificationnodes/Iterations?$depth=100&api-version=7.1",
+		ResponseParser:     ParseRawMessageFromClassificationNodes,
+		AfterResponse:      change203To401,
+	})
+	if err != nil {
+		return err
+	}
+
+	return collector.Execute()
+}
@@ -0,0 +1,117 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/models"
+)
+
+func init() {
+	RegisterSubtaskMeta(&ConvertApiIterationsMeta)
+}
+
+var ConvertApiIterationsMeta = plugin.SubTaskMeta{
+	Name:             "convertApiIterations",
+	EntryPoint:       ConvertApiIterations,
+	EnabledByDefault: true,
+	Description:      "Add domain layer Sprint according to Azure DevOps Iterations",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+	DependencyTables: []string{
+		models.AzuredevopsIteration{}.TableName(),
+	},
+	ProductTables: []string{
+		ticket.Sprint{}.TableName(),
+		ticket.BoardSprint{}.TableName(),
+	},
+}
+
+func ConvertApiIterations(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RawIterationTable)
+	db := taskCtx.GetDal()
+
+	cursor, err := db.Cursor(
+		dal.From(&models.AzuredevopsIteration{}),
+		dal.Where("project_id = ? and connection_id = ?", data.Options.ProjectId, data.Options.ConnectionId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	iterationIdGen := didgen.NewDomainIdGenerator(&models.AzuredevopsIteration{})
+	boardId := didgen.NewDomainIdGenerator(&models.AzuredevopsRepo{}).Generate(data.Options.ConnectionId, data.Options.RepositoryId)
+	now := time.Now()
+
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		InputRowType:       reflect.TypeOf(models.AzuredevopsIteration{}),
+		Input:              cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			iteration := inputRow.(*models.AzuredevopsIteration)
+			// the root node stands for the project itself rather than a sprint
+			if !strings.Contains(iteration.Path, `\`) {
+				return nil, nil
+			}
+			sprint := &ticket.Sprint{
+				DomainEntity:    domainlayer.DomainEntity{Id: iterationIdGen.Generate(iteration.ConnectionId, iteration.AzuredevopsId)},
+				Name:            iteration.Name,
+				Url:             iteration.Url,
+				Status:          getIterationStatus(iteration, now),
+				StartedDate:     iteration.StartDate,
+				EndedDate:       iteration.FinishDate,
+				OriginalBoardID: boardId,
+			}
+			if sprint.Status == "CLOSED" {
+				sprint.CompletedDate = iteration.FinishDate
+			}
+			boardSprint := &ticket.BoardSprint{
+				BoardId:  boardId,
+				SprintId: sprint.Id,
+			}
+			return []interface{}{sprint, boardSprint}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+// getIterationStatus derives the status from the dates since Azure DevOps doesn't track the state of iterations
+func getIterationStatus(iteration *models.AzuredevopsIteration, now time.Time) string {
+	if iteration.StartDate == nil || now.Before(*iteration.StartDate) {
+		return "FUTURE"
+	}
+	// finishDate points to the beginning of the last day of the iteration
+	if iteration.FinishDate != nil && now.After(iteration.FinishDate.AddDate(0, 0, 1)) {
+		return "CLOSED"
+	}
+	return "ACTIVE"
+}
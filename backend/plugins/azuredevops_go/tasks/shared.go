@@ -22,10 +22,13 @@ import (
 	"fmt"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/models"
 	"net/http"
 	"net/url"
+	"strings"
 )
 
 // Build and TimeLine Record State and Result types can be found here:
@@ -84,6 +87,45 @@ func ParseRawMessageFromRecords(res *http.Response) ([]json.RawMessage, errors.E
 	return data.Value, nil
 }
 
+// ParseRawMessageFromClassificationNodes flattens the iteration or area tree into a list of nodes,
+// the children are removed from the nodes to avoid storing them repeatedly
+func ParseRawMessageFromClassificationNodes(res *http.Response) ([]json.RawMessage, errors.Error) {
+	var root json.RawMessage
+	err := api.UnmarshalResponse(res, &root)
+	if err != nil {
+		return nil, err
+	}
+	return flattenClassificationNode(root, nil)
+}
+
+func flattenClassificationNode(node json.RawMessage, nodes []json.RawMessage) ([]json.RawMessage, errors.Error) {
+	var fields map[string]json.RawMessage
+	err := errors.Convert(json.Unmarshal(node, &fields))
+	if err != nil {
+		return nil, err
+	}
+	var children []json.RawMessage
+	if rawChildren, ok := fields["children"]; ok {
+		err = errors.Convert(json.Unmarshal(rawChildren, &children))
+		if err != nil {
+			return nil, err
+		}
+		delete(fields, "children")
+	}
+	flattened, err := errors.Convert01(json.Marshal(fields))
+	if err != nil {
+		return nil, err
+	}
+	nodes = append(nodes, flattened)
+	for _, child := range children {
+		nodes, err = flattenClassificationNode(child, nodes)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return nodes, nil
+}
+
 func BuildPaginator(cursor bool) func(reqData *api.RequestData) (url.Values, errors.Error) {
 	return func(reqData *api.RequestData) (url.Values, errors.Error) {
 		query := url.Values{}
@@ -132,6 +174,73 @@ var cicdTaskStatusRule = &devops.StatusRule{
 	Default:    devops.STATUS_OTHER,
 }
 
+// Default mappings of the work item types and states shipped with the Basic, Agile, Scrum and CMMI processes,
+// they can be overridden by the `typeMappings` of the scope config.
+var defaultWorkItemStdTypes = map[string]string{
+	"BUG":                  ticket.BUG,
+	"EPIC":                 ticket.REQUIREMENT,
+	"FEATURE":              ticket.REQUIREMENT,
+	"USER STORY":           ticket.REQUIREMENT,
+	"PRODUCT BACKLOG ITEM": ticket.REQUIREMENT,
+	"REQUIREMENT":          ticket.REQUIREMENT,
+	"ISSUE":                ticket.TASK,
+	"TASK":                 ticket.TASK,
+}
+
+var defaultWorkItemStatusRule = &ticket.StatusRule{
+	Todo:       []string{"New", "To Do", "Proposed", "Approved"},
+	InProgress: []string{"Active", "Committed", "In Progress", "Doing", "Resolved"},
+	Done:       []string{"Closed", "Done", "Completed", "Removed"},
+	Default:    ticket.OTHER,
+}
+
+type workItemTypeMappings struct {
+	StdTypeMappings        map[string]string
+	StandardStatusMappings map[string]models.StatusMappings
+}
+
+func getWorkItemTypeMappings(scopeConfig *models.AzuredevopsScopeConfig) *workItemTypeMappings {
+	mappings := &workItemTypeMappings{
+		StdTypeMappings:        make(map[string]string),
+		StandardStatusMappings: make(map[string]models.StatusMappings),
+	}
+	if scopeConfig == nil {
+		return mappings
+	}
+	for userType, stdType := range scopeConfig.TypeMappings {
+		mappings.StdTypeMappings[userType] = strings.ToUpper(stdType.StandardType)
+		mappings.StandardStatusMappings[userType] = stdType.StatusMappings
+	}
+	return mappings
+}
+
+func (m *workItemTypeMappings) getStdType(workItemType string) string {
+	if stdType := m.StdTypeMappings[workItemType]; stdType != "" {
+		return stdType
+	}
+	if stdType, ok := defaultWorkItemStdTypes[strings.ToUpper(workItemType)]; ok {
+		return stdType
+	}
+	return strings.ToUpper(workItemType)
+}
+
+func (m *workItemTypeMappings) getStdStatus(workItemType string, state string) string {
+	if value, ok := m.StandardStatusMappings[workItemType][state]; ok && value.StandardStatus != "" {
+		return strings.ToUpper(value.StandardStatus)
+	}
+	return ticket.GetStatus(defaultWorkItemStatusRule, state)
+}
+
+// normalizeClassificationPath converts the path of an iteration or area node, e.g. `\Project\Iteration\Sprint 1`,
+// into the format used by the work items, e.g. `Project\Sprint 1`
+func normalizeClassificationPath(path string) string {
+	parts := strings.Split(strings.TrimPrefix(path, `\`), `\`)
+	if len(parts) < 2 {
+		return strings.Join(parts, `\`)
+	}
+	return strings.Join(append(parts[:1:1], parts[2:]...), `\`)
+}
+
 func change203To401(res *http.Response) errors.Error {
 	if res.StatusCode == http.StatusUnauthorized {
 		return errors.Unauthorized.New("authentication failed, please check your AccessToken")
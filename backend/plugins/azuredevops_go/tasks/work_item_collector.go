@@ -0,0 +1,175 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+)
+
+func init() {
+	RegisterSubtaskMeta(&CollectWorkItemsMeta)
+}
+
+const RawWorkItemTable = "azuredevops_go_api_work_items"
+
+// the work items batch api accepts at most 200 ids per request
+const workItemBatchSize = 200
+
+// a WIQL query returns at most 20000 work items
+const wiqlPageSize = 20000
+
+var CollectWorkItemsMeta = plugin.SubTaskMeta{
+	Name:             "collectApiWorkItems",
+	EntryPoint:       CollectWorkItems,
+	EnabledByDefault: true,
+	Description:      "Collect Work Items data from Azure DevOps API, supports timeFilter and diffSync.",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+	ProductTables:    []string{RawWorkItemTable},
+}
+
+type workItemIdBatch struct {
+	Ids string
+}
+
+func CollectWorkItems(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RawWorkItemTable)
+	collector, err := api.NewStatefulApiCollector(*rawDataSubTaskArgs)
+	if err != nil {
+		return err
+	}
+
+	ids, err := queryWorkItemIds(data, collector.GetSince())
+	if err != nil {
+		return err
+	}
+	iterator := api.NewQueueIterator()
+	for i := 0; i < len(ids); i += workItemBatchSize {
+		end := i + workItemBatchSize
+		if end > len(ids) {
+			end = len(ids)
+		}
+		iterator.Push(&workItemIdBatch{Ids: strings.Join(ids[i:end], ",")})
+	}
+
+	err = collector.InitCollector(api.ApiCollectorArgs{
+		ApiClient:   data.ApiClient,
+		Input:       iterator,
+		UrlTemplate: "{{ .Params.OrganizationId }}/{{ .Params.ProjectId }}/_apis/wit/workitems?api-version=7.1",
+		Query: func(reqData *api.RequestData) (url.Values, errors.Error) {
+			query := url.Values{}
+			query.Set("ids", reqData.Input.(*workItemIdBatch).Ids)
+			query.Set("$expand", "links")
+			// work items deleted in the meantime are returned as null instead of failing the whole batch
+			query.Set("errorPolicy", "omit")
+			return query, nil
+		},
+		ResponseParser: parseRawMessageFromWorkItems,
+		AfterResponse:  change203To401,
+	})
+	if err != nil {
+		return err
+	}
+
+	return collector.Execute()
+}
+
+// queryWorkItemIds runs WIQL queries to find out ids of work items changed since the given time,
+// WIQL is the only way to filter work items by their updated date. A WIQL query returns at most
+// wiqlPageSize work items, so the query is paged by the ids greater than the last one of the previous page.
+func queryWorkItemIds(data *AzuredevopsTaskData, since *time.Time) ([]string, errors.Error) {
+	ids := make([]string, 0)
+	lastId := 0
+	for {
+		pageIds, err := queryWorkItemIdPage(data, since, lastId)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range pageIds {
+			ids = append(ids, fmt.Sprint(id))
+		}
+		if len(pageIds) < wiqlPageSize {
+			return ids, nil
+		}
+		lastId = pageIds[len(pageIds)-1]
+	}
+}
+
+func queryWorkItemIdPage(data *AzuredevopsTaskData, since *time.Time, lastId int) ([]int, errors.Error) {
+	wiql := fmt.Sprintf("SELECT [System.Id] FROM WorkItems WHERE [System.TeamProject] = @project AND [System.Id] > %d", lastId)
+	if since != nil {
+		wiql += fmt.Sprintf(" AND [System.ChangedDate] >= '%s'", since.UTC().Format(time.RFC3339))
+	}
+	wiql += " ORDER BY [System.Id] ASC"
+
+	query := url.Values{}
+	query.Set("api-version", "7.1")
+	query.Set("timePrecision", "true")
+	query.Set("$top", fmt.Sprint(wiqlPageSize))
+	res, err := data.ApiClient.Post(
+		fmt.Sprintf("%s/%s/_apis/wit/wiql", data.Options.OrganizationId, data.Options.ProjectId),
+		query,
+		map[string]string{"query": wiql},
+		nil,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err = change203To401(res); err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("failed to query work items of project %s", data.Options.ProjectId))
+	}
+	var body struct {
+		WorkItems []struct {
+			Id int `json:"id"`
+		} `json:"workItems"`
+	}
+	err = api.UnmarshalResponse(res, &body)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]int, 0, len(body.WorkItems))
+	for _, workItem := range body.WorkItems {
+		ids = append(ids, workItem.Id)
+	}
+	return ids, nil
+}
+
+func parseRawMessageFromWorkItems(res *http.Response) ([]json.RawMessage, errors.Error) {
+	values, err := ParseRawMessageFromValue(res)
+	if err != nil {
+		return nil, err
+	}
+	workItems := make([]json.RawMessage, 0, len(values))
+	for _, value := range values {
+		if string(value) != "null" {
+			workItems = append(workItems, value)
+		}
+	}
+	return workItems, nil
+}
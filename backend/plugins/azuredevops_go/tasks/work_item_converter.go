@@ -0,0 +1,207 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/models"
+)
+
+func init() {
+	RegisterSubtaskMeta(&ConvertApiWorkItemsMeta)
+}
+
+var ConvertApiWorkItemsMeta = plugin.SubTaskMeta{
+	Name:             "convertApiWorkItems",
+	EntryPoint:       ConvertApiWorkItems,
+	EnabledByDefault: true,
+	Description:      "Add domain layer Issue according to Azure DevOps Work Items",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+	DependencyTables: []string{
+		models.AzuredevopsWorkItem{}.TableName(),
+		models.AzuredevopsIteration{}.TableName(),
+		models.AzuredevopsArea{}.TableName(),
+	},
+	ProductTables: []string{
+		ticket.Issue{}.TableName(),
+		ticket.BoardIssue{}.TableName(),
+		ticket.IssueLabel{}.TableName(),
+		ticket.IssueAssignee{}.TableName(),
+		ticket.SprintIssue{}.TableName(),
+	},
+}
+
+func ConvertApiWorkItems(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RawWorkItemTable)
+	db := taskCtx.GetDal()
+	connectionId := data.Options.ConnectionId
+
+	sprintIds, err := getIterationIdsByPath(db, data.Options)
+	if err != nil {
+		return err
+	}
+	areaBoardIds, err := getAreaIdsByPath(db, data.Options)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := db.Cursor(
+		dal.From(&models.AzuredevopsWorkItem{}),
+		dal.Where("project_id = ? and connection_id = ?", data.Options.ProjectId, connectionId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	issueIdGen := didgen.NewDomainIdGenerator(&models.AzuredevopsWorkItem{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.AzuredevopsUser{})
+	boardId := didgen.NewDomainIdGenerator(&models.AzuredevopsRepo{}).Generate(connectionId, data.Options.RepositoryId)
+
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		InputRowType:       reflect.TypeOf(models.AzuredevopsWorkItem{}),
+		Input:              cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			workItem := inputRow.(*models.AzuredevopsWorkItem)
+			issue := &ticket.Issue{
+				DomainEntity:            domainlayer.DomainEntity{Id: issueIdGen.Generate(connectionId, workItem.AzuredevopsId)},
+				Url:                     workItem.Url,
+				IssueKey:                strconv.Itoa(workItem.AzuredevopsId),
+				Title:                   workItem.Title,
+				Description:             workItem.Description,
+				Type:                    workItem.StdType,
+				OriginalType:            workItem.Type,
+				Status:                  workItem.StdStatus,
+				OriginalStatus:          workItem.State,
+				StoryPoint:              workItem.StoryPoint,
+				CreatedDate:             workItem.CreatedDate,
+				UpdatedDate:             workItem.ChangedDate,
+				OriginalEstimateMinutes: hoursToMinutes(workItem.OriginalEstimateHours),
+				TimeSpentMinutes:        hoursToMinutes(workItem.CompletedWorkHours),
+				TimeRemainingMinutes:    hoursToMinutes(workItem.RemainingWorkHours),
+				CreatorName:             workItem.CreatorName,
+				AssigneeName:            workItem.AssigneeName,
+				Priority:                workItem.Priority,
+				Severity:                workItem.Severity,
+				OriginalProject:         workItem.ProjectId,
+				DueDate:                 workItem.DueDate,
+			}
+			if workItem.CreatorId != "" {
+				issue.CreatorId = accountIdGen.Generate(connectionId, workItem.CreatorId)
+			}
+			if workItem.AssigneeId != "" {
+				issue.AssigneeId = accountIdGen.Generate(connectionId, workItem.AssigneeId)
+			}
+			if workItem.ParentId != 0 {
+				issue.ParentIssueId = issueIdGen.Generate(connectionId, workItem.ParentId)
+			}
+			if issue.Status == ticket.DONE {
+				issue.ResolutionDate = workItem.ClosedDate
+				if issue.ResolutionDate == nil {
+					issue.ResolutionDate = workItem.ResolvedDate
+				}
+			}
+			if issue.ResolutionDate != nil && issue.CreatedDate != nil {
+				leadTimeMinutes := uint(issue.ResolutionDate.Sub(*issue.CreatedDate).Minutes())
+				issue.LeadTimeMinutes = &leadTimeMinutes
+			}
+
+			results := []interface{}{
+				issue,
+				&ticket.BoardIssue{BoardId: boardId, IssueId: issue.Id},
+			}
+			if areaBoardId, ok := areaBoardIds[workItem.AreaPath]; ok {
+				results = append(results, &ticket.BoardIssue{BoardId: areaBoardId, IssueId: issue.Id})
+			}
+			if sprintId, ok := sprintIds[workItem.IterationPath]; ok {
+				results = append(results, &ticket.SprintIssue{SprintId: sprintId, IssueId: issue.Id})
+			}
+			if issue.AssigneeId != "" {
+				results = append(results, &ticket.IssueAssignee{
+					IssueId:      issue.Id,
+					AssigneeId:   issue.AssigneeId,
+					AssigneeName: issue.AssigneeName,
+				})
+			}
+			for _, tag := range strings.Split(workItem.Tags, ";") {
+				tag = strings.TrimSpace(tag)
+				if tag != "" {
+					results = append(results, &ticket.IssueLabel{IssueId: issue.Id, LabelName: tag})
+				}
+			}
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+func hoursToMinutes(hours *float64) *int64 {
+	if hours == nil {
+		return nil
+	}
+	minutes := int64(*hours * 60)
+	return &minutes
+}
+
+// getIterationIdsByPath returns the sprint domain ids indexed by the iteration paths of the project
+func getIterationIdsByPath(db dal.Dal, options *AzuredevopsOptions) (map[string]string, errors.Error) {
+	var iterations []models.AzuredevopsIteration
+	err := db.All(&iterations, dal.Where("project_id = ? and connection_id = ?", options.ProjectId, options.ConnectionId))
+	if err != nil {
+		return nil, err
+	}
+	iterationIdGen := didgen.NewDomainIdGenerator(&models.AzuredevopsIteration{})
+	ids := make(map[string]string, len(iterations))
+	for _, iteration := range iterations {
+		// work items not planned into any sprint are placed under the root iteration
+		if strings.Contains(iteration.Path, `\`) {
+			ids[iteration.Path] = iterationIdGen.Generate(iteration.ConnectionId, iteration.AzuredevopsId)
+		}
+	}
+	return ids, nil
+}
+
+// getAreaIdsByPath returns the board domain ids indexed by the area paths of the project
+func getAreaIdsByPath(db dal.Dal, options *AzuredevopsOptions) (map[string]string, errors.Error) {
+	var areas []models.AzuredevopsArea
+	err := db.All(&areas, dal.Where("project_id = ? and connection_id = ?", options.ProjectId, options.ConnectionId))
+	if err != nil {
+		return nil, err
+	}
+	areaIdGen := didgen.NewDomainIdGenerator(&models.AzuredevopsArea{})
+	ids := make(map[string]string, len(areas))
+	for _, area := range areas {
+		ids[area.Path] = areaIdGen.Generate(area.ConnectionId, area.AzuredevopsId)
+	}
+	return ids, nil
+}
@@ -0,0 +1,118 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/models"
+)
+
+func init() {
+	RegisterSubtaskMeta(&ExtractApiWorkItemsMeta)
+}
+
+var ExtractApiWorkItemsMeta = plugin.SubTaskMeta{
+	Name:             "extractApiWorkItems",
+	EntryPoint:       ExtractApiWorkItems,
+	EnabledByDefault: true,
+	Description:      "Extract raw work items data into tool layer table",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+	DependencyTables: []string{RawWorkItemTable},
+	ProductTables: []string{
+		models.AzuredevopsWorkItem{}.TableName(),
+	},
+}
+
+func ExtractApiWorkItems(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RawWorkItemTable)
+	mappings := getWorkItemTypeMappings(data.Options.ScopeConfig)
+
+	extractor, err := api.NewApiExtractor(api.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *api.RawData) ([]interface{}, errors.Error) {
+			apiWorkItem := &models.AzuredevopsApiWorkItem{}
+			err := errors.Convert(json.Unmarshal(row.Data, apiWorkItem))
+			if err != nil {
+				return nil, err
+			}
+			fields := apiWorkItem.Fields
+
+			workItem := &models.AzuredevopsWorkItem{
+				ConnectionId:          data.Options.ConnectionId,
+				AzuredevopsId:         apiWorkItem.Id,
+				ProjectId:             data.Options.ProjectId,
+				Rev:                   apiWorkItem.Rev,
+				Title:                 fields.Title,
+				Description:           fields.Description,
+				Type:                  fields.WorkItemType,
+				State:                 fields.State,
+				Reason:                fields.Reason,
+				StdType:               mappings.getStdType(fields.WorkItemType),
+				StdStatus:             mappings.getStdStatus(fields.WorkItemType, fields.State),
+				AreaPath:              fields.AreaPath,
+				IterationPath:         fields.IterationPath,
+				Tags:                  fields.Tags,
+				Severity:              fields.Severity,
+				StoryPoint:            fields.StoryPoints,
+				OriginalEstimateHours: fields.OriginalEstimate,
+				RemainingWorkHours:    fields.RemainingWork,
+				CompletedWorkHours:    fields.CompletedWork,
+				ParentId:              fields.Parent,
+				Url:                   apiWorkItem.Links.Html.Href,
+				CreatedDate:           fields.CreatedDate,
+				ChangedDate:           fields.ChangedDate,
+				ResolvedDate:          fields.ResolvedDate,
+				ClosedDate:            fields.ClosedDate,
+				DueDate:               fields.DueDate,
+			}
+			// Scrum process uses Effort and CMMI process uses Size instead of Story Points
+			if workItem.StoryPoint == nil {
+				workItem.StoryPoint = fields.Effort
+			}
+			if workItem.StoryPoint == nil {
+				workItem.StoryPoint = fields.Size
+			}
+			if fields.Priority != nil {
+				workItem.Priority = fmt.Sprint(*fields.Priority)
+			}
+			if workItem.Url == "" {
+				workItem.Url = apiWorkItem.Url
+			}
+			if fields.CreatedBy != nil {
+				workItem.CreatorId = fields.CreatedBy.Id
+				workItem.CreatorName = fields.CreatedBy.DisplayName
+			}
+			if fields.AssignedTo != nil {
+				workItem.AssigneeId = fields.AssignedTo.Id
+				workItem.AssigneeName = fields.AssignedTo.DisplayName
+			}
+
+			return []interface{}{workItem}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
@@ -0,0 +1,90 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/models"
+)
+
+func init() {
+	RegisterSubtaskMeta(&CollectWorkItemUpdatesMeta)
+}
+
+const RawWorkItemUpdateTable = "azuredevops_go_api_work_item_updates"
+
+var CollectWorkItemUpdatesMeta = plugin.SubTaskMeta{
+	Name:             "collectApiWorkItemUpdates",
+	EntryPoint:       CollectWorkItemUpdates,
+	EnabledByDefault: true,
+	Description:      "Collect Work Item Updates data from Azure DevOps API, supports timeFilter and diffSync.",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+	DependencyTables: []string{models.AzuredevopsWorkItem{}.TableName()},
+	ProductTables:    []string{RawWorkItemUpdateTable},
+}
+
+type SimpleWorkItem struct {
+	AzuredevopsId int
+}
+
+func CollectWorkItemUpdates(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RawWorkItemUpdateTable)
+	db := taskCtx.GetDal()
+
+	collector, err := api.NewStatefulApiCollector(*rawDataSubTaskArgs)
+	if err != nil {
+		return err
+	}
+
+	clauses := []dal.Clause{
+		dal.Select("azuredevops_id"),
+		dal.From(models.AzuredevopsWorkItem{}.TableName()),
+		dal.Where("project_id = ? and connection_id = ?", data.Options.ProjectId, data.Options.ConnectionId),
+	}
+	if collector.IsIncremental() && collector.GetSince() != nil {
+		clauses = append(clauses, dal.Where("changed_date >= ?", collector.GetSince()))
+	}
+	cursor, err := db.Cursor(clauses...)
+	if err != nil {
+		return err
+	}
+	iterator, err := api.NewDalCursorIterator(db, cursor, reflect.TypeOf(SimpleWorkItem{}))
+	if err != nil {
+		return err
+	}
+
+	err = collector.InitCollector(api.ApiCollectorArgs{
+		ApiClient:      data.ApiClient,
+		PageSize:       200,
+		Input:          iterator,
+		UrlTemplate:    "{{ .Params.OrganizationId }}/{{ .Params.ProjectId }}/_apis/wit/workItems/{{ .Input.AzuredevopsId }}/updates?api-version=7.1",
+		Query:          BuildPaginator(false),
+		ResponseParser: ParseRawMessageFromValue,
+		AfterResponse:  change203To401,
+	})
+	if err != nil {
+		return err
+	}
+
+	return collector.Execute()
+}
@@ -0,0 +1,143 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/models"
+)
+
+func init() {
+	RegisterSubtaskMeta(&ConvertApiWorkItemUpdatesMeta)
+}
+
+var ConvertApiWorkItemUpdatesMeta = plugin.SubTaskMeta{
+	Name:             "convertApiWorkItemUpdates",
+	EntryPoint:       ConvertApiWorkItemUpdates,
+	EnabledByDefault: true,
+	Description:      "Add domain layer IssueChangelogs according to Azure DevOps Work Item Updates",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+	DependencyTables: []string{
+		models.AzuredevopsWorkItemRevision{}.TableName(),
+		models.AzuredevopsWorkItem{}.TableName(),
+		models.AzuredevopsIteration{}.TableName(),
+	},
+	ProductTables: []string{
+		ticket.IssueChangelogs{}.TableName(),
+	},
+}
+
+type workItemRevisionResult struct {
+	models.AzuredevopsWorkItemRevision
+	WorkItemType string
+}
+
+func ConvertApiWorkItemUpdates(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RawWorkItemUpdateTable)
+	db := taskCtx.GetDal()
+	connectionId := data.Options.ConnectionId
+	mappings := getWorkItemTypeMappings(data.Options.ScopeConfig)
+
+	sprintIds, err := getIterationIdsByPath(db, data.Options)
+	if err != nil {
+		return err
+	}
+
+	cursor, err := db.Cursor(
+		dal.Select("r.*, w.type AS work_item_type"),
+		dal.From("_tool_azuredevops_go_work_item_revisions r"),
+		dal.Join(`LEFT JOIN _tool_azuredevops_go_work_items w
+			ON w.connection_id = r.connection_id AND w.azuredevops_id = r.work_item_id`),
+		dal.Where("r.project_id = ? and r.connection_id = ?", data.Options.ProjectId, connectionId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	changelogIdGen := didgen.NewDomainIdGenerator(&models.AzuredevopsWorkItemRevision{})
+	issueIdGen := didgen.NewDomainIdGenerator(&models.AzuredevopsWorkItem{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.AzuredevopsUser{})
+
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		InputRowType:       reflect.TypeOf(workItemRevisionResult{}),
+		Input:              cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			revision := inputRow.(*workItemRevisionResult)
+			changelog := &ticket.IssueChangelogs{
+				DomainEntity: domainlayer.DomainEntity{Id: changelogIdGen.Generate(
+					connectionId, revision.WorkItemId, revision.Rev, revision.Field,
+				)},
+				IssueId:           issueIdGen.Generate(connectionId, revision.WorkItemId),
+				AuthorName:        revision.AuthorName,
+				FieldId:           revision.Field,
+				OriginalFromValue: revision.FromString,
+				OriginalToValue:   revision.ToString,
+			}
+			if revision.AuthorId != "" {
+				changelog.AuthorId = accountIdGen.Generate(connectionId, revision.AuthorId)
+			}
+			if revision.ChangedDate != nil {
+				changelog.CreatedDate = *revision.ChangedDate
+			}
+			switch revision.Field {
+			case workItemFieldState:
+				changelog.FieldName = "status"
+				if revision.FromString != "" {
+					changelog.FromValue = mappings.getStdStatus(revision.WorkItemType, revision.FromString)
+				}
+				if revision.ToString != "" {
+					changelog.ToValue = mappings.getStdStatus(revision.WorkItemType, revision.ToString)
+				}
+			case workItemFieldAssignedTo:
+				changelog.FieldName = "assignee"
+				if revision.FromValue != "" {
+					changelog.OriginalFromValue = accountIdGen.Generate(connectionId, revision.FromValue)
+				}
+				if revision.ToValue != "" {
+					changelog.OriginalToValue = accountIdGen.Generate(connectionId, revision.ToValue)
+				}
+				changelog.FromValue = revision.FromString
+				changelog.ToValue = revision.ToString
+			case workItemFieldIterationPath:
+				changelog.FieldName = "Sprint"
+				changelog.OriginalFromValue = sprintIds[revision.FromString]
+				changelog.OriginalToValue = sprintIds[revision.ToString]
+				changelog.FromValue = revision.FromString
+				changelog.ToValue = revision.ToString
+			default:
+				changelog.FieldName = revision.Field
+			}
+			return []interface{}{changelog}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
@@ -0,0 +1,119 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/azuredevops_go/models"
+)
+
+func init() {
+	RegisterSubtaskMeta(&ExtractApiWorkItemUpdatesMeta)
+}
+
+const (
+	workItemFieldState         = "System.State"
+	workItemFieldAssignedTo    = "System.AssignedTo"
+	workItemFieldIterationPath = "System.IterationPath"
+	workItemFieldChangedDate   = "System.ChangedDate"
+)
+
+var ExtractApiWorkItemUpdatesMeta = plugin.SubTaskMeta{
+	Name:             "extractApiWorkItemUpdates",
+	EntryPoint:       ExtractApiWorkItemUpdates,
+	EnabledByDefault: true,
+	Description:      "Extract raw work item updates data into tool layer table",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+	DependencyTables: []string{RawWorkItemUpdateTable},
+	ProductTables: []string{
+		models.AzuredevopsWorkItemRevision{}.TableName(),
+	},
+}
+
+func ExtractApiWorkItemUpdates(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RawWorkItemUpdateTable)
+
+	extractor, err := api.NewApiExtractor(api.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *api.RawData) ([]interface{}, errors.Error) {
+			update := &models.AzuredevopsApiWorkItemUpdate{}
+			err := errors.Convert(json.Unmarshal(row.Data, update))
+			if err != nil {
+				return nil, err
+			}
+
+			// revisedDate of the latest update is always 9999-01-01, the actual date is kept in System.ChangedDate
+			changedDate := update.RevisedDate
+			if v, ok := update.Fields[workItemFieldChangedDate]; ok {
+				if s, ok := v.NewValue.(string); ok {
+					if t, e := time.Parse(time.RFC3339, s); e == nil {
+						changedDate = &t
+					}
+				}
+			}
+
+			results := make([]interface{}, 0, 3)
+			for _, field := range []string{workItemFieldState, workItemFieldAssignedTo, workItemFieldIterationPath} {
+				v, ok := update.Fields[field]
+				if !ok {
+					continue
+				}
+				revision := &models.AzuredevopsWorkItemRevision{
+					ConnectionId: data.Options.ConnectionId,
+					WorkItemId:   update.WorkItemId,
+					Rev:          update.Rev,
+					Field:        field,
+					ProjectId:    data.Options.ProjectId,
+					AuthorId:     update.RevisedBy.Id,
+					AuthorName:   update.RevisedBy.DisplayName,
+					ChangedDate:  changedDate,
+				}
+				revision.FromValue, revision.FromString = parseWorkItemFieldValue(v.OldValue)
+				revision.ToValue, revision.ToString = parseWorkItemFieldValue(v.NewValue)
+				results = append(results, revision)
+			}
+			return results, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
+
+// parseWorkItemFieldValue returns the id and display name for identity fields, or the plain value for others
+func parseWorkItemFieldValue(value any) (string, string) {
+	switch v := value.(type) {
+	case nil:
+		return "", ""
+	case map[string]any:
+		id, _ := v["id"].(string)
+		displayName, _ := v["displayName"].(string)
+		return id, displayName
+	default:
+		s := fmt.Sprint(v)
+		return s, s
+	}
+}
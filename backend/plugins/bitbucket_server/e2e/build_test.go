@@ -0,0 +1,127 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/impl"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/models"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/tasks"
+)
+
+func TestBuildDataFlow(t *testing.T) {
+	var plugin impl.BitbucketServer
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "bitbucket_server", plugin)
+
+	regexEnricher := api.NewRegexEnricher()
+	_ = regexEnricher.TryAdd(devops.DEPLOYMENT, "deploy")
+	_ = regexEnricher.TryAdd(devops.PRODUCTION, "prod")
+	taskData := &tasks.BitbucketServerTaskData{
+		Options: &tasks.BitbucketServerOptions{
+			ConnectionId: 3,
+			FullName:     "TP/repos/first-repo",
+			BitbucketServerScopeConfig: &models.BitbucketServerScopeConfig{
+				DeploymentPattern: "deploy",
+				ProductionPattern: "prod",
+			},
+		},
+		RegexEnricher: regexEnricher,
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_bitbucket_server_api_commits.csv", "_raw_bitbucket_server_api_commits")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_bitbucket_server_api_builds.csv", "_raw_bitbucket_server_api_builds")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_bitbucket_server_api_deployments.csv", "_raw_bitbucket_server_api_deployments")
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/_tool_bitbucket_server_repos.csv", &models.BitbucketServerRepo{})
+
+	// verify commit extraction
+	dataflowTester.FlushTabler(&models.BitbucketServerCommit{})
+	dataflowTester.Subtask(tasks.ExtractApiCommitsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(
+		models.BitbucketServerCommit{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/_tool_bitbucket_server_commits.csv",
+			IgnoreTypes: []interface{}{common.NoPKModel{}},
+		},
+	)
+
+	// verify build extraction
+	dataflowTester.FlushTabler(&models.BitbucketServerBuild{})
+	dataflowTester.Subtask(tasks.ExtractApiBuildsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(
+		models.BitbucketServerBuild{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/_tool_bitbucket_server_builds.csv",
+			IgnoreTypes: []interface{}{common.NoPKModel{}},
+		},
+	)
+
+	// verify build conversion
+	dataflowTester.FlushTabler(&devops.CICDPipeline{})
+	dataflowTester.FlushTabler(&devops.CiCDPipelineCommit{})
+	dataflowTester.Subtask(tasks.ConvertBuildsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(
+		devops.CICDPipeline{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/cicd_pipelines.csv",
+			IgnoreTypes: []interface{}{common.NoPKModel{}},
+		},
+	)
+	dataflowTester.VerifyTableWithOptions(
+		devops.CiCDPipelineCommit{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/cicd_pipeline_commits.csv",
+			IgnoreTypes: []interface{}{common.NoPKModel{}},
+		},
+	)
+
+	// verify deployment extraction
+	dataflowTester.FlushTabler(&models.BitbucketServerDeployment{})
+	dataflowTester.Subtask(tasks.ExtractApiDeploymentsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(
+		models.BitbucketServerDeployment{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/_tool_bitbucket_server_deployments.csv",
+			IgnoreTypes: []interface{}{common.NoPKModel{}},
+		},
+	)
+
+	// verify deployment conversion
+	dataflowTester.FlushTabler(&devops.CicdDeploymentCommit{})
+	dataflowTester.FlushTabler(&devops.CICDDeployment{})
+	dataflowTester.Subtask(tasks.ConvertDeploymentsMeta, taskData)
+	dataflowTester.VerifyTableWithOptions(
+		devops.CicdDeploymentCommit{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/cicd_deployment_commits.csv",
+			IgnoreTypes: []interface{}{common.NoPKModel{}},
+		},
+	)
+	dataflowTester.VerifyTableWithOptions(
+		devops.CICDDeployment{},
+		e2ehelper.TableOptions{
+			CSVRelPath:  "./snapshot_tables/cicd_deployments.csv",
+			IgnoreTypes: []interface{}{common.NoPKModel{}},
+		},
+	)
+}
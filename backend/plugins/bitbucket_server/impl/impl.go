@@ -24,6 +24,7 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
 	"github.com/apache/incubator-devlake/core/plugin"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/bitbucket_server/api"
@@ -73,6 +74,9 @@ func (p BitbucketServer) GetTablesInfo() []dal.Tabler {
 		&models.BitbucketServerRepo{},
 		&models.BitbucketServerPrCommit{},
 		&models.BitbucketServerScopeConfig{},
+		&models.BitbucketServerCommit{},
+		&models.BitbucketServerBuild{},
+		&models.BitbucketServerDeployment{},
 	}
 }
 
@@ -95,12 +99,24 @@ func (p BitbucketServer) SubTaskMetas() []plugin.SubTaskMeta {
 		tasks.CollectApiPrCommitsMeta,
 		tasks.ExtractApiPrCommitsMeta,
 
+		tasks.CollectApiCommitsMeta,
+		tasks.ExtractApiCommitsMeta,
+
+		tasks.CollectApiBuildsMeta,
+		tasks.ExtractApiBuildsMeta,
+
+		tasks.CollectApiDeploymentsMeta,
+		tasks.ExtractApiDeploymentsMeta,
+
 		tasks.ConvertRepoMeta, // ?
 		tasks.ConvertPullRequestsMeta,
 
 		tasks.ConvertPrCommentsMeta,
 		tasks.ConvertPrCommitsMeta,
 
+		tasks.ConvertBuildsMeta,
+		tasks.ConvertDeploymentsMeta,
+
 		tasks.ConvertUsersMeta,
 	}
 }
@@ -133,6 +149,12 @@ func (p BitbucketServer) PrepareTaskData(taskCtx plugin.TaskContext, options map
 	}
 
 	regexEnricher := helper.NewRegexEnricher()
+	if err := regexEnricher.TryAdd(devops.DEPLOYMENT, op.DeploymentPattern); err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid value for `deploymentPattern`")
+	}
+	if err := regexEnricher.TryAdd(devops.PRODUCTION, op.ProductionPattern); err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid value for `productionPattern`")
+	}
 	taskData := &tasks.BitbucketServerTaskData{
 		Options:       op,
 		ApiClient:     apiClient,
@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// build states reported by the Bitbucket Server build-status api
+const (
+	BUILD_SUCCESSFUL = "SUCCESSFUL"
+	BUILD_FAILED     = "FAILED"
+	BUILD_INPROGRESS = "INPROGRESS"
+	BUILD_CANCELLED  = "CANCELLED"
+	BUILD_UNKNOWN    = "UNKNOWN"
+)
+
+// BitbucketServerBuild is a build status reported against a commit, a build is
+// identified by its key, reporting the same key again overwrites the status.
+type BitbucketServerBuild struct {
+	ConnectionId uint64 `gorm:"primaryKey"`
+	RepoId       string `gorm:"primaryKey;type:varchar(255)"`
+	CommitSha    string `gorm:"primaryKey;type:varchar(40)"`
+	Key          string `gorm:"primaryKey;type:varchar(255)"`
+	Name         string `gorm:"type:varchar(255)"`
+	State        string `gorm:"type:varchar(100)"`
+	Url          string
+	Description  string
+	BuildNumber  string `gorm:"type:varchar(255)"`
+	Ref          string `gorm:"type:varchar(255)"`
+	Parent       string `gorm:"type:varchar(255)"`
+	DurationMs   *int64
+	CreatedDate  time.Time
+	UpdatedDate  *time.Time
+	Type         string `gorm:"type:varchar(100)"`
+	Environment  string `gorm:"type:varchar(255)"`
+	common.NoPKModel
+}
+
+func (BitbucketServerBuild) TableName() string {
+	return "_tool_bitbucket_server_builds"
+}
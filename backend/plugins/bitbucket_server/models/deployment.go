@@ -0,0 +1,61 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// deployment states reported by the Bitbucket Data Center deployments api
+const (
+	DEPLOYMENT_PENDING     = "PENDING"
+	DEPLOYMENT_IN_PROGRESS = "IN_PROGRESS"
+	DEPLOYMENT_CANCELLED   = "CANCELLED"
+	DEPLOYMENT_FAILED      = "FAILED"
+	DEPLOYMENT_ROLLED_BACK = "ROLLED_BACK"
+	DEPLOYMENT_SUCCESSFUL  = "SUCCESSFUL"
+	DEPLOYMENT_UNKNOWN     = "UNKNOWN"
+)
+
+// BitbucketServerDeployment is a deployment of a commit reported to Bitbucket Data Center, a deployment is identified
+// by its key, environment and sequence number, reporting the same ones again overwrites the state.
+type BitbucketServerDeployment struct {
+	ConnectionId             uint64 `gorm:"primaryKey"`
+	RepoId                   string `gorm:"primaryKey;type:varchar(255)"`
+	CommitSha                string `gorm:"primaryKey;type:varchar(40)"`
+	Key                      string `gorm:"primaryKey;type:varchar(255)"`
+	EnvironmentKey           string `gorm:"primaryKey;type:varchar(255)"`
+	DeploymentSequenceNumber int64  `gorm:"primaryKey;autoIncrement:false"`
+	DisplayName              string `gorm:"type:varchar(255)"`
+	Description              string
+	State                    string `gorm:"type:varchar(100)"`
+	Url                      string
+	EnvironmentName          string `gorm:"type:varchar(255)"`
+	EnvironmentType          string `gorm:"type:varchar(100)"`
+	EnvironmentUrl           string
+	FromCommitSha            string `gorm:"type:varchar(40)"`
+	LastUpdated              *time.Time
+	Environment              string `gorm:"type:varchar(255)"`
+	common.NoPKModel
+}
+
+func (BitbucketServerDeployment) TableName() string {
+	return "_tool_bitbucket_server_deployments"
+}
@@ -25,5 +25,7 @@ import (
 func All() []plugin.MigrationScript {
 	return []plugin.MigrationScript{
 		new(addInitTables20240115),
+		new(addBuildTables),
+		new(addDeploymentTable),
 	}
 }
@@ -0,0 +1,102 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/models"
+)
+
+const RAW_BUILDS_TABLE = "bitbucket_server_api_builds"
+
+var CollectApiBuildsMeta = plugin.SubTaskMeta{
+	Name:             "collectApiBuilds",
+	EntryPoint:       CollectApiBuilds,
+	EnabledByDefault: true,
+	Description:      "Collect build statuses of commits from Bitbucket Server api",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+	ProductTables:    []string{RAW_BUILDS_TABLE},
+}
+
+func CollectApiBuilds(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_BUILDS_TABLE)
+	collectorWithState, err := helper.NewStatefulApiCollector(*rawDataSubTaskArgs)
+	if err != nil {
+		return err
+	}
+
+	iterator, err := GetCommitsIterator(taskCtx, collectorWithState)
+	if err != nil {
+		return err
+	}
+	defer iterator.Close()
+
+	err = collectorWithState.InitCollector(helper.ApiCollectorArgs{
+		RawDataSubTaskArgs:    *rawDataSubTaskArgs,
+		ApiClient:             data.ApiClient,
+		PageSize:              100,
+		GetNextPageCustomData: GetNextPageCustomData,
+		Query:                 GetQueryForNextPage,
+		Input:                 iterator,
+		UrlTemplate:           "rest/build-status/1.0/commits/{{ .Input.CommitSha }}",
+		ResponseParser:        GetRawMessageFromResponse,
+		AfterResponse:         ignoreHTTPStatus404,
+	})
+	if err != nil {
+		return err
+	}
+
+	return collectorWithState.Execute()
+}
+
+// GetCommitsIterator returns the commits whose build statuses should be (re)collected, in incremental mode
+// these are the newly synced commits plus the ones still having builds in progress
+func GetCommitsIterator(taskCtx plugin.SubTaskContext, apiCollector *helper.StatefulApiCollector) (*helper.DalCursorIterator, errors.Error) {
+	db := taskCtx.GetDal()
+	data := taskCtx.GetData().(*BitbucketServerTaskData)
+	clauses := []dal.Clause{
+		dal.Select("c.commit_sha"),
+		dal.From("_tool_bitbucket_server_commits c"),
+		dal.Where(
+			`c.repo_id = ? and c.connection_id = ?`,
+			data.Options.FullName, data.Options.ConnectionId,
+		),
+	}
+
+	if apiCollector.IsIncremental() && apiCollector.GetSince() != nil {
+		clauses = append(clauses, dal.Where(
+			`c.committed_date >= ? OR EXISTS (
+				SELECT 1 FROM _tool_bitbucket_server_builds b
+				WHERE b.connection_id = c.connection_id AND b.repo_id = c.repo_id AND b.commit_sha = c.commit_sha AND b.state = ?
+			)`,
+			*apiCollector.GetSince(), models.BUILD_INPROGRESS,
+		))
+	}
+
+	cursor, err := db.Cursor(clauses...)
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.NewDalCursorIterator(db, cursor, reflect.TypeOf(BitbucketServerCommitInput{}))
+}
@@ -0,0 +1,164 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/models"
+)
+
+var ConvertBuildsMeta = plugin.SubTaskMeta{
+	Name:             "convertBuilds",
+	EntryPoint:       ConvertBuilds,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table _tool_bitbucket_server_builds into domain layer tables cicd_pipelines and cicd_pipeline_commits",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+}
+
+type bitbucketServerBuildWithCommit struct {
+	models.BitbucketServerBuild
+	CommitMsg string
+}
+
+func ConvertBuilds(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_BUILDS_TABLE)
+	db := taskCtx.GetDal()
+
+	repo := &models.BitbucketServerRepo{}
+	err := db.First(repo, dal.Where("connection_id = ? AND bitbucket_id = ?", data.Options.ConnectionId, data.Options.FullName))
+	if err != nil {
+		return err
+	}
+	repoId := didgen.NewDomainIdGenerator(&models.BitbucketServerRepo{}).Generate(repo.ConnectionId, repo.BitbucketId)
+
+	cursor, err := getBuildsCursor(db, data)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	buildIdGen := didgen.NewDomainIdGenerator(&models.BitbucketServerBuild{})
+
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		InputRowType:       reflect.TypeOf(bitbucketServerBuildWithCommit{}),
+		Input:              cursor,
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			build := inputRow.(*bitbucketServerBuildWithCommit)
+			pipelineId := buildIdGen.Generate(build.ConnectionId, build.RepoId, build.CommitSha, build.Key)
+			domainPipeline := &devops.CICDPipeline{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: pipelineId,
+				},
+				Name:           build.Name,
+				DisplayTitle:   getBuildDisplayTitle(&build.BitbucketServerBuild),
+				Url:            build.Url,
+				Result:         devops.GetResult(buildResultRule, build.State),
+				OriginalResult: build.State,
+				Status:         devops.GetStatus(buildStatusRule, build.State),
+				OriginalStatus: build.State,
+				Type:           build.Type,
+				Environment:    build.Environment,
+				TaskDatesInfo:  getBuildDatesInfo(&build.BitbucketServerBuild),
+				DurationSec:    getBuildDurationSec(&build.BitbucketServerBuild),
+				CicdScopeId:    repoId,
+			}
+			domainPipelineCommit := &devops.CiCDPipelineCommit{
+				PipelineId:   pipelineId,
+				CommitSha:    build.CommitSha,
+				CommitMsg:    build.CommitMsg,
+				DisplayTitle: domainPipeline.DisplayTitle,
+				Url:          build.Url,
+				Branch:       strings.TrimPrefix(build.Ref, "refs/heads/"),
+				RepoId:       repoId,
+				RepoUrl:      repo.HTMLUrl,
+			}
+			return []interface{}{domainPipeline, domainPipelineCommit}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+var buildResultRule = &devops.ResultRule{
+	Success: []string{models.BUILD_SUCCESSFUL},
+	Failure: []string{models.BUILD_FAILED, models.BUILD_CANCELLED},
+	Default: devops.RESULT_DEFAULT,
+}
+
+var buildStatusRule = &devops.StatusRule{
+	Done:       []string{models.BUILD_SUCCESSFUL, models.BUILD_FAILED, models.BUILD_CANCELLED},
+	InProgress: []string{models.BUILD_INPROGRESS},
+	Default:    devops.STATUS_OTHER,
+}
+
+func getBuildsCursor(db dal.Dal, data *BitbucketServerTaskData, extraClauses ...dal.Clause) (dal.Rows, errors.Error) {
+	clauses := []dal.Clause{
+		dal.Select("b.*, c.message AS commit_msg"),
+		dal.From("_tool_bitbucket_server_builds b"),
+		dal.Join("LEFT JOIN _tool_bitbucket_server_commits c ON (c.connection_id = b.connection_id AND c.repo_id = b.repo_id AND c.commit_sha = b.commit_sha)"),
+		dal.Where("b.connection_id = ? AND b.repo_id = ?", data.Options.ConnectionId, data.Options.FullName),
+	}
+	clauses = append(clauses, extraClauses...)
+	return db.Cursor(clauses...)
+}
+
+func getBuildDisplayTitle(build *models.BitbucketServerBuild) string {
+	if build.BuildNumber == "" {
+		return build.Name
+	}
+	return fmt.Sprintf("%s #%s", build.Name, build.BuildNumber)
+}
+
+// getBuildDatesInfo treats the date a status was first reported as the start of the build, and the date
+// of the last report as its end once the build is done
+func getBuildDatesInfo(build *models.BitbucketServerBuild) devops.TaskDatesInfo {
+	createdDate := build.CreatedDate
+	datesInfo := devops.TaskDatesInfo{
+		CreatedDate: createdDate,
+		StartedDate: &createdDate,
+	}
+	if devops.GetStatus(buildStatusRule, build.State) == devops.STATUS_DONE && build.UpdatedDate != nil {
+		datesInfo.FinishedDate = build.UpdatedDate
+	}
+	return datesInfo
+}
+
+func getBuildDurationSec(build *models.BitbucketServerBuild) float64 {
+	if build.DurationMs != nil {
+		return float64(*build.DurationMs) / 1e3
+	}
+	datesInfo := getBuildDatesInfo(build)
+	if datesInfo.FinishedDate == nil {
+		return 0
+	}
+	return datesInfo.FinishedDate.Sub(datesInfo.CreatedDate).Seconds()
+}
@@ -0,0 +1,113 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/models"
+)
+
+var ExtractApiBuildsMeta = plugin.SubTaskMeta{
+	Name:             "extractApiBuilds",
+	EntryPoint:       ExtractApiBuilds,
+	EnabledByDefault: true,
+	Description:      "Extract raw build statuses data into tool layer table _tool_bitbucket_server_builds",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+}
+
+// ApiBuildResponse is a build status, buildNumber, duration, ref, parent, createdDate and updatedDate
+// are only reported by Bitbucket Data Center 7.4 and later
+type ApiBuildResponse struct {
+	State       string `json:"state"`
+	Key         string `json:"key"`
+	Name        string `json:"name"`
+	Url         string `json:"url"`
+	Description string `json:"description"`
+	BuildNumber string `json:"buildNumber"`
+	Ref         string `json:"ref"`
+	Parent      string `json:"parent"`
+	Duration    *int64 `json:"duration"`
+	DateAdded   int64  `json:"dateAdded"`
+	CreatedDate int64  `json:"createdDate"`
+	UpdatedDate int64  `json:"updatedDate"`
+}
+
+func ExtractApiBuilds(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_BUILDS_TABLE)
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			apiBuild := &ApiBuildResponse{}
+			err := errors.Convert(json.Unmarshal(row.Data, apiBuild))
+			if err != nil {
+				return nil, err
+			}
+			input := &BitbucketServerCommitInput{}
+			err = errors.Convert(json.Unmarshal(row.Input, input))
+			if err != nil {
+				return nil, err
+			}
+
+			createdDate := apiBuild.CreatedDate
+			if createdDate == 0 {
+				createdDate = apiBuild.DateAdded
+			}
+			updatedDate := apiBuild.UpdatedDate
+			if updatedDate == 0 {
+				updatedDate = apiBuild.DateAdded
+			}
+			name := apiBuild.Name
+			if name == "" {
+				name = apiBuild.Key
+			}
+			build := &models.BitbucketServerBuild{
+				ConnectionId: data.Options.ConnectionId,
+				RepoId:       data.Options.FullName,
+				CommitSha:    input.CommitSha,
+				Key:          apiBuild.Key,
+				Name:         name,
+				State:        apiBuild.State,
+				Url:          apiBuild.Url,
+				Description:  apiBuild.Description,
+				BuildNumber:  apiBuild.BuildNumber,
+				Ref:          apiBuild.Ref,
+				Parent:       apiBuild.Parent,
+				DurationMs:   apiBuild.Duration,
+				CreatedDate:  time.UnixMilli(createdDate),
+				Type:         data.RegexEnricher.ReturnNameIfMatched(devops.DEPLOYMENT, name),
+				Environment:  data.RegexEnricher.ReturnNameIfOmittedOrMatched(devops.PRODUCTION, name),
+			}
+			if updatedDate != 0 {
+				updated := time.UnixMilli(updatedDate)
+				build.UpdatedDate = &updated
+			}
+			return []interface{}{build}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
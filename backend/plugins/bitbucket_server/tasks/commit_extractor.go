@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/models"
+)
+
+var ExtractApiCommitsMeta = plugin.SubTaskMeta{
+	Name:             "extractApiCommits",
+	EntryPoint:       ExtractApiCommits,
+	EnabledByDefault: true,
+	Description:      "Extract raw commits data into tool layer table _tool_bitbucket_server_commits",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+}
+
+type ApiCommitResponse struct {
+	BitbucketId        string          `json:"id"`
+	DisplayId          string          `json:"displayId"`
+	Author             ApiUserResponse `json:"author"`
+	Committer          ApiUserResponse `json:"committer"`
+	Message            string          `json:"message"`
+	AuthorTimestamp    int64           `json:"authorTimestamp"`
+	CommitterTimestamp int64           `json:"committerTimestamp"`
+}
+
+func ExtractApiCommits(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_COMMITS_TABLE)
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			apiCommit := &ApiCommitResponse{}
+			err := errors.Convert(json.Unmarshal(row.Data, apiCommit))
+			if err != nil {
+				return nil, err
+			}
+			commit := &models.BitbucketServerCommit{
+				ConnectionId:   data.Options.ConnectionId,
+				RepoId:         data.Options.FullName,
+				CommitSha:      apiCommit.BitbucketId,
+				Message:        apiCommit.Message,
+				AuthorName:     apiCommit.Author.DisplayName,
+				AuthorEmail:    apiCommit.Author.EmailAddress,
+				AuthoredDate:   time.UnixMilli(apiCommit.AuthorTimestamp),
+				CommitterName:  apiCommit.Committer.DisplayName,
+				CommitterEmail: apiCommit.Committer.EmailAddress,
+				CommittedDate:  time.UnixMilli(apiCommit.CommitterTimestamp),
+			}
+			return []interface{}{commit}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
@@ -0,0 +1,104 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/models"
+)
+
+const RAW_DEPLOYMENTS_TABLE = "bitbucket_server_api_deployments"
+
+var CollectApiDeploymentsMeta = plugin.SubTaskMeta{
+	Name:             "collectApiDeployments",
+	EntryPoint:       CollectApiDeployments,
+	EnabledByDefault: true,
+	Description:      "Collect deployments of commits from Bitbucket Data Center api",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+	ProductTables:    []string{RAW_DEPLOYMENTS_TABLE},
+}
+
+// CollectApiDeployments collects the deployments reported against each commit, the api is only available since
+// Bitbucket Data Center 7.16, older servers respond with 404 which is ignored
+func CollectApiDeployments(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_DEPLOYMENTS_TABLE)
+	collectorWithState, err := helper.NewStatefulApiCollector(*rawDataSubTaskArgs)
+	if err != nil {
+		return err
+	}
+
+	iterator, err := GetDeploymentCommitsIterator(taskCtx, collectorWithState)
+	if err != nil {
+		return err
+	}
+	defer iterator.Close()
+
+	err = collectorWithState.InitCollector(helper.ApiCollectorArgs{
+		RawDataSubTaskArgs:    *rawDataSubTaskArgs,
+		ApiClient:             data.ApiClient,
+		PageSize:              100,
+		GetNextPageCustomData: GetNextPageCustomData,
+		Query:                 GetQueryForNextPage,
+		Input:                 iterator,
+		UrlTemplate:           "rest/api/latest/projects/{{ .Params.FullName }}/commits/{{ .Input.CommitSha }}/deployments",
+		ResponseParser:        GetRawMessageFromResponse,
+		AfterResponse:         ignoreHTTPStatus404,
+	})
+	if err != nil {
+		return err
+	}
+
+	return collectorWithState.Execute()
+}
+
+// GetDeploymentCommitsIterator returns the commits whose deployments should be (re)collected, in incremental mode
+// these are the newly synced commits plus the ones still having deployments not finished
+func GetDeploymentCommitsIterator(taskCtx plugin.SubTaskContext, apiCollector *helper.StatefulApiCollector) (*helper.DalCursorIterator, errors.Error) {
+	db := taskCtx.GetDal()
+	data := taskCtx.GetData().(*BitbucketServerTaskData)
+	clauses := []dal.Clause{
+		dal.Select("c.commit_sha"),
+		dal.From("_tool_bitbucket_server_commits c"),
+		dal.Where(
+			`c.repo_id = ? and c.connection_id = ?`,
+			data.Options.FullName, data.Options.ConnectionId,
+		),
+	}
+
+	if apiCollector.IsIncremental() && apiCollector.GetSince() != nil {
+		clauses = append(clauses, dal.Where(
+			`c.committed_date >= ? OR EXISTS (
+				SELECT 1 FROM _tool_bitbucket_server_deployments d
+				WHERE d.connection_id = c.connection_id AND d.repo_id = c.repo_id AND d.commit_sha = c.commit_sha AND d.state IN ?
+			)`,
+			*apiCollector.GetSince(), []string{models.DEPLOYMENT_PENDING, models.DEPLOYMENT_IN_PROGRESS},
+		))
+	}
+
+	cursor, err := db.Cursor(clauses...)
+	if err != nil {
+		return nil, err
+	}
+
+	return helper.NewDalCursorIterator(db, cursor, reflect.TypeOf(BitbucketServerCommitInput{}))
+}
@@ -0,0 +1,153 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/models"
+)
+
+var ConvertDeploymentsMeta = plugin.SubTaskMeta{
+	Name:             "convertDeployments",
+	EntryPoint:       ConvertDeployments,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table _tool_bitbucket_server_deployments into domain layer tables cicd_deployment_commits and cicd_deployments",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+}
+
+type bitbucketServerDeploymentWithCommit struct {
+	models.BitbucketServerDeployment
+	CommitMsg     string
+	CommittedDate *time.Time
+}
+
+// ConvertDeployments turns the deployments reported to Bitbucket Data Center into deployment commits
+func ConvertDeployments(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_DEPLOYMENTS_TABLE)
+	db := taskCtx.GetDal()
+
+	repo := &models.BitbucketServerRepo{}
+	err := db.First(repo, dal.Where("connection_id = ? AND bitbucket_id = ?", data.Options.ConnectionId, data.Options.FullName))
+	if err != nil {
+		return err
+	}
+	repoId := didgen.NewDomainIdGenerator(&models.BitbucketServerRepo{}).Generate(repo.ConnectionId, repo.BitbucketId)
+
+	cursor, err := db.Cursor(
+		dal.Select("d.*, c.message AS commit_msg, c.committed_date"),
+		dal.From("_tool_bitbucket_server_deployments d"),
+		dal.Join("LEFT JOIN _tool_bitbucket_server_commits c ON (c.connection_id = d.connection_id AND c.repo_id = d.repo_id AND c.commit_sha = d.commit_sha)"),
+		dal.Where("d.connection_id = ? AND d.repo_id = ?", data.Options.ConnectionId, data.Options.FullName),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	deploymentIdGen := didgen.NewDomainIdGenerator(&models.BitbucketServerDeployment{})
+
+	converter, err := helper.NewDataConverter(helper.DataConverterArgs{
+		InputRowType:       reflect.TypeOf(bitbucketServerDeploymentWithCommit{}),
+		Input:              cursor,
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			deployment := inputRow.(*bitbucketServerDeploymentWithCommit)
+			deploymentId := deploymentIdGen.Generate(
+				deployment.ConnectionId, deployment.RepoId, deployment.CommitSha,
+				deployment.Key, deployment.EnvironmentKey, deployment.DeploymentSequenceNumber,
+			)
+			name := deployment.DisplayName
+			if name == "" {
+				name = deployment.Key
+			}
+			datesInfo := getDeploymentDatesInfo(deployment)
+			domainDeployCommit := &devops.CicdDeploymentCommit{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: fmt.Sprintf("%s:%s", deploymentId, repo.HTMLUrl),
+				},
+				CicdScopeId:         repoId,
+				CicdDeploymentId:    deploymentId,
+				Name:                name,
+				DisplayTitle:        fmt.Sprintf("%s #%d", name, deployment.DeploymentSequenceNumber),
+				Url:                 deployment.Url,
+				Result:              devops.GetResult(deploymentResultRule, deployment.State),
+				Status:              devops.GetStatus(deploymentStatusRule, deployment.State),
+				OriginalStatus:      deployment.State,
+				OriginalResult:      deployment.State,
+				Environment:         deployment.Environment,
+				OriginalEnvironment: deployment.EnvironmentName,
+				TaskDatesInfo:       datesInfo,
+				CommitSha:           deployment.CommitSha,
+				CommitMsg:           deployment.CommitMsg,
+				RepoId:              repoId,
+				RepoUrl:             repo.HTMLUrl,
+			}
+			if datesInfo.FinishedDate != nil {
+				durationSec := datesInfo.FinishedDate.Sub(datesInfo.CreatedDate).Seconds()
+				domainDeployCommit.DurationSec = &durationSec
+			}
+			return []interface{}{domainDeployCommit, domainDeployCommit.ToDeployment()}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+var deploymentResultRule = &devops.ResultRule{
+	Success: []string{models.DEPLOYMENT_SUCCESSFUL},
+	Failure: []string{models.DEPLOYMENT_FAILED, models.DEPLOYMENT_CANCELLED, models.DEPLOYMENT_ROLLED_BACK},
+	Default: devops.RESULT_DEFAULT,
+}
+
+var deploymentStatusRule = &devops.StatusRule{
+	Done:       []string{models.DEPLOYMENT_SUCCESSFUL, models.DEPLOYMENT_FAILED, models.DEPLOYMENT_CANCELLED, models.DEPLOYMENT_ROLLED_BACK},
+	InProgress: []string{models.DEPLOYMENT_PENDING, models.DEPLOYMENT_IN_PROGRESS},
+	Default:    devops.STATUS_OTHER,
+}
+
+// getDeploymentDatesInfo starts the deployment at the commit since the api only reports the date of the last
+// update, which is the end of the deployment once it is done
+func getDeploymentDatesInfo(deployment *bitbucketServerDeploymentWithCommit) devops.TaskDatesInfo {
+	createdDate := deployment.CreatedAt
+	if deployment.CommittedDate != nil {
+		createdDate = *deployment.CommittedDate
+	} else if deployment.LastUpdated != nil {
+		createdDate = *deployment.LastUpdated
+	}
+	datesInfo := devops.TaskDatesInfo{
+		CreatedDate: createdDate,
+		StartedDate: &createdDate,
+	}
+	if devops.GetStatus(deploymentStatusRule, deployment.State) == devops.STATUS_DONE && deployment.LastUpdated != nil {
+		datesInfo.FinishedDate = deployment.LastUpdated
+	}
+	return datesInfo
+}
@@ -0,0 +1,131 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/bitbucket_server/models"
+)
+
+var ExtractApiDeploymentsMeta = plugin.SubTaskMeta{
+	Name:             "extractApiDeployments",
+	EntryPoint:       ExtractApiDeployments,
+	EnabledByDefault: true,
+	Description:      "Extract raw deployments data into tool layer table _tool_bitbucket_server_deployments",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CICD},
+}
+
+type ApiDeploymentResponse struct {
+	DeploymentSequenceNumber int64  `json:"deploymentSequenceNumber"`
+	Description              string `json:"description"`
+	DisplayName              string `json:"displayName"`
+	Key                      string `json:"key"`
+	State                    string `json:"state"`
+	Url                      string `json:"url"`
+	LastUpdated              int64  `json:"lastUpdated"`
+	Environment              struct {
+		DisplayName string `json:"displayName"`
+		Key         string `json:"key"`
+		Type        string `json:"type"`
+		Url         string `json:"url"`
+	} `json:"environment"`
+	FromCommit *struct {
+		Id string `json:"id"`
+	} `json:"fromCommit"`
+	ToCommit struct {
+		Id string `json:"id"`
+	} `json:"toCommit"`
+}
+
+func ExtractApiDeployments(taskCtx plugin.SubTaskContext) errors.Error {
+	rawDataSubTaskArgs, data := CreateRawDataSubTaskArgs(taskCtx, RAW_DEPLOYMENTS_TABLE)
+	extractor, err := helper.NewApiExtractor(helper.ApiExtractorArgs{
+		RawDataSubTaskArgs: *rawDataSubTaskArgs,
+		Extract: func(row *helper.RawData) ([]interface{}, errors.Error) {
+			apiDeployment := &ApiDeploymentResponse{}
+			err := errors.Convert(json.Unmarshal(row.Data, apiDeployment))
+			if err != nil {
+				return nil, err
+			}
+			input := &BitbucketServerCommitInput{}
+			err = errors.Convert(json.Unmarshal(row.Input, input))
+			if err != nil {
+				return nil, err
+			}
+
+			commitSha := apiDeployment.ToCommit.Id
+			if commitSha == "" {
+				commitSha = input.CommitSha
+			}
+			deployment := &models.BitbucketServerDeployment{
+				ConnectionId:             data.Options.ConnectionId,
+				RepoId:                   data.Options.FullName,
+				CommitSha:                commitSha,
+				Key:                      apiDeployment.Key,
+				EnvironmentKey:           apiDeployment.Environment.Key,
+				DeploymentSequenceNumber: apiDeployment.DeploymentSequenceNumber,
+				DisplayName:              apiDeployment.DisplayName,
+				Description:              apiDeployment.Description,
+				State:                    apiDeployment.State,
+				Url:                      apiDeployment.Url,
+				EnvironmentName:          apiDeployment.Environment.DisplayName,
+				EnvironmentType:          apiDeployment.Environment.Type,
+				EnvironmentUrl:           apiDeployment.Environment.Url,
+				Environment:              getDeploymentEnvironment(data, apiDeployment),
+			}
+			if apiDeployment.FromCommit != nil {
+				deployment.FromCommitSha = apiDeployment.FromCommit.Id
+			}
+			if apiDeployment.LastUpdated != 0 {
+				lastUpdated := time.UnixMilli(apiDeployment.LastUpdated)
+				deployment.LastUpdated = &lastUpdated
+			}
+			return []interface{}{deployment}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return extractor.Execute()
+}
+
+// getDeploymentEnvironment marks the deployment as PRODUCTION if its environment matches the productionPattern,
+// otherwise the type of the environment declared in Bitbucket is used
+func getDeploymentEnvironment(data *BitbucketServerTaskData, apiDeployment *ApiDeploymentResponse) string {
+	environment := apiDeployment.Environment
+	hasProductionPattern := data.Options.BitbucketServerScopeConfig != nil && data.Options.BitbucketServerScopeConfig.ProductionPattern != ""
+	if hasProductionPattern && data.RegexEnricher.ReturnNameIfMatched(devops.PRODUCTION, environment.DisplayName, environment.Key) != "" {
+		return devops.PRODUCTION
+	}
+	switch environment.Type {
+	case devops.STAGING, devops.TESTING:
+		return environment.Type
+	case devops.PRODUCTION:
+		if !hasProductionPattern {
+			return devops.PRODUCTION
+		}
+	}
+	return ""
+}
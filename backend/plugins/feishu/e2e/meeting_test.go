@@ -18,6 +18,7 @@ limitations under the License.
 package e2e
 
 import (
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
 	"github.com/apache/incubator-devlake/helpers/e2ehelper"
 	"github.com/apache/incubator-devlake/plugins/feishu/impl"
 	"github.com/apache/incubator-devlake/plugins/feishu/models"
@@ -48,9 +49,24 @@ func TestMeetingDataFlow(t *testing.T) {
 			"connection_id",
 			"start_time",
 			"name",
+			"user_id",
 			"meeting_count",
 			"meeting_duration",
 			"user_type",
 		),
 	)
+
+	// verify conversion
+	dataflowTester.FlushTabler(&chat.MeetingAccountStat{})
+	dataflowTester.Subtask(tasks.ConvertMeetingTopUserItemMeta, taskData)
+	dataflowTester.VerifyTable(
+		chat.MeetingAccountStat{},
+		"./snapshot_tables/meeting_account_stats.csv",
+		e2ehelper.ColumnWithRawData(
+			"account_id",
+			"stat_date",
+			"meeting_count",
+			"meeting_duration_minutes",
+		),
+	)
 }
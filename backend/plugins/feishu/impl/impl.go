@@ -56,6 +56,7 @@ func (p Feishu) GetTablesInfo() []dal.Tabler {
 		&models.FeishuMeetingTopUserItem{},
 		&models.FeishuChatItem{},
 		&models.FeishuMessage{},
+		&models.FeishuUser{},
 	}
 }
 
@@ -81,6 +82,9 @@ func (p Feishu) ScopeConfig() dal.Tabler {
 
 func (p Feishu) SubTaskMetas() []plugin.SubTaskMeta {
 	return []plugin.SubTaskMeta{
+		tasks.CollectUserMeta,
+		tasks.ExtractUserMeta,
+
 		tasks.CollectChatMeta,
 		tasks.ExtractChatItemMeta,
 
@@ -89,6 +93,11 @@ func (p Feishu) SubTaskMetas() []plugin.SubTaskMeta {
 
 		tasks.CollectMeetingTopUserItemMeta,
 		tasks.ExtractMeetingTopUserItemMeta,
+
+		tasks.ConvertUserMeta,
+		tasks.ConvertChatMeta,
+		tasks.ConvertMessageMeta,
+		tasks.ConvertMeetingTopUserItemMeta,
 	}
 }
 
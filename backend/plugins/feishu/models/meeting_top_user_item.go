@@ -27,6 +27,7 @@ type FeishuMeetingTopUserItem struct {
 	ConnectionId     uint64    `gorm:"primaryKey"`
 	StartTime        time.Time `gorm:"primaryKey"`
 	Name             string    `json:"name" gorm:"primaryKey;type:varchar(255)"`
+	UserId           string    `json:"id" gorm:"type:varchar(255)"`
 	MeetingCount     string    `json:"meeting_count" gorm:"type:varchar(255)"`
 	MeetingDuration  string    `json:"meeting_duration" gorm:"type:varchar(255)"`
 	UserType         int64     `json:"user_type"`
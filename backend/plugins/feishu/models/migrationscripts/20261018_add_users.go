@@ -0,0 +1,54 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/feishu/models/migrationscripts/archived"
+)
+
+var _ plugin.MigrationScript = (*addUsers)(nil)
+
+type feishuMeetingTopUserItem20261018 struct {
+	UserId string `gorm:"type:varchar(255)"`
+}
+
+func (feishuMeetingTopUserItem20261018) TableName() string {
+	return "_tool_feishu_meeting_top_user_items"
+}
+
+type addUsers struct{}
+
+func (*addUsers) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&archived.FeishuUser{},
+		&feishuMeetingTopUserItem20261018{},
+	)
+}
+
+func (*addUsers) Version() uint64 {
+	return 20261018000001
+}
+
+func (*addUsers) Name() string {
+	return "Add _tool_feishu_users and user_id to _tool_feishu_meeting_top_user_items"
+}
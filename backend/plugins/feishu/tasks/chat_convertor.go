@@ -0,0 +1,90 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/feishu/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertChat
+
+// ConvertChat converts the chats the bot is in into chat_channels
+func ConvertChat(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*FeishuTaskData)
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.From(&models.FeishuChatItem{}),
+		dal.Where("connection_id = ?", data.Options.ConnectionId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	chatIdGen := didgen.NewDomainIdGenerator(&models.FeishuChatItem{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.FeishuUser{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: FeishuApiParams{
+				ConnectionId: data.Options.ConnectionId,
+			},
+			Table: RAW_CHAT_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.FeishuChatItem{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			chatItem := inputRow.(*models.FeishuChatItem)
+			channel := &chat.ChatChannel{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: chatIdGen.Generate(chatItem.ConnectionId, chatItem.ChatId),
+				},
+				Name:        chatItem.Name,
+				Description: chatItem.Description,
+				Type:        chat.CHANNEL_TYPE_GROUP,
+			}
+			// the owner of a chat created by a bot is an app
+			if chatItem.OwnerId != "" && chatItem.OwnerIdType == "open_id" {
+				channel.CreatorId = accountIdGen.Generate(chatItem.ConnectionId, chatItem.OwnerId)
+			}
+			return []interface{}{channel}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+var ConvertChatMeta = plugin.SubTaskMeta{
+	Name:             "convertChat",
+	EntryPoint:       ConvertChat,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table _tool_feishu_chats into domain layer table chat_channels",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
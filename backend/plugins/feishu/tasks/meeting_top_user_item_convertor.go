@@ -0,0 +1,92 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/feishu/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertMeetingTopUserItem
+
+// ConvertMeetingTopUserItem converts the daily top user meeting report into meeting_account_stats
+func ConvertMeetingTopUserItem(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*FeishuTaskData)
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.From(&models.FeishuMeetingTopUserItem{}),
+		dal.Where("connection_id = ? AND user_id != ''", data.Options.ConnectionId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	accountIdGen := didgen.NewDomainIdGenerator(&models.FeishuUser{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: FeishuApiParams{
+				ConnectionId: data.Options.ConnectionId,
+			},
+			Table: RAW_MEETING_TOP_USER_ITEM_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.FeishuMeetingTopUserItem{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			item := inputRow.(*models.FeishuMeetingTopUserItem)
+			meetingCount, err := errors.Convert01(strconv.Atoi(item.MeetingCount))
+			if err != nil {
+				return nil, err
+			}
+			meetingDuration, err := errors.Convert01(strconv.Atoi(item.MeetingDuration))
+			if err != nil {
+				return nil, err
+			}
+			return []interface{}{
+				&chat.MeetingAccountStat{
+					AccountId:              accountIdGen.Generate(item.ConnectionId, item.UserId),
+					StatDate:               item.StartTime,
+					MeetingCount:           meetingCount,
+					MeetingDurationMinutes: meetingDuration,
+				},
+			}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+var ConvertMeetingTopUserItemMeta = plugin.SubTaskMeta{
+	Name:             "convertMeetingTopUserItem",
+	EntryPoint:       ConvertMeetingTopUserItem,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table _tool_feishu_meeting_top_user_items into domain layer table meeting_account_stats",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
@@ -55,6 +55,7 @@ func ExtractMeetingTopUserItem(taskCtx plugin.SubTaskContext) errors.Error {
 				MeetingCount:    body.MeetingCount,
 				MeetingDuration: body.MeetingDuration,
 				Name:            body.Name,
+				UserId:          body.UserId,
 				UserType:        body.UserType,
 			})
 			return results, nil
@@ -0,0 +1,96 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/feishu/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertMessage
+
+// ConvertMessage converts messages into chat_messages, replies refer to the root message of their thread
+func ConvertMessage(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*FeishuTaskData)
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.From(&models.FeishuMessage{}),
+		dal.Where("connection_id = ? AND deleted = ?", data.Options.ConnectionId, false),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	chatIdGen := didgen.NewDomainIdGenerator(&models.FeishuChatItem{})
+	messageIdGen := didgen.NewDomainIdGenerator(&models.FeishuMessage{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.FeishuUser{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: FeishuApiParams{
+				ConnectionId: data.Options.ConnectionId,
+			},
+			Table: RAW_MESSAGE_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.FeishuMessage{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			feishuMessage := inputRow.(*models.FeishuMessage)
+			createTime := feishuMessage.CreateTime
+			message := &chat.ChatMessage{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: messageIdGen.Generate(feishuMessage.ConnectionId, feishuMessage.MessageId),
+				},
+				ChatChannelId: chatIdGen.Generate(feishuMessage.ConnectionId, feishuMessage.ChatId),
+				Type:          feishuMessage.MsgType,
+				OriginalType:  feishuMessage.MsgType,
+				Content:       feishuMessage.Content,
+				CreatedDate:   &createTime,
+			}
+			if feishuMessage.SenderType == "user" && feishuMessage.SenderId != "" {
+				message.AuthorId = accountIdGen.Generate(feishuMessage.ConnectionId, feishuMessage.SenderId)
+			}
+			if feishuMessage.RootId != "" && feishuMessage.RootId != feishuMessage.MessageId {
+				message.ParentMessageId = messageIdGen.Generate(feishuMessage.ConnectionId, feishuMessage.RootId)
+			}
+			return []interface{}{message}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+var ConvertMessageMeta = plugin.SubTaskMeta{
+	Name:             "convertMessage",
+	EntryPoint:       ConvertMessage,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table _tool_feishu_messages into domain layer table chat_messages",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
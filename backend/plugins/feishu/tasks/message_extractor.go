@@ -80,7 +80,7 @@ func ExtractMessage(taskCtx plugin.SubTaskContext) errors.Error {
 }
 
 var ExtractMessageMeta = plugin.SubTaskMeta{
-	Name:             "extractChatItem",
+	Name:             "extractMessage",
 	EntryPoint:       ExtractMessage,
 	EnabledByDefault: true,
 	Description:      "Extract raw messages data into tool layer table feishu_meeting_top_user_item",
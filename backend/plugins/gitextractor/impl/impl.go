@@ -18,12 +18,16 @@ limitations under the License.
 package impl
 
 import (
+	gocontext "context"
 	"fmt"
 	"net/url"
 
+	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/core/utils"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/gitextractor/parser"
 	"github.com/apache/incubator-devlake/plugins/gitextractor/tasks"
@@ -34,6 +38,7 @@ var _ interface {
 	plugin.PluginMeta
 	plugin.PluginTask
 	plugin.PluginModel
+	plugin.RepoDataCleaner
 } = (*GitExtractor)(nil)
 
 type GitExtractor struct{}
@@ -62,6 +67,7 @@ func (p GitExtractor) SubTaskMetas() []plugin.SubTaskMeta {
 		tasks.CollectGitBranchMeta,
 		tasks.CollectGitTagMeta,
 		tasks.CollectGitDiffLineMeta,
+		tasks.CollectGitFileStatsMeta,
 	}
 }
 
@@ -95,6 +101,9 @@ func (p GitExtractor) PrepareTaskData(taskCtx plugin.TaskContext, options map[st
 	if err != nil {
 		return nil, errors.BadInput.Wrap(err, "failed to parse git url")
 	}
+	if err := resolveCredentials(&op, parsedURL); err != nil {
+		return nil, err
+	}
 
 	// append username to the git url
 	if op.User != "" {
@@ -129,6 +138,29 @@ func (p GitExtractor) PrepareTaskData(taskCtx plugin.TaskContext, options map[st
 	return taskData, nil
 }
 
+// resolveCredentials replaces the secret references of the credentials, which are kept by the plans instead of the
+// secrets, with the secrets, so they only live in memory while the task runs
+func resolveCredentials(op *parser.GitExtractorOptions, parsedURL *url.URL) errors.Error {
+	if parsedURL.User != nil {
+		if password, ok := parsedURL.User.Password(); ok {
+			secret, err := plugin.ResolveSecret(password)
+			if err != nil {
+				return errors.Default.Wrap(err, "failed to resolve the password of the git url")
+			}
+			if secret != password {
+				parsedURL.User = url.UserPassword(parsedURL.User.Username(), secret)
+				op.Url = parsedURL.String()
+			}
+		}
+	}
+	password, err := plugin.ResolveSecret(op.Password)
+	if err != nil {
+		return errors.Default.Wrap(err, "failed to resolve the password")
+	}
+	op.Password = password
+	return nil
+}
+
 func (p GitExtractor) Close(taskCtx plugin.TaskContext) errors.Error {
 	if taskData, ok := taskCtx.GetData().(*parser.GitExtractorTaskData); ok {
 		if !taskCtx.GetConfigReader().GetBool("GIT_EXTRACTOR_KEEP_REPO") {
@@ -149,3 +181,25 @@ func (p GitExtractor) RootPkgPath() string {
 func (p GitExtractor) TestConnection(id uint64) errors.Error {
 	return nil
 }
+
+// DeleteRepoData removes the cached clone and the sync states of the repo, so it would be cloned again from scratch
+func (p GitExtractor) DeleteRepoData(basicRes context.BasicRes, repoId string) errors.Error {
+	err := basicRes.GetDal().Delete(
+		&models.SubtaskState{},
+		dal.Where("plugin = ? AND params = ?", p.Name(), utils.ToJsonString(parser.GitExtractorApiParams{RepoId: repoId})),
+	)
+	if err != nil {
+		return err
+	}
+	cacheDir := basicRes.GetConfigReader().GetString("GIT_EXTRACTOR_CACHE_DIR")
+	if cacheDir == "" {
+		return nil
+	}
+	// the clone might still be used by a running task
+	go func() {
+		if err := tasks.DeleteCacheDir(gocontext.Background(), cacheDir, repoId); err != nil {
+			basicRes.GetLogger().Error(err, "failed to delete the cached clone of %s", repoId)
+		}
+	}()
+	return nil
+}
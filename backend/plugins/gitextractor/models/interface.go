@@ -32,5 +32,7 @@ type Store interface {
 	CommitFileComponents(commitFileComponent *code.CommitFileComponent) errors.Error
 	CommitLineChange(commitLineChange *code.CommitLineChange) errors.Error
 	RepoSnapshot(snapshot *code.RepoSnapshot) errors.Error
+	FileOwnership(ownership *code.FileOwnership) errors.Error
+	FileChurn(churn *code.FileChurn) errors.Error
 	Close() errors.Error
 }
@@ -24,4 +24,8 @@ import (
 type RepoCloner interface {
 	CloneRepo() errors.Error
 	CloseRepo() errors.Error
+	// ListCommitsToCollect returns the shas of the commits added since the previous run, nil means all the commits
+	ListCommitsToCollect() ([]string, errors.Error)
+	// MarkCommitsCollected records the tips of the refs for the next run to start from
+	MarkCommitsCollected()
 }
@@ -18,6 +18,7 @@ limitations under the License.
 package parser
 
 import (
+	"bytes"
 	"fmt"
 	"net/url"
 	"os"
@@ -26,8 +27,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	giturls "github.com/chainguard-dev/git-urls"
@@ -53,12 +56,21 @@ type GitcliCloner struct {
 	since        *time.Time
 	remoteUrl    string
 	localDir     string
+	cached       bool
 	success      bool
 	syncEnvs     []string
 	syncArgs     []string
+	prevTips     RefTips
+	tips         RefTips
+	resynced     bool
 }
 
-func NewGitcliCloner(ctx plugin.SubTaskContext, localDir string) (*GitcliCloner, errors.Error) {
+// RefTips maps the full names of the refs to the shas of the commits they point to
+type RefTips map[string]string
+
+// NewGitcliCloner creates a cloner cloning the repo into localDir, the clone is kept in the localDir between runs
+// if cached is true, so only the new commits have to be fetched
+func NewGitcliCloner(ctx plugin.SubTaskContext, localDir string, cached bool) (*GitcliCloner, errors.Error) {
 	taskData := ctx.GetData().(*GitExtractorTaskData)
 	stateManager := errors.Must1(api.NewSubtaskStateManager(&api.SubtaskCommonArgs{
 		SubTaskContext: ctx,
@@ -79,8 +91,12 @@ func NewGitcliCloner(ctx plugin.SubTaskContext, localDir string) (*GitcliCloner,
 		since:        stateManager.GetSince(),
 		remoteUrl:    taskData.Options.Url,
 		localDir:     localDir,
+		cached:       cached,
 		success:      false,
 	}
+	if err := stateManager.GetCheckpoint(&cloner.prevTips); err != nil {
+		return nil, err
+	}
 	return cloner, cloner.prepareSync()
 }
 
@@ -154,6 +170,9 @@ func (g *GitcliCloner) prepareSync() errors.Error {
 
 func (g *GitcliCloner) IsIncremental() bool {
 	if g != nil && g.stateManager != nil {
+		if g.resynced {
+			return false
+		}
 		if g.stateManager.GetSince() != nil {
 			return true
 		}
@@ -164,6 +183,9 @@ func (g *GitcliCloner) IsIncremental() bool {
 }
 
 func (g *GitcliCloner) CloneRepo() errors.Error {
+	if g.cached {
+		return g.syncCache()
+	}
 	if g.since == nil {
 		// full sync
 		if err := g.fullClone(); err != nil {
@@ -199,6 +221,226 @@ func (g *GitcliCloner) CloseRepo() errors.Error {
 	return nil
 }
 
+// syncCache fetches the new commits into the clone kept by the previous run, the repo is cloned again in Full Sync mode
+// or if the clone is missing or broken
+func (g *GitcliCloner) syncCache() errors.Error {
+	if g.stateManager.IsIncremental() {
+		isBare, err := g.gitOutput(nil, "", "rev-parse", "--is-bare-repository")
+		if err == nil && isBare == "true" {
+			if err = g.fetchCache(); err == nil {
+				g.success = true
+				return nil
+			}
+		}
+		g.logger.Warn(err, "the cached clone is not usable, clone the repo again")
+	}
+	if err := os.RemoveAll(g.localDir); err != nil {
+		return errors.Convert(err)
+	}
+	if err := g.fullClone(); err != nil {
+		return err
+	}
+	g.success = true
+	return g.forgetCredentials()
+}
+
+func (g *GitcliCloner) fetchCache() errors.Error {
+	// the credentials might have been renewed since the last run
+	if err := g.gitCmd("remote", "set-url", "origin", g.remoteUrl); err != nil {
+		return err
+	}
+	args := append([]string{"--prune", "--force", "origin", "+refs/heads/*:refs/heads/*", "+refs/tags/*:refs/tags/*"}, g.syncArgs...)
+	if err := g.git(g.syncEnvs, g.localDir, "fetch", args...); err != nil {
+		return err
+	}
+	return g.forgetCredentials()
+}
+
+// forgetCredentials removes the password from the remote url saved in the config of the cached clone
+func (g *GitcliCloner) forgetCredentials() errors.Error {
+	remoteUrl, err := url.Parse(g.remoteUrl)
+	if err != nil || remoteUrl.User == nil {
+		return nil
+	}
+	if _, ok := remoteUrl.User.Password(); !ok {
+		return nil
+	}
+	remoteUrl.User = url.User(remoteUrl.User.Username())
+	return g.gitCmd("remote", "set-url", "origin", remoteUrl.String())
+}
+
+// ListCommitsToCollect lists the commits reachable from the refs but not from their tips recorded by the previous run.
+// The commits of a force-pushed ref are recomputed from the merge base of its previous and current tips, and the
+// commits rewritten by the force push are removed from the repo, or all the commits are collected again from a full
+// clone if the shallow history does not tell which commits were rewritten.
+func (g *GitcliCloner) ListCommitsToCollect() ([]string, errors.Error) {
+	tips, err := g.listRefTips()
+	if err != nil {
+		return nil, err
+	}
+	g.tips = tips
+	if len(g.prevTips) == 0 {
+		return nil, nil
+	}
+	prevShas := make([]string, 0, len(g.prevTips))
+	for _, sha := range g.prevTips {
+		prevShas = append(prevShas, sha)
+	}
+	existing, err := g.existingCommits(prevShas)
+	if err != nil {
+		return nil, err
+	}
+	revs := make([]string, 0, len(tips)+len(g.prevTips))
+	for _, sha := range tips {
+		revs = append(revs, sha)
+	}
+	var rewritten []string
+	for ref, prevSha := range g.prevTips {
+		// the previous tip might be gone with the shallow history, its ref would be walked as a new one
+		if !existing[prevSha] {
+			continue
+		}
+		if sha, ok := tips[ref]; ok && sha != prevSha {
+			base, err := g.gitOutput(nil, "", "merge-base", prevSha, sha)
+			if err != nil {
+				// there is no common ancestor
+				base = ""
+			}
+			if base != prevSha {
+				g.logger.Info("ref %s was force-pushed from %s to %s", ref, prevSha, sha)
+				rewritten = append(rewritten, prevSha)
+				if base != "" {
+					revs = append(revs, "^"+base)
+				}
+				continue
+			}
+		}
+		revs = append(revs, "^"+prevSha)
+	}
+	if len(rewritten) > 0 {
+		isShallow, err := g.gitOutput(nil, "", "rev-parse", "--is-shallow-repository")
+		if err != nil {
+			return nil, err
+		}
+		if isShallow == "true" {
+			return nil, g.resyncAll()
+		}
+	}
+	shas, err := g.revList(revs)
+	if err != nil {
+		return nil, err
+	}
+	if len(rewritten) > 0 {
+		if err := g.removeRewrittenCommits(rewritten); err != nil {
+			return nil, err
+		}
+	}
+	g.logger.Info("%d commits were added since the previous run", len(shas))
+	return shas, nil
+}
+
+func (g *GitcliCloner) MarkCommitsCollected() {
+	if g.tips != nil {
+		g.stateManager.SetCheckpoint(g.tips)
+	}
+}
+
+// removeRewrittenCommits removes the commits which are only reachable from the previous tips of the force-pushed refs
+func (g *GitcliCloner) removeRewrittenCommits(prevTips []string) errors.Error {
+	revs := append([]string{}, prevTips...)
+	for _, sha := range g.tips {
+		revs = append(revs, "^"+sha)
+	}
+	shas, err := g.revList(revs)
+	if err != nil {
+		return err
+	}
+	g.logger.Info("remove %d commits rewritten by the force pushes", len(shas))
+	db := g.ctx.GetDal()
+	for len(shas) > 0 {
+		batch := shas
+		if len(batch) > 1000 {
+			batch = batch[:1000]
+		}
+		shas = shas[len(batch):]
+		err = db.Delete(&code.RepoCommit{}, dal.Where("repo_id = ? AND commit_sha IN ?", g.taskData.Options.RepoId, batch))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resyncAll replaces the shallow clone with a full one, all the commits are collected again to replace the existing ones
+// since the commits rewritten by the force pushes might be beyond the shallow history
+func (g *GitcliCloner) resyncAll() errors.Error {
+	g.logger.Warn(nil, "refs were force-pushed beyond the shallow history, collect all the commits again")
+	if err := os.RemoveAll(g.localDir); err != nil {
+		return errors.Convert(err)
+	}
+	if err := g.fullClone(); err != nil {
+		return err
+	}
+	if err := g.forgetCredentials(); err != nil {
+		return err
+	}
+	tips, err := g.listRefTips()
+	if err != nil {
+		return err
+	}
+	g.tips = tips
+	g.resynced = true
+	return nil
+}
+
+// listRefTips returns the commits pointed by the refs, the annotated tags are peeled to the commits they point to
+func (g *GitcliCloner) listRefTips() (RefTips, errors.Error) {
+	output, err := g.gitOutput(nil, "", "for-each-ref", "--format=%(refname) %(objecttype) %(objectname) %(*objecttype) %(*objectname)")
+	if err != nil {
+		return nil, err
+	}
+	tips := make(RefTips)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 3 && fields[1] == "commit" {
+			tips[fields[0]] = fields[2]
+		} else if len(fields) == 5 && fields[3] == "commit" {
+			tips[fields[0]] = fields[4]
+		}
+	}
+	return tips, nil
+}
+
+// existingCommits returns the shas of the commits present in the local repo
+func (g *GitcliCloner) existingCommits(shas []string) (map[string]bool, errors.Error) {
+	// do not try to fetch the missing objects from the promisor remote of a partial clone
+	output, err := g.gitOutput([]string{"GIT_NO_LAZY_FETCH=1"}, strings.Join(shas, "\n")+"\n", "cat-file", "--batch-check")
+	if err != nil {
+		return nil, err
+	}
+	existing := make(map[string]bool, len(shas))
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 3 && fields[1] == "commit" {
+			existing[fields[0]] = true
+		}
+	}
+	return existing, nil
+}
+
+// revList lists the commits by the revisions like `sha` and `^sha`, which are passed by the stdin since there might
+// be too many refs for the command line
+func (g *GitcliCloner) revList(revs []string) ([]string, errors.Error) {
+	if len(revs) == 0 {
+		return nil, nil
+	}
+	output, err := g.gitOutput(nil, strings.Join(revs, "\n")+"\n", "rev-list", "--stdin")
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(output), nil
+}
+
 func (g *GitcliCloner) fullClone() errors.Error {
 	return g.gitClone(g.remoteUrl, g.localDir, "--bare")
 }
@@ -311,6 +553,25 @@ func (g *GitcliCloner) git(env []string, dir string, gitcmd string, args ...stri
 	return g.execCommand(cmd)
 }
 
+// gitOutput runs the git command in the local repo with the input, and returns the trimmed output
+func (g *GitcliCloner) gitOutput(env []string, input string, gitcmd string, args ...string) (string, errors.Error) {
+	g.logger.Debug("git %s %v", gitcmd, args)
+	args = append([]string{gitcmd}, args...)
+	cmd := exec.CommandContext(g.ctx.GetContext(), "git", args...)
+	cmd.Env = env
+	cmd.Dir = g.localDir
+	if input != "" {
+		cmd.Stdin = strings.NewReader(input)
+	}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return "", errors.Default.New(fmt.Sprintf("git cmd %v in %s failed: %s", sanitizeArgs(cmd.Args), cmd.Dir, generateErrMsg(stderr.Bytes(), err)))
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
 func (g *GitcliCloner) execCommand(cmd *exec.Cmd) errors.Error {
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -0,0 +1,220 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/utils"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func gitRun(t *testing.T, dir string, args ...string) string {
+	args = append([]string{"-c", "user.name=devlake", "-c", "user.email=devlake@example.com", "-c", "init.defaultBranch=main"}, args...)
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v failed: %s", args, output)
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func gitCommit(t *testing.T, dir string, message string) string {
+	gitRun(t, dir, "commit", "--allow-empty", "-m", message)
+	return gitRun(t, dir, "rev-parse", "HEAD")
+}
+
+func newTestRemote(t *testing.T, messages ...string) (string, []string) {
+	remote := t.TempDir()
+	gitRun(t, remote, "init")
+	var shas []string
+	for _, message := range messages {
+		shas = append(shas, gitCommit(t, remote, message))
+	}
+	return remote, shas
+}
+
+func newTestCloner(t *testing.T, remote string, options *GitExtractorOptions) (*GitcliCloner, *mockdal.Dal) {
+	mockDal := mockdal.NewDal(t)
+	mockCtx := mockplugin.NewSubTaskContext(t)
+	mockCtx.On("GetContext").Return(context.Background()).Maybe()
+	mockCtx.On("GetDal").Return(mockDal).Maybe()
+	mockCtx.On("GetLogger").Return(unithelper.DummyLogger()).Maybe()
+	options.RepoId = "github:GithubRepo:1:1"
+	options.Url = remote
+	return &GitcliCloner{
+		ctx:       mockCtx,
+		taskData:  &GitExtractorTaskData{Options: options},
+		logger:    unithelper.DummyLogger(),
+		remoteUrl: remote,
+		localDir:  filepath.Join(t.TempDir(), "repo"),
+		cached:    true,
+	}, mockDal
+}
+
+// cloneTestRemote clones the remote into the cache dir and records the tips like the previous run
+func cloneTestRemote(t *testing.T, remote string) (*GitcliCloner, *mockdal.Dal) {
+	g, mockDal := newTestCloner(t, remote, &GitExtractorOptions{})
+	assert.Nil(t, g.fullClone())
+	prevTips, err := g.listRefTips()
+	assert.Nil(t, err)
+	g.prevTips = prevTips
+	return g, mockDal
+}
+
+func expectCommitsRemoved(mockDal *mockdal.Dal, removed *[]string) {
+	mockDal.On("Delete", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		where := args.Get(1).([]dal.Clause)[0].Data.(dal.DalClause)
+		*removed = append(*removed, where.Params[1].([]string)...)
+	}).Return(nil)
+}
+
+func TestListCommitsToCollectWalksNewCommits(t *testing.T) {
+	remote, shas := newTestRemote(t, "a", "b")
+	g, _ := cloneTestRemote(t, remote)
+	c := gitCommit(t, remote, "c")
+	gitRun(t, remote, "checkout", "-b", "feature", shas[1])
+	d := gitCommit(t, remote, "d")
+	gitRun(t, remote, "tag", "-a", "v1", "-m", "v1")
+
+	assert.Nil(t, g.fetchCache())
+	commits, err := g.ListCommitsToCollect()
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{c, d}, commits)
+	assert.Equal(t, RefTips{"refs/heads/main": c, "refs/heads/feature": d, "refs/tags/v1": d}, g.tips)
+}
+
+func TestListCommitsToCollectForcePushSharingMergeBase(t *testing.T) {
+	remote, shas := newTestRemote(t, "a", "b", "c")
+	g, mockDal := cloneTestRemote(t, remote)
+	gitRun(t, remote, "reset", "--hard", shas[0])
+	d := gitCommit(t, remote, "d")
+	var removed []string
+	expectCommitsRemoved(mockDal, &removed)
+
+	assert.Nil(t, g.fetchCache())
+	commits, err := g.ListCommitsToCollect()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{d}, commits)
+	assert.ElementsMatch(t, shas[1:], removed)
+}
+
+func TestListCommitsToCollectForcePushWithoutCommonAncestor(t *testing.T) {
+	remote, shas := newTestRemote(t, "a", "b")
+	g, mockDal := cloneTestRemote(t, remote)
+	gitRun(t, remote, "checkout", "--orphan", "rewritten")
+	x := gitCommit(t, remote, "x")
+	y := gitCommit(t, remote, "y")
+	gitRun(t, remote, "branch", "-M", "main")
+	var removed []string
+	expectCommitsRemoved(mockDal, &removed)
+
+	assert.Nil(t, g.fetchCache())
+	commits, err := g.ListCommitsToCollect()
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{x, y}, commits)
+	assert.ElementsMatch(t, shas, removed)
+}
+
+func TestListCommitsToCollectDeletedRef(t *testing.T) {
+	remote, _ := newTestRemote(t, "a")
+	gitRun(t, remote, "checkout", "-b", "feature")
+	gitCommit(t, remote, "b")
+	gitRun(t, remote, "checkout", "main")
+	g, _ := cloneTestRemote(t, remote)
+	gitRun(t, remote, "branch", "-D", "feature")
+	c := gitCommit(t, remote, "c")
+
+	// the commits of the deleted ref are neither walked again nor removed
+	assert.Nil(t, g.fetchCache())
+	commits, err := g.ListCommitsToCollect()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{c}, commits)
+	assert.Equal(t, RefTips{"refs/heads/main": c}, g.tips)
+}
+
+func TestListCommitsToCollectResyncsShallowClone(t *testing.T) {
+	remote, shas := newTestRemote(t, "a", "b", "c")
+	g, _ := newTestCloner(t, remote, &GitExtractorOptions{})
+	gitRun(t, filepath.Dir(g.localDir), "clone", "--bare", "--depth=1", "file://"+remote, g.localDir)
+	prevTips, err := g.listRefTips()
+	assert.Nil(t, err)
+	g.prevTips = prevTips
+	gitRun(t, remote, "reset", "--hard", shas[0])
+	gitCommit(t, remote, "d")
+
+	// the merge base is beyond the shallow history, the rewritten commits are unknown
+	assert.Nil(t, g.fetchCache())
+	commits, err := g.ListCommitsToCollect()
+	assert.Nil(t, err)
+	assert.Nil(t, commits)
+	assert.True(t, g.resynced)
+	assert.Equal(t, "false", gitRun(t, g.localDir, "rev-parse", "--is-shallow-repository"))
+	assert.Equal(t, RefTips{"refs/heads/main": gitRun(t, remote, "rev-parse", "HEAD")}, g.tips)
+}
+
+func TestNewGitcliClonerFullSyncClearsCheckpoint(t *testing.T) {
+	remote, shas := newTestRemote(t, "a", "b")
+	skip := false
+	options := &GitExtractorOptions{SkipCommitStat: &skip, SkipCommitFiles: &skip, UseGoGit: &skip}
+	config := utils.ToJsonString(CloneRepoConfig{UseGoGit: &skip, SkipCommitStat: &skip, SkipCommitFiles: &skip})
+	prevStartedAt := time.Now().Add(-time.Hour)
+
+	for _, fullSync := range []bool{false, true} {
+		template, mockDal := newTestCloner(t, remote, options)
+		assert.Nil(t, template.fullClone())
+		mockDal.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+			state := args.Get(0).(*models.SubtaskState)
+			state.PrevStartedAt = &prevStartedAt
+			state.PrevConfig = config
+			state.Checkpoint = utils.ToJsonString(RefTips{"refs/heads/main": shas[0]})
+		}).Return(nil).Once()
+		mockCtx := template.ctx.(*mockplugin.SubTaskContext)
+		mockCtx.On("GetData").Return(template.taskData)
+		mockCtx.On("GetName").Return("Clone Git Repo")
+		mockTaskCtx := mockplugin.NewTaskContext(t)
+		mockTaskCtx.On("GetName").Return("gitextractor")
+		mockTaskCtx.On("SyncPolicy").Return(&models.SyncPolicy{TriggerSyncPolicy: models.TriggerSyncPolicy{FullSync: fullSync}})
+		mockCtx.On("TaskContext").Return(mockTaskCtx)
+
+		g, err := NewGitcliCloner(mockCtx, template.localDir, true)
+		assert.Nil(t, err)
+		commits, err := g.ListCommitsToCollect()
+		assert.Nil(t, err)
+		if fullSync {
+			assert.Empty(t, g.prevTips)
+			assert.False(t, g.IsIncremental())
+			assert.Nil(t, commits)
+		} else {
+			assert.True(t, g.IsIncremental())
+			assert.Equal(t, []string{shas[1]}, commits)
+		}
+	}
+}
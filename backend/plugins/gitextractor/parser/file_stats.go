@@ -0,0 +1,214 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
+)
+
+const (
+	FILE_CHURN_SHORT_DAYS = 30
+	FILE_CHURN_LONG_DAYS  = 90
+)
+
+// fileStats accumulates the lines of each author in the files at HEAD according to git blame, and the commits changing
+// them within the last FILE_CHURN_LONG_DAYS days, which are saved as the file_ownership and file_churn of the repo
+type fileStats struct {
+	repoId     string
+	components map[string]*regexp.Regexp
+	excluded   []string
+	now        time.Time
+	files      map[string]*fileStat
+}
+
+type fileStat struct {
+	ownership    map[string]*code.FileOwnership
+	churn        *code.FileChurn
+	churnAuthors map[string]bool
+}
+
+func newFileStats(db dal.Dal, repoId string, excludeExts []string, now time.Time) (*fileStats, errors.Error) {
+	components := make([]code.Component, 0)
+	err := db.All(&components, dal.From(components), dal.Where("repo_id= ?", repoId))
+	if err != nil {
+		return nil, err
+	}
+	componentMap := make(map[string]*regexp.Regexp)
+	for _, component := range components {
+		componentMap[component.Name] = regexp.MustCompile(component.PathRegex)
+	}
+	var excluded []string
+	for _, ext := range excludeExts {
+		if e := strings.ToLower(strings.TrimSpace(ext)); e != "" {
+			excluded = append(excluded, e)
+		}
+	}
+	return &fileStats{
+		repoId:     repoId,
+		components: componentMap,
+		excluded:   excluded,
+		now:        now,
+		files:      make(map[string]*fileStat),
+	}, nil
+}
+
+// churnSince returns the time since which the commits are counted as the changes of the files
+func (s *fileStats) churnSince() time.Time {
+	return s.now.AddDate(0, 0, -FILE_CHURN_LONG_DAYS)
+}
+
+// addFile registers a file at HEAD, returns false if the file is excluded by its extension
+func (s *fileStats) addFile(path string) bool {
+	lower := strings.ToLower(path)
+	for _, ext := range s.excluded {
+		if strings.HasSuffix(lower, ext) {
+			return false
+		}
+	}
+	component := s.componentOf(path)
+	s.files[path] = &fileStat{
+		ownership: make(map[string]*code.FileOwnership),
+		churn: &code.FileChurn{
+			DomainEntity:  domainlayer.DomainEntity{Id: genFileStatId(s.repoId, path)},
+			RepoId:        s.repoId,
+			FilePath:      path,
+			ComponentName: component,
+		},
+		churnAuthors: make(map[string]bool),
+	}
+	return true
+}
+
+// addBlame adds the lines last modified by the author to the file
+func (s *fileStats) addBlame(path, authorEmail, authorName string, lines int) {
+	file := s.files[path]
+	if file == nil || lines <= 0 {
+		return
+	}
+	ownership := file.ownership[authorEmail]
+	if ownership == nil {
+		ownership = &code.FileOwnership{
+			DomainEntity:  domainlayer.DomainEntity{Id: genFileStatId(s.repoId, path+"\n"+authorEmail)},
+			RepoId:        s.repoId,
+			FilePath:      path,
+			ComponentName: file.churn.ComponentName,
+			AuthorId:      authorEmail,
+			AuthorName:    authorName,
+		}
+		file.ownership[authorEmail] = ownership
+	}
+	ownership.Lines += lines
+}
+
+// addChange counts the commit changing the file, the files no longer at HEAD are ignored
+func (s *fileStats) addChange(path, authorEmail string, when time.Time) {
+	file := s.files[path]
+	if file == nil || when.Before(s.churnSince()) {
+		return
+	}
+	churn := file.churn
+	churn.Changes90d++
+	if !when.Before(s.now.AddDate(0, 0, -FILE_CHURN_SHORT_DAYS)) {
+		churn.Changes30d++
+	}
+	if !file.churnAuthors[authorEmail] {
+		file.churnAuthors[authorEmail] = true
+		churn.Authors90d++
+	}
+	if churn.LastChangedDate == nil || churn.LastChangedDate.Before(when) {
+		changed := when
+		churn.LastChangedDate = &changed
+	}
+}
+
+// save replaces the file_ownership and file_churn of the repo, the authors of each file are ranked by their lines
+func (s *fileStats) save(db dal.Dal, store models.Store) errors.Error {
+	err := db.Delete(&code.FileOwnership{}, dal.Where("repo_id = ?", s.repoId))
+	if err != nil {
+		return err
+	}
+	err = db.Delete(&code.FileChurn{}, dal.Where("repo_id = ?", s.repoId))
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(s.files))
+	for path := range s.files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		file := s.files[path]
+		for _, ownership := range rankFileOwnership(file.ownership) {
+			if err = store.FileOwnership(ownership); err != nil {
+				return err
+			}
+		}
+		if err = store.FileChurn(file.churn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *fileStats) componentOf(path string) string {
+	for component, reg := range s.components {
+		if reg.MatchString(path) {
+			return component
+		}
+	}
+	return "Default"
+}
+
+// rankFileOwnership sorts the authors of a file by their lines, and fills the ranks and the total lines of the file
+func rankFileOwnership(ownership map[string]*code.FileOwnership) []*code.FileOwnership {
+	ranked := make([]*code.FileOwnership, 0, len(ownership))
+	totalLines := 0
+	for _, o := range ownership {
+		ranked = append(ranked, o)
+		totalLines += o.Lines
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].Lines != ranked[j].Lines {
+			return ranked[i].Lines > ranked[j].Lines
+		}
+		return ranked[i].AuthorId < ranked[j].AuthorId
+	})
+	for i, o := range ranked {
+		o.Rank = i + 1
+		o.TotalLines = totalLines
+	}
+	return ranked
+}
+
+// genFileStatId generates the id by the repo id and the sha256 of the key, since the file paths might be too long
+func genFileStatId(repoId, key string) string {
+	hash := sha256.New()
+	hash.Write([]byte(key))
+	return repoId + ":" + hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,287 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package parser
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/code"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	mockplugin "github.com/apache/incubator-devlake/mocks/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/gitextractor/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// fileStatsStore keeps the file_ownership and file_churn saved by the fileStats
+type fileStatsStore struct {
+	models.Store
+	ownership []*code.FileOwnership
+	churn     []*code.FileChurn
+}
+
+func (s *fileStatsStore) FileOwnership(ownership *code.FileOwnership) errors.Error {
+	s.ownership = append(s.ownership, ownership)
+	return nil
+}
+
+func (s *fileStatsStore) FileChurn(churn *code.FileChurn) errors.Error {
+	s.churn = append(s.churn, churn)
+	return nil
+}
+
+func mockFileStatsDal(t *testing.T, components ...code.Component) *mockdal.Dal {
+	mockDal := mockdal.NewDal(t)
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]code.Component) = components
+	}).Return(nil).Once()
+	return mockDal
+}
+
+func TestFileStatsChurnWindows(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	cases := []struct {
+		name       string
+		daysAgo    int
+		changes30d int
+		changes90d int
+	}{
+		{"today", 0, 1, 1},
+		{"within 30 days", 29, 1, 1},
+		{"30 days ago", 30, 1, 1},
+		{"31 days ago", 31, 0, 1},
+		{"90 days ago", 90, 0, 1},
+		{"91 days ago", 91, 0, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stats, err := newFileStats(mockFileStatsDal(t), "repo", nil, now)
+			assert.Nil(t, err)
+			assert.True(t, stats.addFile("main.go"))
+			stats.addChange("main.go", "alice@example.com", now.AddDate(0, 0, -c.daysAgo))
+			churn := stats.files["main.go"].churn
+			assert.Equal(t, c.changes30d, churn.Changes30d)
+			assert.Equal(t, c.changes90d, churn.Changes90d)
+			assert.Equal(t, c.changes90d, churn.Authors90d)
+		})
+	}
+}
+
+func TestFileStatsChurnAuthorsAndLastChange(t *testing.T) {
+	now := time.Date(2024, 6, 30, 12, 0, 0, 0, time.UTC)
+	stats, err := newFileStats(mockFileStatsDal(t), "repo", nil, now)
+	assert.Nil(t, err)
+	stats.addFile("main.go")
+	stats.addChange("main.go", "alice@example.com", now.AddDate(0, 0, -5))
+	stats.addChange("main.go", "bob@example.com", now.AddDate(0, 0, -40))
+	stats.addChange("main.go", "alice@example.com", now.AddDate(0, 0, -60))
+	// the files no longer at HEAD are ignored
+	stats.addChange("deleted.go", "alice@example.com", now)
+
+	churn := stats.files["main.go"].churn
+	assert.Equal(t, 1, churn.Changes30d)
+	assert.Equal(t, 3, churn.Changes90d)
+	assert.Equal(t, 2, churn.Authors90d)
+	assert.Equal(t, now.AddDate(0, 0, -5), *churn.LastChangedDate)
+	assert.NotContains(t, stats.files, "deleted.go")
+}
+
+func TestFileStatsExcludedExtensions(t *testing.T) {
+	cases := []struct {
+		path  string
+		added bool
+	}{
+		{"main.go", true},
+		{"README.md", false},
+		{"docs/GUIDE.MD", false},
+		{"go.sum", false},
+		{"yarn.lock", false},
+		{"markdown.go", true},
+		{"lock", true},
+	}
+	stats, err := newFileStats(mockFileStatsDal(t), "repo", []string{" .MD ", ".sum", "", ".lock"}, time.Now())
+	assert.Nil(t, err)
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			assert.Equal(t, c.added, stats.addFile(c.path))
+			if c.added {
+				assert.Contains(t, stats.files, c.path)
+			} else {
+				assert.NotContains(t, stats.files, c.path)
+			}
+		})
+	}
+}
+
+func TestFileStatsComponents(t *testing.T) {
+	cases := []struct {
+		path      string
+		component string
+	}{
+		{"backend/server/main.go", "backend"},
+		{"config-ui/src/App.tsx", "frontend"},
+		{"README.md", "Default"},
+		{"docs/backend/README.md", "Default"},
+	}
+	stats, err := newFileStats(mockFileStatsDal(t,
+		code.Component{RepoId: "repo", Name: "backend", PathRegex: `^backend/`},
+		code.Component{RepoId: "repo", Name: "frontend", PathRegex: `^config-ui/.*\.tsx?$`},
+	), "repo", nil, time.Now())
+	assert.Nil(t, err)
+	for _, c := range cases {
+		t.Run(c.path, func(t *testing.T) {
+			assert.True(t, stats.addFile(c.path))
+			stats.addBlame(c.path, "alice@example.com", "Alice", 1)
+			file := stats.files[c.path]
+			assert.Equal(t, c.component, file.churn.ComponentName)
+			assert.Equal(t, c.component, file.ownership["alice@example.com"].ComponentName)
+		})
+	}
+}
+
+func TestRankFileOwnership(t *testing.T) {
+	cases := []struct {
+		name   string
+		blames map[string]int
+		ranked []string
+		total  int
+	}{
+		{"single author", map[string]int{"alice": 3}, []string{"alice"}, 3},
+		{"ranked by lines", map[string]int{"alice": 1, "bob": 5, "carol": 2}, []string{"bob", "carol", "alice"}, 8},
+		{"ties ranked by author", map[string]int{"carol": 2, "alice": 2, "bob": 4}, []string{"bob", "alice", "carol"}, 8},
+		{"empty lines ignored", map[string]int{"alice": 2, "bob": 0}, []string{"alice"}, 2},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			stats, err := newFileStats(mockFileStatsDal(t), "repo", nil, time.Now())
+			assert.Nil(t, err)
+			stats.addFile("main.go")
+			for author, lines := range c.blames {
+				// the lines of an author are accumulated
+				stats.addBlame("main.go", author, author, lines-lines/2)
+				stats.addBlame("main.go", author, author, lines/2)
+			}
+			ranked := rankFileOwnership(stats.files["main.go"].ownership)
+			authors := make([]string, 0, len(ranked))
+			for i, o := range ranked {
+				authors = append(authors, o.AuthorId)
+				assert.Equal(t, i+1, o.Rank)
+				assert.Equal(t, c.total, o.TotalLines)
+				assert.Equal(t, c.blames[o.AuthorId], o.Lines)
+			}
+			assert.Equal(t, c.ranked, authors)
+		})
+	}
+}
+
+func TestFileStatsSave(t *testing.T) {
+	mockDal := mockFileStatsDal(t)
+	mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil).Twice()
+	stats, err := newFileStats(mockDal, "repo", nil, time.Now())
+	assert.Nil(t, err)
+	stats.addFile("b.go")
+	stats.addFile("a.go")
+	stats.addBlame("a.go", "alice@example.com", "Alice", 1)
+	stats.addBlame("a.go", "bob@example.com", "Bob", 2)
+
+	store := &fileStatsStore{}
+	assert.Nil(t, stats.save(mockDal, store))
+	if assert.Len(t, store.churn, 2) {
+		assert.Equal(t, "a.go", store.churn[0].FilePath)
+		assert.Equal(t, "b.go", store.churn[1].FilePath)
+		assert.NotEqual(t, store.churn[0].Id, store.churn[1].Id)
+	}
+	if assert.Len(t, store.ownership, 2) {
+		assert.Equal(t, "bob@example.com", store.ownership[0].AuthorId)
+		assert.Equal(t, 1, store.ownership[0].Rank)
+		assert.Equal(t, "alice@example.com", store.ownership[1].AuthorId)
+		assert.Equal(t, 2, store.ownership[1].Rank)
+	}
+}
+
+// commitFiles commits the files to the repo by the author at the given time
+func commitFiles(t *testing.T, dir, name, email string, when time.Time, files map[string]string) {
+	for path, content := range files {
+		assert.Nil(t, os.MkdirAll(filepath.Dir(filepath.Join(dir, path)), 0755))
+		assert.Nil(t, os.WriteFile(filepath.Join(dir, path), []byte(content), 0644))
+	}
+	gitRun(t, dir, "add", "-A")
+	cmd := exec.Command("git", "-c", "user.name="+name, "-c", "user.email="+email, "commit", "-m", "update")
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GIT_AUTHOR_DATE="+when.Format(time.RFC3339), "GIT_COMMITTER_DATE="+when.Format(time.RFC3339))
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("git commit failed: %s", output)
+	}
+}
+
+func TestGogitCollectFileStats(t *testing.T) {
+	dir := t.TempDir()
+	gitRun(t, dir, "init")
+	now := time.Now()
+	commitFiles(t, dir, "Alice", "alice@example.com", now.AddDate(0, 0, -100), map[string]string{
+		"backend/main.go": "package main\n\nfunc main() {}\n",
+		"README.md":       "# readme\n",
+	})
+	commitFiles(t, dir, "Bob", "bob@example.com", now.AddDate(0, 0, -60), map[string]string{
+		"backend/main.go": "package main\n\nfunc main() { run() }\n",
+	})
+	commitFiles(t, dir, "Alice", "alice@example.com", now.AddDate(0, 0, -10), map[string]string{
+		"backend/main.go": "package main\n\nfunc main() { run() }\n\nfunc run() {}\n",
+	})
+
+	mockDal := mockFileStatsDal(t, code.Component{RepoId: "repo", Name: "backend", PathRegex: `^backend/`})
+	mockDal.On("Delete", mock.Anything, mock.Anything).Return(nil).Twice()
+	mockCtx := mockplugin.NewSubTaskContext(t)
+	mockCtx.On("GetData").Return(&GitExtractorTaskData{Options: &GitExtractorOptions{ExcludeFileExtensions: []string{".md"}}})
+	mockCtx.On("GetDal").Return(mockDal)
+	mockCtx.On("GetContext").Return(context.Background())
+	mockCtx.On("SetProgress", 0, 1).Once()
+	mockCtx.On("IncProgress", 1).Once()
+	store := &fileStatsStore{}
+	collector, err := NewGogitRepoCollector(dir, "repo", store, unithelper.DummyLogger())
+	assert.Nil(t, err)
+
+	assert.Nil(t, collector.CollectFileStats(mockCtx))
+	if assert.Len(t, store.ownership, 2) {
+		alice, bob := store.ownership[0], store.ownership[1]
+		assert.Equal(t, "alice@example.com", alice.AuthorId)
+		assert.Equal(t, "Alice", alice.AuthorName)
+		assert.Equal(t, 4, alice.Lines)
+		assert.Equal(t, 1, alice.Rank)
+		assert.Equal(t, "bob@example.com", bob.AuthorId)
+		assert.Equal(t, 1, bob.Lines)
+		assert.Equal(t, 2, bob.Rank)
+		assert.Equal(t, 5, alice.TotalLines)
+		assert.Equal(t, "backend", alice.ComponentName)
+	}
+	if assert.Len(t, store.churn, 1) {
+		churn := store.churn[0]
+		assert.Equal(t, "backend/main.go", churn.FilePath)
+		assert.Equal(t, 1, churn.Changes30d)
+		assert.Equal(t, 2, churn.Changes90d)
+		assert.Equal(t, 2, churn.Authors90d)
+		assert.Equal(t, now.AddDate(0, 0, -10).Unix(), churn.LastChangedDate.Unix())
+	}
+}
@@ -42,4 +42,5 @@ type RepoCollector interface {
 	CollectBranches(subtaskCtx plugin.SubTaskContext) error
 	CollectCommits(subtaskCtx plugin.SubTaskContext) error
 	CollectDiffLine(subtaskCtx plugin.SubTaskContext) error
+	CollectFileStats(subtaskCtx plugin.SubTaskContext) error
 }
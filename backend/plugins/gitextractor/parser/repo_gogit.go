@@ -24,6 +24,7 @@ import (
 	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
@@ -287,9 +288,18 @@ func (r *GogitRepoCollector) CollectCommits(subtaskCtx plugin.SubTaskContext) (e
 	repo := r.repo
 	store := r.store
 
-	commitsObjectsIter, err := repo.CommitObjects()
-	if err != nil {
-		return err
+	var commitsObjectsIter object.CommitIter
+	if commitShas := subtaskCtx.GetData().(*GitExtractorTaskData).CommitShas; commitShas != nil {
+		hashes := make([]plumbing.Hash, 0, len(commitShas))
+		for _, sha := range commitShas {
+			hashes = append(hashes, plumbing.NewHash(sha))
+		}
+		commitsObjectsIter = object.NewCommitIter(repo.Storer, storer.NewEncodedObjectLookupIter(repo.Storer, plumbing.CommitObject, hashes))
+	} else {
+		commitsObjectsIter, err = repo.CommitObjects()
+		if err != nil {
+			return err
+		}
 	}
 
 	if err := commitsObjectsIter.ForEach(func(commit *object.Commit) error {
@@ -601,6 +611,102 @@ func (r *GogitRepoCollector) GetCommitList(subtaskCtx plugin.SubTaskContext) ([]
 	return commitList, nil
 }
 
+// CollectFileStats blames the files at HEAD for the file_ownership, and counts the recent commits changing them for the
+// file_churn
+func (r *GogitRepoCollector) CollectFileStats(subtaskCtx plugin.SubTaskContext) error {
+	taskOpts := subtaskCtx.GetData().(*GitExtractorTaskData).Options
+	stats, statsErr := newFileStats(subtaskCtx.GetDal(), r.id, taskOpts.ExcludeFileExtensions, time.Now())
+	if statsErr != nil {
+		return statsErr
+	}
+	head, err := r.repo.Head()
+	if err != nil {
+		return err
+	}
+	headCommit, err := r.repo.CommitObject(head.Hash())
+	if err != nil {
+		return err
+	}
+	headTree, err := headCommit.Tree()
+	if err != nil {
+		return err
+	}
+	var paths []string
+	err = headTree.Files().ForEach(func(file *object.File) error {
+		if stats.addFile(file.Name) {
+			paths = append(paths, file.Name)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	subtaskCtx.SetProgress(0, len(paths))
+	for _, path := range paths {
+		select {
+		case <-subtaskCtx.GetContext().Done():
+			return subtaskCtx.GetContext().Err()
+		default:
+		}
+		blameResult, err := gogit.Blame(headCommit, path)
+		if err != nil {
+			return err
+		}
+		for _, line := range blameResult.Lines {
+			stats.addBlame(path, line.Author, line.AuthorName, 1)
+		}
+		subtaskCtx.IncProgress(1)
+	}
+	// the commits are ordered by the committer time, so the walk stops at the first one out of the churn window
+	since := stats.churnSince()
+	commitIter, err := r.repo.Log(&gogit.LogOptions{From: head.Hash(), Order: gogit.LogOrderCommitterTime})
+	if err != nil {
+		return err
+	}
+	err = commitIter.ForEach(func(commit *object.Commit) error {
+		if commit.Committer.When.Before(since) {
+			return storer.ErrStop
+		}
+		// the changes of a merge commit are counted by the merged commits
+		if commit.NumParents() > 1 {
+			return nil
+		}
+		tree, err := commit.Tree()
+		if err != nil {
+			return err
+		}
+		var parentTree *object.Tree
+		if commit.NumParents() == 1 {
+			parent, err := commit.Parent(0)
+			if err != nil {
+				if err == plumbing.ErrObjectNotFound {
+					return nil
+				}
+				return err
+			}
+			if parentTree, err = parent.Tree(); err != nil {
+				return err
+			}
+		}
+		changes, err := object.DiffTree(parentTree, tree)
+		if err != nil {
+			return err
+		}
+		for _, change := range changes {
+			path := change.To.Name
+			if path == "" {
+				path = change.From.Name
+			}
+			stats.addChange(path, commit.Author.Email, commit.Committer.When)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return stats.save(subtaskCtx.GetDal(), r.store)
+}
+
 func (r *GogitRepoCollector) CollectDiffLine(subtaskCtx plugin.SubTaskContext) error {
 	commitList, err := r.GetCommitList(subtaskCtx)
 	if err != nil {
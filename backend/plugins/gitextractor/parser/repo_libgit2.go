@@ -26,6 +26,7 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
@@ -263,11 +264,7 @@ func (r *Libgit2RepoCollector) CollectCommits(subtaskCtx plugin.SubTaskContext)
 	for _, component := range components {
 		componentMap[component.Name] = regexp.MustCompile(component.PathRegex)
 	}
-	odb, err := errors.Convert01(r.repo.Odb())
-	if err != nil {
-		return err
-	}
-	return errors.Convert(odb.ForEach(func(id *git.Oid) error {
+	collectCommit := func(id *git.Oid) error {
 		select {
 		case <-subtaskCtx.GetContext().Done():
 			return subtaskCtx.GetContext().Err()
@@ -341,7 +338,24 @@ func (r *Libgit2RepoCollector) CollectCommits(subtaskCtx plugin.SubTaskContext)
 		}
 		subtaskCtx.IncProgress(1)
 		return nil
-	}))
+	}
+	if commitShas := subtaskCtx.GetData().(*GitExtractorTaskData).CommitShas; commitShas != nil {
+		for _, sha := range commitShas {
+			id, e := git.NewOid(sha)
+			if e != nil {
+				return errors.Convert(e)
+			}
+			if e = collectCommit(id); e != nil {
+				return errors.Convert(e)
+			}
+		}
+		return nil
+	}
+	odb, err := errors.Convert01(r.repo.Odb())
+	if err != nil {
+		return err
+	}
+	return errors.Convert(odb.ForEach(collectCommit))
 }
 
 func (r *Libgit2RepoCollector) storeParentCommits(commitSha string, commit *git.Commit) errors.Error {
@@ -676,6 +690,150 @@ func (r *Libgit2RepoCollector) CollectDiffLine(subtaskCtx plugin.SubTaskContext)
 	return nil
 }
 
+// CollectFileStats blames the files at HEAD for the file_ownership, and counts the recent commits changing them for the
+// file_churn
+func (r *Libgit2RepoCollector) CollectFileStats(subtaskCtx plugin.SubTaskContext) error {
+	taskOpts := subtaskCtx.GetData().(*GitExtractorTaskData).Options
+	stats, err := newFileStats(subtaskCtx.GetDal(), r.id, taskOpts.ExcludeFileExtensions, time.Now())
+	if err != nil {
+		return err
+	}
+	head, e := r.repo.Head()
+	if e != nil {
+		return errors.Convert(e)
+	}
+	headCommit, e := r.repo.LookupCommit(head.Target())
+	if e != nil {
+		return errors.Convert(e)
+	}
+	headTree, e := headCommit.Tree()
+	if e != nil {
+		return errors.Convert(e)
+	}
+	var paths []string
+	e = headTree.Walk(func(root string, entry *git.TreeEntry) error {
+		if entry.Type == git.ObjectBlob && stats.addFile(root+entry.Name) {
+			paths = append(paths, root+entry.Name)
+		}
+		return nil
+	})
+	if e != nil {
+		return errors.Convert(e)
+	}
+	blameOpts, e := git.DefaultBlameOptions()
+	if e != nil {
+		return errors.Convert(e)
+	}
+	blameOpts.NewestCommit = headCommit.Id()
+	subtaskCtx.SetProgress(0, len(paths))
+	for _, path := range paths {
+		select {
+		case <-subtaskCtx.GetContext().Done():
+			return subtaskCtx.GetContext().Err()
+		default:
+		}
+		if e = r.addBlame(stats, path, &blameOpts); e != nil {
+			return e
+		}
+		subtaskCtx.IncProgress(1)
+	}
+	// the commits are sorted by the committer time, so the walk stops at the first one out of the churn window
+	walk, e := r.repo.Walk()
+	if e != nil {
+		return errors.Convert(e)
+	}
+	defer walk.Free()
+	walk.Sorting(git.SortTime)
+	if e = walk.Push(headCommit.Id()); e != nil {
+		return errors.Convert(e)
+	}
+	diffOpts, err := getDiffOpts()
+	if err != nil {
+		return err
+	}
+	since := stats.churnSince()
+	var changesErr error
+	e = walk.Iterate(func(commit *git.Commit) bool {
+		if commit.Committer().When.Before(since) {
+			return false
+		}
+		// the changes of a merge commit are counted by the merged commits
+		if commit.ParentCount() > 1 {
+			return true
+		}
+		changesErr = r.addChanges(stats, commit, diffOpts)
+		return changesErr == nil
+	})
+	if e != nil {
+		return errors.Convert(e)
+	}
+	if changesErr != nil {
+		return errors.Convert(changesErr)
+	}
+	return stats.save(subtaskCtx.GetDal(), r.store)
+}
+
+func (r *Libgit2RepoCollector) addBlame(stats *fileStats, path string, opts *git.BlameOptions) error {
+	blame, err := r.repo.BlameFile(path, opts)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	defer func() {
+		_ = blame.Free()
+	}()
+	for i := 0; i < blame.HunkCount(); i++ {
+		hunk, err := blame.HunkByIndex(i)
+		if err != nil {
+			return errors.Convert(err)
+		}
+		if hunk.FinalSignature != nil {
+			stats.addBlame(path, hunk.FinalSignature.Email, hunk.FinalSignature.Name, int(hunk.LinesInHunk))
+		}
+	}
+	return nil
+}
+
+func (r *Libgit2RepoCollector) addChanges(stats *fileStats, commit *git.Commit, opts *git.DiffOptions) error {
+	tree, err := commit.Tree()
+	if err != nil {
+		return err
+	}
+	var parentTree *git.Tree
+	if commit.ParentCount() == 1 {
+		parent := commit.Parent(0)
+		// the parent is missing from a shallow clone
+		if parent == nil {
+			return nil
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return err
+		}
+	}
+	diff, err := r.repo.DiffTreeToTree(parentTree, tree, opts)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = diff.Free()
+	}()
+	numDeltas, err := diff.NumDeltas()
+	if err != nil {
+		return err
+	}
+	for i := 0; i < numDeltas; i++ {
+		delta, err := diff.Delta(i)
+		if err != nil {
+			return err
+		}
+		path := delta.NewFile.Path
+		if path == "" {
+			path = delta.OldFile.Path
+		}
+		stats.addChange(path, commit.Author().Email, commit.Committer().When)
+	}
+	return nil
+}
+
 func updateSnapshotFileBlame(currentCommit *git.Commit, deleted models.DiffLines, added models.DiffLines, lastFile string, snapshot map[string]*models.FileBlame) {
 	sort.Sort(deleted)
 	for _, line := range deleted {
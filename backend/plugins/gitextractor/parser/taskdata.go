@@ -25,7 +25,10 @@ type GitExtractorTaskData struct {
 	Options         *GitExtractorOptions
 	ParsedURL       *url.URL
 	GitRepo         RepoCollector
+	RepoCloner      RepoCloner
 	SkipAllSubtasks bool // silently skip all tasks without raising errors
+	// CommitShas are the commits to be collected, all the commits in the repo are collected if it is nil
+	CommitShas []string
 }
 
 type GitExtractorApiParams struct {
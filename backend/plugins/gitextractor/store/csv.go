@@ -86,6 +86,8 @@ type CsvStore struct {
 	commitFileComponentWriter *csvWriter
 	commitLineChangeWriter    *csvWriter
 	snapshotWriter            *csvWriter
+	fileOwnershipWriter       *csvWriter
+	fileChurnWriter           *csvWriter
 }
 
 func NewCsvStore(dir string) (*CsvStore, errors.Error) {
@@ -129,6 +131,14 @@ func NewCsvStore(dir string) (*CsvStore, errors.Error) {
 	if err != nil {
 		return nil, errors.Convert(err)
 	}
+	s.fileOwnershipWriter, err = newCsvWriter(filepath.Join(dir, "file_ownership.csv"), code.FileOwnership{})
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	s.fileChurnWriter, err = newCsvWriter(filepath.Join(dir, "file_churn.csv"), code.FileChurn{})
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
 	return s, nil
 }
 
@@ -163,6 +173,14 @@ func (c *CsvStore) RepoSnapshot(ss *code.RepoSnapshot) errors.Error {
 	return c.snapshotWriter.Write(ss)
 }
 
+func (c *CsvStore) FileOwnership(ownership *code.FileOwnership) errors.Error {
+	return c.fileOwnershipWriter.Write(ownership)
+}
+
+func (c *CsvStore) FileChurn(churn *code.FileChurn) errors.Error {
+	return c.fileChurnWriter.Write(churn)
+}
+
 func (c *CsvStore) CommitParents(pp []*code.CommitParent) errors.Error {
 	var err error
 	for _, p := range pp {
@@ -199,5 +217,11 @@ func (c *CsvStore) Close() errors.Error {
 	if c.commitLineChangeWriter != nil {
 		c.commitLineChangeWriter.Close()
 	}
+	if c.fileOwnershipWriter != nil {
+		c.fileOwnershipWriter.Close()
+	}
+	if c.fileChurnWriter != nil {
+		c.fileChurnWriter.Close()
+	}
 	return nil
 }
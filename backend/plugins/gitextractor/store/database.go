@@ -133,6 +133,24 @@ func (d *Database) RepoSnapshot(snapshotElement *code.RepoSnapshot) errors.Error
 	return batch.Add(snapshotElement)
 }
 
+func (d *Database) FileOwnership(ownership *code.FileOwnership) errors.Error {
+	batch, err := d.driver.ForType(reflect.TypeOf(ownership))
+	if err != nil {
+		return err
+	}
+	d.updateRawDataFields(&ownership.RawDataOrigin)
+	return batch.Add(ownership)
+}
+
+func (d *Database) FileChurn(churn *code.FileChurn) errors.Error {
+	batch, err := d.driver.ForType(reflect.TypeOf(churn))
+	if err != nil {
+		return err
+	}
+	d.updateRawDataFields(&churn.RawDataOrigin)
+	return batch.Add(churn)
+}
+
 func (d *Database) CommitLineChange(commitLineChange *code.CommitLineChange) errors.Error {
 	batch, err := d.driver.ForType(reflect.TypeOf(commitLineChange))
 	if err != nil {
@@ -0,0 +1,85 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"regexp"
+	"syscall"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+var cacheDirNamePattern = regexp.MustCompile(`[^\w.-]`)
+
+// CacheDir returns the dir keeping the clone of the repo between runs
+func CacheDir(cacheDir, repoId string) string {
+	return filepath.Join(cacheDir, cacheDirNamePattern.ReplaceAllString(repoId, "_"))
+}
+
+// LockCacheDir waits until no other task is using the clone in localDir, the lock is held until the returned func is called
+func LockCacheDir(ctx context.Context, localDir string) (func(), errors.Error) {
+	lockPath := localDir + ".lock"
+	for {
+		f, e := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+		if e != nil {
+			return nil, errors.Convert(e)
+		}
+		e = syscall.Flock(int(f.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+		if e == nil {
+			// the lock file might have been removed along with the clone by the previous holder
+			locked, _ := f.Stat()
+			current, e := os.Stat(lockPath)
+			if e == nil && os.SameFile(locked, current) {
+				return func() {
+					_ = syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+					_ = f.Close()
+				}, nil
+			}
+		} else if e != syscall.EWOULDBLOCK {
+			_ = f.Close()
+			return nil, errors.Default.Wrap(e, "failed to lock the cached clone")
+		}
+		_ = f.Close()
+		select {
+		case <-ctx.Done():
+			return nil, errors.Convert(ctx.Err())
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// DeleteCacheDir removes the clone of the repo kept in the cacheDir
+func DeleteCacheDir(ctx context.Context, cacheDir, repoId string) errors.Error {
+	if _, e := os.Stat(cacheDir); os.IsNotExist(e) {
+		return nil
+	}
+	localDir := CacheDir(cacheDir, repoId)
+	unlock, err := LockCacheDir(ctx, localDir)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	if e := os.RemoveAll(localDir); e != nil {
+		return errors.Convert(e)
+	}
+	return errors.Convert(os.Remove(localDir + ".lock"))
+}
@@ -0,0 +1,46 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLockCacheDir(t *testing.T) {
+	cacheDir := t.TempDir()
+	localDir := CacheDir(cacheDir, "github:GithubRepo:1:1")
+	assert.Nil(t, os.MkdirAll(localDir, 0755))
+	unlock, err := LockCacheDir(context.Background(), localDir)
+	assert.Nil(t, err)
+
+	// the clone can not be removed while it is in use
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	assert.NotNil(t, DeleteCacheDir(ctx, cacheDir, "github:GithubRepo:1:1"))
+	assert.DirExists(t, localDir)
+
+	unlock()
+	assert.Nil(t, DeleteCacheDir(context.Background(), cacheDir, "github:GithubRepo:1:1"))
+	assert.NoDirExists(t, localDir)
+	assert.NoFileExists(t, localDir+".lock")
+}
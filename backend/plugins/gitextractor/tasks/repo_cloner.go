@@ -47,14 +47,39 @@ func CloneGitRepo(subTaskCtx plugin.SubTaskContext) errors.Error {
 	var err errors.Error
 	logger := subTaskCtx.GetLogger()
 
-	// temporary dir for cloning
-	localDir, e := os.MkdirTemp("", "gitextractor")
-	if e != nil {
-		return errors.Convert(e)
+	// keep the clone in the cache dir to fetch only the new commits next time, or clone into a temporary dir
+	cacheDir := subTaskCtx.GetConfigReader().GetString("GIT_EXTRACTOR_CACHE_DIR")
+	var localDir string
+	if cacheDir != "" {
+		if e := os.MkdirAll(cacheDir, 0755); e != nil {
+			return errors.Convert(e)
+		}
+		localDir = CacheDir(cacheDir, op.RepoId)
+	} else {
+		var e error
+		localDir, e = os.MkdirTemp("", "gitextractor")
+		if e != nil {
+			return errors.Convert(e)
+		}
+	}
+
+	// the cached clone must not be fetched or removed by another task until the collection is done
+	unlock := func() {}
+	if cacheDir != "" {
+		unlock, err = LockCacheDir(subTaskCtx.GetContext(), localDir)
+		if err != nil {
+			return err
+		}
 	}
+	locked := true
+	defer func() {
+		if locked {
+			unlock()
+		}
+	}()
 
 	// clone repo
-	repoCloner, err := parser.NewGitcliCloner(subTaskCtx, localDir)
+	repoCloner, err := parser.NewGitcliCloner(subTaskCtx, localDir, cacheDir != "")
 	if err != nil {
 		return err
 	}
@@ -66,6 +91,11 @@ func CloneGitRepo(subTaskCtx plugin.SubTaskContext) errors.Error {
 		}
 		return err
 	}
+	taskData.CommitShas, err = repoCloner.ListCommitsToCollect()
+	if err != nil {
+		return err
+	}
+	// the commits are collected again after a full resync of the repo
 	if repoCloner.IsIncremental() {
 		storage.SetIncrementalMode(repoCloner.IsIncremental())
 	}
@@ -82,15 +112,20 @@ func CloneGitRepo(subTaskCtx plugin.SubTaskContext) errors.Error {
 
 	// inject clean up callback to remove the cloned dir
 	cleanup := func() {
-		_ = os.RemoveAll(localDir)
+		if cacheDir == "" {
+			_ = os.RemoveAll(localDir)
+		}
 		_ = repoCloner.CloseRepo()
+		unlock()
 	}
 	if e := repoCollector.SetCleanUp(cleanup); e != nil {
 		return errors.Convert(e)
 	}
+	locked = false
 
 	// pass the collector down to next subtask
 	taskData.GitRepo = repoCollector
+	taskData.RepoCloner = repoCloner
 	subTaskCtx.TaskContext().SetData(taskData)
 	return nil
 }
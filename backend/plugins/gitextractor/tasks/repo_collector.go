@@ -27,15 +27,22 @@ func CollectGitCommits(subTaskCtx plugin.SubTaskContext) errors.Error {
 	if subTaskCtx.TaskContext().GetData().(*parser.GitExtractorTaskData).SkipAllSubtasks {
 		return nil
 	}
+	taskData := subTaskCtx.GetData().(*parser.GitExtractorTaskData)
 	repo := getGitRepo(subTaskCtx)
-	if count, err := repo.CountCommits(subTaskCtx.GetContext()); err != nil {
+	if taskData.CommitShas != nil {
+		subTaskCtx.SetProgress(0, len(taskData.CommitShas))
+	} else if count, err := repo.CountCommits(subTaskCtx.GetContext()); err != nil {
 		subTaskCtx.GetLogger().Error(err, "unable to get commit count")
 		subTaskCtx.SetProgress(0, -1)
 		return errors.Convert(err)
 	} else {
 		subTaskCtx.SetProgress(0, count)
 	}
-	return errors.Convert(repo.CollectCommits(subTaskCtx))
+	if err := repo.CollectCommits(subTaskCtx); err != nil {
+		return errors.Convert(err)
+	}
+	taskData.RepoCloner.MarkCommitsCollected()
+	return nil
 }
 
 func CollectGitBranches(subTaskCtx plugin.SubTaskContext) errors.Error {
@@ -81,6 +88,20 @@ func CollectGitDiffLines(subTaskCtx plugin.SubTaskContext) errors.Error {
 	return nil
 }
 
+// CollectGitFileStats requires the blobs which are filtered out from the clone when the commit stat is skipped
+func CollectGitFileStats(subTaskCtx plugin.SubTaskContext) errors.Error {
+	if subTaskCtx.TaskContext().GetData().(*parser.GitExtractorTaskData).SkipAllSubtasks {
+		return nil
+	}
+	repo := getGitRepo(subTaskCtx)
+	opt := subTaskCtx.GetData().(*parser.GitExtractorTaskData).Options
+	if !*opt.SkipCommitStat {
+		return errors.Convert(repo.CollectFileStats(subTaskCtx))
+	}
+	subTaskCtx.GetLogger().Info("skip collecting file stats since the commit stat is skipped")
+	return nil
+}
+
 func getGitRepo(subTaskCtx plugin.SubTaskContext) parser.RepoCollector {
 	taskData, ok := subTaskCtx.GetData().(*parser.GitExtractorTaskData)
 	if !ok {
@@ -129,3 +150,12 @@ var CollectGitDiffLineMeta = plugin.SubTaskMeta{
 	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE},
 	Dependencies:     []*plugin.SubTaskMeta{&CloneGitRepoMeta},
 }
+
+var CollectGitFileStatsMeta = plugin.SubTaskMeta{
+	Name:             "Collect File Ownership And Churn",
+	EntryPoint:       CollectGitFileStats,
+	EnabledByDefault: false,
+	Description:      "blame the files at HEAD and count their recent changes into file_ownership and file_churn",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CODE},
+	Dependencies:     []*plugin.SubTaskMeta{&CloneGitRepoMeta},
+}
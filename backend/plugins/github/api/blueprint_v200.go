@@ -57,10 +57,18 @@ func MakeDataSourcePipelinePlanV200(
 	}
 
 	// needed for the connection to populate its access tokens
-	// if AppKey authentication method is selected
-	_, err = helper.NewApiClientFromConnection(context.TODO(), basicRes, connection)
-	if err != nil {
-		return nil, nil, err
+	// if AppKey authentication method is selected, the secret references of the access tokens are kept in the clone
+	// url instead, they are resolved by gitextractor when the task runs so the secrets are never saved in the plan
+	if connection.AuthMethod == models.AppKey {
+		resolved, err := plugin.ResolvedCopy(connection)
+		if err != nil {
+			return nil, nil, err
+		}
+		connection = resolved.(*models.GithubConnection)
+		_, err = helper.NewApiClientFromResolvedConnection(context.TODO(), basicRes, connection)
+		if err != nil {
+			return nil, nil, err
+		}
 	}
 
 	plan, err := makeDataSourcePipelinePlanV200(subtaskMetas, scopeDetails, connection)
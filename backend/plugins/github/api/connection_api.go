@@ -200,7 +200,11 @@ type GithubMultiTestConnResponse struct {
 
 // testGithubConnAccessTokenAuth only works when conn has one token
 func testGithubConnAccessTokenAuth(ctx context.Context, conn models.GithubConn) (*GitHubTestConnResult, error) {
-	apiClient, err := api.NewApiClientFromConnection(ctx, basicRes, &conn)
+	// conn is a copy, the secrets read below are resolved in place
+	if err := plugin.ResolveSecrets(&conn); err != nil {
+		return nil, err
+	}
+	apiClient, err := api.NewApiClientFromResolvedConnection(ctx, basicRes, &conn)
 	if err != nil {
 		return nil, err
 	}
@@ -270,7 +274,11 @@ func testGithubConnAccessTokenAuth(ctx context.Context, conn models.GithubConn)
 }
 
 func getInstallationsWithGithubConnAppKeyAuth(ctx context.Context, conn models.GithubConn) (*GitHubTestConnResult, error) {
-	apiClient, err := api.NewApiClientFromConnection(ctx, basicRes, &conn)
+	// conn is a copy, the secrets read below are resolved in place
+	if err := plugin.ResolveSecrets(&conn); err != nil {
+		return nil, err
+	}
+	apiClient, err := api.NewApiClientFromResolvedConnection(ctx, basicRes, &conn)
 	if err != nil {
 		return nil, err
 	}
@@ -335,6 +343,10 @@ func testExistingConnection(ctx context.Context, conn models.GithubConn) (*Githu
 			return nil, errors.Convert(err)
 		}
 	}
+	// conn is a copy, the tokens are resolved in place before they are split
+	if err := plugin.ResolveSecrets(&conn); err != nil {
+		return nil, err
+	}
 	githubApiResponse := &GithubMultiTestConnResponse{}
 	if conn.AuthMethod == models.AppKey {
 		if tokenTestResult, err := testGithubConnAppKeyAuth(ctx, conn); err != nil {
@@ -32,6 +32,7 @@ var dsHelper *api.DsHelper[models.GithubConnection, models.GithubRepo, models.Gi
 var raProxy *api.DsRemoteApiProxyHelper[models.GithubConnection]
 var raScopeList *api.DsRemoteApiScopeListHelper[models.GithubConnection, models.GithubRepo, GithubRemotePagination]
 var raScopeSearch *api.DsRemoteApiScopeSearchHelper[models.GithubConnection, models.GithubRepo]
+var webhookEventHelper *api.WebhookEventHelper
 
 func Init(br context.BasicRes, p plugin.PluginMeta) {
 	basicRes = br
@@ -53,4 +54,5 @@ func Init(br context.BasicRes, p plugin.PluginMeta) {
 	raProxy = api.NewDsRemoteApiProxyHelper[models.GithubConnection](dsHelper.ConnApi.ModelApiHelper)
 	raScopeList = api.NewDsRemoteApiScopeListHelper[models.GithubConnection, models.GithubRepo, GithubRemotePagination](raProxy, listGithubRemoteScopes)
 	raScopeSearch = api.NewDsRemoteApiScopeSearchHelper[models.GithubConnection, models.GithubRepo](raProxy, searchGithubRepos)
+	webhookEventHelper = api.NewWebhookEventHelper(br)
 }
@@ -0,0 +1,299 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/github/models"
+	"github.com/apache/incubator-devlake/plugins/github/tasks"
+	graphqlTasks "github.com/apache/incubator-devlake/plugins/github_graphql/tasks"
+)
+
+type githubWebhookEvent struct {
+	Repository *struct {
+		Id      int    `json:"id"`
+		NodeId  string `json:"node_id"`
+		Name    string `json:"name"`
+		HtmlUrl string `json:"html_url"`
+	} `json:"repository"`
+	PullRequest      json.RawMessage          `json:"pull_request"`
+	Review           json.RawMessage          `json:"review"`
+	WorkflowRun      json.RawMessage          `json:"workflow_run"`
+	WorkflowJob      json.RawMessage          `json:"workflow_job"`
+	Deployment       *githubWebhookDeployment `json:"deployment"`
+	DeploymentStatus *struct {
+		NodeId    string     `json:"node_id"`
+		State     string     `json:"state"`
+		UpdatedAt *time.Time `json:"updated_at"`
+	} `json:"deployment_status"`
+}
+
+type githubWebhookDeployment struct {
+	Id          uint            `json:"id"`
+	NodeId      string          `json:"node_id"`
+	Sha         string          `json:"sha"`
+	Ref         string          `json:"ref"`
+	Task        string          `json:"task"`
+	Payload     json.RawMessage `json:"payload"`
+	Environment string          `json:"environment"`
+	Description string          `json:"description"`
+	Url         string          `json:"url"`
+	CreatedAt   time.Time       `json:"created_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+}
+
+type githubWebhookEntity struct {
+	Id     int64  `json:"id"`
+	Number int    `json:"number"`
+	Url    string `json:"url"`
+	RunId  int64  `json:"run_id"`
+}
+
+type WebhookEventResponse struct {
+	Message string `json:"message"`
+}
+
+// PostWebhookEvent receives a webhook event of a github repository
+// @Summary receive a github webhook event
+// @Description verify the X-Hub-Signature-256 header against the webhook secret of the connection, save the payload into
+// @Description the raw table of the entity and enqueue a pipeline extracting and converting the saved rows. Supported events
+// @Description are pull_request, pull_request_review, workflow_run, workflow_job, deployment and deployment_status, the others
+// @Description are acknowledged and ignored.
+// @Tags plugins/github
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Success 202  {object} WebhookEventResponse
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/github/connections/{connectionId}/events [POST]
+func PostWebhookEvent(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection, err := dsHelper.ConnApi.FindByPk(input)
+	if err != nil {
+		return nil, err
+	}
+	if input.Request == nil {
+		return nil, errors.BadInput.New("the payload of a github webhook event must be sent as application/json")
+	}
+	if !api.VerifyHmacSha256Signature(connection.WebhookSecret, input.RawBody, input.Request.Header.Get("X-Hub-Signature-256")) {
+		return nil, errors.Unauthorized.New("invalid signature of the webhook event")
+	}
+
+	eventType := input.Request.Header.Get("X-GitHub-Event")
+	event := &githubWebhookEvent{}
+	err = errors.Convert(json.Unmarshal(input.RawBody, event))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "failed to decode the webhook event")
+	}
+	if event.Repository == nil {
+		return ignoreWebhookEvent("no repository in the %s event", eventType)
+	}
+	repo := &models.GithubRepo{}
+	err = basicRes.GetDal().First(repo, dal.Where("connection_id = ? AND github_id = ?", connection.ID, event.Repository.Id))
+	if err != nil {
+		if basicRes.GetDal().IsErrorNotFound(err) {
+			return ignoreWebhookEvent("repository %d is not a scope of the connection", event.Repository.Id)
+		}
+		return nil, err
+	}
+
+	params := tasks.GithubApiParams{
+		ConnectionId: repo.ConnectionId,
+		Name:         repo.FullName,
+	}
+	pluginName := "github"
+	var rawEvent *api.RawEvent
+	var subtaskNames []string
+	switch eventType {
+	case "pull_request":
+		pr, err := decodeWebhookEntity(event.PullRequest)
+		if err != nil {
+			return nil, err
+		}
+		rawEvent = &api.RawEvent{Table: tasks.RAW_PULL_REQUEST_TABLE, Params: params, Data: event.PullRequest, Url: pr.Url}
+		subtaskNames = []string{
+			tasks.ExtractApiPullRequestsMeta.Name,
+			tasks.ConvertPullRequestsMeta.Name,
+			tasks.ConvertPullRequestLabelsMeta.Name,
+		}
+	case "pull_request_review":
+		pr, err := decodeWebhookEntity(event.PullRequest)
+		if err != nil {
+			return nil, err
+		}
+		review, err := decodeWebhookEntity(event.Review)
+		if err != nil {
+			return nil, err
+		}
+		rawEvent = &api.RawEvent{
+			Table:  tasks.RAW_PR_REVIEW_TABLE,
+			Params: params,
+			Data:   event.Review,
+			Url:    review.Url,
+			Input:  tasks.SimplePr{Number: pr.Number, GithubId: int(pr.Id)},
+		}
+		subtaskNames = []string{
+			tasks.ExtractApiPullRequestReviewsMeta.Name,
+			tasks.ConvertPullRequestReviewsMeta.Name,
+			tasks.ConvertReviewsMeta.Name,
+		}
+	case "workflow_run":
+		run, err := decodeWebhookEntity(event.WorkflowRun)
+		if err != nil {
+			return nil, err
+		}
+		rawEvent = &api.RawEvent{Table: tasks.RAW_RUN_TABLE, Params: params, Data: event.WorkflowRun, Url: run.Url}
+		subtaskNames = []string{
+			tasks.ExtractRunsMeta.Name,
+			tasks.ConvertRunsMeta.Name,
+		}
+	case "workflow_job":
+		job, err := decodeWebhookEntity(event.WorkflowJob)
+		if err != nil {
+			return nil, err
+		}
+		rawEvent = &api.RawEvent{
+			Table:  tasks.RAW_JOB_TABLE,
+			Params: params,
+			Data:   event.WorkflowJob,
+			Url:    job.Url,
+			Input:  tasks.SimpleGithubRun{ID: job.RunId},
+		}
+		subtaskNames = []string{
+			tasks.ExtractJobsMeta.Name,
+			tasks.ConvertJobsMeta.Name,
+		}
+	case "deployment", "deployment_status":
+		// deployments are collected by the github_graphql plugin, the payload is saved in the shape of its graphql query
+		if event.Deployment == nil {
+			return nil, errors.BadInput.New("the webhook event misses its payload")
+		}
+		deployment := toGraphqlDeployment(event)
+		data, err := errors.Convert01(json.Marshal(deployment))
+		if err != nil {
+			return nil, err
+		}
+		pluginName = "github_graphql"
+		rawEvent = &api.RawEvent{Table: graphqlTasks.RAW_DEPLOYMENT, Params: params, Data: data, Url: event.Deployment.Url}
+		subtaskNames = []string{
+			graphqlTasks.ExtractDeploymentsMeta.Name,
+			graphqlTasks.ConvertDeploymentsMeta.Name,
+		}
+	default:
+		return ignoreWebhookEvent("%s events are not supported", eventType)
+	}
+
+	rawDataIds, err := webhookEventHelper.SaveRawEvents(rawEvent)
+	if err != nil {
+		return nil, err
+	}
+	var options map[string]interface{}
+	err = api.Decode(&tasks.GithubOptions{
+		ConnectionId: repo.ConnectionId,
+		GithubId:     repo.GithubId,
+		Name:         repo.FullName,
+		FullName:     repo.FullName,
+	}, &options, nil)
+	if err != nil {
+		return nil, err
+	}
+	webhookEventHelper.RefreshScope(&api.WebhookRefresh{
+		ScopeKey:   fmt.Sprintf("github:%d:%s", repo.ConnectionId, repo.FullName),
+		Plugin:     pluginName,
+		Options:    options,
+		Subtasks:   subtaskNames,
+		RawDataIds: rawDataIds,
+	})
+	return &plugin.ApiResourceOutput{
+		Body:   WebhookEventResponse{Message: fmt.Sprintf("%s event accepted", eventType)},
+		Status: http.StatusAccepted,
+	}, nil
+}
+
+// toGraphqlDeployment maps the deployment of a webhook event to the deployment node returned by the graphql api
+func toGraphqlDeployment(event *githubWebhookEvent) *graphqlTasks.GraphqlQueryDeploymentDeployment {
+	d := event.Deployment
+	deployment := &graphqlTasks.GraphqlQueryDeploymentDeployment{
+		Task:        d.Task,
+		Id:          d.NodeId,
+		CommitOid:   d.Sha,
+		Environment: d.Environment,
+		// a deployment without any status is pending
+		State:       tasks.StatusPending,
+		DatabaseId:  d.Id,
+		Description: d.Description,
+		CreatedAt:   d.CreatedAt,
+		UpdatedAt:   d.UpdatedAt,
+	}
+	if len(d.Payload) > 0 && string(d.Payload) != "null" {
+		deployment.Payload = string(d.Payload)
+	}
+	if d.Ref != "" {
+		deployment.Ref = &struct {
+			ID     string `graphql:"id"`
+			Name   string `graphql:"name"`
+			Prefix string `graphql:"prefix"`
+		}{Name: d.Ref}
+	}
+	deployment.Repository.Id = event.Repository.NodeId
+	deployment.Repository.Name = event.Repository.Name
+	deployment.Repository.Url = event.Repository.HtmlUrl
+	deployment.Commit.Oid = d.Sha
+	if status := event.DeploymentStatus; status != nil {
+		deployment.LatestStatus.Id = status.NodeId
+		deployment.LatestStatus.State = strings.ToUpper(status.State)
+		deployment.LatestStatus.UpdatedAt = status.UpdatedAt
+		// the state of a deployment is ACTIVE once it has succeeded, otherwise it follows its latest status
+		deployment.State = deployment.LatestStatus.State
+		if deployment.State == tasks.StatusSuccess {
+			deployment.State = tasks.StatusActive
+		}
+		if status.UpdatedAt != nil {
+			deployment.UpdatedAt = *status.UpdatedAt
+		}
+	}
+	return deployment
+}
+
+func decodeWebhookEntity(raw json.RawMessage) (*githubWebhookEntity, errors.Error) {
+	if len(raw) == 0 {
+		return nil, errors.BadInput.New("the webhook event misses its payload")
+	}
+	entity := &githubWebhookEntity{}
+	err := errors.Convert(json.Unmarshal(raw, entity))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "failed to decode the payload of the webhook event")
+	}
+	return entity, nil
+}
+
+func ignoreWebhookEvent(format string, a ...interface{}) (*plugin.ApiResourceOutput, errors.Error) {
+	return &plugin.ApiResourceOutput{
+		Body:   WebhookEventResponse{Message: fmt.Sprintf("ignored: "+format, a...)},
+		Status: http.StatusAccepted,
+	}, nil
+}
@@ -138,6 +138,12 @@ func (p Github) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]i
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "unable to get github connection by the given connection ID")
 	}
+	// the secret references are resolved on a copy, the connection must never be saved afterward
+	resolved, err := plugin.ResolvedCopy(connection)
+	if err != nil {
+		return nil, err
+	}
+	connection = resolved.(*models.GithubConnection)
 	apiClient, err := tasks.CreateApiClient(taskCtx, connection)
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "unable to get github API client instance")
@@ -192,6 +198,9 @@ func (p Github) ApiResources() map[string]map[string]plugin.ApiResourceHandler {
 		"connections/:connectionId/test": {
 			"POST": api.TestExistingConnection,
 		},
+		"connections/:connectionId/events": {
+			"POST": api.PostWebhookEvent,
+		},
 		"connections/:connectionId/scopes/:scopeId": {
 			"GET":    api.GetScope,
 			"PATCH":  api.PatchScope,
@@ -256,6 +265,12 @@ func (p Github) GetDynamicGitUrl(taskCtx plugin.TaskContext, connectionId uint64
 	if err != nil {
 		return "", errors.Default.Wrap(err, "unable to get github connection by the given connection ID")
 	}
+	// the token embedded into the url is resolved from a copy, so the secret reference is never replaced in the database
+	resolved, err := plugin.ResolvedCopy(connection)
+	if err != nil {
+		return "", err
+	}
+	connection = resolved.(*models.GithubConnection)
 
 	apiClient, err := helper.NewApiClient(taskCtx.GetContext(), connection.GetEndpoint(), nil, 0, connection.GetProxy(), taskCtx)
 	if err != nil {
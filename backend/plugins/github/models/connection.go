@@ -57,7 +57,7 @@ type GithubConn struct {
 	helper.MultiAuth      `mapstructure:",squash"`
 	GithubAccessToken     `mapstructure:",squash" authMethod:"AccessToken"`
 	GithubAppKey          `mapstructure:",squash" authMethod:"AppKey"`
-	RefreshToken          string     `mapstructure:"refreshToken" json:"refreshToken" gorm:"type:text;serializer:encdec"`
+	RefreshToken          string     `mapstructure:"refreshToken" json:"refreshToken" gorm:"type:text;serializer:encdec" encrypt:"yes"`
 	TokenExpiresAt        *time.Time `mapstructure:"tokenExpiresAt" json:"tokenExpiresAt"`
 	RefreshTokenExpiresAt *time.Time `mapstructure:"refreshTokenExpiresAt" json:"refreshTokenExpiresAt"`
 }
@@ -114,7 +114,8 @@ func (gat *GithubAccessToken) GetTokensCount() int {
 type GithubConnection struct {
 	helper.BaseConnection `mapstructure:",squash"`
 	GithubConn            `mapstructure:",squash"`
-	EnableGraphql         bool `mapstructure:"enableGraphql" json:"enableGraphql"`
+	EnableGraphql         bool   `mapstructure:"enableGraphql" json:"enableGraphql"`
+	WebhookSecret         string `mapstructure:"webhookSecret" json:"webhookSecret" gorm:"type:text;serializer:encdec"`
 }
 
 const (
@@ -182,6 +183,11 @@ func (connection *GithubConnection) Merge(existed, modified *GithubConnection, b
 		}
 	}
 
+	// handle webhook secret, keep it unchanged if the sanitized one was sent back
+	if _, ok := body["webhookSecret"]; ok && modified.WebhookSecret != existed.SanitizeWebhookSecret() {
+		existed.WebhookSecret = modified.WebhookSecret
+	}
+
 	// handle tokens
 	existedTokens := strings.Split(strings.TrimSpace(existedTokenStr), ",")
 	existedTokenMap := make(map[string]string)          // {originalToken:sanitizedToken}
@@ -302,9 +308,14 @@ func (conn *GithubConn) typeIs(token string) string {
 
 func (connection GithubConnection) Sanitize() GithubConnection {
 	connection.GithubConn = connection.GithubConn.Sanitize()
+	connection.WebhookSecret = connection.SanitizeWebhookSecret()
 	return connection
 }
 
+func (connection *GithubConnection) SanitizeWebhookSecret() string {
+	return utils.SanitizeString(connection.WebhookSecret)
+}
+
 func (conn *GithubConn) Sanitize() GithubConn {
 	conn.SanitizeTokens()
 	conn.SanitizeSecret()
@@ -29,8 +29,10 @@ import (
 	"github.com/apache/incubator-devlake/plugins/github/token"
 )
 
+// CreateApiClient creates the ApiAsyncClient of the connection, which must be returned by plugin.ResolvedCopy since it
+// is prepared in place, i.e. the installation token of a GitHub App is set into the Token.
 func CreateApiClient(taskCtx plugin.TaskContext, connection *models.GithubConnection) (*api.ApiAsyncClient, errors.Error) {
-	apiClient, err := api.NewApiClientFromConnection(taskCtx.GetContext(), taskCtx, connection)
+	apiClient, err := api.NewApiClientFromResolvedConnection(taskCtx.GetContext(), taskCtx, connection)
 	if err != nil {
 		return nil, err
 	}
@@ -35,6 +35,8 @@ type GithubOptions struct {
 	Name          string                    `json:"name"  mapstructure:"name,omitempty"`
 	FullName      string                    `json:"fullName"  mapstructure:"fullName,omitempty"`
 	ScopeConfig   *models.GithubScopeConfig `mapstructure:"scopeConfig,omitempty" json:"scopeConfig"`
+	// RawDataIds limits the extractors to the given rows of the raw tables, it is set by the webhook pipelines
+	RawDataIds map[string][]uint64 `json:"rawDataIds,omitempty" mapstructure:"rawDataIds,omitempty"`
 }
 
 type GithubTaskData struct {
@@ -44,6 +46,15 @@ type GithubTaskData struct {
 	RegexEnricher *helper.RegexEnricher
 }
 
+// GetRawDataIds returns the rows of the raw table the extractors are limited to
+func (d *GithubTaskData) GetRawDataIds(table string) ([]uint64, bool) {
+	if d.Options == nil || d.Options.RawDataIds == nil {
+		return nil, false
+	}
+	ids, ok := d.Options.RawDataIds[table]
+	return ids, ok
+}
+
 // TODO: avoid touching too many files, should be removed in the future
 type GithubApiParams models.GithubApiParams
 
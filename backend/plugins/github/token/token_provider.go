@@ -32,6 +32,7 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/plugins/github/models"
 )
 
@@ -159,20 +160,37 @@ func (tp *TokenProvider) refreshToken() errors.Error {
 	)
 
 	if tp.dal != nil {
-		err := tp.dal.UpdateColumns(tp.conn, []dal.DalSet{
-			{ColumnName: "token", Value: tp.conn.Token},
-			{ColumnName: "refresh_token", Value: tp.conn.RefreshToken},
-			{ColumnName: "token_expires_at", Value: tp.conn.TokenExpiresAt},
-			{ColumnName: "refresh_token_expires_at", Value: tp.conn.RefreshTokenExpiresAt},
-		})
-		if err != nil {
-			tp.logger.Warn(err, "failed to persist refreshed token")
-		}
+		tp.persistToken()
 	}
 
 	return nil
 }
 
+// persistToken saves the refreshed token, the columns holding secret references are left untouched since the
+// tokens only live in memory in that case
+func (tp *TokenProvider) persistToken() {
+	stored := &models.GithubConnection{}
+	err := tp.dal.First(stored, dal.Where("id = ?", tp.conn.ID))
+	if err != nil {
+		tp.logger.Warn(err, "failed to load the connection to persist refreshed token")
+		return
+	}
+	sets := []dal.DalSet{
+		{ColumnName: "token_expires_at", Value: tp.conn.TokenExpiresAt},
+		{ColumnName: "refresh_token_expires_at", Value: tp.conn.RefreshTokenExpiresAt},
+	}
+	if _, _, ok := plugin.ParseSecretReference(stored.Token); !ok {
+		sets = append(sets, dal.DalSet{ColumnName: "token", Value: tp.conn.Token})
+	}
+	if _, _, ok := plugin.ParseSecretReference(stored.RefreshToken); !ok {
+		sets = append(sets, dal.DalSet{ColumnName: "refresh_token", Value: tp.conn.RefreshToken})
+	}
+	err = tp.dal.UpdateColumns(&models.GithubConnection{}, sets, dal.Where("id = ?", tp.conn.ID))
+	if err != nil {
+		tp.logger.Warn(err, "failed to persist refreshed token")
+	}
+}
+
 // ForceRefresh refreshes the access token if the current token is still equal to oldToken.
 // The oldToken parameter should be the token value observed by the caller when it determined
 // that a refresh might be needed; if the token has changed since then, another goroutine has
@@ -26,7 +26,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/impls/logruslog"
 	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
@@ -177,6 +179,7 @@ func TestPersistenceFailure(t *testing.T) {
 	mockRT.On("RoundTrip", mock.Anything).Return(resp, nil).Once()
 
 	// Mock DAL failure
+	mockDal.On("First", mock.Anything, mock.Anything).Return(nil)
 	mockDal.On("UpdateColumns", mock.Anything, mock.Anything, mock.AnythingOfType("[]dal.Clause")).Return(errors.Default.New("db error"))
 	err := tp.ForceRefresh("old_token")
 	assert.NoError(t, err) // Should not return error even if persistence fails
@@ -184,3 +187,67 @@ func TestPersistenceFailure(t *testing.T) {
 	mockRT.AssertExpectations(t)
 	mockDal.AssertExpectations(t)
 }
+
+type mockSecretProvider struct{}
+
+func (p *mockSecretProvider) Scheme() string {
+	return "mock"
+}
+
+func (p *mockSecretProvider) GetSecret(path string) (string, errors.Error) {
+	return path, nil
+}
+
+func TestPersistenceKeepsSecretReferences(t *testing.T) {
+	plugin.RegisterSecretProvider(&mockSecretProvider{})
+	mockRT := new(MockRoundTripper)
+	client := &http.Client{Transport: mockRT}
+	mockDal := new(mockdal.Dal)
+
+	// the connection is a resolved copy, the refresh token is stored as a reference
+	conn := &models.GithubConnection{
+		GithubConn: models.GithubConn{
+			RefreshToken: "refresh_token",
+			GithubAccessToken: models.GithubAccessToken{
+				AccessToken: api.AccessToken{
+					Token: "old_token",
+				},
+			},
+		},
+	}
+	conn.ID = 1
+
+	logger, _ := logruslog.NewDefaultLogger(logrus.New())
+	tp := NewTokenProvider(conn, mockDal, client, logger)
+
+	respBody := `{"access_token":"new_token","refresh_token":"new_refresh_token","expires_in":3600,"refresh_token_expires_in":3600}`
+	resp := &http.Response{
+		StatusCode: 200,
+		Body:       io.NopCloser(bytes.NewBufferString(respBody)),
+	}
+	mockRT.On("RoundTrip", mock.Anything).Return(resp, nil).Once()
+
+	mockDal.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored := args.Get(0).(*models.GithubConnection)
+		stored.Token = "old_token"
+		stored.RefreshToken = "mock://refresh_token"
+	}).Return(nil)
+	var columns []string
+	mockDal.On("UpdateColumns", mock.Anything, mock.Anything, mock.AnythingOfType("[]dal.Clause")).Run(func(args mock.Arguments) {
+		for _, set := range args.Get(1).([]dal.DalSet) {
+			columns = append(columns, set.ColumnName)
+		}
+	}).Return(nil)
+
+	err := tp.ForceRefresh("old_token")
+	assert.NoError(t, err)
+	assert.ElementsMatch(t, []string{"token_expires_at", "refresh_token_expires_at", "token"}, columns)
+	// the refreshed tokens are still used in memory
+	assert.Equal(t, "new_refresh_token", conn.RefreshToken)
+	token, err := tp.GetToken()
+	assert.NoError(t, err)
+	assert.Equal(t, "new_token", token)
+
+	mockRT.AssertExpectations(t)
+	mockDal.AssertExpectations(t)
+}
@@ -169,6 +169,12 @@ func (p GithubGraphql) PrepareTaskData(taskCtx plugin.TaskContext, options map[s
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "unable to get github connection by the given connection ID: %v")
 	}
+	// the secret references are resolved on a copy, the connection must never be saved afterward
+	resolved, err := plugin.ResolvedCopy(connection)
+	if err != nil {
+		return nil, err
+	}
+	connection = resolved.(*models.GithubConnection)
 
 	apiClient, err := githubTasks.CreateApiClient(taskCtx, connection)
 	if err != nil {
@@ -18,6 +18,7 @@ limitations under the License.
 package api
 
 import (
+	"net/url"
 	"testing"
 
 	coreModels "github.com/apache/incubator-devlake/core/models"
@@ -188,3 +189,39 @@ func TestMakeDataSourcePipelinePlanV200(t *testing.T) {
 
 	assert.Equal(t, expectPlans, actualPlans)
 }
+
+func TestMakeDataSourcePipelinePlanV200KeepsSecretReference(t *testing.T) {
+	mockGitlabPlugin(t)
+
+	const reference = "env://DEVLAKE_SECRET_GITLAB_TOKEN"
+	t.Setenv("DEVLAKE_SECRET_GITLAB_TOKEN", "nddtf")
+	plans, err := makePipelinePlanV200(
+		[]plugin.SubTaskMeta{tasks.ConvertProjectMeta},
+		&models.GitlabConnection{
+			BaseConnection: api.BaseConnection{Model: common.Model{ID: 1}},
+			GitlabConn: models.GitlabConn{
+				AccessToken: api.AccessToken{Token: reference},
+			},
+		},
+		[]*srvhelper.ScopeDetail[models.GitlabProject, models.GitlabScopeConfig]{
+			{
+				Scope: models.GitlabProject{
+					GitlabId:      37,
+					HttpUrlToRepo: "https://this_is_cloneUrl",
+					Scope:         common.Scope{ConnectionId: 1},
+				},
+				ScopeConfig: &models.GitlabScopeConfig{},
+			},
+		},
+	)
+	assert.Nil(t, err)
+
+	// the plan is saved, so the reference is left for gitextractor to resolve it when the task runs
+	gitextractor := plans[0][1]
+	assert.Equal(t, "gitextractor", gitextractor.Plugin)
+	cloneUrl, e := url.Parse(gitextractor.Options["url"].(string))
+	assert.Nil(t, e)
+	password, _ := cloneUrl.User.Password()
+	assert.Equal(t, reference, password)
+	assert.NotContains(t, gitextractor.Options["url"], "nddtf")
+}
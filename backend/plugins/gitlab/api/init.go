@@ -34,6 +34,7 @@ var dsHelper *api.DsHelper[models.GitlabConnection, models.GitlabProject, models
 var raProxy *api.DsRemoteApiProxyHelper[models.GitlabConnection]
 var raScopeList *api.DsRemoteApiScopeListHelper[models.GitlabConnection, models.GitlabProject, GitlabRemotePagination]
 var raScopeSearch *api.DsRemoteApiScopeSearchHelper[models.GitlabConnection, models.GitlabProject]
+var webhookEventHelper *api.WebhookEventHelper
 
 func Init(br context.BasicRes, p plugin.PluginMeta) {
 	vld = validator.New()
@@ -54,4 +55,5 @@ func Init(br context.BasicRes, p plugin.PluginMeta) {
 	raProxy = api.NewDsRemoteApiProxyHelper[models.GitlabConnection](dsHelper.ConnApi.ModelApiHelper)
 	raScopeList = api.NewDsRemoteApiScopeListHelper[models.GitlabConnection, models.GitlabProject, GitlabRemotePagination](raProxy, listGitlabRemoteScopes)
 	raScopeSearch = api.NewDsRemoteApiScopeSearchHelper[models.GitlabConnection, models.GitlabProject](raProxy, searchGitlabScopes)
+	webhookEventHelper = api.NewWebhookEventHelper(br)
 }
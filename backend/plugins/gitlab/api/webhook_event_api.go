@@ -0,0 +1,250 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/gitlab/models"
+	"github.com/apache/incubator-devlake/plugins/gitlab/tasks"
+)
+
+type gitlabWebhookEvent struct {
+	ProjectId int `json:"project_id"`
+	Project   *struct {
+		Id int `json:"id"`
+	} `json:"project"`
+	ObjectAttributes *struct {
+		Id           int    `json:"id"`
+		Iid          int    `json:"iid"`
+		NoteableType string `json:"noteable_type"`
+	} `json:"object_attributes"`
+	MergeRequest *struct {
+		Id  int `json:"id"`
+		Iid int `json:"iid"`
+	} `json:"merge_request"`
+	BuildId      int `json:"build_id"`
+	DeploymentId int `json:"deployment_id"`
+}
+
+type WebhookEventResponse struct {
+	Message string `json:"message"`
+}
+
+// PostWebhookEvent receives a webhook event of a gitlab project
+// @Summary receive a gitlab webhook event
+// @Description verify the X-Gitlab-Token header against the webhook secret of the connection, fetch the referenced
+// @Description resource from the gitlab api, save it into the raw table of the entity and enqueue a pipeline extracting
+// @Description and converting the saved rows. Supported events are Merge Request Hook, Note Hook (on merge requests), Pipeline Hook,
+// @Description Job Hook and Deployment Hook, the others are acknowledged and ignored.
+// @Tags plugins/gitlab
+// @Accept application/json
+// @Param connectionId path int true "connection ID"
+// @Success 202  {object} WebhookEventResponse
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 401  {string} errcode.Error "Unauthorized"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /plugins/gitlab/connections/{connectionId}/events [POST]
+func PostWebhookEvent(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	connection, err := dsHelper.ConnApi.FindByPk(input)
+	if err != nil {
+		return nil, err
+	}
+	if input.Request == nil {
+		return nil, errors.BadInput.New("the payload of a gitlab webhook event must be sent as application/json")
+	}
+	if !api.VerifyWebhookToken(connection.WebhookSecret, input.Request.Header.Get("X-Gitlab-Token")) {
+		return nil, errors.Unauthorized.New("invalid token of the webhook event")
+	}
+
+	eventType := input.Request.Header.Get("X-Gitlab-Event")
+	event := &gitlabWebhookEvent{}
+	err = errors.Convert(json.Unmarshal(input.RawBody, event))
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "failed to decode the webhook event")
+	}
+	projectId := event.ProjectId
+	if event.Project != nil {
+		projectId = event.Project.Id
+	}
+	if projectId == 0 {
+		return ignoreWebhookEvent("no project in the %s event", eventType)
+	}
+	project := &models.GitlabProject{}
+	err = basicRes.GetDal().First(project, dal.Where("connection_id = ? AND gitlab_id = ?", connection.ID, projectId))
+	if err != nil {
+		if basicRes.GetDal().IsErrorNotFound(err) {
+			return ignoreWebhookEvent("project %d is not a scope of the connection", projectId)
+		}
+		return nil, err
+	}
+
+	// payloads of gitlab webhooks are not in the shape of the rest api, so the referenced resource is fetched
+	// and saved as it would have been by the collectors
+	apiClient, err := api.NewApiClientFromConnection(context.TODO(), basicRes, connection)
+	if err != nil {
+		return nil, err
+	}
+	params := models.GitlabApiParams{
+		ConnectionId: project.ConnectionId,
+		ProjectId:    project.GitlabId,
+	}
+	var rawEvents []*api.RawEvent
+	var subtaskNames []string
+	switch eventType {
+	case "Merge Request Hook":
+		if event.ObjectAttributes == nil {
+			return nil, errors.BadInput.New("the webhook event misses its object_attributes")
+		}
+		rawEvent, err := fetchRawEvent(apiClient, tasks.RAW_MERGE_REQUEST_TABLE, params, nil,
+			"projects/%d/merge_requests/%d", project.GitlabId, event.ObjectAttributes.Iid)
+		if err != nil {
+			return nil, err
+		}
+		rawEvents = append(rawEvents, rawEvent)
+		subtaskNames = []string{
+			tasks.ExtractApiMergeRequestsMeta.Name,
+			tasks.ConvertApiMergeRequestsMeta.Name,
+			tasks.ConvertMrLabelsMeta.Name,
+			tasks.ConvertMrAssigneesMeta.Name,
+			tasks.ConvertMrReviewersMeta.Name,
+		}
+	case "Note Hook":
+		if event.ObjectAttributes == nil || event.ObjectAttributes.NoteableType != "MergeRequest" || event.MergeRequest == nil {
+			return ignoreWebhookEvent("only notes of merge requests are supported")
+		}
+		rawEvent, err := fetchRawEvent(apiClient, tasks.RAW_MERGE_REQUEST_NOTES_TABLE, params,
+			tasks.GitlabInput{GitlabId: event.MergeRequest.Id, Iid: event.MergeRequest.Iid},
+			"projects/%d/merge_requests/%d/notes/%d", project.GitlabId, event.MergeRequest.Iid, event.ObjectAttributes.Id)
+		if err != nil {
+			return nil, err
+		}
+		rawEvents = append(rawEvents, rawEvent)
+		subtaskNames = []string{
+			tasks.ExtractApiMrNotesMeta.Name,
+			tasks.ConvertMrCommentMeta.Name,
+		}
+	case "Pipeline Hook":
+		if event.ObjectAttributes == nil {
+			return nil, errors.BadInput.New("the webhook event misses its object_attributes")
+		}
+		rawEvent, err := fetchRawEvent(apiClient, tasks.RAW_PIPELINE_DETAILS_TABLE, params,
+			tasks.PipelineInput{PipelineId: event.ObjectAttributes.Id},
+			"projects/%d/pipelines/%d", project.GitlabId, event.ObjectAttributes.Id)
+		if err != nil {
+			return nil, err
+		}
+		// the detail of a pipeline is a superset of the item in the list, save it for both extractors
+		rawEvents = append(rawEvents, &api.RawEvent{Table: tasks.RAW_PIPELINE_TABLE, Params: params, Data: rawEvent.Data, Url: rawEvent.Url}, rawEvent)
+		subtaskNames = []string{
+			tasks.ExtractApiPipelinesMeta.Name,
+			tasks.ExtractApiPipelineDetailsMeta.Name,
+			tasks.ConvertDetailPipelineMeta.Name,
+			tasks.ConvertPipelineCommitMeta.Name,
+		}
+	case "Job Hook":
+		rawEvent, err := fetchRawEvent(apiClient, tasks.RAW_JOB_TABLE, params, nil,
+			"projects/%d/jobs/%d", project.GitlabId, event.BuildId)
+		if err != nil {
+			return nil, err
+		}
+		rawEvents = append(rawEvents, rawEvent)
+		subtaskNames = []string{
+			tasks.ExtractApiJobsMeta.Name,
+			tasks.ConvertJobMeta.Name,
+		}
+	case "Deployment Hook":
+		rawEvent, err := fetchRawEvent(apiClient, tasks.RAW_DEPLOYMENT, params, nil,
+			"projects/%d/deployments/%d", project.GitlabId, event.DeploymentId)
+		if err != nil {
+			return nil, err
+		}
+		rawEvents = append(rawEvents, rawEvent)
+		subtaskNames = []string{
+			tasks.ExtractDeploymentMeta.Name,
+			tasks.ConvertDeploymentMeta.Name,
+		}
+	default:
+		return ignoreWebhookEvent("%s events are not supported", eventType)
+	}
+
+	rawDataIds, err := webhookEventHelper.SaveRawEvents(rawEvents...)
+	if err != nil {
+		return nil, err
+	}
+	var options map[string]interface{}
+	err = api.Decode(&tasks.GitlabOptions{
+		ConnectionId:  project.ConnectionId,
+		ProjectId:     project.GitlabId,
+		FullName:      project.PathWithNamespace,
+		ScopeConfigId: project.ScopeConfigId,
+	}, &options, nil)
+	if err != nil {
+		return nil, err
+	}
+	webhookEventHelper.RefreshScope(&api.WebhookRefresh{
+		ScopeKey:   fmt.Sprintf("gitlab:%d:%d", project.ConnectionId, project.GitlabId),
+		Plugin:     "gitlab",
+		Options:    options,
+		Subtasks:   subtaskNames,
+		RawDataIds: rawDataIds,
+	})
+	return &plugin.ApiResourceOutput{
+		Body:   WebhookEventResponse{Message: fmt.Sprintf("%s event accepted", eventType)},
+		Status: http.StatusAccepted,
+	}, nil
+}
+
+func fetchRawEvent(
+	apiClient plugin.ApiClient,
+	table string,
+	params models.GitlabApiParams,
+	input interface{},
+	pathFormat string,
+	a ...interface{},
+) (*api.RawEvent, errors.Error) {
+	path := fmt.Sprintf(pathFormat, a...)
+	res, err := apiClient.Get(path, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, errors.HttpStatus(res.StatusCode).New(fmt.Sprintf("unexpected status code when fetching %s", path))
+	}
+	var data json.RawMessage
+	err = api.UnmarshalResponse(res, &data)
+	if err != nil {
+		return nil, err
+	}
+	return &api.RawEvent{Table: table, Params: params, Data: data, Url: res.Request.URL.String(), Input: input}, nil
+}
+
+func ignoreWebhookEvent(format string, a ...interface{}) (*plugin.ApiResourceOutput, errors.Error) {
+	return &plugin.ApiResourceOutput{
+		Body:   WebhookEventResponse{Message: fmt.Sprintf("ignored: "+format, a...)},
+		Status: http.StatusAccepted,
+	}, nil
+}
@@ -123,6 +123,7 @@ var _ plugin.ApiConnection = (*GitlabConnection)(nil)
 type GitlabConnection struct {
 	api.BaseConnection `mapstructure:",squash"`
 	GitlabConn         `mapstructure:",squash"`
+	WebhookSecret      string `mapstructure:"webhookSecret" json:"webhookSecret" gorm:"type:text;serializer:encdec"`
 }
 
 // This object conforms to what the frontend currently expects.
@@ -149,11 +150,13 @@ func (GitlabConnection) TableName() string {
 
 func (connection GitlabConnection) Sanitize() GitlabConnection {
 	connection.GitlabConn = connection.GitlabConn.Sanitize()
+	connection.WebhookSecret = utils.SanitizeString(connection.WebhookSecret)
 	return connection
 }
 
 func (connection *GitlabConnection) MergeFromRequest(target *GitlabConnection, body map[string]interface{}) error {
 	token := target.Token
+	webhookSecret := target.WebhookSecret
 	if err := api.DecodeMapStruct(body, target, true); err != nil {
 		return err
 	}
@@ -161,5 +164,9 @@ func (connection *GitlabConnection) MergeFromRequest(target *GitlabConnection, b
 	if modifiedToken == "" || modifiedToken == utils.SanitizeString(token) {
 		target.Token = token
 	}
+	modifiedWebhookSecret := target.WebhookSecret
+	if modifiedWebhookSecret == utils.SanitizeString(webhookSecret) {
+		target.WebhookSecret = webhookSecret
+	}
 	return nil
 }
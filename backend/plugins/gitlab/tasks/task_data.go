@@ -30,6 +30,8 @@ type GitlabOptions struct {
 	ScopeConfigId   uint64                    `mapstructure:"scopeConfigId" json:"scopeConfigId"`
 	ScopeConfig     *models.GitlabScopeConfig `mapstructure:"scopeConfig" json:"scopeConfig"`
 	CollectAllUsers bool
+	// RawDataIds limits the extractors to the given rows of the raw tables, it is set by the webhook pipelines
+	RawDataIds map[string][]uint64 `mapstructure:"rawDataIds,omitempty" json:"rawDataIds,omitempty"`
 }
 
 type GitlabTaskData struct {
@@ -39,6 +41,15 @@ type GitlabTaskData struct {
 	RegexEnricher *helper.RegexEnricher
 }
 
+// GetRawDataIds returns the rows of the raw table the extractors are limited to
+func (d *GitlabTaskData) GetRawDataIds(table string) ([]uint64, bool) {
+	if d.Options == nil || d.Options.RawDataIds == nil {
+		return nil, false
+	}
+	ids, ok := d.Options.RawDataIds[table]
+	return ids, ok
+}
+
 func DecodeAndValidateTaskOptions(options map[string]interface{}) (*GitlabOptions, errors.Error) {
 	var op GitlabOptions
 	err := helper.Decode(options, &op, nil)
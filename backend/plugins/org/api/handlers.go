@@ -28,11 +28,15 @@ import (
 const maxMemory = 32 << 20 // 32 MB
 
 type Handlers struct {
-	store store
+	store       store
+	scimEnabled bool
 }
 
 func NewHandlers(basicRes context.BasicRes) *Handlers {
-	return &Handlers{store: NewDbStore(basicRes.GetDal(), basicRes)}
+	return &Handlers{
+		store:       NewDbStore(basicRes.GetDal(), basicRes),
+		scimEnabled: basicRes.GetConfigReader().GetBool("ORG_SCIM_ENABLED"),
+	}
 }
 
 func (h *Handlers) unmarshal(r *http.Request, items interface{}) errors.Error {
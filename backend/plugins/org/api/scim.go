@@ -0,0 +1,555 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/google/uuid"
+)
+
+const (
+	scimUserSchema   = "urn:ietf:params:scim:schemas:core:2.0:User"
+	scimGroupSchema  = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	scimListSchema   = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	scimErrorSchema  = "urn:ietf:params:scim:api:messages:2.0:Error"
+	scimConfigSchema = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	scimContentType  = "application/scim+json"
+	scimMaxResults   = 1000
+)
+
+// `userName eq "john@example.com"`, the only filter supported
+var scimFilterPattern = regexp.MustCompile(`^\s*([\w.]+)\s+eq\s+"((?:[^"\\]|\\.)*)"\s*$`)
+
+// `members[value eq "42"]`
+var scimMemberPathPattern = regexp.MustCompile(`^members\[\s*value\s+eq\s+"((?:[^"\\]|\\.)*)"\s*\]$`)
+
+type scimName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+type scimMultiValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+}
+
+type scimMeta struct {
+	ResourceType string `json:"resourceType"`
+}
+
+// scimUser is a user of SCIM 2.0, the userName is the email of the user. The user is active unless all its memberships
+// were ended, and deactivating it ends its current memberships
+type scimUser struct {
+	Schemas     []string         `json:"schemas"`
+	Id          string           `json:"id,omitempty"`
+	UserName    string           `json:"userName"`
+	DisplayName string           `json:"displayName,omitempty"`
+	Name        *scimName        `json:"name,omitempty"`
+	Emails      []scimMultiValue `json:"emails,omitempty"`
+	Active      *bool            `json:"active,omitempty"`
+	Groups      []scimMultiValue `json:"groups,omitempty"`
+	Meta        *scimMeta        `json:"meta,omitempty"`
+}
+
+// scimGroup is a team of SCIM 2.0 along with its current members
+type scimGroup struct {
+	Schemas     []string         `json:"schemas"`
+	Id          string           `json:"id,omitempty"`
+	DisplayName string           `json:"displayName"`
+	Members     []scimMultiValue `json:"members,omitempty"`
+	Meta        *scimMeta        `json:"meta,omitempty"`
+}
+
+type scimListResponse struct {
+	Schemas      []string    `json:"schemas"`
+	TotalResults int         `json:"totalResults"`
+	StartIndex   int         `json:"startIndex"`
+	ItemsPerPage int         `json:"itemsPerPage"`
+	Resources    interface{} `json:"Resources"`
+}
+
+type scimPatchRequest struct {
+	Operations []scimPatchOperation `json:"Operations"`
+}
+
+type scimPatchOperation struct {
+	Op    string          `json:"op"`
+	Path  string          `json:"path"`
+	Value json.RawMessage `json:"value"`
+}
+
+type scimError struct {
+	Schemas  []string `json:"schemas"`
+	Status   string   `json:"status"`
+	ScimType string   `json:"scimType,omitempty"`
+	Detail   string   `json:"detail"`
+}
+
+// ScimServiceProviderConfig returns the SCIM features supported
+// @Summary      Get the SCIM service provider config
+// @Description  get the SCIM 2.0 service provider config, SCIM is enabled by ORG_SCIM_ENABLED=true
+// @Tags 		 plugins/org
+// @Produce      application/scim+json
+// @Success      200
+// @Failure 404  {object} scimError "SCIM is disabled"
+// @Router       /plugins/org/scim/v2/ServiceProviderConfig [get]
+func (h *Handlers) ScimServiceProviderConfig(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		return http.StatusOK, map[string]interface{}{
+			"schemas":        []string{scimConfigSchema},
+			"patch":          map[string]bool{"supported": true},
+			"bulk":           map[string]interface{}{"supported": false, "maxOperations": 0, "maxPayloadSize": 0},
+			"filter":         map[string]interface{}{"supported": true, "maxResults": scimMaxResults},
+			"changePassword": map[string]bool{"supported": false},
+			"sort":           map[string]bool{"supported": false},
+			"etag":           map[string]bool{"supported": false},
+			"authenticationSchemes": []map[string]string{{
+				"type":        "oauthbearertoken",
+				"name":        "OAuth Bearer Token",
+				"description": "Authentication with an api key of DevLake through the /rest prefix",
+			}},
+		}, nil
+	})
+}
+
+// ScimListUsers returns the users of SCIM
+// @Summary      List SCIM users
+// @Description  list the SCIM 2.0 users, the filter supports `eq` on userName, emails.value and displayName
+// @Tags 		 plugins/org
+// @Param        filter query string false "filter"
+// @Param        startIndex query int false "1-based index of the first result"
+// @Param        count query int false "max number of results"
+// @Produce      application/scim+json
+// @Success      200
+// @Failure 400  {object} scimError "Bad Request"
+// @Router       /plugins/org/scim/v2/Users [get]
+func (h *Handlers) ScimListUsers(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		attr, value, err := parseScimFilter(input.Query.Get("filter"))
+		if err != nil {
+			return 0, nil, err
+		}
+		users, err := h.scimUsers()
+		if err != nil {
+			return 0, nil, err
+		}
+		matched := make([]*scimUser, 0)
+		for _, u := range users {
+			switch attr {
+			case "":
+			case "username", "emails.value", "emails":
+				if !strings.EqualFold(u.UserName, value) {
+					continue
+				}
+			case "displayname":
+				if u.DisplayName != value {
+					continue
+				}
+			default:
+				return 0, nil, errors.BadInput.New(fmt.Sprintf("filtering by %s is not supported", attr))
+			}
+			matched = append(matched, u)
+		}
+		return http.StatusOK, paginateScim(input, matched), nil
+	})
+}
+
+// ScimPostUser provisions a user
+// @Summary      Create a SCIM user
+// @Description  create a SCIM 2.0 user, the userName must be unique
+// @Tags 		 plugins/org
+// @Accept       application/scim+json
+// @Produce      application/scim+json
+// @Success      201
+// @Failure 400  {object} scimError "Bad Request"
+// @Failure 409  {object} scimError "Conflict"
+// @Router       /plugins/org/scim/v2/Users [post]
+func (h *Handlers) ScimPostUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		u := &scimUser{}
+		err := errors.Convert(json.Unmarshal(input.RawBody, u))
+		if err != nil {
+			return 0, nil, errors.BadInput.Wrap(err, "invalid user")
+		}
+		u.Id = uuid.New().String()
+		saved, err := h.saveScimUser(u)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusCreated, saved, nil
+	})
+}
+
+// ScimGetUser returns the user of SCIM
+// @Summary      Get a SCIM user
+// @Description  get a SCIM 2.0 user
+// @Tags 		 plugins/org
+// @Param        userId path string true "user id"
+// @Produce      application/scim+json
+// @Success      200
+// @Failure 404  {object} scimError "Not Found"
+// @Router       /plugins/org/scim/v2/Users/{userId} [get]
+func (h *Handlers) ScimGetUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		u, err := h.scimUser(input.Params["userId"])
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusOK, u, nil
+	})
+}
+
+// ScimPutUser replaces the user of SCIM
+// @Summary      Replace a SCIM user
+// @Description  replace a SCIM 2.0 user, the current memberships are ended if the user is deactivated
+// @Tags 		 plugins/org
+// @Param        userId path string true "user id"
+// @Accept       application/scim+json
+// @Produce      application/scim+json
+// @Success      200
+// @Failure 400  {object} scimError "Bad Request"
+// @Failure 404  {object} scimError "Not Found"
+// @Router       /plugins/org/scim/v2/Users/{userId} [put]
+func (h *Handlers) ScimPutUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		_, err := h.store.findUserById(input.Params["userId"])
+		if err != nil {
+			return 0, nil, err
+		}
+		u := &scimUser{}
+		err = errors.Convert(json.Unmarshal(input.RawBody, u))
+		if err != nil {
+			return 0, nil, errors.BadInput.Wrap(err, "invalid user")
+		}
+		u.Id = input.Params["userId"]
+		saved, err := h.saveScimUser(u)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusOK, saved, nil
+	})
+}
+
+// ScimPatchUser updates the user of SCIM
+// @Summary      Patch a SCIM user
+// @Description  apply the PatchOp operations to a SCIM 2.0 user, the current memberships are ended if the user is deactivated
+// @Tags 		 plugins/org
+// @Param        userId path string true "user id"
+// @Accept       application/scim+json
+// @Produce      application/scim+json
+// @Success      200
+// @Failure 400  {object} scimError "Bad Request"
+// @Failure 404  {object} scimError "Not Found"
+// @Router       /plugins/org/scim/v2/Users/{userId} [patch]
+func (h *Handlers) ScimPatchUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		u, err := h.scimUser(input.Params["userId"])
+		if err != nil {
+			return 0, nil, err
+		}
+		patch := &scimPatchRequest{}
+		err = errors.Convert(json.Unmarshal(input.RawBody, patch))
+		if err != nil {
+			return 0, nil, errors.BadInput.Wrap(err, "invalid patch")
+		}
+		u, err = patchScimUser(u, patch.Operations)
+		if err != nil {
+			return 0, nil, err
+		}
+		saved, err := h.saveScimUser(u)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusOK, saved, nil
+	})
+}
+
+// ScimDeleteUser deletes the user of SCIM
+// @Summary      Delete a SCIM user
+// @Description  delete a SCIM 2.0 user along with its account links and end its memberships today
+// @Tags 		 plugins/org
+// @Param        userId path string true "user id"
+// @Success      204
+// @Failure 404  {object} scimError "Not Found"
+// @Router       /plugins/org/scim/v2/Users/{userId} [delete]
+func (h *Handlers) ScimDeleteUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		return http.StatusNoContent, nil, h.removeUser(input.Params["userId"])
+	})
+}
+
+// ScimListGroups returns the groups of SCIM
+// @Summary      List SCIM groups
+// @Description  list the teams as SCIM 2.0 groups, the filter supports `eq` on displayName
+// @Tags 		 plugins/org
+// @Param        filter query string false "filter"
+// @Param        excludedAttributes query string false "members to exclude the members"
+// @Param        startIndex query int false "1-based index of the first result"
+// @Param        count query int false "max number of results"
+// @Produce      application/scim+json
+// @Success      200
+// @Failure 400  {object} scimError "Bad Request"
+// @Router       /plugins/org/scim/v2/Groups [get]
+func (h *Handlers) ScimListGroups(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		attr, value, err := parseScimFilter(input.Query.Get("filter"))
+		if err != nil {
+			return 0, nil, err
+		}
+		if attr != "" && attr != "displayname" {
+			return 0, nil, errors.BadInput.New(fmt.Sprintf("filtering by %s is not supported", attr))
+		}
+		groups, err := h.scimGroups()
+		if err != nil {
+			return 0, nil, err
+		}
+		excludeMembers := strings.Contains(input.Query.Get("excludedAttributes"), "members")
+		matched := make([]*scimGroup, 0)
+		for _, g := range groups {
+			if attr != "" && g.DisplayName != value {
+				continue
+			}
+			if excludeMembers {
+				g.Members = nil
+			}
+			matched = append(matched, g)
+		}
+		return http.StatusOK, paginateScim(input, matched), nil
+	})
+}
+
+// ScimPostGroup provisions a team
+// @Summary      Create a SCIM group
+// @Description  create a team by a SCIM 2.0 group, the displayName must be unique and the members start today
+// @Tags 		 plugins/org
+// @Accept       application/scim+json
+// @Produce      application/scim+json
+// @Success      201
+// @Failure 400  {object} scimError "Bad Request"
+// @Failure 409  {object} scimError "Conflict"
+// @Router       /plugins/org/scim/v2/Groups [post]
+func (h *Handlers) ScimPostGroup(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		g := &scimGroup{}
+		err := errors.Convert(json.Unmarshal(input.RawBody, g))
+		if err != nil {
+			return 0, nil, errors.BadInput.Wrap(err, "invalid group")
+		}
+		g.Id = uuid.New().String()
+		saved, err := h.saveScimGroup(g, nil)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusCreated, saved, nil
+	})
+}
+
+// ScimGetGroup returns the group of SCIM
+// @Summary      Get a SCIM group
+// @Description  get a team as a SCIM 2.0 group
+// @Tags 		 plugins/org
+// @Param        teamId path string true "team id"
+// @Produce      application/scim+json
+// @Success      200
+// @Failure 404  {object} scimError "Not Found"
+// @Router       /plugins/org/scim/v2/Groups/{teamId} [get]
+func (h *Handlers) ScimGetGroup(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		g, err := h.scimGroup(input.Params["teamId"])
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusOK, g, nil
+	})
+}
+
+// ScimPutGroup replaces the group of SCIM
+// @Summary      Replace a SCIM group
+// @Description  replace the name and the members of a team by a SCIM 2.0 group
+// @Tags 		 plugins/org
+// @Param        teamId path string true "team id"
+// @Accept       application/scim+json
+// @Produce      application/scim+json
+// @Success      200
+// @Failure 400  {object} scimError "Bad Request"
+// @Failure 404  {object} scimError "Not Found"
+// @Router       /plugins/org/scim/v2/Groups/{teamId} [put]
+func (h *Handlers) ScimPutGroup(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		t, err := h.store.findTeamById(input.Params["teamId"])
+		if err != nil {
+			return 0, nil, err
+		}
+		g := &scimGroup{}
+		err = errors.Convert(json.Unmarshal(input.RawBody, g))
+		if err != nil {
+			return 0, nil, errors.BadInput.Wrap(err, "invalid group")
+		}
+		g.Id = t.Id
+		resource := &teamResource{}
+		resource.fromDomainLayer(t)
+		saved, err := h.saveScimGroup(g, resource)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusOK, saved, nil
+	})
+}
+
+// ScimPatchGroup updates the group of SCIM
+// @Summary      Patch a SCIM group
+// @Description  apply the PatchOp operations on the displayName and the members of a team
+// @Tags 		 plugins/org
+// @Param        teamId path string true "team id"
+// @Accept       application/scim+json
+// @Produce      application/scim+json
+// @Success      200
+// @Failure 400  {object} scimError "Bad Request"
+// @Failure 404  {object} scimError "Not Found"
+// @Router       /plugins/org/scim/v2/Groups/{teamId} [patch]
+func (h *Handlers) ScimPatchGroup(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		resource, err := h.getTeamResource(input.Params["teamId"])
+		if err != nil {
+			return 0, nil, err
+		}
+		patch := &scimPatchRequest{}
+		err = errors.Convert(json.Unmarshal(input.RawBody, patch))
+		if err != nil {
+			return 0, nil, errors.BadInput.Wrap(err, "invalid patch")
+		}
+		g := &scimGroup{Id: resource.Id, DisplayName: resource.Name}
+		for _, userId := range resource.UserIds {
+			g.Members = append(g.Members, scimMultiValue{Value: userId})
+		}
+		g, err = patchScimGroup(g, patch.Operations)
+		if err != nil {
+			return 0, nil, err
+		}
+		saved, err := h.saveScimGroup(g, resource)
+		if err != nil {
+			return 0, nil, err
+		}
+		return http.StatusOK, saved, nil
+	})
+}
+
+// ScimDeleteGroup deletes the group of SCIM
+// @Summary      Delete a SCIM group
+// @Description  delete a team along with its memberships, the team must not have any sub team
+// @Tags 		 plugins/org
+// @Param        teamId path string true "team id"
+// @Success      204
+// @Failure 400  {object} scimError "Bad Request"
+// @Failure 404  {object} scimError "Not Found"
+// @Router       /plugins/org/scim/v2/Groups/{teamId} [delete]
+func (h *Handlers) ScimDeleteGroup(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.serveScim(func() (int, interface{}, errors.Error) {
+		return http.StatusNoContent, nil, h.removeTeam(input.Params["teamId"])
+	})
+}
+
+// serveScim responds in application/scim+json, the errors are responded as the SCIM errors
+func (h *Handlers) serveScim(serve func() (int, interface{}, errors.Error)) (*plugin.ApiResourceOutput, errors.Error) {
+	if !h.scimEnabled {
+		return scimOutput(http.StatusNotFound, newScimError(http.StatusNotFound, "SCIM is disabled, set ORG_SCIM_ENABLED=true to enable it"))
+	}
+	status, body, err := serve()
+	if err != nil {
+		status = err.GetType().GetHttpCode()
+		scimErr := newScimError(status, err.Error())
+		switch status {
+		case http.StatusConflict:
+			scimErr.ScimType = "uniqueness"
+		case http.StatusBadRequest:
+			scimErr.ScimType = "invalidValue"
+		}
+		body = scimErr
+	}
+	return scimOutput(status, body)
+}
+
+func scimOutput(status int, body interface{}) (*plugin.ApiResourceOutput, errors.Error) {
+	blob := []byte{}
+	if body != nil {
+		var err error
+		blob, err = json.Marshal(body)
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+	}
+	return &plugin.ApiResourceOutput{Body: blob, ContentType: scimContentType, Status: status}, nil
+}
+
+func newScimError(status int, detail string) *scimError {
+	return &scimError{Schemas: []string{scimErrorSchema}, Status: strconv.Itoa(status), Detail: detail}
+}
+
+// parseScimFilter returns the lower-cased attribute and the value of the `eq` filter
+func parseScimFilter(filter string) (string, string, errors.Error) {
+	if strings.TrimSpace(filter) == "" {
+		return "", "", nil
+	}
+	matches := scimFilterPattern.FindStringSubmatch(filter)
+	if matches == nil {
+		return "", "", errors.BadInput.New(fmt.Sprintf("filter %s is not supported, only `attribute eq \"value\"` is", filter))
+	}
+	value, err := strconv.Unquote(`"` + matches[2] + `"`)
+	if err != nil {
+		return "", "", errors.BadInput.Wrap(err, fmt.Sprintf("invalid value of filter %s", filter))
+	}
+	return strings.ToLower(matches[1]), value, nil
+}
+
+// paginateScim slices the resources by the 1-based startIndex and the count
+func paginateScim[T any](input *plugin.ApiResourceInput, resources []T) *scimListResponse {
+	startIndex, err := strconv.Atoi(input.Query.Get("startIndex"))
+	if err != nil || startIndex < 1 {
+		startIndex = 1
+	}
+	count, err := strconv.Atoi(input.Query.Get("count"))
+	if err != nil || count < 0 || count > scimMaxResults {
+		count = scimMaxResults
+	}
+	page := make([]T, 0)
+	if startIndex <= len(resources) {
+		end := startIndex - 1 + count
+		if end > len(resources) {
+			end = len(resources)
+		}
+		page = resources[startIndex-1 : end]
+	}
+	return &scimListResponse{
+		Schemas:      []string{scimListSchema},
+		TotalResults: len(resources),
+		StartIndex:   startIndex,
+		ItemsPerPage: len(page),
+		Resources:    page,
+	}
+}
@@ -0,0 +1,391 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/errors"
+)
+
+// the SCIM attributes are case-insensitive
+var scimUserAttributes = map[string]string{
+	"username":    "userName",
+	"displayname": "displayName",
+	"name":        "name",
+	"emails":      "emails",
+	"active":      "active",
+}
+
+func newScimUser(resource *userResource, teamNames map[string]string, hasHistory bool) *scimUser {
+	active := len(resource.TeamIds) > 0 || !hasHistory
+	u := &scimUser{
+		Schemas:     []string{scimUserSchema},
+		Id:          resource.Id,
+		UserName:    resource.Email,
+		DisplayName: resource.Name,
+		Name:        &scimName{Formatted: resource.Name},
+		Active:      &active,
+		Meta:        &scimMeta{ResourceType: "User"},
+	}
+	if resource.Email != "" {
+		u.Emails = []scimMultiValue{{Value: resource.Email, Type: "work", Primary: true}}
+	} else {
+		u.UserName = resource.Id
+	}
+	for _, teamId := range resource.TeamIds {
+		u.Groups = append(u.Groups, scimMultiValue{Value: teamId, Display: teamNames[teamId]})
+	}
+	return u
+}
+
+func newScimGroup(resource *teamResource) *scimGroup {
+	g := &scimGroup{
+		Schemas:     []string{scimGroupSchema},
+		Id:          resource.Id,
+		DisplayName: resource.Name,
+		Meta:        &scimMeta{ResourceType: "Group"},
+	}
+	for _, userId := range resource.UserIds {
+		g.Members = append(g.Members, scimMultiValue{Value: userId})
+	}
+	return g
+}
+
+// scimUsers maps all the users to SCIM along with their current teams
+func (h *Handlers) scimUsers() ([]*scimUser, errors.Error) {
+	users, err := h.store.findAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	teamNames, hasHistory, err := h.scimContext()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*scimUser, 0, len(users))
+	for _, u := range users {
+		resource := &userResource{Id: u.Id, Name: u.Name, Email: u.Email}
+		if u.TeamIds != "" {
+			resource.TeamIds = strings.Split(u.TeamIds, ";")
+		}
+		result = append(result, newScimUser(resource, teamNames, hasHistory[u.Id]))
+	}
+	return result, nil
+}
+
+func (h *Handlers) scimUser(userId string) (*scimUser, errors.Error) {
+	resource, err := h.getUserResource(userId)
+	if err != nil {
+		return nil, err
+	}
+	teamNames, hasHistory, err := h.scimContext()
+	if err != nil {
+		return nil, err
+	}
+	return newScimUser(resource, teamNames, hasHistory[userId]), nil
+}
+
+// scimContext returns the names of the teams, and the users who have been a member of any team
+func (h *Handlers) scimContext() (map[string]string, map[string]bool, errors.Error) {
+	teams, err := h.store.findAllTeams()
+	if err != nil {
+		return nil, nil, err
+	}
+	teamNames := make(map[string]string, len(teams))
+	for _, t := range teams {
+		teamNames[t.Id] = t.Name
+	}
+	teamUsers, err := h.store.findAllTeamUsers()
+	if err != nil {
+		return nil, nil, err
+	}
+	hasHistory := make(map[string]bool)
+	for _, tu := range teamUsers {
+		hasHistory[tu.UserId] = true
+	}
+	return teamNames, hasHistory, nil
+}
+
+// saveScimUser saves the name and the email of the user, its current memberships are ended if it is deactivated
+func (h *Handlers) saveScimUser(u *scimUser) (*scimUser, errors.Error) {
+	email := ""
+	for _, e := range u.Emails {
+		if email == "" || e.Primary {
+			email = e.Value
+		}
+	}
+	if strings.Contains(u.UserName, "@") {
+		email = u.UserName
+	}
+	if email == "" {
+		return nil, errors.BadInput.New("the userName or one of the emails must be an email")
+	}
+	name := u.DisplayName
+	if name == "" && u.Name != nil {
+		name = u.Name.Formatted
+		if name == "" {
+			name = strings.TrimSpace(u.Name.GivenName + " " + u.Name.FamilyName)
+		}
+	}
+	if name == "" {
+		name = u.UserName
+	}
+	users, err := h.store.findAllUsers()
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range users {
+		if other.Id != u.Id && strings.EqualFold(other.Email, email) {
+			return nil, errors.Conflict.New(fmt.Sprintf("user with userName %s already exists", email))
+		}
+	}
+	deactivated := u.Active != nil && !*u.Active
+	err = h.saveUser(&userResource{Id: u.Id, Name: name, Email: email}, deactivated)
+	if err != nil {
+		return nil, err
+	}
+	return h.scimUser(u.Id)
+}
+
+func (h *Handlers) scimGroups() ([]*scimGroup, errors.Error) {
+	teams, err := h.store.findAllTeams()
+	if err != nil {
+		return nil, err
+	}
+	teamUsers, err := h.store.findAllTeamUsers()
+	if err != nil {
+		return nil, err
+	}
+	now := today()
+	members := make(map[string][]string)
+	for i := range teamUsers {
+		if teamUsers[i].IsEffectiveAt(now) {
+			members[teamUsers[i].TeamId] = append(members[teamUsers[i].TeamId], teamUsers[i].UserId)
+		}
+	}
+	result := make([]*scimGroup, 0, len(teams))
+	for _, t := range teams {
+		result = append(result, newScimGroup(&teamResource{Id: t.Id, Name: t.Name, UserIds: members[t.Id]}))
+	}
+	return result, nil
+}
+
+func (h *Handlers) scimGroup(teamId string) (*scimGroup, errors.Error) {
+	resource, err := h.getTeamResource(teamId)
+	if err != nil {
+		return nil, err
+	}
+	return newScimGroup(resource), nil
+}
+
+// saveScimGroup saves the name and the members of the team, the other fields of the existing team are kept
+func (h *Handlers) saveScimGroup(g *scimGroup, existing *teamResource) (*scimGroup, errors.Error) {
+	if g.DisplayName == "" {
+		return nil, errors.BadInput.New("displayName is required")
+	}
+	teams, err := h.store.findAllTeams()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range teams {
+		if t.Id != g.Id && t.Name == g.DisplayName {
+			return nil, errors.Conflict.New(fmt.Sprintf("group with displayName %s already exists", g.DisplayName))
+		}
+	}
+	resource := existing
+	if resource == nil {
+		resource = &teamResource{Id: g.Id}
+	}
+	resource.Name = g.DisplayName
+	resource.UserIds = make([]string, 0, len(g.Members))
+	for _, member := range g.Members {
+		resource.UserIds = append(resource.UserIds, member.Value)
+	}
+	err = h.saveTeam(resource, true)
+	if err != nil {
+		return nil, err
+	}
+	return newScimGroup(resource), nil
+}
+
+// patchScimUser applies the operations on the attributes of the user
+func patchScimUser(u *scimUser, operations []scimPatchOperation) (*scimUser, errors.Error) {
+	blob, err := json.Marshal(u)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	attributes := make(map[string]interface{})
+	err = json.Unmarshal(blob, &attributes)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	for _, operation := range operations {
+		var value interface{}
+		if len(operation.Value) > 0 {
+			err = json.Unmarshal(operation.Value, &value)
+			if err != nil {
+				return nil, errors.BadInput.Wrap(err, "invalid value of the operation")
+			}
+		}
+		switch strings.ToLower(operation.Op) {
+		case "add", "replace":
+			if operation.Path != "" {
+				setScimUserAttribute(attributes, operation.Path, value)
+				continue
+			}
+			values, ok := value.(map[string]interface{})
+			if !ok {
+				return nil, errors.BadInput.New("the value of the operation without path must be an object")
+			}
+			for path, v := range values {
+				setScimUserAttribute(attributes, path, v)
+			}
+		case "remove":
+			path := strings.ToLower(strings.SplitN(operation.Path, "[", 2)[0])
+			delete(attributes, scimUserAttributes[path])
+		default:
+			return nil, errors.BadInput.New(fmt.Sprintf("operation %s is not supported", operation.Op))
+		}
+	}
+	// some IdPs send the booleans as strings
+	if active, ok := attributes["active"].(string); ok {
+		attributes["active"], _ = strconv.ParseBool(active)
+	}
+	blob, err = json.Marshal(attributes)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	patched := &scimUser{}
+	err = json.Unmarshal(blob, patched)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid user after the operations")
+	}
+	patched.Id = u.Id
+	return patched, nil
+}
+
+// setScimUserAttribute sets the attribute by the path like `active`, `name.formatted` or `emails[type eq "work"].value`,
+// the unsupported attributes are ignored
+func setScimUserAttribute(attributes map[string]interface{}, path string, value interface{}) {
+	lowerPath := strings.ToLower(path)
+	if strings.HasPrefix(lowerPath, "emails[") {
+		attributes["emails"] = []interface{}{map[string]interface{}{"value": value, "primary": true}}
+		return
+	}
+	parts := strings.SplitN(path, ".", 2)
+	attribute, ok := scimUserAttributes[strings.ToLower(parts[0])]
+	if !ok {
+		return
+	}
+	if len(parts) == 1 {
+		attributes[attribute] = value
+		return
+	}
+	nested, _ := attributes[attribute].(map[string]interface{})
+	if nested == nil {
+		nested = make(map[string]interface{})
+	}
+	nested[parts[1]] = value
+	attributes[attribute] = nested
+}
+
+// patchScimGroup applies the operations on the displayName and the members of the group
+func patchScimGroup(g *scimGroup, operations []scimPatchOperation) (*scimGroup, errors.Error) {
+	patched := *g
+	patched.Members = append([]scimMultiValue{}, g.Members...)
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			return nil, errors.BadInput.New(fmt.Sprintf("operation %s is not supported", operation.Op))
+		}
+		path := operation.Path
+		if path == "" {
+			var values struct {
+				DisplayName *string          `json:"displayName"`
+				Members     []scimMultiValue `json:"members"`
+			}
+			err := errors.Convert(json.Unmarshal(operation.Value, &values))
+			if err != nil || op == "remove" {
+				return nil, errors.BadInput.New("the value of the operation without path must be an object")
+			}
+			if values.DisplayName != nil {
+				patched.DisplayName = *values.DisplayName
+			}
+			if values.Members != nil {
+				patched.Members = patchScimMembers(patched.Members, op, values.Members)
+			}
+			continue
+		}
+		if strings.EqualFold(path, "displayName") {
+			if op == "remove" || json.Unmarshal(operation.Value, &patched.DisplayName) != nil {
+				return nil, errors.BadInput.New("displayName must be a string")
+			}
+			continue
+		}
+		if strings.EqualFold(path, "members") {
+			var members []scimMultiValue
+			if len(operation.Value) > 0 && json.Unmarshal(operation.Value, &members) != nil {
+				return nil, errors.BadInput.New("members must be an array")
+			}
+			if op == "remove" && len(operation.Value) == 0 {
+				op = "replace"
+			}
+			patched.Members = patchScimMembers(patched.Members, op, members)
+			continue
+		}
+		if matches := scimMemberPathPattern.FindStringSubmatch(path); matches != nil && op == "remove" {
+			patched.Members = patchScimMembers(patched.Members, op, []scimMultiValue{{Value: matches[1]}})
+			continue
+		}
+		return nil, errors.BadInput.New(fmt.Sprintf("%s of path %s is not supported", operation.Op, path))
+	}
+	return &patched, nil
+}
+
+func patchScimMembers(members []scimMultiValue, op string, values []scimMultiValue) []scimMultiValue {
+	if op == "replace" {
+		return append([]scimMultiValue{}, values...)
+	}
+	existing := make(map[string]bool, len(members))
+	for _, member := range members {
+		existing[member.Value] = true
+	}
+	if op == "add" {
+		for _, value := range values {
+			if !existing[value.Value] {
+				existing[value.Value] = true
+				members = append(members, value)
+			}
+		}
+		return members
+	}
+	removed := make(map[string]bool, len(values))
+	for _, value := range values {
+		removed[value.Value] = true
+	}
+	result := make([]scimMultiValue, 0, len(members))
+	for _, member := range members {
+		if !removed[member.Value] {
+			result = append(result, member)
+		}
+	}
+	return result
+}
@@ -0,0 +1,105 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"encoding/json"
+	"net/url"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseScimFilter(t *testing.T) {
+	attribute, value, err := parseScimFilter(`userName eq "john@example.com"`)
+	assert.Nil(t, err)
+	assert.Equal(t, "username", attribute)
+	assert.Equal(t, "john@example.com", value)
+
+	attribute, value, err = parseScimFilter(`displayName eq "say \"hi\""`)
+	assert.Nil(t, err)
+	assert.Equal(t, "displayname", attribute)
+	assert.Equal(t, `say "hi"`, value)
+
+	attribute, _, err = parseScimFilter("")
+	assert.Nil(t, err)
+	assert.Equal(t, "", attribute)
+
+	_, _, err = parseScimFilter(`userName sw "john"`)
+	assert.NotNil(t, err)
+}
+
+func TestPaginateScim(t *testing.T) {
+	input := &plugin.ApiResourceInput{Query: url.Values{"startIndex": {"2"}, "count": {"2"}}}
+	list := paginateScim(input, []string{"a", "b", "c", "d"})
+	assert.Equal(t, 4, list.TotalResults)
+	assert.Equal(t, 2, list.ItemsPerPage)
+	assert.Equal(t, []string{"b", "c"}, list.Resources)
+
+	input.Query = url.Values{"startIndex": {"9"}}
+	list = paginateScim(input, []string{"a"})
+	assert.Equal(t, 0, list.ItemsPerPage)
+	assert.Equal(t, []string{}, list.Resources)
+}
+
+func TestPatchScimUser(t *testing.T) {
+	active := true
+	u := &scimUser{Id: "1", UserName: "john@example.com", DisplayName: "John", Active: &active}
+	operations := []scimPatchOperation{}
+	assert.Nil(t, json.Unmarshal([]byte(`[
+		{"op": "Replace", "path": "active", "value": "False"},
+		{"op": "replace", "path": "name.givenName", "value": "Johnny"},
+		{"op": "add", "path": "emails[type eq \"work\"].value", "value": "johnny@example.com"},
+		{"op": "replace", "value": {"displayName": "Johnny", "urn:ietf:params:scim:schemas:extension:enterprise:2.0:User:department": "R&D"}}
+	]`), &operations))
+	patched, err := patchScimUser(u, operations)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", patched.Id)
+	assert.Equal(t, "john@example.com", patched.UserName)
+	assert.Equal(t, "Johnny", patched.DisplayName)
+	assert.Equal(t, "Johnny", patched.Name.GivenName)
+	assert.Equal(t, "johnny@example.com", patched.Emails[0].Value)
+	assert.False(t, *patched.Active)
+	assert.True(t, *u.Active)
+
+	_, err = patchScimUser(u, []scimPatchOperation{{Op: "move", Path: "active"}})
+	assert.NotNil(t, err)
+}
+
+func TestPatchScimGroup(t *testing.T) {
+	g := &scimGroup{Id: "t1", DisplayName: "Team", Members: []scimMultiValue{{Value: "1"}, {Value: "2"}}}
+	operations := []scimPatchOperation{}
+	assert.Nil(t, json.Unmarshal([]byte(`[
+		{"op": "add", "path": "members", "value": [{"value": "2"}, {"value": "3"}]},
+		{"op": "remove", "path": "members[value eq \"1\"]"},
+		{"op": "replace", "path": "displayName", "value": "Renamed"}
+	]`), &operations))
+	patched, err := patchScimGroup(g, operations)
+	assert.Nil(t, err)
+	assert.Equal(t, "Renamed", patched.DisplayName)
+	assert.Equal(t, []scimMultiValue{{Value: "2"}, {Value: "3"}}, patched.Members)
+	assert.Len(t, g.Members, 2)
+
+	patched, err = patchScimGroup(g, []scimPatchOperation{{Op: "Remove", Path: "members"}})
+	assert.Nil(t, err)
+	assert.Empty(t, patched.Members)
+
+	_, err = patchScimGroup(g, []scimPatchOperation{{Op: "replace", Path: "externalId", Value: json.RawMessage(`"x"`)}})
+	assert.NotNil(t, err)
+}
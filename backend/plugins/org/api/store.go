@@ -18,32 +18,63 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/helpers/dbhelper"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/org/models"
 	"reflect"
 )
 
 type store interface {
 	findAllUsers() ([]user, errors.Error)
 	findAllTeams() ([]team, errors.Error)
+	findAllTeamUsers() ([]crossdomain.TeamUser, errors.Error)
 	findAllAccounts() ([]account, errors.Error)
 	findAllUserAccounts() ([]userAccount, errors.Error)
 	findAllProjectMapping() ([]projectMapping, errors.Error)
 	deleteAll(i interface{}) errors.Error
 	save(items []interface{}) errors.Error
+	updateTeamUsers(upserts []*crossdomain.TeamUser, deletes []*crossdomain.TeamUser) errors.Error
+	findTeams(search string, limit, offset int) ([]crossdomain.Team, int64, errors.Error)
+	findTeamById(teamId string) (*crossdomain.Team, errors.Error)
+	deleteTeam(teamId string, now time.Time) errors.Error
+	replaceTeamClosures(closures []*crossdomain.TeamClosure) errors.Error
+	findUsers(search string, limit, offset int) ([]crossdomain.User, int64, errors.Error)
+	findUserById(userId string) (*crossdomain.User, errors.Error)
+	deleteUser(userId string, now time.Time) errors.Error
+	findAccountsOfUser(userId string) ([]crossdomain.Account, errors.Error)
+	findAccountById(accountId string) (*crossdomain.Account, errors.Error)
+	linkUserAccount(userId, accountId string) errors.Error
+	unlinkUserAccount(userId, accountId string) errors.Error
+	createOrUpdate(item interface{}) errors.Error
+	findUserAccountSuggestions(status, accountId string, limit, offset int) ([]userAccountSuggestion, int64, errors.Error)
+	reviewUserAccountSuggestion(accountId, userId, status string) errors.Error
+	transaction(fn func(s store) errors.Error) errors.Error
 }
 
 type dbStore struct {
-	db     dal.Dal
-	driver *helper.BatchSaveDivider
+	db       dal.Dal
+	basicRes context.BasicRes
+	driver   *helper.BatchSaveDivider
 }
 
 func NewDbStore(db dal.Dal, basicRes context.BasicRes) *dbStore {
 	driver := helper.NewBatchSaveDivider(basicRes, 1000, "", "")
-	return &dbStore{db: db, driver: driver}
+	return &dbStore{db: db, basicRes: basicRes, driver: driver}
+}
+
+// transaction runs fn with a store whose changes are committed together, or rolled back if fn fails
+func (d *dbStore) transaction(fn func(s store) errors.Error) (err errors.Error) {
+	txHelper := dbhelper.NewTxHelper(d.basicRes, &err)
+	defer txHelper.End()
+	tx := txHelper.Begin()
+	return fn(&dbStore{db: tx, basicRes: d.basicRes, driver: d.driver})
 }
 
 func (d *dbStore) findAllUsers() ([]user, errors.Error) {
@@ -69,6 +100,14 @@ func (d *dbStore) findAllTeams() ([]team, errors.Error) {
 	var t *team
 	return t.fromDomainLayer(tt), nil
 }
+func (d *dbStore) findAllTeamUsers() ([]crossdomain.TeamUser, errors.Error) {
+	var tus []crossdomain.TeamUser
+	err := d.db.All(&tus, dal.Orderby("team_id, user_id, start_date"))
+	if err != nil {
+		return nil, err
+	}
+	return tus, nil
+}
 func (d *dbStore) findAllAccounts() ([]account, errors.Error) {
 	var aa []crossdomain.Account
 	err := d.db.All(&aa)
@@ -122,3 +161,229 @@ func (d *dbStore) save(items []interface{}) errors.Error {
 	d.driver.Close()
 	return nil
 }
+
+func (d *dbStore) updateTeamUsers(upserts []*crossdomain.TeamUser, deletes []*crossdomain.TeamUser) errors.Error {
+	for _, tu := range deletes {
+		err := d.db.Delete(
+			&crossdomain.TeamUser{},
+			dal.Where("team_id = ? AND user_id = ? AND start_date = ?", tu.TeamId, tu.UserId, tu.StartDate),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	if len(upserts) == 0 {
+		return nil
+	}
+	return d.db.CreateOrUpdate(upserts)
+}
+
+func (d *dbStore) findTeams(search string, limit, offset int) ([]crossdomain.Team, int64, errors.Error) {
+	clauses := []dal.Clause{dal.From(&crossdomain.Team{})}
+	if search != "" {
+		search = "%" + search + "%"
+		clauses = append(clauses, dal.Where("id LIKE ? OR name LIKE ? OR alias LIKE ?", search, search, search))
+	}
+	count, err := d.db.Count(clauses...)
+	if err != nil {
+		return nil, 0, err
+	}
+	clauses = append(clauses, dal.Orderby("sorting_index, id"), dal.Limit(limit), dal.Offset(offset))
+	teams := make([]crossdomain.Team, 0)
+	err = d.db.All(&teams, clauses...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return teams, count, nil
+}
+
+func (d *dbStore) findTeamById(teamId string) (*crossdomain.Team, errors.Error) {
+	team := &crossdomain.Team{}
+	err := d.db.First(team, dal.Where("id = ?", teamId))
+	if err != nil {
+		if d.db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.New(fmt.Sprintf("team %s not found", teamId))
+		}
+		return nil, err
+	}
+	return team, nil
+}
+
+// deleteTeam deletes the team, its memberships end now so the metrics of its former members stay attributed to it
+func (d *dbStore) deleteTeam(teamId string, now time.Time) errors.Error {
+	err := d.endTeamUsers(dal.Where("team_id = ?", teamId), now)
+	if err != nil {
+		return err
+	}
+	return d.db.Delete(&crossdomain.Team{}, dal.Where("id = ?", teamId))
+}
+
+// endTeamUsers ends the current memberships now, the ones which have not started yet are dropped
+func (d *dbStore) endTeamUsers(where dal.Clause, now time.Time) errors.Error {
+	err := d.db.UpdateColumn(
+		&crossdomain.TeamUser{},
+		"end_date", now,
+		where,
+		dal.Where("start_date < ? AND (end_date IS NULL OR end_date > ?)", now, now),
+	)
+	if err != nil {
+		return err
+	}
+	return d.db.Delete(&crossdomain.TeamUser{}, where, dal.Where("start_date >= ?", now))
+}
+
+func (d *dbStore) replaceTeamClosures(closures []*crossdomain.TeamClosure) errors.Error {
+	err := d.deleteAll(&crossdomain.TeamClosure{})
+	if err != nil {
+		return err
+	}
+	if len(closures) == 0 {
+		return nil
+	}
+	return d.db.CreateOrUpdate(closures)
+}
+
+func (d *dbStore) findUsers(search string, limit, offset int) ([]crossdomain.User, int64, errors.Error) {
+	clauses := []dal.Clause{dal.From(&crossdomain.User{})}
+	if search != "" {
+		search = "%" + search + "%"
+		clauses = append(clauses, dal.Where("id LIKE ? OR name LIKE ? OR email LIKE ?", search, search, search))
+	}
+	count, err := d.db.Count(clauses...)
+	if err != nil {
+		return nil, 0, err
+	}
+	clauses = append(clauses, dal.Orderby("name, id"), dal.Limit(limit), dal.Offset(offset))
+	users := make([]crossdomain.User, 0)
+	err = d.db.All(&users, clauses...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return users, count, nil
+}
+
+func (d *dbStore) findUserById(userId string) (*crossdomain.User, errors.Error) {
+	user := &crossdomain.User{}
+	err := d.db.First(user, dal.Where("id = ?", userId))
+	if err != nil {
+		if d.db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.New(fmt.Sprintf("user %s not found", userId))
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// deleteUser deletes the user along with its accounts links and suggestions, its memberships end now
+func (d *dbStore) deleteUser(userId string, now time.Time) errors.Error {
+	err := d.endTeamUsers(dal.Where("user_id = ?", userId), now)
+	if err != nil {
+		return err
+	}
+	for _, entity := range []interface{}{&crossdomain.UserAccount{}, &models.UserAccountSuggestion{}} {
+		err = d.db.Delete(entity, dal.Where("user_id = ?", userId))
+		if err != nil {
+			return err
+		}
+	}
+	return d.db.Delete(&crossdomain.User{}, dal.Where("id = ?", userId))
+}
+
+func (d *dbStore) findAccountsOfUser(userId string) ([]crossdomain.Account, errors.Error) {
+	accounts := make([]crossdomain.Account, 0)
+	err := d.db.All(
+		&accounts,
+		dal.Select("a.*"),
+		dal.From("accounts a"),
+		dal.Join("JOIN user_accounts ua ON ua.account_id = a.id"),
+		dal.Where("ua.user_id = ?", userId),
+		dal.Orderby("a.id"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	return accounts, nil
+}
+
+func (d *dbStore) findAccountById(accountId string) (*crossdomain.Account, errors.Error) {
+	account := &crossdomain.Account{}
+	err := d.db.First(account, dal.Where("id = ?", accountId))
+	if err != nil {
+		if d.db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.New(fmt.Sprintf("account %s not found", accountId))
+		}
+		return nil, err
+	}
+	return account, nil
+}
+
+// linkUserAccount links the account to the user, an account belongs to one user at most so it is moved from the user
+// it was linked to before
+func (d *dbStore) linkUserAccount(userId, accountId string) errors.Error {
+	return d.db.CreateOrUpdate(&crossdomain.UserAccount{UserId: userId, AccountId: accountId})
+}
+
+func (d *dbStore) unlinkUserAccount(userId, accountId string) errors.Error {
+	return d.db.Delete(&crossdomain.UserAccount{}, dal.Where("user_id = ? AND account_id = ?", userId, accountId))
+}
+
+func (d *dbStore) createOrUpdate(item interface{}) errors.Error {
+	return d.db.CreateOrUpdate(item)
+}
+
+func (d *dbStore) findUserAccountSuggestions(status, accountId string, limit, offset int) ([]userAccountSuggestion, int64, errors.Error) {
+	clauses := []dal.Clause{
+		dal.From("_tool_org_user_account_suggestions s"),
+		dal.Join("LEFT JOIN accounts a ON a.id = s.account_id"),
+		dal.Join("LEFT JOIN users u ON u.id = s.user_id"),
+	}
+	if status != "" {
+		clauses = append(clauses, dal.Where("s.status = ?", status))
+	}
+	if accountId != "" {
+		clauses = append(clauses, dal.Where("s.account_id = ?", accountId))
+	}
+	count, err := d.db.Count(clauses...)
+	if err != nil {
+		return nil, 0, err
+	}
+	clauses = append(clauses,
+		dal.Select("s.account_id, s.user_id, s.score, s.reasons, s.status, a.full_name AS account_full_name, a.user_name AS account_user_name, a.email AS account_email, u.name AS user_name, u.email AS user_email"),
+		dal.Orderby("s.score DESC, s.account_id, s.user_id"),
+		dal.Limit(limit),
+		dal.Offset(offset),
+	)
+	suggestions := make([]userAccountSuggestion, 0)
+	err = d.db.All(&suggestions, clauses...)
+	if err != nil {
+		return nil, 0, err
+	}
+	return suggestions, count, nil
+}
+
+// reviewUserAccountSuggestion approves or rejects the suggestion, the account is linked to the user once approved and
+// the other candidates of the account are dropped
+func (d *dbStore) reviewUserAccountSuggestion(accountId, userId, status string) errors.Error {
+	suggestion := &models.UserAccountSuggestion{}
+	err := d.db.First(suggestion, dal.Where("account_id = ? AND user_id = ?", accountId, userId))
+	if err != nil {
+		if d.db.IsErrorNotFound(err) {
+			return errors.NotFound.New(fmt.Sprintf("suggestion of account %s and user %s not found", accountId, userId))
+		}
+		return err
+	}
+	if status == models.SUGGESTION_STATUS_APPROVED {
+		err = d.db.CreateOrUpdate(&crossdomain.UserAccount{UserId: userId, AccountId: accountId})
+		if err != nil {
+			return err
+		}
+		err = d.db.Delete(
+			&models.UserAccountSuggestion{},
+			dal.Where("account_id = ? AND user_id != ? AND status = ?", accountId, userId, models.SUGGESTION_STATUS_PENDING),
+		)
+		if err != nil {
+			return err
+		}
+	}
+	return d.db.UpdateColumn(suggestion, "status", status)
+}
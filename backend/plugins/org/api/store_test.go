@@ -0,0 +1,94 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	mockcontext "github.com/apache/incubator-devlake/mocks/core/context"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockStoreTransaction(t *testing.T, teams []crossdomain.Team) (*Handlers, *mockdal.Transaction) {
+	mockDal := mockdal.NewDal(t)
+	mockTx := mockdal.NewTransaction(t)
+	mockDal.On("Begin").Return(mockTx).Once()
+	mockRes := mockcontext.NewBasicRes(t)
+	mockRes.On("GetDal").Return(mockDal).Once()
+	mockRes.On("GetLogger").Return(unithelper.DummyLogger()).Once()
+	mockTx.On("First", mock.AnythingOfType("*crossdomain.Team"), mock.Anything).Return(nil).Once()
+	mockTx.On("All", mock.AnythingOfType("*[]crossdomain.Team"), mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]crossdomain.Team) = teams
+	}).Return(nil).Once()
+	mockTx.On("UnlockTables").Return(nil).Once()
+	return &Handlers{store: &dbStore{db: mockDal, basicRes: mockRes}}, mockTx
+}
+
+func whereExprs(clauses []dal.Clause) []string {
+	exprs := make([]string, 0, len(clauses))
+	for _, clause := range clauses {
+		exprs = append(exprs, clause.Data.(dal.DalClause).Expr)
+	}
+	return exprs
+}
+
+func TestRemoveTeamEndsMembershipsInTransaction(t *testing.T) {
+	h, mockTx := mockStoreTransaction(t, []crossdomain.Team{
+		{DomainEntity: domainlayer.DomainEntity{Id: "1"}, Name: "root"},
+		{DomainEntity: domainlayer.DomainEntity{Id: "2"}, Name: "other"},
+	})
+	now := today()
+	mockTx.On("UpdateColumn", mock.AnythingOfType("*crossdomain.TeamUser"), "end_date", now, mock.Anything).Run(func(args mock.Arguments) {
+		assert.Equal(t, []string{"team_id = ?", "start_date < ? AND (end_date IS NULL OR end_date > ?)"}, whereExprs(args.Get(3).([]dal.Clause)))
+	}).Return(nil).Once()
+	mockTx.On("Delete", mock.AnythingOfType("*crossdomain.TeamUser"), mock.Anything).Run(func(args mock.Arguments) {
+		// only the memberships which have not started yet are deleted
+		assert.Equal(t, []string{"team_id = ?", "start_date >= ?"}, whereExprs(args.Get(1).([]dal.Clause)))
+	}).Return(nil).Once()
+	mockTx.On("Delete", mock.AnythingOfType("*crossdomain.Team"), mock.Anything).Return(nil).Once()
+	mockTx.On("Delete", mock.AnythingOfType("*crossdomain.TeamClosure"), mock.Anything).Return(nil).Once()
+	mockTx.On("CreateOrUpdate", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		closures := args.Get(0).([]*crossdomain.TeamClosure)
+		assert.Len(t, closures, 1)
+		assert.Equal(t, "2", closures[0].AncestorId)
+	}).Return(nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, h.removeTeam("1"))
+}
+
+func TestRemoveTeamRollsBackOnError(t *testing.T) {
+	h, mockTx := mockStoreTransaction(t, []crossdomain.Team{
+		{DomainEntity: domainlayer.DomainEntity{Id: "1"}, Name: "root"},
+		{DomainEntity: domainlayer.DomainEntity{Id: "2"}, Name: "child", ParentId: "1"},
+	})
+	mockTx.On("Rollback").Return(nil).Once()
+
+	err := h.removeTeam("1")
+	assert.NotNil(t, err)
+	assert.Equal(t, errors.BadInput, err.GetType())
+	mockTx.AssertNotCalled(t, "Commit")
+	mockTx.AssertNotCalled(t, "Delete", mock.Anything, mock.Anything)
+}
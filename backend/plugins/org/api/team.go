@@ -22,6 +22,7 @@ import (
 	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"net/http"
+	"time"
 
 	"github.com/gocarina/gocsv"
 )
@@ -63,7 +64,8 @@ func (h *Handlers) GetTeam(input *plugin.ApiResourceInput) (*plugin.ApiResourceO
 	}, nil
 }
 
-// CreateTeam accepts a CSV file containing team information and saves it to the database
+// CreateTeam accepts a CSV file containing team information and saves it to the database along with the ancestors of
+// each team, the parents must exist and must not form a cycle
 // @Summary      Upload teams.csv file
 // @Description  upload teams.csv file
 // @Tags 		 plugins/org
@@ -80,17 +82,58 @@ func (h *Handlers) CreateTeam(input *plugin.ApiResourceInput) (*plugin.ApiResour
 	if err != nil {
 		return nil, err
 	}
+	err = validateTeamTree(tt)
+	if err != nil {
+		return nil, err
+	}
 	var items []interface{}
 	for _, tm := range (&team{}).toDomainLayer(tt) {
 		items = append(items, tm)
 	}
+	for _, closure := range buildTeamClosures(tt) {
+		items = append(items, closure)
+	}
 	err = h.store.deleteAll(&crossdomain.Team{})
 	if err != nil {
 		return nil, err
 	}
+	err = h.store.deleteAll(&crossdomain.TeamClosure{})
+	if err != nil {
+		return nil, err
+	}
 	err = h.store.save(items)
 	if err != nil {
 		return nil, err
 	}
 	return &plugin.ApiResourceOutput{Status: http.StatusOK}, nil
 }
+
+// GetTeamTree returns the teams nested under their parents along with the number of their members
+// @Summary      Get the tree of teams
+// @Description  get the tree of teams, the members of the sub teams are rolled up to the totalMembers of their ancestors
+// @Tags 		 plugins/org
+// @Param        at query string false "effective date of the memberships formatted as 2006-01-02, default today"
+// @Produce      json
+// @Success      200  {array} teamNode
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/teams.json [get]
+func (h *Handlers) GetTeamTree(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	at, err := parseDate(input.Query, "at")
+	if err != nil {
+		return nil, err
+	}
+	if at == nil {
+		now := time.Now()
+		at = &now
+	}
+	teams, err := h.store.findAllTeams()
+	if err != nil {
+		return nil, err
+	}
+	teamUsers, err := h.store.findAllTeamUsers()
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: buildTeamTree(teams, teamUsers, *at), Status: http.StatusOK}, nil
+}
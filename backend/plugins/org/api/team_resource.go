@@ -0,0 +1,326 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/google/uuid"
+)
+
+// teamResource is a team in json along with the ids of its current members
+type teamResource struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	Alias        string   `json:"alias"`
+	ParentId     string   `json:"parentId"`
+	SortingIndex int      `json:"sortingIndex"`
+	UserIds      []string `json:"userIds,omitempty"`
+}
+
+func (r *teamResource) fromDomainLayer(t *crossdomain.Team) {
+	r.Id = t.Id
+	r.Name = t.Name
+	r.Alias = t.Alias
+	r.ParentId = t.ParentId
+	r.SortingIndex = t.SortingIndex
+}
+
+func (r *teamResource) toDomainLayer() *crossdomain.Team {
+	return &crossdomain.Team{
+		DomainEntity: domainlayer.DomainEntity{Id: r.Id},
+		Name:         r.Name,
+		Alias:        r.Alias,
+		ParentId:     r.ParentId,
+		SortingIndex: r.SortingIndex,
+	}
+}
+
+// ListTeams returns the teams page by page
+// @Summary      List teams
+// @Description  list the teams, optionally the ones whose id, name or alias contains the search
+// @Tags 		 plugins/org
+// @Param        search query string false "search"
+// @Param        page query int false "page number, default 1"
+// @Param        pageSize query int false "page size, default 50"
+// @Produce      json
+// @Success      200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/teams [get]
+func (h *Handlers) ListTeams(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	teams, count, err := h.store.findTeams(input.Query.Get("search"), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*teamResource, 0, len(teams))
+	for i := range teams {
+		resource := &teamResource{}
+		resource.fromDomainLayer(&teams[i])
+		resources = append(resources, resource)
+	}
+	return &plugin.ApiResourceOutput{
+		Body: map[string]interface{}{
+			"teams": resources,
+			"count": count,
+		},
+		Status: http.StatusOK,
+	}, nil
+}
+
+// PostTeam creates a team
+// @Summary      Create a team
+// @Description  create a team, the id is generated if it is empty and the userIds are the members starting today
+// @Tags 		 plugins/org
+// @Accept       application/json
+// @Param        team body teamResource true "json"
+// @Produce      json
+// @Success      201  {object} teamResource
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 409  {object} shared.ApiBody "Conflict"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/teams [post]
+func (h *Handlers) PostTeam(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	resource := &teamResource{}
+	err := helper.DecodeMapStruct(input.Body, resource, false)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid team")
+	}
+	if resource.Id == "" {
+		resource.Id = uuid.New().String()
+	} else if _, err = h.store.findTeamById(resource.Id); err == nil {
+		return nil, errors.Conflict.New(fmt.Sprintf("team %s already exists", resource.Id))
+	} else if err.GetType() != errors.NotFound {
+		return nil, err
+	}
+	err = h.saveTeam(resource, input.Body["userIds"] != nil)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: resource, Status: http.StatusCreated}, nil
+}
+
+// GetTeamById returns the team along with its current members
+// @Summary      Get a team
+// @Description  get the team along with the ids of its current members
+// @Tags 		 plugins/org
+// @Param        teamId path string true "team id"
+// @Produce      json
+// @Success      200  {object} teamResource
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/teams/{teamId} [get]
+func (h *Handlers) GetTeamById(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	resource, err := h.getTeamResource(input.Params["teamId"])
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: resource, Status: http.StatusOK}, nil
+}
+
+// PatchTeam updates the given fields of the team
+// @Summary      Patch a team
+// @Description  update the given fields of the team, the members are replaced if the userIds are given
+// @Tags 		 plugins/org
+// @Accept       application/json
+// @Param        teamId path string true "team id"
+// @Param        team body teamResource true "json"
+// @Produce      json
+// @Success      200  {object} teamResource
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/teams/{teamId} [patch]
+func (h *Handlers) PatchTeam(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	resource, err := h.getTeamResource(input.Params["teamId"])
+	if err != nil {
+		return nil, err
+	}
+	err = helper.DecodeMapStruct(input.Body, resource, false)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid team")
+	}
+	resource.Id = input.Params["teamId"]
+	err = h.saveTeam(resource, input.Body["userIds"] != nil)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: resource, Status: http.StatusOK}, nil
+}
+
+// DeleteTeam deletes the team and ends its memberships
+// @Summary      Delete a team
+// @Description  delete the team and end its memberships today, the team must not have any sub team
+// @Tags 		 plugins/org
+// @Param        teamId path string true "team id"
+// @Produce      json
+// @Success      200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/teams/{teamId} [delete]
+func (h *Handlers) DeleteTeam(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	err := h.removeTeam(input.Params["teamId"])
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Status: http.StatusOK}, nil
+}
+
+func (h *Handlers) getTeamResource(teamId string) (*teamResource, errors.Error) {
+	t, err := h.store.findTeamById(teamId)
+	if err != nil {
+		return nil, err
+	}
+	resource := &teamResource{}
+	resource.fromDomainLayer(t)
+	resource.UserIds, err = currentMembers(h.store, teamId)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// saveTeam creates or updates the team as long as the tree stays valid, and rebuilds the ancestors of the teams. The
+// members are replaced by the UserIds if withMembers is true. The changes are saved in a transaction so the team,
+// its ancestors and its members never get out of sync
+func (h *Handlers) saveTeam(resource *teamResource, withMembers bool) errors.Error {
+	if withMembers {
+		err := h.checkUsersExist(resource.UserIds)
+		if err != nil {
+			return err
+		}
+	}
+	return h.store.transaction(func(s store) errors.Error {
+		teams, err := s.findAllTeams()
+		if err != nil {
+			return err
+		}
+		t := resource.toDomainLayer()
+		updated := team{Id: t.Id, Name: t.Name, Alias: t.Alias, ParentId: t.ParentId, SortingIndex: t.SortingIndex}
+		found := false
+		for i := range teams {
+			if teams[i].Id == t.Id {
+				teams[i] = updated
+				found = true
+			}
+		}
+		if !found {
+			teams = append(teams, updated)
+		}
+		err = validateTeamTree(teams)
+		if err != nil {
+			return err
+		}
+		err = s.createOrUpdate(t)
+		if err != nil {
+			return err
+		}
+		err = s.replaceTeamClosures(buildTeamClosures(teams))
+		if err != nil {
+			return err
+		}
+		if withMembers {
+			err = setTeamMembers(s, t.Id, resource.UserIds)
+			if err != nil {
+				return err
+			}
+		}
+		resource.UserIds, err = currentMembers(s, t.Id)
+		return err
+	})
+}
+
+// removeTeam deletes the team without sub teams and rebuilds the ancestors of the teams in a transaction
+func (h *Handlers) removeTeam(teamId string) errors.Error {
+	return h.store.transaction(func(s store) errors.Error {
+		_, err := s.findTeamById(teamId)
+		if err != nil {
+			return err
+		}
+		teams, err := s.findAllTeams()
+		if err != nil {
+			return err
+		}
+		remaining := make([]team, 0, len(teams))
+		for _, t := range teams {
+			if t.ParentId == teamId {
+				return errors.BadInput.New(fmt.Sprintf("team %s has sub team %s", teamId, t.Id))
+			}
+			if t.Id != teamId {
+				remaining = append(remaining, t)
+			}
+		}
+		err = s.deleteTeam(teamId, today())
+		if err != nil {
+			return err
+		}
+		return s.replaceTeamClosures(buildTeamClosures(remaining))
+	})
+}
+
+// currentMembers returns the ids of the users who are members of the team today
+func currentMembers(s store, teamId string) ([]string, errors.Error) {
+	teamUsers, err := s.findAllTeamUsers()
+	if err != nil {
+		return nil, err
+	}
+	now := today()
+	userIds := make([]string, 0)
+	for i := range teamUsers {
+		if teamUsers[i].TeamId == teamId && teamUsers[i].IsEffectiveAt(now) {
+			userIds = append(userIds, teamUsers[i].UserId)
+		}
+	}
+	return userIds, nil
+}
+
+// setTeamMembers replaces the current members of the team, the memberships of the other teams are kept
+func setTeamMembers(s store, teamId string, userIds []string) errors.Error {
+	teamUsers, err := s.findAllTeamUsers()
+	if err != nil {
+		return err
+	}
+	listed := make([]*crossdomain.TeamUser, 0, len(userIds))
+	for _, userId := range userIds {
+		listed = append(listed, &crossdomain.TeamUser{TeamId: teamId, UserId: userId})
+	}
+	return s.updateTeamUsers(diffTeamUsers(teamUsers, listed, today(), func(tu *crossdomain.TeamUser) bool {
+		return tu.TeamId == teamId
+	}))
+}
+
+func (h *Handlers) checkUsersExist(userIds []string) errors.Error {
+	for _, userId := range userIds {
+		_, err := h.store.findUserById(userId)
+		if err != nil {
+			if err.GetType() == errors.NotFound {
+				return errors.BadInput.Wrap(err, fmt.Sprintf("user %s does not exist", userId))
+			}
+			return err
+		}
+	}
+	return nil
+}
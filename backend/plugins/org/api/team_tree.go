@@ -0,0 +1,164 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+)
+
+// teamNode is a team of the tree along with its members at a given date
+type teamNode struct {
+	Id           string `json:"id"`
+	Name         string `json:"name"`
+	Alias        string `json:"alias"`
+	ParentId     string `json:"parentId"`
+	SortingIndex int    `json:"sortingIndex"`
+	// Members counts the direct members of the team
+	Members int `json:"members"`
+	// TotalMembers counts the distinct members of the team and all its descendants
+	TotalMembers int         `json:"totalMembers"`
+	Children     []*teamNode `json:"children"`
+}
+
+// validateTeamTree makes sure the ids are unique and the parents exist without any cycle
+func validateTeamTree(teams []team) errors.Error {
+	parents := make(map[string]string, len(teams))
+	for _, t := range teams {
+		if t.Id == "" {
+			return errors.BadInput.New(fmt.Sprintf("id of team %s is empty", t.Name))
+		}
+		if _, ok := parents[t.Id]; ok {
+			return errors.BadInput.New(fmt.Sprintf("team %s is duplicated", t.Id))
+		}
+		parents[t.Id] = t.ParentId
+	}
+	for _, t := range teams {
+		if _, ok := parents[t.ParentId]; t.ParentId != "" && !ok {
+			return errors.BadInput.New(fmt.Sprintf("parent %s of team %s does not exist", t.ParentId, t.Id))
+		}
+		depth := 0
+		for id := t.ParentId; id != ""; id = parents[id] {
+			if id == t.Id || depth > len(teams) {
+				return errors.BadInput.New(fmt.Sprintf("team %s is an ancestor of itself", t.Id))
+			}
+			depth++
+		}
+	}
+	return nil
+}
+
+// buildTeamClosures relates each team to itself and its ancestors, the walk stops at the missing parents and the
+// cycles of the teams which were not validated
+func buildTeamClosures(teams []team) []*crossdomain.TeamClosure {
+	parents := make(map[string]string, len(teams))
+	for _, t := range teams {
+		parents[t.Id] = t.ParentId
+	}
+	var closures []*crossdomain.TeamClosure
+	for _, t := range teams {
+		visited := make(map[string]bool)
+		for id, depth := t.Id, 0; id != "" && !visited[id]; id, depth = parents[id], depth+1 {
+			if _, ok := parents[id]; !ok {
+				break
+			}
+			visited[id] = true
+			closures = append(closures, &crossdomain.TeamClosure{AncestorId: id, DescendantId: t.Id, Depth: depth})
+		}
+	}
+	return closures
+}
+
+// buildTeamTree nests the teams under their parents and counts their members at the given date, the members of the
+// descendants are rolled up to the TotalMembers of their ancestors
+func buildTeamTree(teams []team, teamUsers []crossdomain.TeamUser, at time.Time) []*teamNode {
+	nodes := make(map[string]*teamNode, len(teams))
+	for _, t := range teams {
+		nodes[t.Id] = &teamNode{
+			Id:           t.Id,
+			Name:         t.Name,
+			Alias:        t.Alias,
+			ParentId:     t.ParentId,
+			SortingIndex: t.SortingIndex,
+			Children:     make([]*teamNode, 0),
+		}
+	}
+	parents := make(map[string]string, len(teams))
+	for _, t := range teams {
+		parents[t.Id] = t.ParentId
+	}
+	ancestors := make(map[string][]string)
+	for _, closure := range buildTeamClosures(teams) {
+		ancestors[closure.DescendantId] = append(ancestors[closure.DescendantId], closure.AncestorId)
+	}
+	members := make(map[string]map[string]bool)
+	for i := range teamUsers {
+		tu := &teamUsers[i]
+		if !tu.IsEffectiveAt(at) || nodes[tu.TeamId] == nil {
+			continue
+		}
+		nodes[tu.TeamId].Members++
+		for _, ancestorId := range ancestors[tu.TeamId] {
+			if members[ancestorId] == nil {
+				members[ancestorId] = make(map[string]bool)
+			}
+			members[ancestorId][tu.UserId] = true
+		}
+	}
+
+	roots := make([]*teamNode, 0)
+	for _, t := range teams {
+		node := nodes[t.Id]
+		node.TotalMembers = len(members[t.Id])
+		// the teams in a cycle are listed as roots as well
+		if parent := nodes[t.ParentId]; parent != nil && !isInCycle(parents, t.Id) {
+			parent.Children = append(parent.Children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+	sortTeamNodes(roots)
+	return roots
+}
+
+func isInCycle(parents map[string]string, teamId string) bool {
+	visited := make(map[string]bool)
+	for id := parents[teamId]; id != "" && !visited[id]; id = parents[id] {
+		if id == teamId {
+			return true
+		}
+		visited[id] = true
+	}
+	return false
+}
+
+func sortTeamNodes(nodes []*teamNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].SortingIndex != nodes[j].SortingIndex {
+			return nodes[i].SortingIndex < nodes[j].SortingIndex
+		}
+		return nodes[i].Id < nodes[j].Id
+	})
+	for _, node := range nodes {
+		sortTeamNodes(node.Children)
+	}
+}
@@ -0,0 +1,128 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTeamTree(t *testing.T) {
+	assert.Nil(t, validateTeamTree(fakeTeams))
+	assert.NotNil(t, validateTeamTree([]team{{Id: "1", ParentId: "2"}}))
+	assert.NotNil(t, validateTeamTree([]team{{Id: "1"}, {Id: "1"}}))
+	assert.NotNil(t, validateTeamTree([]team{{Id: "1", ParentId: "1"}}))
+	assert.NotNil(t, validateTeamTree([]team{{Id: "1", ParentId: "3"}, {Id: "2", ParentId: "1"}, {Id: "3", ParentId: "2"}}))
+}
+
+func TestBuildTeamTree(t *testing.T) {
+	teams := []team{
+		{Id: "org", Name: "Org"},
+		{Id: "be", Name: "Backend", ParentId: "org", SortingIndex: 2},
+		{Id: "fe", Name: "Frontend", ParentId: "org", SortingIndex: 1},
+		{Id: "db", Name: "Database", ParentId: "be"},
+	}
+	assert.Len(t, buildTeamClosures(teams), 8)
+
+	moved := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	teamUsers := []crossdomain.TeamUser{
+		{TeamId: "fe", UserId: "alice", StartDate: crossdomain.TeamUserUnboundedStart, EndDate: &moved},
+		{TeamId: "db", UserId: "alice", StartDate: moved},
+		{TeamId: "be", UserId: "bob", StartDate: crossdomain.TeamUserUnboundedStart},
+		{TeamId: "db", UserId: "bob", StartDate: crossdomain.TeamUserUnboundedStart},
+	}
+	roots := buildTeamTree(teams, teamUsers, moved.AddDate(0, 0, -1))
+	if assert.Len(t, roots, 1) && assert.Len(t, roots[0].Children, 2) {
+		assert.Equal(t, 2, roots[0].TotalMembers)
+		fe, be := roots[0].Children[0], roots[0].Children[1]
+		assert.Equal(t, "fe", fe.Id)
+		assert.Equal(t, 1, fe.Members)
+		assert.Equal(t, 1, be.TotalMembers)
+		assert.Equal(t, 1, be.Children[0].TotalMembers)
+	}
+	roots = buildTeamTree(teams, teamUsers, moved)
+	fe, be := roots[0].Children[0], roots[0].Children[1]
+	assert.Equal(t, 0, fe.TotalMembers)
+	assert.Equal(t, 1, be.Members)
+	assert.Equal(t, 2, be.TotalMembers)
+	assert.Equal(t, 2, be.Children[0].Members)
+
+	// the teams in a cycle are not nested
+	roots = buildTeamTree([]team{{Id: "1", ParentId: "2"}, {Id: "2", ParentId: "1"}, {Id: "3", ParentId: "1"}}, nil, moved)
+	if assert.Len(t, roots, 2) {
+		assert.Equal(t, "3", roots[0].Children[0].Id)
+	}
+}
+
+func TestDiffTeamUsers(t *testing.T) {
+	lastMonth := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	today := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	existing := []crossdomain.TeamUser{
+		{TeamId: "1", UserId: "alice", StartDate: crossdomain.TeamUserUnboundedStart},
+		{TeamId: "2", UserId: "alice", StartDate: crossdomain.TeamUserUnboundedStart, EndDate: &lastMonth},
+		{TeamId: "3", UserId: "bob", StartDate: lastMonth},
+		{TeamId: "1", UserId: "dave", StartDate: today},
+	}
+	upserts, deletes := diffTeamUsers(existing, []*crossdomain.TeamUser{
+		{TeamId: "2", UserId: "alice"},
+		{TeamId: "3", UserId: "bob"},
+		{TeamId: "1", UserId: "carol"},
+	}, today, nil)
+	if assert.Len(t, upserts, 3) {
+		assert.Equal(t, "1", upserts[0].TeamId)
+		assert.Equal(t, today, *upserts[0].EndDate)
+		assert.Equal(t, "2", upserts[1].TeamId)
+		assert.Equal(t, today, upserts[1].StartDate)
+		assert.Nil(t, upserts[1].EndDate)
+		assert.Equal(t, "carol", upserts[2].UserId)
+		assert.Equal(t, crossdomain.TeamUserUnboundedStart, upserts[2].StartDate)
+	}
+	if assert.Len(t, deletes, 1) {
+		assert.Equal(t, "dave", deletes[0].UserId)
+	}
+
+	// only the memberships of bob are in scope
+	upserts, deletes = diffTeamUsers(existing, nil, today, func(tu *crossdomain.TeamUser) bool {
+		return tu.UserId == "bob"
+	})
+	assert.Empty(t, deletes)
+	if assert.Len(t, upserts, 1) {
+		assert.Equal(t, "bob", upserts[0].UserId)
+		assert.Equal(t, today, *upserts[0].EndDate)
+	}
+}
+
+func TestTeamUserToDomainLayer(t *testing.T) {
+	teamUsers, err := (&teamUser{}).toDomainLayer([]teamUser{
+		{TeamId: "1", UserId: "alice", EndDate: "2024-03-01"},
+		{TeamId: "2", UserId: "alice", StartDate: "2024-03-01"},
+	})
+	if assert.Nil(t, err) && assert.Len(t, teamUsers, 2) {
+		assert.Equal(t, crossdomain.TeamUserUnboundedStart, teamUsers[0].StartDate)
+		assert.True(t, teamUsers[0].IsEffectiveAt(time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)))
+		assert.False(t, teamUsers[0].IsEffectiveAt(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+		assert.True(t, teamUsers[1].IsEffectiveAt(time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)))
+	}
+	_, err = (&teamUser{}).toDomainLayer([]teamUser{{TeamId: "1", UserId: "alice", StartDate: "2024-03-01", EndDate: "2024-03-01"}})
+	assert.NotNil(t, err)
+	_, err = (&teamUser{}).toDomainLayer([]teamUser{{TeamId: "1", UserId: "alice", StartDate: "03/01/2024"}})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,227 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/gocarina/gocsv"
+)
+
+// teamMembership is a membership in json, a nil StartDate means since ever
+type teamMembership struct {
+	TeamId    string     `json:"teamId"`
+	UserId    string     `json:"userId"`
+	StartDate *time.Time `json:"startDate"`
+	EndDate   *time.Time `json:"endDate"`
+}
+
+// GetTeamUser returns all the memberships along with their effective dates in csv format
+// @Summary      Get team_users.csv file
+// @Description  get team_users.csv file, an empty StartDate means since ever and an empty EndDate means not ended
+// @Tags 		 plugins/org
+// @Produce      text/csv
+// @Success      200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/team_users.csv [get]
+func (h *Handlers) GetTeamUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	teamUsers, err := h.store.findAllTeamUsers()
+	if err != nil {
+		return nil, err
+	}
+	blob, err := errors.Convert01(gocsv.MarshalBytes((&teamUser{}).fromDomainLayer(teamUsers)))
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{
+		Body:   nil,
+		Status: http.StatusOK,
+		File: &plugin.OutputFile{
+			ContentType: "text/csv",
+			Data:        blob,
+		},
+	}, nil
+}
+
+// CreateTeamUser accepts a CSV file containing the memberships with their effective dates and replaces all the
+// memberships with them
+// @Summary      Upload team_users.csv file
+// @Description  upload team_users.csv file, the dates are formatted as 2006-01-02 and the EndDate is exclusive
+// @Tags 		 plugins/org
+// @Accept       multipart/form-data
+// @Param        file formData file true "select file to upload"
+// @Produce      json
+// @Success      200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/team_users.csv [put]
+func (h *Handlers) CreateTeamUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	var tt []teamUser
+	err := h.unmarshal(input.Request, &tt)
+	if err != nil {
+		return nil, err
+	}
+	teamUsers, err := (&teamUser{}).toDomainLayer(tt)
+	if err != nil {
+		return nil, err
+	}
+	var items []interface{}
+	for _, tu := range teamUsers {
+		items = append(items, tu)
+	}
+	err = h.store.deleteAll(&crossdomain.TeamUser{})
+	if err != nil {
+		return nil, err
+	}
+	err = h.store.save(items)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Status: http.StatusOK}, nil
+}
+
+// GetTeamUsers returns the memberships in json
+// @Summary      Get team memberships
+// @Description  get the memberships, optionally the ones of a team (and its sub teams) or a user effective at a date
+// @Tags 		 plugins/org
+// @Param        teamId query string false "team id"
+// @Param        includeSubTeams query bool false "include the members of the descendants of the team"
+// @Param        userId query string false "user id"
+// @Param        at query string false "effective date, formatted as 2006-01-02"
+// @Produce      json
+// @Success      200  {array} teamMembership
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/team_users.json [get]
+func (h *Handlers) GetTeamUsers(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	at, err := parseDate(input.Query, "at")
+	if err != nil {
+		return nil, err
+	}
+	teamIds := make(map[string]bool)
+	if teamId := input.Query.Get("teamId"); teamId != "" {
+		teamIds[teamId] = true
+		if input.Query.Get("includeSubTeams") == "true" {
+			teams, err := h.store.findAllTeams()
+			if err != nil {
+				return nil, err
+			}
+			for _, closure := range buildTeamClosures(teams) {
+				if closure.AncestorId == teamId {
+					teamIds[closure.DescendantId] = true
+				}
+			}
+		}
+	}
+	userId := input.Query.Get("userId")
+	teamUsers, err := h.store.findAllTeamUsers()
+	if err != nil {
+		return nil, err
+	}
+	memberships := make([]teamMembership, 0)
+	for i := range teamUsers {
+		tu := &teamUsers[i]
+		if len(teamIds) > 0 && !teamIds[tu.TeamId] || userId != "" && tu.UserId != userId || at != nil && !tu.IsEffectiveAt(*at) {
+			continue
+		}
+		membership := teamMembership{TeamId: tu.TeamId, UserId: tu.UserId, EndDate: tu.EndDate}
+		if !tu.StartDate.Equal(crossdomain.TeamUserUnboundedStart) {
+			membership.StartDate = &tu.StartDate
+		}
+		memberships = append(memberships, membership)
+	}
+	return &plugin.ApiResourceOutput{Body: memberships, Status: http.StatusOK}, nil
+}
+
+// diffTeamUsers keeps the history of the memberships while only the current teams of the users are listed: the
+// current memberships in scope which are not listed anymore end now, and the listed teams the users are not currently
+// members of start now, or since ever for the users who were never a member of any team. A nil inScope means all the
+// memberships are in scope. It returns the memberships to be saved and the ones to be deleted
+func diffTeamUsers(
+	existing []crossdomain.TeamUser,
+	listed []*crossdomain.TeamUser,
+	now time.Time,
+	inScope func(*crossdomain.TeamUser) bool,
+) (upserts []*crossdomain.TeamUser, deletes []*crossdomain.TeamUser) {
+	isListed := make(map[string]bool, len(listed))
+	for _, tu := range listed {
+		isListed[tu.TeamId+"\n"+tu.UserId] = true
+	}
+	isCurrent := make(map[string]bool)
+	hasHistory := make(map[string]bool)
+	for i := range existing {
+		tu := existing[i]
+		hasHistory[tu.UserId] = true
+		if tu.EndDate != nil && !tu.EndDate.After(now) {
+			continue
+		}
+		key := tu.TeamId + "\n" + tu.UserId
+		if isListed[key] {
+			isCurrent[key] = true
+			continue
+		}
+		if inScope != nil && !inScope(&tu) {
+			continue
+		}
+		if tu.StartDate.Before(now) {
+			tu.EndDate = &now
+			upserts = append(upserts, &tu)
+		} else {
+			// the membership which has not started yet is dropped
+			deletes = append(deletes, &tu)
+		}
+	}
+	for _, tu := range listed {
+		key := tu.TeamId + "\n" + tu.UserId
+		if isCurrent[key] {
+			continue
+		}
+		// the duplicated listings are started once
+		isCurrent[key] = true
+		tu.StartDate = crossdomain.TeamUserUnboundedStart
+		if hasHistory[tu.UserId] {
+			tu.StartDate = now
+		}
+		upserts = append(upserts, tu)
+	}
+	return upserts, deletes
+}
+
+// today is the date the memberships changed through the apis start or end at
+func today() time.Time {
+	return time.Now().UTC().Truncate(24 * time.Hour)
+}
+
+func parseDate(query url.Values, key string) (*time.Time, errors.Error) {
+	value := query.Get(key)
+	if value == "" {
+		return nil, nil
+	}
+	date, err := time.Parse(TimeFormat, value)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid %s, the format should be %s", key, TimeFormat))
+	}
+	return &date, nil
+}
@@ -18,8 +18,11 @@ limitations under the License.
 package api
 
 import (
+	"fmt"
 	"strings"
+	"time"
 
+	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models/common"
 	"github.com/apache/incubator-devlake/core/models/domainlayer"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
@@ -84,10 +87,15 @@ type user struct {
 	TeamIds string
 }
 
+// fromDomainLayer lists the teams the users are currently members of
 func (*user) fromDomainLayer(users []crossdomain.User, teamUsers []crossdomain.TeamUser) []user {
 	var result []user
+	now := time.Now()
 	teamUserMap := make(map[string][]string)
 	for _, tu := range teamUsers {
+		if !tu.IsEffectiveAt(now) {
+			continue
+		}
 		teamUserMap[tu.UserId] = append(teamUserMap[tu.UserId], tu.TeamId)
 	}
 	for _, u := range users {
@@ -244,6 +252,74 @@ func (*team) fakeData() []team {
 	return fakeTeams
 }
 
+type teamUser struct {
+	TeamId    string
+	UserId    string
+	StartDate string
+	EndDate   string
+}
+
+func (*teamUser) fromDomainLayer(teamUsers []crossdomain.TeamUser) []teamUser {
+	var result []teamUser
+	for _, tu := range teamUsers {
+		var startDate, endDate string
+		if !tu.StartDate.Equal(crossdomain.TeamUserUnboundedStart) {
+			startDate = tu.StartDate.Format(TimeFormat)
+		}
+		if tu.EndDate != nil {
+			endDate = tu.EndDate.Format(TimeFormat)
+		}
+		result = append(result, teamUser{
+			TeamId:    tu.TeamId,
+			UserId:    tu.UserId,
+			StartDate: startDate,
+			EndDate:   endDate,
+		})
+	}
+	return result
+}
+
+// toDomainLayer parses the effective dates of the memberships, an empty StartDate means since ever and an empty
+// EndDate means the membership is not ended
+func (*teamUser) toDomainLayer(tt []teamUser) ([]*crossdomain.TeamUser, errors.Error) {
+	var result []*crossdomain.TeamUser
+	seen := make(map[string]bool)
+	for _, t := range tt {
+		if t.TeamId == "" || t.UserId == "" {
+			continue
+		}
+		tu := &crossdomain.TeamUser{
+			TeamId:    t.TeamId,
+			UserId:    t.UserId,
+			StartDate: crossdomain.TeamUserUnboundedStart,
+		}
+		if t.StartDate != "" {
+			startDate, err := time.Parse(TimeFormat, t.StartDate)
+			if err != nil {
+				return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid StartDate of user %s in team %s", t.UserId, t.TeamId))
+			}
+			tu.StartDate = startDate
+		}
+		if t.EndDate != "" {
+			endDate, err := time.Parse(TimeFormat, t.EndDate)
+			if err != nil {
+				return nil, errors.BadInput.Wrap(err, fmt.Sprintf("invalid EndDate of user %s in team %s", t.UserId, t.TeamId))
+			}
+			if !endDate.After(tu.StartDate) {
+				return nil, errors.BadInput.New(fmt.Sprintf("EndDate of user %s in team %s must be after its StartDate", t.UserId, t.TeamId))
+			}
+			tu.EndDate = &endDate
+		}
+		key := fmt.Sprintf("%s\n%s\n%s", tu.TeamId, tu.UserId, tu.StartDate.Format(TimeFormat))
+		if seen[key] {
+			return nil, errors.BadInput.New(fmt.Sprintf("duplicated membership of user %s in team %s starting at %s", t.UserId, t.TeamId, t.StartDate))
+		}
+		seen[key] = true
+		result = append(result, tu)
+	}
+	return result, nil
+}
+
 type projectMapping struct {
 	ProjectName string
 	Table       string
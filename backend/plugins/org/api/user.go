@@ -62,7 +62,8 @@ func (h *Handlers) GetUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceO
 	}, nil
 }
 
-// CreateUser accepts a CSV file containing user information mapping and saves it to the database
+// CreateUser accepts a CSV file containing user information mapping and saves it to the database, the TeamIds are the
+// current teams of the user, the memberships of the other teams are ended instead of being deleted
 // @Summary      Upload users.csv file
 // @Description  upload users.csv file
 // @Tags 		 plugins/org
@@ -84,18 +85,19 @@ func (h *Handlers) CreateUser(input *plugin.ApiResourceInput) (*plugin.ApiResour
 	for _, user := range users {
 		items = append(items, user)
 	}
-	for _, teamUser := range teamUsers {
-		items = append(items, teamUser)
+	existingTeamUsers, err := h.store.findAllTeamUsers()
+	if err != nil {
+		return nil, err
 	}
 	err = h.store.deleteAll(&crossdomain.User{})
 	if err != nil {
 		return nil, err
 	}
-	err = h.store.deleteAll(&crossdomain.TeamUser{})
+	err = h.store.save(items)
 	if err != nil {
 		return nil, err
 	}
-	err = h.store.save(items)
+	err = h.store.updateTeamUsers(diffTeamUsers(existingTeamUsers, teamUsers, today(), nil))
 	if err != nil {
 		return nil, err
 	}
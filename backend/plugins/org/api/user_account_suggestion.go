@@ -0,0 +1,123 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/org/models"
+	"github.com/go-playground/validator/v10"
+)
+
+type userAccountSuggestion struct {
+	AccountId       string  `json:"accountId"`
+	UserId          string  `json:"userId"`
+	Score           float64 `json:"score"`
+	Reasons         string  `json:"reasons"`
+	Status          string  `json:"status"`
+	AccountFullName string  `json:"accountFullName"`
+	AccountUserName string  `json:"accountUserName"`
+	AccountEmail    string  `json:"accountEmail"`
+	UserName        string  `json:"userName"`
+	UserEmail       string  `json:"userEmail"`
+}
+
+type userAccountSuggestionReview struct {
+	AccountId string `json:"accountId" validate:"required"`
+	UserId    string `json:"userId" validate:"required"`
+}
+
+// GetUserAccountSuggestions returns the candidate users of the accounts which were not linked automatically
+// @Summary      Get user account suggestions
+// @Description  get the user account suggestions produced by the connectUserAccountsFuzzy subtask
+// @Tags 		 plugins/org
+// @Param        status query string false "PENDING(default), APPROVED or REJECTED"
+// @Param        accountId query string false "account id"
+// @Param        page query int false "page number, default 1"
+// @Param        pageSize query int false "page size, default 50"
+// @Produce      json
+// @Success      200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/user_account_suggestions [get]
+func (h *Handlers) GetUserAccountSuggestions(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	status := input.Query.Get("status")
+	if status == "" {
+		status = models.SUGGESTION_STATUS_PENDING
+	}
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	suggestions, count, err := h.store.findUserAccountSuggestions(status, input.Query.Get("accountId"), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{
+		Body: map[string]interface{}{
+			"suggestions": suggestions,
+			"count":       count,
+		},
+		Status: http.StatusOK,
+	}, nil
+}
+
+// ApproveUserAccountSuggestion links the account to the suggested user
+// @Summary      Approve a user account suggestion
+// @Description  link the account to the suggested user, the other pending suggestions of the account are dropped
+// @Tags 		 plugins/org
+// @Accept       application/json
+// @Param        review body userAccountSuggestionReview true "json"
+// @Produce      json
+// @Success      200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/user_account_suggestions/approve [post]
+func (h *Handlers) ApproveUserAccountSuggestion(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.reviewUserAccountSuggestion(input, models.SUGGESTION_STATUS_APPROVED)
+}
+
+// RejectUserAccountSuggestion marks the suggestion as rejected so that it is not suggested again
+// @Summary      Reject a user account suggestion
+// @Description  reject the suggestion, the account won't be suggested or linked to the user again
+// @Tags 		 plugins/org
+// @Accept       application/json
+// @Param        review body userAccountSuggestionReview true "json"
+// @Produce      json
+// @Success      200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/user_account_suggestions/reject [post]
+func (h *Handlers) RejectUserAccountSuggestion(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	return h.reviewUserAccountSuggestion(input, models.SUGGESTION_STATUS_REJECTED)
+}
+
+func (h *Handlers) reviewUserAccountSuggestion(input *plugin.ApiResourceInput, status string) (*plugin.ApiResourceOutput, errors.Error) {
+	var review userAccountSuggestionReview
+	err := helper.Decode(input.Body, &review, validator.New())
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid review")
+	}
+	err = h.store.reviewUserAccountSuggestion(review.AccountId, review.UserId, status)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Status: http.StatusOK}, nil
+}
@@ -0,0 +1,379 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+)
+
+// userResource is a user in json along with the ids of the teams it is currently a member of
+type userResource struct {
+	Id      string   `json:"id"`
+	Name    string   `json:"name"`
+	Email   string   `json:"email"`
+	TeamIds []string `json:"teamIds,omitempty"`
+}
+
+func (r *userResource) fromDomainLayer(u *crossdomain.User) {
+	r.Id = u.Id
+	r.Name = u.Name
+	r.Email = u.Email
+}
+
+func (r *userResource) toDomainLayer() *crossdomain.User {
+	return &crossdomain.User{
+		DomainEntity: domainlayer.DomainEntity{Id: r.Id},
+		Name:         r.Name,
+		Email:        r.Email,
+	}
+}
+
+type accountResource struct {
+	Id           string     `json:"id"`
+	Email        string     `json:"email"`
+	FullName     string     `json:"fullName"`
+	UserName     string     `json:"userName"`
+	AvatarUrl    string     `json:"avatarUrl"`
+	Organization string     `json:"organization"`
+	CreatedDate  *time.Time `json:"createdDate"`
+	Status       int        `json:"status"`
+}
+
+func (r *accountResource) fromDomainLayer(a *crossdomain.Account) {
+	r.Id = a.Id
+	r.Email = a.Email
+	r.FullName = a.FullName
+	r.UserName = a.UserName
+	r.AvatarUrl = a.AvatarUrl
+	r.Organization = a.Organization
+	r.CreatedDate = a.CreatedDate
+	r.Status = a.Status
+}
+
+type userAccountLink struct {
+	AccountId string `json:"accountId" validate:"required"`
+}
+
+// ListUsers returns the users page by page
+// @Summary      List users
+// @Description  list the users, optionally the ones whose id, name or email contains the search
+// @Tags 		 plugins/org
+// @Param        search query string false "search"
+// @Param        page query int false "page number, default 1"
+// @Param        pageSize query int false "page size, default 50"
+// @Produce      json
+// @Success      200
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/users [get]
+func (h *Handlers) ListUsers(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	limit, offset := helper.GetLimitOffset(input.Query, "pageSize", "page")
+	users, count, err := h.store.findUsers(input.Query.Get("search"), limit, offset)
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*userResource, 0, len(users))
+	for i := range users {
+		resource := &userResource{}
+		resource.fromDomainLayer(&users[i])
+		resources = append(resources, resource)
+	}
+	return &plugin.ApiResourceOutput{
+		Body: map[string]interface{}{
+			"users": resources,
+			"count": count,
+		},
+		Status: http.StatusOK,
+	}, nil
+}
+
+// PostUser creates a user
+// @Summary      Create a user
+// @Description  create a user, the id is generated if it is empty and the teamIds are the memberships starting today
+// @Tags 		 plugins/org
+// @Accept       application/json
+// @Param        user body userResource true "json"
+// @Produce      json
+// @Success      201  {object} userResource
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 409  {object} shared.ApiBody "Conflict"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/users [post]
+func (h *Handlers) PostUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	resource := &userResource{}
+	err := helper.DecodeMapStruct(input.Body, resource, false)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid user")
+	}
+	if resource.Id == "" {
+		resource.Id = uuid.New().String()
+	} else if _, err = h.store.findUserById(resource.Id); err == nil {
+		return nil, errors.Conflict.New(fmt.Sprintf("user %s already exists", resource.Id))
+	} else if err.GetType() != errors.NotFound {
+		return nil, err
+	}
+	err = h.saveUser(resource, input.Body["teamIds"] != nil)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: resource, Status: http.StatusCreated}, nil
+}
+
+// GetUserById returns the user along with its current teams
+// @Summary      Get a user
+// @Description  get the user along with the ids of its current teams
+// @Tags 		 plugins/org
+// @Param        userId path string true "user id"
+// @Produce      json
+// @Success      200  {object} userResource
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/users/{userId} [get]
+func (h *Handlers) GetUserById(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	resource, err := h.getUserResource(input.Params["userId"])
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: resource, Status: http.StatusOK}, nil
+}
+
+// PatchUser updates the given fields of the user
+// @Summary      Patch a user
+// @Description  update the given fields of the user, the current teams are replaced if the teamIds are given
+// @Tags 		 plugins/org
+// @Accept       application/json
+// @Param        userId path string true "user id"
+// @Param        user body userResource true "json"
+// @Produce      json
+// @Success      200  {object} userResource
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/users/{userId} [patch]
+func (h *Handlers) PatchUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	resource, err := h.getUserResource(input.Params["userId"])
+	if err != nil {
+		return nil, err
+	}
+	err = helper.DecodeMapStruct(input.Body, resource, false)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid user")
+	}
+	resource.Id = input.Params["userId"]
+	err = h.saveUser(resource, input.Body["teamIds"] != nil)
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Body: resource, Status: http.StatusOK}, nil
+}
+
+// DeleteUser deletes the user along with its account links and ends its memberships
+// @Summary      Delete a user
+// @Description  delete the user along with its account links and end its memberships today
+// @Tags 		 plugins/org
+// @Param        userId path string true "user id"
+// @Produce      json
+// @Success      200
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/users/{userId} [delete]
+func (h *Handlers) DeleteUser(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	err := h.removeUser(input.Params["userId"])
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Status: http.StatusOK}, nil
+}
+
+// ListUserAccounts returns the accounts linked to the user
+// @Summary      List the accounts of a user
+// @Description  list the accounts linked to the user
+// @Tags 		 plugins/org
+// @Param        userId path string true "user id"
+// @Produce      json
+// @Success      200  {array} accountResource
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/users/{userId}/accounts [get]
+func (h *Handlers) ListUserAccounts(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	_, err := h.store.findUserById(input.Params["userId"])
+	if err != nil {
+		return nil, err
+	}
+	accounts, err := h.store.findAccountsOfUser(input.Params["userId"])
+	if err != nil {
+		return nil, err
+	}
+	resources := make([]*accountResource, 0, len(accounts))
+	for i := range accounts {
+		resource := &accountResource{}
+		resource.fromDomainLayer(&accounts[i])
+		resources = append(resources, resource)
+	}
+	return &plugin.ApiResourceOutput{Body: resources, Status: http.StatusOK}, nil
+}
+
+// PostUserAccount links the account to the user
+// @Summary      Link an account to a user
+// @Description  link the account to the user, the account is unlinked from the user it was linked to before
+// @Tags 		 plugins/org
+// @Accept       application/json
+// @Param        userId path string true "user id"
+// @Param        link body userAccountLink true "json"
+// @Produce      json
+// @Success      200  {object} accountResource
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 404  {object} shared.ApiBody "Not Found"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/users/{userId}/accounts [post]
+func (h *Handlers) PostUserAccount(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	var link userAccountLink
+	err := helper.Decode(input.Body, &link, validator.New())
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid account link")
+	}
+	_, err = h.store.findUserById(input.Params["userId"])
+	if err != nil {
+		return nil, err
+	}
+	account, err := h.store.findAccountById(link.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	err = h.store.linkUserAccount(input.Params["userId"], link.AccountId)
+	if err != nil {
+		return nil, err
+	}
+	resource := &accountResource{}
+	resource.fromDomainLayer(account)
+	return &plugin.ApiResourceOutput{Body: resource, Status: http.StatusOK}, nil
+}
+
+// DeleteUserAccount unlinks the account from the user
+// @Summary      Unlink an account from a user
+// @Description  unlink the account from the user
+// @Tags 		 plugins/org
+// @Param        userId path string true "user id"
+// @Param        accountId path string true "account id"
+// @Produce      json
+// @Success      200
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router       /plugins/org/users/{userId}/accounts/{accountId} [delete]
+func (h *Handlers) DeleteUserAccount(input *plugin.ApiResourceInput) (*plugin.ApiResourceOutput, errors.Error) {
+	err := h.store.unlinkUserAccount(input.Params["userId"], input.Params["accountId"])
+	if err != nil {
+		return nil, err
+	}
+	return &plugin.ApiResourceOutput{Status: http.StatusOK}, nil
+}
+
+func (h *Handlers) getUserResource(userId string) (*userResource, errors.Error) {
+	u, err := h.store.findUserById(userId)
+	if err != nil {
+		return nil, err
+	}
+	resource := &userResource{}
+	resource.fromDomainLayer(u)
+	resource.TeamIds, err = currentTeams(h.store, userId)
+	if err != nil {
+		return nil, err
+	}
+	return resource, nil
+}
+
+// saveUser creates or updates the user, its current teams are replaced by the TeamIds if withTeams is true. The
+// changes are saved in a transaction
+func (h *Handlers) saveUser(resource *userResource, withTeams bool) errors.Error {
+	if withTeams {
+		for _, teamId := range resource.TeamIds {
+			_, err := h.store.findTeamById(teamId)
+			if err != nil {
+				if err.GetType() == errors.NotFound {
+					return errors.BadInput.Wrap(err, fmt.Sprintf("team %s does not exist", teamId))
+				}
+				return err
+			}
+		}
+	}
+	return h.store.transaction(func(s store) errors.Error {
+		err := s.createOrUpdate(resource.toDomainLayer())
+		if err != nil {
+			return err
+		}
+		if withTeams {
+			err = setUserTeams(s, resource.Id, resource.TeamIds)
+			if err != nil {
+				return err
+			}
+		}
+		resource.TeamIds, err = currentTeams(s, resource.Id)
+		return err
+	})
+}
+
+// removeUser deletes the user and ends its memberships in a transaction
+func (h *Handlers) removeUser(userId string) errors.Error {
+	return h.store.transaction(func(s store) errors.Error {
+		_, err := s.findUserById(userId)
+		if err != nil {
+			return err
+		}
+		return s.deleteUser(userId, today())
+	})
+}
+
+// currentTeams returns the ids of the teams the user is a member of today
+func currentTeams(s store, userId string) ([]string, errors.Error) {
+	teamUsers, err := s.findAllTeamUsers()
+	if err != nil {
+		return nil, err
+	}
+	now := today()
+	teamIds := make([]string, 0)
+	for i := range teamUsers {
+		if teamUsers[i].UserId == userId && teamUsers[i].IsEffectiveAt(now) {
+			teamIds = append(teamIds, teamUsers[i].TeamId)
+		}
+	}
+	return teamIds, nil
+}
+
+// setUserTeams replaces the current teams of the user, the memberships of the other users are kept
+func setUserTeams(s store, userId string, teamIds []string) errors.Error {
+	teamUsers, err := s.findAllTeamUsers()
+	if err != nil {
+		return err
+	}
+	listed := make([]*crossdomain.TeamUser, 0, len(teamIds))
+	for _, teamId := range teamIds {
+		listed = append(listed, &crossdomain.TeamUser{TeamId: teamId, UserId: userId})
+	}
+	return s.updateTeamUsers(diffTeamUsers(teamUsers, listed, today(), func(tu *crossdomain.TeamUser) bool {
+		return tu.UserId == userId
+	}))
+}
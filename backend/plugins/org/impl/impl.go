@@ -25,6 +25,8 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/plugins/org/api"
+	"github.com/apache/incubator-devlake/plugins/org/models"
+	"github.com/apache/incubator-devlake/plugins/org/models/migrationscripts"
 	"github.com/apache/incubator-devlake/plugins/org/tasks"
 )
 
@@ -34,6 +36,7 @@ var _ interface {
 	plugin.PluginTask
 	plugin.PluginModel
 	plugin.ProjectMapper
+	plugin.PluginMigration
 } = (*Org)(nil)
 
 type Org struct {
@@ -46,7 +49,9 @@ func (p *Org) Init(basicRes context.BasicRes) errors.Error {
 }
 
 func (p Org) GetTablesInfo() []dal.Tabler {
-	return []dal.Tabler{}
+	return []dal.Tabler{
+		&models.UserAccountSuggestion{},
+	}
 }
 
 func (p Org) Description() string {
@@ -60,6 +65,7 @@ func (p Org) Name() string {
 func (p Org) SubTaskMetas() []plugin.SubTaskMeta {
 	return []plugin.SubTaskMeta{
 		tasks.ConnectUserAccountsExactMeta,
+		tasks.ConnectUserAccountsFuzzyMeta,
 		tasks.SetProjectMappingMeta,
 		tasks.SleepMeta,
 	}
@@ -98,6 +104,10 @@ func (p Org) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]inte
 	return taskData, nil
 }
 
+func (p Org) MigrationScripts() []plugin.MigrationScript {
+	return migrationscripts.All()
+}
+
 func (p Org) RootPkgPath() string {
 	return "github.com/apache/incubator-devlake/plugins/org"
 }
@@ -108,6 +118,16 @@ func (p Org) ApiResources() map[string]map[string]plugin.ApiResourceHandler {
 			"GET": p.handlers.GetTeam,
 			"PUT": p.handlers.CreateTeam,
 		},
+		"teams.json": {
+			"GET": p.handlers.GetTeamTree,
+		},
+		"team_users.csv": {
+			"GET": p.handlers.GetTeamUser,
+			"PUT": p.handlers.CreateTeamUser,
+		},
+		"team_users.json": {
+			"GET": p.handlers.GetTeamUsers,
+		},
 		"users.csv": {
 			"GET": p.handlers.GetUser,
 			"PUT": p.handlers.CreateUser,
@@ -121,5 +141,62 @@ func (p Org) ApiResources() map[string]map[string]plugin.ApiResourceHandler {
 			"GET": p.handlers.GetProjectMapping,
 			"PUT": p.handlers.CreateProjectMapping,
 		},
+		"user_account_suggestions": {
+			"GET": p.handlers.GetUserAccountSuggestions,
+		},
+		"user_account_suggestions/approve": {
+			"POST": p.handlers.ApproveUserAccountSuggestion,
+		},
+		"user_account_suggestions/reject": {
+			"POST": p.handlers.RejectUserAccountSuggestion,
+		},
+		"teams": {
+			"GET":  p.handlers.ListTeams,
+			"POST": p.handlers.PostTeam,
+		},
+		"teams/:teamId": {
+			"GET":    p.handlers.GetTeamById,
+			"PATCH":  p.handlers.PatchTeam,
+			"DELETE": p.handlers.DeleteTeam,
+		},
+		"users": {
+			"GET":  p.handlers.ListUsers,
+			"POST": p.handlers.PostUser,
+		},
+		"users/:userId": {
+			"GET":    p.handlers.GetUserById,
+			"PATCH":  p.handlers.PatchUser,
+			"DELETE": p.handlers.DeleteUser,
+		},
+		"users/:userId/accounts": {
+			"GET":  p.handlers.ListUserAccounts,
+			"POST": p.handlers.PostUserAccount,
+		},
+		"users/:userId/accounts/:accountId": {
+			"DELETE": p.handlers.DeleteUserAccount,
+		},
+		"scim/v2/ServiceProviderConfig": {
+			"GET": p.handlers.ScimServiceProviderConfig,
+		},
+		"scim/v2/Users": {
+			"GET":  p.handlers.ScimListUsers,
+			"POST": p.handlers.ScimPostUser,
+		},
+		"scim/v2/Users/:userId": {
+			"GET":    p.handlers.ScimGetUser,
+			"PUT":    p.handlers.ScimPutUser,
+			"PATCH":  p.handlers.ScimPatchUser,
+			"DELETE": p.handlers.ScimDeleteUser,
+		},
+		"scim/v2/Groups": {
+			"GET":  p.handlers.ScimListGroups,
+			"POST": p.handlers.ScimPostGroup,
+		},
+		"scim/v2/Groups/:teamId": {
+			"GET":    p.handlers.ScimGetGroup,
+			"PUT":    p.handlers.ScimPutGroup,
+			"PATCH":  p.handlers.ScimPatchGroup,
+			"DELETE": p.handlers.ScimDeleteGroup,
+		},
 	}
 }
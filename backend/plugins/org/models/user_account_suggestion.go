@@ -0,0 +1,44 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+const (
+	SUGGESTION_STATUS_PENDING  = "PENDING"
+	SUGGESTION_STATUS_APPROVED = "APPROVED"
+	SUGGESTION_STATUS_REJECTED = "REJECTED"
+)
+
+// UserAccountSuggestion is a candidate user of an account scored by connectUserAccountsFuzzy, which is waiting for
+// the review since the score is not high enough to link them automatically
+type UserAccountSuggestion struct {
+	AccountId string  `json:"accountId" gorm:"primaryKey;type:varchar(255)"`
+	UserId    string  `json:"userId" gorm:"primaryKey;type:varchar(255)"`
+	Score     float64 `json:"score"`
+	// Reasons lists the matched signals separated by comma, i.e. `email_local_part,full_name`
+	Reasons          string `json:"reasons" gorm:"type:varchar(255)"`
+	Status           string `json:"status" gorm:"type:varchar(20);index"`
+	common.NoPKModel `json:"-"`
+}
+
+func (UserAccountSuggestion) TableName() string {
+	return "_tool_org_user_account_suggestions"
+}
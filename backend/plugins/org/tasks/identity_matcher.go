@@ -0,0 +1,232 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"golang.org/x/text/unicode/norm"
+)
+
+// the signals of a match, the score of a candidate combines the weights of its signals as independent evidences
+const (
+	REASON_EMAIL            = "email"
+	REASON_EMAIL_LOCAL_PART = "email_local_part"
+	REASON_GITHUB_NOREPLY   = "github_noreply"
+	REASON_USERNAME         = "username"
+	REASON_FULL_NAME        = "full_name"
+	REASON_COMMIT_EMAIL     = "commit_email"
+)
+
+const githubNoreplyDomain = "users.noreply.github.com"
+
+// letters which are not decomposed into a base letter and marks by NFD
+var transliterations = map[rune]string{
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+}
+
+// commitEmail is an email used by the commits in the pull requests of an account
+type commitEmail struct {
+	Email        string
+	PullRequests int
+}
+
+// identityMatch is a candidate user of an account
+type identityMatch struct {
+	UserId  string
+	Score   float64
+	Reasons []string
+}
+
+type identityMatcher struct {
+	usersByEmail     map[string][]string
+	usersByLocalPart map[string][]*crossdomain.User
+	usersByName      map[string][]string
+	// names guessed from the local part of the emails, i.e. `john.doe@example.com`
+	usersByEmailName map[string][]string
+}
+
+func newIdentityMatcher(users []crossdomain.User) *identityMatcher {
+	m := &identityMatcher{
+		usersByEmail:     make(map[string][]string),
+		usersByLocalPart: make(map[string][]*crossdomain.User),
+		usersByName:      make(map[string][]string),
+		usersByEmailName: make(map[string][]string),
+	}
+	for i := range users {
+		user := &users[i]
+		if email := strings.ToLower(strings.TrimSpace(user.Email)); email != "" {
+			m.usersByEmail[email] = append(m.usersByEmail[email], user.Id)
+			local, _ := splitEmail(email)
+			if key := normalizeLocalPart(local); key != "" {
+				m.usersByLocalPart[key] = append(m.usersByLocalPart[key], user)
+			}
+			if key := nameKey(local); key != "" {
+				m.usersByEmailName[key] = append(m.usersByEmailName[key], user.Id)
+			}
+		}
+		if key := nameKey(user.Name); key != "" {
+			m.usersByName[key] = append(m.usersByName[key], user.Id)
+		}
+	}
+	return m
+}
+
+// match returns the candidate users of the account, the best match first
+func (m *identityMatcher) match(account *crossdomain.Account, commitEmails []commitEmail) []*identityMatch {
+	signals := make(map[string]map[string]float64)
+	add := func(userIds []string, reason string, weight float64) {
+		for _, userId := range userIds {
+			if signals[userId] == nil {
+				signals[userId] = make(map[string]float64)
+			}
+			if weight > signals[userId][reason] {
+				signals[userId][reason] = weight
+			}
+		}
+	}
+
+	login := strings.TrimSpace(account.UserName)
+	if email := strings.ToLower(strings.TrimSpace(account.Email)); email != "" {
+		add(m.usersByEmail[email], REASON_EMAIL, 1)
+		local, domain := splitEmail(email)
+		if domain == githubNoreplyDomain {
+			// 12345+octocat@users.noreply.github.com or octocat@users.noreply.github.com
+			if i := strings.Index(local, "+"); i >= 0 {
+				local = local[i+1:]
+			}
+			add(m.findByLocalPart(normalizeLocalPart(local), ""), REASON_GITHUB_NOREPLY, 0.7)
+			add(m.usersByName[nameKey(local)], REASON_GITHUB_NOREPLY, 0.7)
+		} else {
+			key := normalizeLocalPart(local)
+			add(m.findByLocalPart(key, domain), REASON_EMAIL_LOCAL_PART, 0.9)
+			add(m.findByLocalPart(key, ""), REASON_EMAIL_LOCAL_PART, 0.6)
+		}
+	}
+	if login != "" {
+		add(m.findByLocalPart(normalizeLocalPart(login), ""), REASON_USERNAME, 0.5)
+		add(m.usersByName[nameKey(login)], REASON_USERNAME, 0.5)
+	}
+	if key := nameKey(account.FullName); key != "" {
+		add(m.usersByName[key], REASON_FULL_NAME, 0.7)
+		add(m.usersByEmailName[key], REASON_FULL_NAME, 0.5)
+	}
+	for _, commitEmail := range commitEmails {
+		weight := 0.6
+		if commitEmail.PullRequests > 1 {
+			weight = 0.8
+		}
+		add(m.usersByEmail[strings.ToLower(commitEmail.Email)], REASON_COMMIT_EMAIL, weight)
+	}
+
+	matches := make([]*identityMatch, 0, len(signals))
+	for userId, reasons := range signals {
+		match := &identityMatch{UserId: userId}
+		unlikely := 1.0
+		for reason, weight := range reasons {
+			unlikely *= 1 - weight
+			match.Reasons = append(match.Reasons, reason)
+		}
+		match.Score = math.Round((1-unlikely)*1000) / 1000
+		sort.Strings(match.Reasons)
+		matches = append(matches, match)
+	}
+	sort.Slice(matches, func(i, j int) bool {
+		if matches[i].Score != matches[j].Score {
+			return matches[i].Score > matches[j].Score
+		}
+		return matches[i].UserId < matches[j].UserId
+	})
+	return matches
+}
+
+// findByLocalPart returns the users whose normalized local part of the email is key, and whose domain is the given
+// one unless it is empty
+func (m *identityMatcher) findByLocalPart(key, domain string) []string {
+	if key == "" {
+		return nil
+	}
+	userIds := make([]string, 0)
+	for _, user := range m.usersByLocalPart[key] {
+		if _, userDomain := splitEmail(strings.ToLower(user.Email)); domain == "" || userDomain == domain {
+			userIds = append(userIds, user.Id)
+		}
+	}
+	return userIds
+}
+
+func splitEmail(email string) (string, string) {
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return email, ""
+	}
+	return email[:i], email[i+1:]
+}
+
+// normalizeLocalPart drops the sub-address and the separators, `John.Doe+ci` and `john_doe` are both `johndoe`
+func normalizeLocalPart(local string) string {
+	if i := strings.Index(local, "+"); i >= 0 {
+		local = local[:i]
+	}
+	var sb strings.Builder
+	for _, r := range transliterate(local) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// nameKey returns the sorted words of the transliterated name, so that `José Müller` matches `muller jose`, the
+// initials are ignored and names of less than 2 words are not keyed to avoid matching the first names only
+func nameKey(name string) string {
+	words := strings.FieldsFunc(transliterate(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	keyWords := make([]string, 0, len(words))
+	for _, word := range words {
+		if len([]rune(word)) > 1 {
+			keyWords = append(keyWords, word)
+		}
+	}
+	if len(keyWords) < 2 {
+		return ""
+	}
+	sort.Strings(keyWords)
+	return strings.Join(keyWords, " ")
+}
+
+// transliterate lowers the case and strips the diacritics
+func transliterate(s string) string {
+	var sb strings.Builder
+	for _, r := range norm.NFD.String(strings.ToLower(s)) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if t, ok := transliterations[r]; ok {
+			sb.WriteString(t)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
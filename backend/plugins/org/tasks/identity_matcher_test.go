@@ -0,0 +1,78 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdentityMatcher(t *testing.T) {
+	matcher := newIdentityMatcher([]crossdomain.User{
+		{DomainEntity: domainlayer.DomainEntity{Id: "u1"}, Name: "José Müller", Email: "jose.muller@example.com"},
+		{DomainEntity: domainlayer.DomainEntity{Id: "u2"}, Name: "Octo Cat", Email: "octocat@corp.com"},
+		{DomainEntity: domainlayer.DomainEntity{Id: "u3"}, Name: "Jane Smith", Email: "jsmith@example.com"},
+		{DomainEntity: domainlayer.DomainEntity{Id: "u4"}, Name: "Jane Smith", Email: "jane@other.com"},
+	})
+
+	matches := matcher.match(&crossdomain.Account{Email: "12345+octocat@users.noreply.github.com"}, nil)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "u2", matches[0].UserId)
+		assert.Equal(t, 0.7, matches[0].Score)
+		assert.Equal(t, []string{REASON_GITHUB_NOREPLY}, matches[0].Reasons)
+	}
+
+	matches = matcher.match(&crossdomain.Account{Email: "Jose_Muller+ci@example.com", FullName: "Jose Muller"}, nil)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "u1", matches[0].UserId)
+		assert.Equal(t, 0.97, matches[0].Score)
+		assert.Equal(t, []string{REASON_EMAIL_LOCAL_PART, REASON_FULL_NAME}, matches[0].Reasons)
+	}
+
+	matches = matcher.match(&crossdomain.Account{Email: "octocat@corp.com", UserName: "octocat"}, nil)
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, 1.0, matches[0].Score)
+	}
+
+	matches = matcher.match(&crossdomain.Account{UserName: "js"}, []commitEmail{{Email: "JSmith@example.com", PullRequests: 3}})
+	if assert.Len(t, matches, 1) {
+		assert.Equal(t, "u3", matches[0].UserId)
+		assert.Equal(t, 0.8, matches[0].Score)
+	}
+
+	// namesakes are equally likely
+	matches = matcher.match(&crossdomain.Account{FullName: "Smith, Jane"}, nil)
+	if assert.Len(t, matches, 2) {
+		assert.Equal(t, "u3", matches[0].UserId)
+		assert.Equal(t, "u4", matches[1].UserId)
+		assert.Equal(t, matches[0].Score, matches[1].Score)
+	}
+
+	assert.Empty(t, matcher.match(&crossdomain.Account{FullName: "Jose"}, nil))
+}
+
+func TestNameKey(t *testing.T) {
+	assert.Equal(t, "jose muller", nameKey("Müller, José"))
+	assert.Equal(t, "lukasz zolc", nameKey("Łukasz Żółć"))
+	assert.Equal(t, "strasse thomas", nameKey("Thomas Straße"))
+	assert.Equal(t, "", nameKey("J. Tolkien"))
+	assert.Equal(t, "", nameKey("octocat"))
+}
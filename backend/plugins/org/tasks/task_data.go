@@ -23,6 +23,9 @@ type Options struct {
 	ConnectionId    uint64           `json:"connectionId"`
 	ProjectMappings []ProjectMapping `json:"projectMappings"`
 	SleepSeconds    uint64           `json:"sleepSeconds"`
+	// scores of connectUserAccountsFuzzy, from 0 to 1
+	AutoLinkThreshold   float64 `json:"autoLinkThreshold"`
+	SuggestionThreshold float64 `json:"suggestionThreshold"`
 }
 
 // ProjectMapping represents the relations between project and scopes
@@ -0,0 +1,172 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/plugins/org/models"
+)
+
+const (
+	DEFAULT_AUTO_LINK_THRESHOLD  = 0.9
+	DEFAULT_SUGGESTION_THRESHOLD = 0.5
+)
+
+var ConnectUserAccountsFuzzyMeta = plugin.SubTaskMeta{
+	Name:             "connectUserAccountsFuzzy",
+	EntryPoint:       ConnectUserAccountsFuzzy,
+	EnabledByDefault: true,
+	Description:      "associate users and accounts by scoring the fuzzy matches, the uncertain ones are suggested for review",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
+
+// ConnectUserAccountsFuzzy links the accounts left by connectUserAccountsExact to the best matching user if the score
+// reaches the autoLinkThreshold, the candidates scored above the suggestionThreshold are saved as the pending
+// suggestions instead. The suggestions reviewed before are respected: the approved ones are linked and the rejected
+// ones are never suggested again.
+func ConnectUserAccountsFuzzy(taskCtx plugin.SubTaskContext) errors.Error {
+	db := taskCtx.GetDal()
+	logger := taskCtx.GetLogger()
+	data := taskCtx.GetData().(*TaskData)
+	autoLinkThreshold := data.Options.AutoLinkThreshold
+	if autoLinkThreshold <= 0 {
+		autoLinkThreshold = DEFAULT_AUTO_LINK_THRESHOLD
+	}
+	suggestionThreshold := data.Options.SuggestionThreshold
+	if suggestionThreshold <= 0 {
+		suggestionThreshold = DEFAULT_SUGGESTION_THRESHOLD
+	}
+
+	var users []crossdomain.User
+	err := db.All(&users)
+	if err != nil {
+		return err
+	}
+	var accounts []crossdomain.Account
+	err = db.All(&accounts, dal.Where("id NOT IN (SELECT account_id FROM user_accounts)"))
+	if err != nil {
+		return err
+	}
+	commitEmails, err := loadCommitEmails(db)
+	if err != nil {
+		return err
+	}
+	var reviewed []models.UserAccountSuggestion
+	err = db.All(&reviewed, dal.Where("status != ?", models.SUGGESTION_STATUS_PENDING))
+	if err != nil {
+		return err
+	}
+	reviewedStatus := make(map[string]string, len(reviewed))
+	for _, suggestion := range reviewed {
+		reviewedStatus[suggestion.AccountId+"\n"+suggestion.UserId] = suggestion.Status
+	}
+	// the pending suggestions are scored again
+	err = db.Delete(&models.UserAccountSuggestion{}, dal.Where("status = ?", models.SUGGESTION_STATUS_PENDING))
+	if err != nil {
+		return err
+	}
+
+	matcher := newIdentityMatcher(users)
+	links := make([]*crossdomain.UserAccount, 0)
+	suggestions := make([]*models.UserAccountSuggestion, 0)
+	taskCtx.SetProgress(0, len(accounts))
+	for i := range accounts {
+		account := &accounts[i]
+		taskCtx.IncProgress(1)
+		matches := make([]*identityMatch, 0)
+		var approved *identityMatch
+		for _, match := range matcher.match(account, commitEmails[account.Id]) {
+			switch reviewedStatus[account.Id+"\n"+match.UserId] {
+			case models.SUGGESTION_STATUS_REJECTED:
+				continue
+			case models.SUGGESTION_STATUS_APPROVED:
+				approved = match
+			}
+			matches = append(matches, match)
+		}
+		if approved == nil && len(matches) > 0 && matches[0].Score >= autoLinkThreshold &&
+			(len(matches) == 1 || matches[1].Score < matches[0].Score) {
+			approved = matches[0]
+		}
+		if approved != nil {
+			links = append(links, &crossdomain.UserAccount{UserId: approved.UserId, AccountId: account.Id})
+			continue
+		}
+		for _, match := range matches {
+			if match.Score < suggestionThreshold {
+				break
+			}
+			suggestions = append(suggestions, &models.UserAccountSuggestion{
+				AccountId: account.Id,
+				UserId:    match.UserId,
+				Score:     match.Score,
+				Reasons:   strings.Join(match.Reasons, ","),
+				Status:    models.SUGGESTION_STATUS_PENDING,
+			})
+		}
+	}
+	if len(links) > 0 {
+		err = db.CreateOrUpdate(links)
+		if err != nil {
+			return err
+		}
+	}
+	if len(suggestions) > 0 {
+		err = db.CreateOrUpdate(suggestions)
+		if err != nil {
+			return err
+		}
+	}
+	logger.Info("linked %d accounts, suggested %d candidates for review", len(links), len(suggestions))
+	return nil
+}
+
+// loadCommitEmails returns the author emails of the commits in the pull requests of each account, which are recorded
+// in the commits table by gitextractor while the emails returned by the apis might be hidden behind noreply addresses
+func loadCommitEmails(db dal.Dal) (map[string][]commitEmail, errors.Error) {
+	var rows []struct {
+		AccountId    string
+		Email        string
+		PullRequests int
+	}
+	err := db.All(
+		&rows,
+		dal.Select("pr.author_id AS account_id, c.author_email AS email, COUNT(DISTINCT pr.id) AS pull_requests"),
+		dal.From("pull_requests pr"),
+		dal.Join("JOIN pull_request_commits prc ON prc.pull_request_id = pr.id"),
+		dal.Join("JOIN commits c ON c.sha = prc.commit_sha"),
+		dal.Where("pr.author_id != '' AND c.author_email != ''"),
+		dal.Groupby("pr.author_id, c.author_email"),
+	)
+	if err != nil {
+		return nil, err
+	}
+	commitEmails := make(map[string][]commitEmail)
+	for _, row := range rows {
+		commitEmails[row.AccountId] = append(commitEmails[row.AccountId], commitEmail{
+			Email:        row.Email,
+			PullRequests: row.PullRequests,
+		})
+	}
+	return commitEmails, nil
+}
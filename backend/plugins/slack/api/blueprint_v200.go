@@ -20,8 +20,11 @@ package api
 import (
 	"github.com/apache/incubator-devlake/core/errors"
 	coreModels "github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
 	"github.com/apache/incubator-devlake/core/plugin"
 	helperapi "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/slack/models"
 	"github.com/apache/incubator-devlake/plugins/slack/tasks"
 )
 
@@ -37,6 +40,8 @@ func MakeDataSourcePipelinePlanV200(
 	}
 	// Build one stage per selected channel
 	plan := make(coreModels.PipelinePlan, len(scopeDetails))
+	scopes := make([]plugin.Scope, 0, len(scopeDetails))
+	idgen := didgen.NewDomainIdGenerator(&models.SlackChannel{})
 	for i, scopeDetail := range scopeDetails {
 		stage := plan[i]
 		if stage == nil {
@@ -56,7 +61,7 @@ func MakeDataSourcePipelinePlanV200(
 		}
 		stage = append(stage, task)
 		plan[i] = stage
+		scopes = append(scopes, chat.NewChatChannel(idgen.Generate(connectionId, scope.Id), scope.Name))
 	}
-	// No domain scopes emitted by Slack for now
-	return plan, nil, nil
+	return plan, scopes, nil
 }
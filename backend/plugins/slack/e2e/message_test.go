@@ -0,0 +1,86 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/plugins/slack/impl"
+	"github.com/apache/incubator-devlake/plugins/slack/models"
+	"github.com/apache/incubator-devlake/plugins/slack/tasks"
+)
+
+func TestSlackMessageDataFlow(t *testing.T) {
+	var slack impl.Slack
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "slack", slack)
+
+	taskData := &tasks.SlackTaskData{
+		Options: &tasks.SlackOptions{
+			ConnectionId: 1,
+			ChannelId:    "C01",
+		},
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_slack_channel_message.csv", "_raw_slack_channel_message")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_slack_thread.csv", "_raw_slack_thread")
+
+	// verify conversion of the channel messages and the thread replies
+	dataflowTester.FlushTabler(&models.SlackChannelMessage{})
+	dataflowTester.FlushTabler(&models.SlackChannelMessageReaction{})
+	dataflowTester.FlushTabler(&chat.ChatMessage{})
+	dataflowTester.FlushTabler(&chat.ChatMessageReaction{})
+	runMessageSubtasks(dataflowTester, taskData)
+	verifyMessages(dataflowTester, "")
+
+	// the last reply was deleted from the thread, it must be cleared along with its reactions
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_slack_thread_after_deletion.csv", "_raw_slack_thread")
+	runMessageSubtasks(dataflowTester, taskData)
+	verifyMessages(dataflowTester, "_after_deletion")
+}
+
+func runMessageSubtasks(dataflowTester *e2ehelper.DataFlowTester, taskData *tasks.SlackTaskData) {
+	dataflowTester.Subtask(tasks.ExtractChannelMessageMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractThreadMeta, taskData)
+	dataflowTester.Subtask(tasks.ConvertChannelMessageMeta, taskData)
+	dataflowTester.Subtask(tasks.ConvertChannelMessageReactionMeta, taskData)
+}
+
+func verifyMessages(dataflowTester *e2ehelper.DataFlowTester, suffix string) {
+	dataflowTester.VerifyTableWithRawData(
+		chat.ChatMessage{},
+		"./snapshot_tables/chat_messages"+suffix+".csv",
+		[]string{
+			"chat_channel_id",
+			"parent_message_id",
+			"type",
+			"original_type",
+			"content",
+			"author_id",
+			"reply_count",
+			"created_date",
+		},
+	)
+	dataflowTester.VerifyTableWithRawData(
+		chat.ChatMessageReaction{},
+		"./snapshot_tables/chat_message_reactions"+suffix+".csv",
+		[]string{},
+	)
+}
@@ -58,6 +58,8 @@ func (p Slack) GetTablesInfo() []dal.Tabler {
 		&models.SlackConnection{},
 		&models.SlackChannelMessage{},
 		&models.SlackChannel{},
+		&models.SlackChannelMessageReaction{},
+		&models.SlackUser{},
 	}
 }
 
@@ -83,6 +85,9 @@ func (p Slack) ScopeConfig() dal.Tabler {
 
 func (p Slack) SubTaskMetas() []plugin.SubTaskMeta {
 	return []plugin.SubTaskMeta{
+		tasks.CollectUserMeta,
+		tasks.ExtractUserMeta,
+
 		tasks.CollectChannelMeta,
 		tasks.ExtractChannelMeta,
 
@@ -91,6 +96,11 @@ func (p Slack) SubTaskMetas() []plugin.SubTaskMeta {
 
 		tasks.CollectThreadMeta,
 		tasks.ExtractThreadMeta,
+
+		tasks.ConvertUserMeta,
+		tasks.ConvertChannelMeta,
+		tasks.ConvertChannelMessageMeta,
+		tasks.ConvertChannelMessageReactionMeta,
 	}
 }
 
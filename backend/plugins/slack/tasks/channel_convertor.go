@@ -0,0 +1,106 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/slack/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertChannel
+
+// ConvertChannel converts the channel of the current task into chat_channels
+func ConvertChannel(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*SlackTaskData)
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.From(&models.SlackChannel{}),
+		dal.Where("connection_id = ? AND id = ?", data.Options.ConnectionId, data.Options.ChannelId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	channelIdGen := didgen.NewDomainIdGenerator(&models.SlackChannel{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.SlackUser{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx:     taskCtx,
+			Options: data.Options,
+			Table:   RAW_CHANNEL_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.SlackChannel{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			slackChannel := inputRow.(*models.SlackChannel)
+			channel := &chat.ChatChannel{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: channelIdGen.Generate(slackChannel.ConnectionId, slackChannel.Id),
+				},
+				Name:        slackChannel.Name,
+				Type:        getChannelType(slackChannel),
+				IsArchived:  slackChannel.IsArchived,
+				MemberCount: slackChannel.NumMembers,
+			}
+			if slackChannel.Creator != "" {
+				channel.CreatorId = accountIdGen.Generate(slackChannel.ConnectionId, slackChannel.Creator)
+			}
+			if slackChannel.Created > 0 {
+				createdDate := time.Unix(int64(slackChannel.Created), 0)
+				channel.CreatedDate = &createdDate
+			}
+			return []interface{}{channel}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+func getChannelType(channel *models.SlackChannel) string {
+	switch {
+	case channel.IsIm:
+		return chat.CHANNEL_TYPE_DIRECT
+	case channel.IsMpim:
+		return chat.CHANNEL_TYPE_GROUP
+	case channel.IsPrivate:
+		return chat.CHANNEL_TYPE_PRIVATE
+	default:
+		return chat.CHANNEL_TYPE_PUBLIC
+	}
+}
+
+var ConvertChannelMeta = plugin.SubTaskMeta{
+	Name:             "convertChannel",
+	EntryPoint:       ConvertChannel,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table _tool_slack_channels into domain layer table chat_channels",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
@@ -0,0 +1,131 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/slack/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertChannelMessage
+
+// messageRawTables are the raw tables messages are extracted from, the domain records are converted from each of them
+// separately so that the records of the messages removed from either table are cleared on re-runs
+var messageRawTables = []string{RAW_CHANNEL_MESSAGE_TABLE, RAW_THREAD_TABLE}
+
+// ConvertChannelMessage converts channel messages and thread replies into chat_messages
+func ConvertChannelMessage(taskCtx plugin.SubTaskContext) errors.Error {
+	for _, rawTable := range messageRawTables {
+		err := convertChannelMessages(taskCtx, rawTable)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertChannelMessages(taskCtx plugin.SubTaskContext, rawTable string) errors.Error {
+	data := taskCtx.GetData().(*SlackTaskData)
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.From(&models.SlackChannelMessage{}),
+		dal.Where("connection_id = ? AND channel_id = ? AND _raw_data_table = ?",
+			data.Options.ConnectionId, data.Options.ChannelId, "_raw_"+rawTable),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	channelIdGen := didgen.NewDomainIdGenerator(&models.SlackChannel{})
+	messageIdGen := didgen.NewDomainIdGenerator(&models.SlackChannelMessage{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.SlackUser{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx:     taskCtx,
+			Options: data.Options,
+			Table:   rawTable,
+		},
+		InputRowType: reflect.TypeOf(models.SlackChannelMessage{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			slackMessage := inputRow.(*models.SlackChannelMessage)
+			message := &chat.ChatMessage{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: messageIdGen.Generate(slackMessage.ConnectionId, slackMessage.ChannelId, slackMessage.Ts),
+				},
+				ChatChannelId: channelIdGen.Generate(slackMessage.ConnectionId, slackMessage.ChannelId),
+				Type:          slackMessage.Type,
+				OriginalType:  slackMessage.Subtype,
+				Content:       slackMessage.Text,
+				ReplyCount:    slackMessage.ReplyCount,
+				CreatedDate:   parseSlackTs(slackMessage.Ts),
+			}
+			if slackMessage.Subtype != "" {
+				message.Type = slackMessage.Subtype
+			}
+			if slackMessage.User != "" {
+				message.AuthorId = accountIdGen.Generate(slackMessage.ConnectionId, slackMessage.User)
+			}
+			// replies carry the ts of the thread root message
+			if slackMessage.ThreadTs != "" && slackMessage.ThreadTs != slackMessage.Ts {
+				message.ParentMessageId = messageIdGen.Generate(slackMessage.ConnectionId, slackMessage.ChannelId, slackMessage.ThreadTs)
+			}
+			return []interface{}{message}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+// parseSlackTs converts a slack timestamp like `1512085950.000216` to time.Time
+func parseSlackTs(ts string) *time.Time {
+	parts := strings.SplitN(ts, ".", 2)
+	sec, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return nil
+	}
+	var usec int64
+	if len(parts) == 2 {
+		usec, _ = strconv.ParseInt(parts[1], 10, 64)
+	}
+	t := time.Unix(sec, usec*int64(time.Microsecond))
+	return &t
+}
+
+var ConvertChannelMessageMeta = plugin.SubTaskMeta{
+	Name:             "convertChannelMessage",
+	EntryPoint:       ConvertChannelMessage,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table _tool_slack_channel_messages into domain layer table chat_messages",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
@@ -66,7 +66,8 @@ func ExtractChannelMessage(taskCtx plugin.SubTaskContext) errors.Error {
 			message.IsLocked = body.IsLocked
 			message.Subscribed = body.Subscribed
 			message.ParentUserId = body.ParentUserId
-			return []interface{}{message}, nil
+			results := []interface{}{message}
+			return append(results, extractReactions(data.Options.ConnectionId, channel.ChannelId, body)...), nil
 		},
 	})
 	if err != nil {
@@ -76,6 +77,23 @@ func ExtractChannelMessage(taskCtx plugin.SubTaskContext) errors.Error {
 	return extractor.Execute()
 }
 
+// extractReactions flattens the reactions of a message into one record per emoji and user
+func extractReactions(connectionId uint64, channelId string, body *apimodels.SlackChannelMessageResultItem) []interface{} {
+	var reactions []interface{}
+	for _, reaction := range body.Reactions {
+		for _, userId := range reaction.Users {
+			reactions = append(reactions, &models.SlackChannelMessageReaction{
+				ConnectionId: connectionId,
+				ChannelId:    channelId,
+				Ts:           body.Ts,
+				Name:         reaction.Name,
+				UserId:       userId,
+			})
+		}
+	}
+	return reactions
+}
+
 var ExtractChannelMessageMeta = plugin.SubTaskMeta{
 	Name:             "extractChannelMessage",
 	EntryPoint:       ExtractChannelMessage,
@@ -0,0 +1,92 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/chat"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/slack/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertChannelMessageReaction
+
+// ConvertChannelMessageReaction converts the emoji reactions of messages into chat_message_reactions
+func ConvertChannelMessageReaction(taskCtx plugin.SubTaskContext) errors.Error {
+	for _, rawTable := range messageRawTables {
+		err := convertChannelMessageReactions(taskCtx, rawTable)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func convertChannelMessageReactions(taskCtx plugin.SubTaskContext, rawTable string) errors.Error {
+	data := taskCtx.GetData().(*SlackTaskData)
+	db := taskCtx.GetDal()
+	cursor, err := db.Cursor(
+		dal.From(&models.SlackChannelMessageReaction{}),
+		dal.Where("connection_id = ? AND channel_id = ? AND _raw_data_table = ?",
+			data.Options.ConnectionId, data.Options.ChannelId, "_raw_"+rawTable),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	messageIdGen := didgen.NewDomainIdGenerator(&models.SlackChannelMessage{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.SlackUser{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx:     taskCtx,
+			Options: data.Options,
+			Table:   rawTable,
+		},
+		InputRowType: reflect.TypeOf(models.SlackChannelMessageReaction{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			reaction := inputRow.(*models.SlackChannelMessageReaction)
+			return []interface{}{
+				&chat.ChatMessageReaction{
+					ChatMessageId: messageIdGen.Generate(reaction.ConnectionId, reaction.ChannelId, reaction.Ts),
+					AccountId:     accountIdGen.Generate(reaction.ConnectionId, reaction.UserId),
+					Reaction:      reaction.Name,
+				},
+			}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
+
+var ConvertChannelMessageReactionMeta = plugin.SubTaskMeta{
+	Name:             "convertChannelMessageReaction",
+	EntryPoint:       ConvertChannelMessageReaction,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table _tool_slack_channel_message_reactions into domain layer table chat_message_reactions",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
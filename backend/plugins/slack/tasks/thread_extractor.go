@@ -66,7 +66,8 @@ func ExtractThread(taskCtx plugin.SubTaskContext) errors.Error {
 			message.IsLocked = body.IsLocked
 			message.Subscribed = body.Subscribed
 			message.ParentUserId = body.ParentUserId
-			return []interface{}{message}, nil
+			results := []interface{}{message}
+			return append(results, extractReactions(data.Options.ConnectionId, threadInput.ChannelId, body)...), nil
 		},
 	})
 	if err != nil {
@@ -0,0 +1,93 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/slack/apimodels"
+)
+
+const RAW_USER_TABLE = "slack_user"
+
+var _ plugin.SubTaskEntryPoint = CollectUser
+
+// CollectUser collect all users of the workspace
+func CollectUser(taskCtx plugin.SubTaskContext) errors.Error {
+	data := taskCtx.GetData().(*SlackTaskData)
+	pageSize := 200
+	collector, err := api.NewApiCollector(api.ApiCollectorArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: SlackApiParams{
+				ConnectionId: data.Options.ConnectionId,
+			},
+			Table: RAW_USER_TABLE,
+		},
+		ApiClient:   data.ApiClient,
+		Incremental: false,
+		UrlTemplate: "users.list",
+		PageSize:    pageSize,
+		GetNextPageCustomData: func(prevReqData *api.RequestData, prevPageResponse *http.Response) (interface{}, errors.Error) {
+			res := apimodels.SlackUserApiResult{}
+			err := api.UnmarshalResponse(prevPageResponse, &res)
+			if err != nil {
+				return nil, err
+			}
+			if res.ResponseMetadata.NextCursor == "" {
+				return nil, api.ErrFinishCollect
+			}
+			return res.ResponseMetadata.NextCursor, nil
+		},
+		Query: func(reqData *api.RequestData) (url.Values, errors.Error) {
+			query := url.Values{}
+			query.Set("limit", strconv.Itoa(pageSize))
+			if pageToken, ok := reqData.CustomData.(string); ok && pageToken != "" {
+				query.Set("cursor", pageToken)
+			}
+			return query, nil
+		},
+		ResponseParser: func(res *http.Response) ([]json.RawMessage, errors.Error) {
+			body := &apimodels.SlackUserApiResult{}
+			err := api.UnmarshalResponse(res, body)
+			if err != nil {
+				return nil, err
+			}
+			return body.Members, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return collector.Execute()
+}
+
+var CollectUserMeta = plugin.SubTaskMeta{
+	Name:             "collectUser",
+	EntryPoint:       CollectUser,
+	EnabledByDefault: true,
+	Description:      "Collect users from Slack api",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_CROSS},
+}
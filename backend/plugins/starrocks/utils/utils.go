@@ -33,6 +33,8 @@ func GetTablesByDomainLayer(domainLayer string) []string {
 			"commit_file_components",
 			"commit_line_change",
 			"repo_snapshot",
+			"file_ownership",
+			"file_churn",
 			"commits_diffs",
 			"ref_commits",
 			"components",
@@ -64,6 +66,7 @@ func GetTablesByDomainLayer(domainLayer string) []string {
 			"project_pr_metrics",
 			"pull_request_issues",
 			"refs_issues_diffs",
+			"team_closures",
 			"team_users",
 			"teams",
 			"user_accounts",
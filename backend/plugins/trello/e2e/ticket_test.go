@@ -0,0 +1,186 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package e2e
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/helpers/e2ehelper"
+	"github.com/apache/incubator-devlake/plugins/trello/impl"
+	"github.com/apache/incubator-devlake/plugins/trello/models"
+	"github.com/apache/incubator-devlake/plugins/trello/tasks"
+)
+
+func TestTrelloTicketDataFlow(t *testing.T) {
+	var trello impl.Trello
+	dataflowTester := e2ehelper.NewDataFlowTester(t, "trello", trello)
+
+	taskData := &tasks.TrelloTaskData{
+		Options: &tasks.TrelloOptions{
+			ConnectionId: 1,
+			BoardId:      "6402f643d23aa9af56b28f4b",
+			ScopeConfig: &models.TrelloScopeConfig{
+				StatusMappings: map[string]string{
+					"🗒 Backlog":                          ticket.TODO,
+					"🗓 Sprint Backlog - [Timeline]":      ticket.TODO,
+					"📅 Working On":                       ticket.IN_PROGRESS,
+					"🧑🏾\u200d💻 Testing [Staging Server]": ticket.IN_PROGRESS,
+					"📆 Sprint - Done [Version: 1.2.0]":   ticket.DONE,
+					"🗄 Sprint - Done [Version: 1.1.0]":   ticket.DONE,
+				},
+			},
+		},
+	}
+
+	// import raw data table
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_trello_lists.csv", "_raw_trello_lists")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_trello_cards.csv", "_raw_trello_cards")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_trello_labels.csv", "_raw_trello_labels")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_trello_members.csv", "_raw_trello_members")
+	dataflowTester.ImportCsvIntoRawTable("./raw_tables/_raw_trello_actions.csv", "_raw_trello_actions")
+	dataflowTester.FlushTabler(&models.TrelloBoard{})
+	dataflowTester.ImportCsvIntoTabler("./raw_tables/_tool_trello_boards.csv", &models.TrelloBoard{})
+
+	// verify extraction
+	dataflowTester.FlushTabler(&models.TrelloList{})
+	dataflowTester.FlushTabler(&models.TrelloCard{})
+	dataflowTester.FlushTabler(&models.TrelloCardLabel{})
+	dataflowTester.FlushTabler(&models.TrelloCardMember{})
+	dataflowTester.FlushTabler(&models.TrelloLabel{})
+	dataflowTester.FlushTabler(&models.TrelloMember{})
+	dataflowTester.FlushTabler(&models.TrelloAction{})
+	dataflowTester.Subtask(tasks.ExtractListMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractCardMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractLabelMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractMemberMeta, taskData)
+	dataflowTester.Subtask(tasks.ExtractActionMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		models.TrelloCardLabel{},
+		"./snapshot_tables/_tool_trello_card_labels.csv",
+		[]string{"card_id", "label_id"},
+	)
+	dataflowTester.VerifyTableWithRawData(
+		models.TrelloCardMember{},
+		"./snapshot_tables/_tool_trello_card_members.csv",
+		[]string{"card_id", "member_id"},
+	)
+	dataflowTester.VerifyTableWithRawData(
+		models.TrelloAction{},
+		"./snapshot_tables/_tool_trello_actions.csv",
+		[]string{
+			"id",
+			"id_board",
+			"id_card",
+			"type",
+			"date",
+			"id_member_creator",
+			"member_creator_name",
+			"list_before_id",
+			"list_before_name",
+			"list_after_id",
+			"list_after_name",
+		},
+	)
+
+	// verify conversion
+	dataflowTester.FlushTabler(&ticket.Board{})
+	dataflowTester.Subtask(tasks.ConvertBoardMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		ticket.Board{},
+		"./snapshot_tables/boards.csv",
+		[]string{"id", "name", "url", "created_date", "type"},
+	)
+
+	dataflowTester.FlushTabler(&crossdomain.Account{})
+	dataflowTester.Subtask(tasks.ConvertMemberMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		crossdomain.Account{},
+		"./snapshot_tables/accounts.csv",
+		[]string{"id", "user_name", "full_name", "created_date"},
+	)
+
+	dataflowTester.FlushTabler(&ticket.Issue{})
+	dataflowTester.FlushTabler(&ticket.BoardIssue{})
+	dataflowTester.Subtask(tasks.ConvertCardMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		ticket.Issue{},
+		"./snapshot_tables/issues.csv",
+		[]string{
+			"id",
+			"url",
+			"issue_key",
+			"title",
+			"type",
+			"original_type",
+			"status",
+			"original_status",
+			"resolution_date",
+			"created_date",
+			"updated_date",
+			"lead_time_minutes",
+			"creator_id",
+			"creator_name",
+			"assignee_id",
+			"assignee_name",
+			"due_date",
+		},
+	)
+	dataflowTester.VerifyTableWithRawData(
+		ticket.BoardIssue{},
+		"./snapshot_tables/board_issues.csv",
+		[]string{"board_id", "issue_id"},
+	)
+
+	dataflowTester.FlushTabler(&ticket.IssueLabel{})
+	dataflowTester.Subtask(tasks.ConvertCardLabelMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		ticket.IssueLabel{},
+		"./snapshot_tables/issue_labels.csv",
+		[]string{"issue_id", "label_name"},
+	)
+
+	dataflowTester.FlushTabler(&ticket.IssueAssignee{})
+	dataflowTester.Subtask(tasks.ConvertCardMemberMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		ticket.IssueAssignee{},
+		"./snapshot_tables/issue_assignees.csv",
+		[]string{"issue_id", "assignee_id", "assignee_name"},
+	)
+
+	dataflowTester.FlushTabler(&ticket.IssueChangelogs{})
+	dataflowTester.Subtask(tasks.ConvertActionMeta, taskData)
+	dataflowTester.VerifyTableWithRawData(
+		ticket.IssueChangelogs{},
+		"./snapshot_tables/issue_changelogs.csv",
+		[]string{
+			"id",
+			"issue_id",
+			"author_id",
+			"author_name",
+			"field_id",
+			"field_name",
+			"original_from_value",
+			"original_to_value",
+			"from_value",
+			"to_value",
+			"created_date",
+		},
+	)
+}
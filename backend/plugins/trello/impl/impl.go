@@ -61,6 +61,9 @@ func (p Trello) GetTablesInfo() []dal.Tabler {
 		&models.TrelloLabel{},
 		&models.TrelloMember{},
 		&models.TrelloCheckItem{},
+		&models.TrelloCardLabel{},
+		&models.TrelloCardMember{},
+		&models.TrelloAction{},
 		&models.TrelloScopeConfig{},
 	}
 }
@@ -89,6 +92,16 @@ func (p Trello) SubTaskMetas() []plugin.SubTaskMeta {
 
 		tasks.CollectMemberMeta,
 		tasks.ExtractMemberMeta,
+
+		tasks.CollectActionMeta,
+		tasks.ExtractActionMeta,
+
+		tasks.ConvertBoardMeta,
+		tasks.ConvertMemberMeta,
+		tasks.ConvertCardMeta,
+		tasks.ConvertCardLabelMeta,
+		tasks.ConvertCardMemberMeta,
+		tasks.ConvertActionMeta,
 	}
 }
 
@@ -116,6 +129,22 @@ func (p Trello) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]i
 	if err != nil {
 		return nil, errors.Default.Wrap(err, "error getting connection for Trello plugin")
 	}
+
+	db := taskCtx.GetDal()
+	if op.ScopeConfigId == 0 {
+		scope := &models.TrelloBoard{}
+		err = db.First(scope, dal.Where("connection_id = ? AND board_id = ?", op.ConnectionId, op.BoardId))
+		if err != nil && !db.IsErrorNotFound(err) {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("fail to find board %s", op.BoardId))
+		}
+		op.ScopeConfigId = scope.ScopeConfigId
+	}
+	if op.ScopeConfig == nil && op.ScopeConfigId != 0 {
+		err = db.First(&op.ScopeConfig, dal.Where("id = ?", op.ScopeConfigId))
+		if err != nil {
+			return nil, errors.BadInput.Wrap(err, "fail to get scopeConfig")
+		}
+	}
 	apiClient, err := tasks.CreateApiClient(taskCtx, connection)
 	if err != nil {
 		return nil, err
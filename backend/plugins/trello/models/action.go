@@ -0,0 +1,49 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package models
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+const (
+	ACTION_TYPE_CREATE_CARD = "createCard"
+	ACTION_TYPE_UPDATE_CARD = "updateCard"
+)
+
+// TrelloAction records a card being created or moved between lists
+type TrelloAction struct {
+	ID                string `gorm:"primaryKey;type:varchar(255)"`
+	IDBoard           string `gorm:"type:varchar(255);index"`
+	IDCard            string `gorm:"type:varchar(255);index"`
+	Type              string `gorm:"type:varchar(100)"`
+	Date              time.Time
+	IDMemberCreator   string `gorm:"type:varchar(255)"`
+	MemberCreatorName string `gorm:"type:varchar(255)"`
+	ListBeforeId      string `gorm:"type:varchar(255)"`
+	ListBeforeName    string `gorm:"type:varchar(255)"`
+	ListAfterId       string `gorm:"type:varchar(255)"`
+	ListAfterName     string `gorm:"type:varchar(255)"`
+	common.NoPKModel
+}
+
+func (TrelloAction) TableName() string {
+	return "_tool_trello_actions"
+}
@@ -27,6 +27,8 @@ type TrelloCard struct {
 	Name             string `gorm:"type:varchar(255)"`
 	Closed           bool
 	DueComplete      bool
+	Desc             string `gorm:"type:text"`
+	Due              *time.Time
 	DateLastActivity time.Time
 	IDBoard          string `gorm:"type:varchar(255)"`
 	IDList           string `gorm:"type:varchar(255)"`
@@ -0,0 +1,79 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts/archived"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+)
+
+var _ plugin.MigrationScript = (*addBoardIdToPrimaryKey)(nil)
+
+type board20261018Before struct {
+	archived.NoPKModel
+	ConnectionId  uint64 `gorm:"primaryKey"`
+	BoardId       string `gorm:"type:varchar(255)"`
+	ScopeConfigId uint64
+	Name          string `gorm:"type:varchar(255)"`
+}
+
+func (board20261018Before) TableName() string {
+	return "_tool_trello_boards"
+}
+
+type board20261018After struct {
+	archived.NoPKModel
+	ConnectionId  uint64 `gorm:"primaryKey"`
+	BoardId       string `gorm:"primaryKey;type:varchar(255)"`
+	ScopeConfigId uint64
+	Name          string `gorm:"type:varchar(255)"`
+}
+
+func (board20261018After) TableName() string {
+	return "_tool_trello_boards"
+}
+
+type addBoardIdToPrimaryKey struct{}
+
+func (script *addBoardIdToPrimaryKey) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.TransformTable(
+		basicRes,
+		script,
+		"_tool_trello_boards",
+		func(s *board20261018Before) (*board20261018After, errors.Error) {
+			return &board20261018After{
+				NoPKModel:     s.NoPKModel,
+				ConnectionId:  s.ConnectionId,
+				BoardId:       s.BoardId,
+				ScopeConfigId: s.ScopeConfigId,
+				Name:          s.Name,
+			}, nil
+		},
+	)
+}
+
+func (*addBoardIdToPrimaryKey) Version() uint64 {
+	return 20261018000002
+}
+
+func (*addBoardIdToPrimaryKey) Name() string {
+	return "add board_id to the primary key of trello boards"
+}
@@ -0,0 +1,68 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package migrationscripts
+
+import (
+	"time"
+
+	"github.com/apache/incubator-devlake/core/context"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/migrationhelper"
+	"github.com/apache/incubator-devlake/plugins/trello/models/migrationscripts/archived"
+)
+
+var _ plugin.MigrationScript = (*addTicketConversion)(nil)
+
+type card20261018 struct {
+	Desc string `gorm:"type:text"`
+	Due  *time.Time
+}
+
+func (card20261018) TableName() string {
+	return "_tool_trello_cards"
+}
+
+type scopeConfig20261018 struct {
+	StatusMappings map[string]string `gorm:"serializer:json"`
+}
+
+func (scopeConfig20261018) TableName() string {
+	return "_tool_trello_scope_configs"
+}
+
+type addTicketConversion struct{}
+
+func (*addTicketConversion) Up(basicRes context.BasicRes) errors.Error {
+	return migrationhelper.AutoMigrateTables(
+		basicRes,
+		&card20261018{},
+		&scopeConfig20261018{},
+		&archived.TrelloCardLabel{},
+		&archived.TrelloCardMember{},
+		&archived.TrelloAction{},
+	)
+}
+
+func (*addTicketConversion) Version() uint64 {
+	return 20261018000001
+}
+
+func (*addTicketConversion) Name() string {
+	return "add card relations, actions and status mappings for trello ticket conversion"
+}
@@ -23,6 +23,8 @@ import (
 
 type TrelloScopeConfig struct {
 	common.ScopeConfig `mapstructure:",squash" json:",inline" gorm:"embedded"`
+	// StatusMappings maps list names to the standard issue statuses (TODO, IN_PROGRESS, DONE)
+	StatusMappings map[string]string `mapstructure:"statusMappings,omitempty" json:"statusMappings" gorm:"serializer:json"`
 }
 
 func (TrelloScopeConfig) TableName() string {
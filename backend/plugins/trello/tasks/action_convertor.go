@@ -0,0 +1,99 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/trello/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertAction
+
+var ConvertActionMeta = plugin.SubTaskMeta{
+	Name:             "ConvertAction",
+	EntryPoint:       ConvertAction,
+	EnabledByDefault: true,
+	Description:      "Convert card movements in tool layer table trello_actions into domain layer table issue_changelogs",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+}
+
+func ConvertAction(taskCtx plugin.SubTaskContext) errors.Error {
+	taskData := taskCtx.GetData().(*TrelloTaskData)
+	db := taskCtx.GetDal()
+	stdStatusMappings := getStdStatusMappings(taskData)
+
+	cursor, err := db.Cursor(
+		dal.From(&models.TrelloAction{}),
+		dal.Where("id_board = ? AND type = ? AND list_before_id != ''", taskData.Options.BoardId, models.ACTION_TYPE_UPDATE_CARD),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	changelogIdGen := didgen.NewDomainIdGenerator(&models.TrelloAction{})
+	issueIdGen := didgen.NewDomainIdGenerator(&models.TrelloCard{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.TrelloMember{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: TrelloApiParams{
+				ConnectionId: taskData.Options.ConnectionId,
+				BoardId:      taskData.Options.BoardId,
+			},
+			Table: RAW_ACTION_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.TrelloAction{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			action := inputRow.(*models.TrelloAction)
+			// moving a card to another list is the status change of the issue
+			changelog := &ticket.IssueChangelogs{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: changelogIdGen.Generate(action.ID),
+				},
+				IssueId:           issueIdGen.Generate(action.IDCard),
+				AuthorName:        action.MemberCreatorName,
+				FieldId:           "idList",
+				FieldName:         "status",
+				OriginalFromValue: action.ListBeforeName,
+				OriginalToValue:   action.ListAfterName,
+				FromValue:         getStdStatus(stdStatusMappings, action.ListBeforeName),
+				ToValue:           getStdStatus(stdStatusMappings, action.ListAfterName),
+				CreatedDate:       action.Date,
+			}
+			if action.IDMemberCreator != "" {
+				changelog.AuthorId = accountIdGen.Generate(action.IDMemberCreator)
+			}
+			return []interface{}{changelog}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
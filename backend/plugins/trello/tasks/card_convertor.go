@@ -0,0 +1,180 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/trello/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertCard
+
+var ConvertCardMeta = plugin.SubTaskMeta{
+	Name:             "ConvertCard",
+	EntryPoint:       ConvertCard,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table trello_cards into domain layer table issues and board_issues",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+}
+
+func ConvertCard(taskCtx plugin.SubTaskContext) errors.Error {
+	taskData := taskCtx.GetData().(*TrelloTaskData)
+	db := taskCtx.GetDal()
+	stdStatusMappings := getStdStatusMappings(taskData)
+
+	// lists of the board, cards get their status from the list they are in
+	var lists []models.TrelloList
+	err := db.All(&lists, dal.Where("id_board = ?", taskData.Options.BoardId))
+	if err != nil {
+		return err
+	}
+	listNames := make(map[string]string, len(lists))
+	for _, list := range lists {
+		listNames[list.ID] = list.Name
+	}
+
+	// the first member of a card is taken as its assignee
+	var cardMembers []struct {
+		CardId   string
+		MemberId string
+		FullName string
+	}
+	err = db.All(&cardMembers,
+		dal.Select("cm.card_id, cm.member_id, m.full_name"),
+		dal.From("_tool_trello_card_members cm"),
+		dal.Join("INNER JOIN _tool_trello_cards c ON c.id = cm.card_id"),
+		dal.Join("LEFT JOIN _tool_trello_members m ON m.id = cm.member_id"),
+		dal.Where("c.id_board = ?", taskData.Options.BoardId),
+		dal.Orderby("cm.card_id, cm.member_id"),
+	)
+	if err != nil {
+		return err
+	}
+	assignees := make(map[string]*models.TrelloMember)
+	for _, cardMember := range cardMembers {
+		if _, ok := assignees[cardMember.CardId]; !ok {
+			assignees[cardMember.CardId] = &models.TrelloMember{ID: cardMember.MemberId, FullName: cardMember.FullName}
+		}
+	}
+
+	// creators come from createCard actions, and a card is resolved when it was last moved into a DONE list
+	var actions []models.TrelloAction
+	err = db.All(&actions,
+		dal.Where("id_board = ?", taskData.Options.BoardId),
+		dal.Orderby("date"),
+	)
+	if err != nil {
+		return err
+	}
+	creators := make(map[string]*models.TrelloAction)
+	resolutionDates := make(map[string]*time.Time)
+	for i := range actions {
+		action := &actions[i]
+		if action.Type == models.ACTION_TYPE_CREATE_CARD {
+			creators[action.IDCard] = action
+		}
+		if getStdStatus(stdStatusMappings, action.ListAfterName) == ticket.DONE {
+			resolutionDates[action.IDCard] = &action.Date
+		} else {
+			delete(resolutionDates, action.IDCard)
+		}
+	}
+
+	cursor, err := db.Cursor(
+		dal.From(&models.TrelloCard{}),
+		dal.Where("id_board = ?", taskData.Options.BoardId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	boardId := didgen.NewDomainIdGenerator(&models.TrelloBoard{}).Generate(taskData.Options.ConnectionId, taskData.Options.BoardId)
+	issueIdGen := didgen.NewDomainIdGenerator(&models.TrelloCard{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.TrelloMember{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: TrelloApiParams{
+				ConnectionId: taskData.Options.ConnectionId,
+				BoardId:      taskData.Options.BoardId,
+			},
+			Table: RAW_CARD_TABLE,
+		},
+		InputRowType: reflect.TypeOf(models.TrelloCard{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			card := inputRow.(*models.TrelloCard)
+			listName := listNames[card.IDList]
+			issue := &ticket.Issue{
+				DomainEntity: domainlayer.DomainEntity{
+					Id: issueIdGen.Generate(card.ID),
+				},
+				Url:            card.Url,
+				IssueKey:       strconv.Itoa(card.IDShort),
+				Title:          card.Name,
+				Description:    card.Desc,
+				Type:           ticket.TASK,
+				OriginalType:   "card",
+				Status:         getStdStatus(stdStatusMappings, listName),
+				OriginalStatus: listName,
+				CreatedDate:    getCreatedDate(card.ID),
+				UpdatedDate:    &card.DateLastActivity,
+				DueDate:        card.Due,
+			}
+			if issue.Status == ticket.DONE {
+				issue.ResolutionDate = resolutionDates[card.ID]
+				if issue.ResolutionDate == nil {
+					issue.ResolutionDate = &card.DateLastActivity
+				}
+				if issue.CreatedDate != nil && issue.ResolutionDate.After(*issue.CreatedDate) {
+					leadTimeMinutes := uint(issue.ResolutionDate.Sub(*issue.CreatedDate).Minutes())
+					issue.LeadTimeMinutes = &leadTimeMinutes
+				}
+			}
+			if creator, ok := creators[card.ID]; ok && creator.IDMemberCreator != "" {
+				issue.CreatorId = accountIdGen.Generate(creator.IDMemberCreator)
+				issue.CreatorName = creator.MemberCreatorName
+			}
+			if assignee, ok := assignees[card.ID]; ok {
+				issue.AssigneeId = accountIdGen.Generate(assignee.ID)
+				issue.AssigneeName = assignee.FullName
+			}
+			boardIssue := &ticket.BoardIssue{
+				BoardId: boardId,
+				IssueId: issue.Id,
+			}
+			return []interface{}{issue, boardIssue}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
@@ -32,7 +32,8 @@ var ExtractCardMeta = plugin.SubTaskMeta{
 	Name:             "ExtractCard",
 	EntryPoint:       ExtractCard,
 	EnabledByDefault: true,
-	Description:      "Extract raw data into tool layer table trello_cards",
+	Description:      "Extract raw data into tool layer table trello_cards, trello_card_labels and trello_card_members",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
 }
 
 type TrelloApiCard struct {
@@ -44,7 +45,7 @@ type TrelloApiCard struct {
 	DateLastActivity      time.Time     `json:"dateLastActivity"`
 	Desc                  string        `json:"desc"`
 	DescData              interface{}   `json:"descData"`
-	Due                   interface{}   `json:"due"`
+	Due                   *time.Time    `json:"due"`
 	DueReminder           interface{}   `json:"dueReminder"`
 	Email                 interface{}   `json:"email"`
 	IDBoard               string        `json:"idBoard"`
@@ -87,12 +88,14 @@ func ExtractCard(taskCtx plugin.SubTaskContext) errors.Error {
 			if err != nil {
 				return nil, err
 			}
-			return []interface{}{
+			results := []interface{}{
 				&models.TrelloCard{
 					ID:               apiCard.ID,
 					Name:             apiCard.Name,
 					Closed:           apiCard.Closed,
 					DueComplete:      apiCard.DueComplete,
+					Desc:             apiCard.Desc,
+					Due:              apiCard.Due,
 					DateLastActivity: apiCard.DateLastActivity,
 					IDBoard:          apiCard.IDBoard,
 					IDList:           apiCard.IDList,
@@ -103,7 +106,20 @@ func ExtractCard(taskCtx plugin.SubTaskContext) errors.Error {
 					Subscribed:       apiCard.Subscribed,
 					Url:              apiCard.Url,
 				},
-			}, nil
+			}
+			for _, labelId := range apiCard.IDLabels {
+				results = append(results, &models.TrelloCardLabel{
+					CardId:  apiCard.ID,
+					LabelId: labelId,
+				})
+			}
+			for _, memberId := range apiCard.IDMembers {
+				results = append(results, &models.TrelloCardMember{
+					CardId:   apiCard.ID,
+					MemberId: memberId,
+				})
+			}
+			return results, nil
 		},
 	})
 	if err != nil {
@@ -0,0 +1,97 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/trello/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertCardLabel
+
+var ConvertCardLabelMeta = plugin.SubTaskMeta{
+	Name:             "ConvertCardLabel",
+	EntryPoint:       ConvertCardLabel,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table trello_card_labels into domain layer table issue_labels",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+}
+
+type cardLabel struct {
+	CardId string
+	Name   string
+	Color  string
+	common.RawDataOrigin
+}
+
+func ConvertCardLabel(taskCtx plugin.SubTaskContext) errors.Error {
+	taskData := taskCtx.GetData().(*TrelloTaskData)
+	db := taskCtx.GetDal()
+
+	cursor, err := db.Cursor(
+		dal.Select("cl.*, l.name, l.color"),
+		dal.From("_tool_trello_card_labels cl"),
+		dal.Join("INNER JOIN _tool_trello_cards c ON c.id = cl.card_id"),
+		dal.Join("INNER JOIN _tool_trello_labels l ON l.id = cl.label_id"),
+		dal.Where("c.id_board = ?", taskData.Options.BoardId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	issueIdGen := didgen.NewDomainIdGenerator(&models.TrelloCard{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: TrelloApiParams{
+				ConnectionId: taskData.Options.ConnectionId,
+				BoardId:      taskData.Options.BoardId,
+			},
+			Table: RAW_CARD_TABLE,
+		},
+		InputRowType: reflect.TypeOf(cardLabel{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			label := inputRow.(*cardLabel)
+			// labels in trello may only have a color
+			labelName := label.Name
+			if labelName == "" {
+				labelName = label.Color
+			}
+			issueLabel := &ticket.IssueLabel{
+				IssueId:   issueIdGen.Generate(label.CardId),
+				LabelName: labelName,
+			}
+			return []interface{}{issueLabel}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
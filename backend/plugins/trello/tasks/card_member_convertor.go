@@ -0,0 +1,94 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"reflect"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/didgen"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/apache/incubator-devlake/plugins/trello/models"
+)
+
+var _ plugin.SubTaskEntryPoint = ConvertCardMember
+
+var ConvertCardMemberMeta = plugin.SubTaskMeta{
+	Name:             "ConvertCardMember",
+	EntryPoint:       ConvertCardMember,
+	EnabledByDefault: true,
+	Description:      "Convert tool layer table trello_card_members into domain layer table issue_assignees",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
+}
+
+type cardMember struct {
+	CardId   string
+	MemberId string
+	FullName string
+	common.RawDataOrigin
+}
+
+func ConvertCardMember(taskCtx plugin.SubTaskContext) errors.Error {
+	taskData := taskCtx.GetData().(*TrelloTaskData)
+	db := taskCtx.GetDal()
+
+	cursor, err := db.Cursor(
+		dal.Select("cm.*, m.full_name"),
+		dal.From("_tool_trello_card_members cm"),
+		dal.Join("INNER JOIN _tool_trello_cards c ON c.id = cm.card_id"),
+		dal.Join("LEFT JOIN _tool_trello_members m ON m.id = cm.member_id"),
+		dal.Where("c.id_board = ?", taskData.Options.BoardId),
+	)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close()
+
+	issueIdGen := didgen.NewDomainIdGenerator(&models.TrelloCard{})
+	accountIdGen := didgen.NewDomainIdGenerator(&models.TrelloMember{})
+	converter, err := api.NewDataConverter(api.DataConverterArgs{
+		RawDataSubTaskArgs: api.RawDataSubTaskArgs{
+			Ctx: taskCtx,
+			Params: TrelloApiParams{
+				ConnectionId: taskData.Options.ConnectionId,
+				BoardId:      taskData.Options.BoardId,
+			},
+			Table: RAW_CARD_TABLE,
+		},
+		InputRowType: reflect.TypeOf(cardMember{}),
+		Input:        cursor,
+		Convert: func(inputRow interface{}) ([]interface{}, errors.Error) {
+			member := inputRow.(*cardMember)
+			issueAssignee := &ticket.IssueAssignee{
+				IssueId:      issueIdGen.Generate(member.CardId),
+				AssigneeId:   accountIdGen.Generate(member.MemberId),
+				AssigneeName: member.FullName,
+			}
+			return []interface{}{issueAssignee}, nil
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return converter.Execute()
+}
@@ -32,6 +32,7 @@ var ExtractListMeta = plugin.SubTaskMeta{
 	EntryPoint:       ExtractList,
 	EnabledByDefault: true,
 	Description:      "Extract raw data into tool layer table trello_lists",
+	DomainTypes:      []string{plugin.DOMAIN_TYPE_TICKET},
 }
 
 type TrelloApiList struct {
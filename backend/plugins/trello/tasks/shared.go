@@ -0,0 +1,59 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tasks
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models/domainlayer/ticket"
+)
+
+// getStdStatusMappings returns the standard status of each list name configured in the scope config
+func getStdStatusMappings(data *TrelloTaskData) map[string]string {
+	stdStatusMappings := make(map[string]string)
+	if data.Options.ScopeConfig == nil {
+		return stdStatusMappings
+	}
+	for listName, stdStatus := range data.Options.ScopeConfig.StatusMappings {
+		stdStatusMappings[listName] = strings.ToUpper(stdStatus)
+	}
+	return stdStatusMappings
+}
+
+// getStdStatus returns the standard status of cards in the given list, or OTHER if the list is not mapped
+func getStdStatus(stdStatusMappings map[string]string, listName string) string {
+	if stdStatus, ok := stdStatusMappings[listName]; ok && stdStatus != "" {
+		return stdStatus
+	}
+	return ticket.OTHER
+}
+
+// getCreatedDate extracts the creation time embedded in the first 8 hex digits of a Trello object id
+func getCreatedDate(id string) *time.Time {
+	if len(id) < 8 {
+		return nil
+	}
+	seconds, err := strconv.ParseInt(id[:8], 16, 64)
+	if err != nil {
+		return nil
+	}
+	createdDate := time.Unix(seconds, 0).UTC()
+	return &createdDate
+}
@@ -23,9 +23,10 @@ import (
 )
 
 type TrelloOptions struct {
-	ConnectionId  uint64 `json:"connectionId" mapstructure:"connectionId,omitempty"`
-	BoardId       string `json:"boardId" mapstructure:"boardId,omitempty"`
-	ScopeConfigId uint64 `json:"scopeConfigId" mapstructure:"scopeConfigId,omitempty"`
+	ConnectionId  uint64                    `json:"connectionId" mapstructure:"connectionId,omitempty"`
+	BoardId       string                    `json:"boardId" mapstructure:"boardId,omitempty"`
+	ScopeConfigId uint64                    `json:"scopeConfigId" mapstructure:"scopeConfigId,omitempty"`
+	ScopeConfig   *models.TrelloScopeConfig `json:"scopeConfig" mapstructure:"scopeConfig,omitempty"`
 }
 
 type TrelloTaskData struct {
@@ -34,6 +34,7 @@ import (
 	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
 	"github.com/apache/incubator-devlake/impls/logruslog"
 	_ "github.com/apache/incubator-devlake/server/api/docs"
 	"github.com/apache/incubator-devlake/server/api/ping"
@@ -107,6 +108,10 @@ func CreateApiServer() *gin.Engine {
 	// Api keys
 	router.Use(RestAuthentication(router, basicRes))
 	router.Use(OAuth2ProxyAuthentication(basicRes))
+	router.Use(RbacAuthorization(basicRes))
+
+	// Protected routes, metrics may be scraped through `/rest/metrics` with an api key
+	router.GET("/metrics", gin.WrapH(metricshelper.Handler()))
 
 	return router
 }
@@ -77,7 +77,11 @@ func DeleteApiKey(c *gin.Context) {
 		shared.ApiOutputError(c, errors.BadInput.Wrap(err, "bad apiKeyId format supplied"))
 		return
 	}
-	err = services.DeleteApiKey(id)
+	user, exist := shared.GetUser(c)
+	if !exist {
+		logruslog.Global.Warn(nil, "user doesn't exist")
+	}
+	err = services.DeleteApiKey(user, id)
 	if err != nil {
 		shared.ApiOutputError(c, errors.Default.Wrap(err, "error deleting api key"))
 		return
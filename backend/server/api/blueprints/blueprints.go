@@ -50,7 +50,8 @@ func Post(c *gin.Context) {
 		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
 		return
 	}
-	err = services.CreateBlueprint(blueprint)
+	user, _ := shared.GetUser(c)
+	err = services.CreateBlueprint(user, blueprint)
 	if err != nil {
 		shared.ApiOutputError(c, errors.Default.Wrap(err, "error creating blueprint"))
 		return
@@ -109,6 +110,30 @@ func Get(c *gin.Context) {
 	shared.ApiOutputSuccess(c, blueprint, http.StatusOK)
 }
 
+// @Summary preview the plan of a blueprint
+// @Description expand the plan of the blueprint with its sync policy without creating any pipeline
+// @Tags framework/blueprints
+// @Accept application/json
+// @Param blueprintId path int true "blueprint id"
+// @Success 200  {object} services.BlueprintPlanPreview
+// @Failure 400  {object} shared.ApiBody "Bad Request"
+// @Failure 500  {object} shared.ApiBody "Internal Error"
+// @Router /blueprints/{blueprintId}/plan-preview [get]
+func GetPlanPreview(c *gin.Context) {
+	blueprintId := c.Param("blueprintId")
+	id, err := strconv.ParseUint(blueprintId, 10, 64)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, "bad blueprintId format supplied"))
+		return
+	}
+	preview, err := services.PreviewBlueprintPlan(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error previewing blueprint plan"))
+		return
+	}
+	shared.ApiOutputSuccess(c, preview, http.StatusOK)
+}
+
 // // @Summary delete blueprints
 // // @Description Delete BluePrints
 // // @Tags framework/blueprints
@@ -152,7 +177,8 @@ func Patch(c *gin.Context) {
 		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
 		return
 	}
-	blueprint, err := services.PatchBlueprint(id, body)
+	user, _ := shared.GetUser(c)
+	blueprint, err := services.PatchBlueprint(user, id, body)
 	if err != nil {
 		shared.ApiOutputError(c, errors.Default.Wrap(err, "error patching the blueprint"))
 		return
@@ -243,7 +269,8 @@ func Delete(c *gin.Context) {
 		shared.ApiOutputError(c, errors.BadInput.Wrap(err, "bad blueprintId format supplied"))
 		return
 	}
-	err = services.DeleteBlueprint(id)
+	user, _ := shared.GetUser(c)
+	err = services.DeleteBlueprint(user, id)
 	if err != nil {
 		shared.ApiOutputError(c, errors.Default.Wrap(err, "error deleting blueprint"))
 		return
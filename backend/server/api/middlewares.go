@@ -18,22 +18,30 @@ limitations under the License.
 package api
 
 import (
+	gocontext "context"
 	"encoding/base64"
 	"fmt"
 	"github.com/apache/incubator-devlake/core/log"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/apache/incubator-devlake/core/context"
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/models/common"
 	"github.com/apache/incubator-devlake/helpers/apikeyhelper"
+	"github.com/apache/incubator-devlake/server/api/shared"
+	"github.com/apache/incubator-devlake/server/services"
 	"github.com/gin-gonic/gin"
 )
 
+// apiKeyUserKey carries the user of the api key through router.HandleContext, which resets the keys of the gin context
+type apiKeyUserKey struct{}
+
 func getOAuthUserInfo(c *gin.Context) (*common.User, error) {
 	if c == nil {
 		return nil, errors.Default.New("request is nil")
@@ -113,6 +121,10 @@ func RestAuthentication(router *gin.Engine, basicRes context.BasicRes) gin.Handl
 		path := c.Request.URL.Path
 		// Only open api needs to check api key
 		if !strings.HasPrefix(path, "/rest") {
+			// the request redirected from the open api is made by the user of the api key
+			if user, ok := c.Request.Context().Value(apiKeyUserKey{}).(*common.User); ok {
+				c.Set(common.USER, user)
+			}
 			logger.Debug("path %s will continue", path)
 			c.Next()
 			return
@@ -207,9 +219,108 @@ func CheckAuthorizationHeader(c *gin.Context, logger log.Logger, db dal.Dal, api
 
 	logger.Info("redirect path: %s to: %s", c.Request.URL.Path, path)
 	c.Request.URL.Path = path
-	c.Set(common.USER, &common.User{
+	user := &common.User{
 		Name:  apiKey.Creator.Creator,
 		Email: apiKey.Creator.CreatorEmail,
-	})
+	}
+	c.Set(common.USER, user)
+	c.Request = c.Request.WithContext(gocontext.WithValue(c.Request.Context(), apiKeyUserKey{}, user))
 	return true
 }
+
+// isRbacEnabled and authorizeUser are replaced by the tests
+var isRbacEnabled = services.IsRbacEnabled
+var authorizeUser = services.AuthorizeUser
+
+// RbacAuthorization rejects the requests of the users who are not granted the required role when RBAC_ENABLED is true
+func RbacAuthorization(basicRes context.BasicRes) gin.HandlerFunc {
+	logger := basicRes.GetLogger()
+	return func(c *gin.Context) {
+		if !isRbacEnabled() || isRbacExempted(c) {
+			c.Next()
+			return
+		}
+		user, exist := shared.GetUser(c)
+		if !exist {
+			c.Abort()
+			c.JSON(http.StatusUnauthorized, &apiBody{
+				Success: false,
+				Message: "user is not authenticated",
+			})
+			return
+		}
+		role, projectName, err := getRequiredRole(c)
+		if err != nil {
+			logger.Error(err, "get required role")
+			c.Abort()
+			c.JSON(http.StatusInternalServerError, &apiBody{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+		authorized, err := authorizeUser(user, role, projectName)
+		if err != nil {
+			logger.Error(err, "authorize user")
+			c.Abort()
+			c.JSON(http.StatusInternalServerError, &apiBody{
+				Success: false,
+				Message: err.Error(),
+			})
+			return
+		}
+		if !authorized {
+			c.Abort()
+			message := fmt.Sprintf("%s role is required", role)
+			if projectName != "" {
+				message = fmt.Sprintf("%s role on project %s is required", role, projectName)
+			}
+			c.JSON(http.StatusForbidden, &apiBody{
+				Success: false,
+				Message: message,
+			})
+			return
+		}
+		c.Next()
+	}
+}
+
+// isRbacExempted returns true for the routes which authenticate the requests by themselves, i.e. webhooks verifying
+// the signatures of the payloads
+func isRbacExempted(c *gin.Context) bool {
+	path := c.FullPath()
+	if strings.HasPrefix(path, "/swagger/") {
+		return true
+	}
+	return c.Request.Method == http.MethodPost &&
+		strings.HasPrefix(path, "/plugins/") &&
+		strings.HasSuffix(path, "/connections/:connectionId/events")
+}
+
+// getRequiredRole returns the role required by the route, and the project it is required on if the route operates
+// on a project, a blueprint or a pipeline
+func getRequiredRole(c *gin.Context) (string, string, errors.Error) {
+	path := c.FullPath()
+	for _, prefix := range []string{"/api-keys", "/role-bindings", "/audit-logs", "/raw-data-retention-policies", "/proceed-db-migration", "/metrics"} {
+		if strings.HasPrefix(path, prefix) {
+			return models.ROLE_ADMIN, "", nil
+		}
+	}
+	role := models.ROLE_PROJECT_MAINTAINER
+	switch c.Request.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		role = models.ROLE_VIEWER
+	}
+	if projectName := c.Param("projectName"); projectName != "" {
+		return role, projectName, nil
+	}
+	if blueprintId, err := strconv.ParseUint(c.Param("blueprintId"), 10, 64); err == nil {
+		projectName, err := services.GetProjectNameByBlueprintId(blueprintId)
+		return role, projectName, err
+	}
+	if pipelineId, err := strconv.ParseUint(c.Param("pipelineId"), 10, 64); err == nil {
+		projectName, err := services.GetProjectNameByPipelineId(pipelineId)
+		return role, projectName, err
+	}
+	return role, "", nil
+}
@@ -0,0 +1,119 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/helpers/apikeyhelper"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	mockcontext "github.com/apache/incubator-devlake/mocks/core/context"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/apache/incubator-devlake/server/api/shared"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// newRbacTestRouter sets up the middlewares like the api server, alice is a viewer and root is an admin
+func newRbacTestRouter(t *testing.T) *gin.Engine {
+	t.Setenv("ENCRYPTION_SECRET", "test-encryption-secret")
+	gin.SetMode(gin.TestMode)
+	mockDal := mockdal.NewDal(t)
+	mockRes := mockcontext.NewBasicRes(t)
+	mockRes.On("GetDal").Return(mockDal)
+	mockRes.On("GetLogger").Return(unithelper.DummyLogger())
+
+	apiKeyHelper := apikeyhelper.NewApiKeyHelper(mockRes, unithelper.DummyLogger())
+	creators := map[string]string{}
+	for _, creator := range []string{"alice", "root"} {
+		digest, err := apiKeyHelper.DigestToken(creator + "-key")
+		assert.Nil(t, err)
+		creators[digest] = creator
+	}
+	mockDal.On("First", mock.Anything, mock.Anything).Return(func(dst interface{}, clauses ...dal.Clause) errors.Error {
+		creator, ok := creators[clauses[0].Data.(dal.DalClause).Params[0].(string)]
+		if !ok {
+			return errors.NotFound.New("api key not found")
+		}
+		*dst.(*models.ApiKey) = models.ApiKey{Creator: common.Creator{Creator: creator}, AllowedPath: ".*"}
+		return nil
+	}).Maybe()
+	mockDal.On("IsErrorNotFound", mock.Anything).Return(true).Maybe()
+
+	oldIsRbacEnabled, oldAuthorizeUser := isRbacEnabled, authorizeUser
+	isRbacEnabled = func() bool { return true }
+	authorizeUser = func(user *common.User, role string, projectName string) (bool, errors.Error) {
+		return user.Name == "root" || (user.Name == "alice" && role == models.ROLE_VIEWER), nil
+	}
+	t.Cleanup(func() {
+		isRbacEnabled, authorizeUser = oldIsRbacEnabled, oldAuthorizeUser
+	})
+
+	router := gin.New()
+	router.Use(RestAuthentication(router, mockRes))
+	router.Use(OAuth2ProxyAuthentication(mockRes))
+	router.Use(RbacAuthorization(mockRes))
+	handler := func(c *gin.Context) {
+		user, _ := shared.GetUser(c)
+		c.String(http.StatusOK, user.Name)
+	}
+	router.GET("/projects", handler)
+	router.POST("/projects", handler)
+	router.GET("/metrics", handler)
+	return router
+}
+
+func TestRbacAuthorizationWithApiKeys(t *testing.T) {
+	router := newRbacTestRouter(t)
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		header http.Header
+		status int
+		user   string
+	}{
+		{"viewer with api key", http.MethodGet, "/rest/projects", http.Header{"Authorization": {"Bearer alice-key"}}, http.StatusOK, "alice"},
+		{"viewer with api key modifying", http.MethodPost, "/rest/projects", http.Header{"Authorization": {"Bearer alice-key"}}, http.StatusForbidden, ""},
+		{"viewer with api key scraping metrics", http.MethodGet, "/rest/metrics", http.Header{"Authorization": {"Bearer alice-key"}}, http.StatusForbidden, ""},
+		{"admin with api key scraping metrics", http.MethodGet, "/rest/metrics", http.Header{"Authorization": {"Bearer root-key"}}, http.StatusOK, "root"},
+		{"invalid api key", http.MethodGet, "/rest/projects", http.Header{"Authorization": {"Bearer bob-key"}}, http.StatusForbidden, ""},
+		{"missing api key", http.MethodGet, "/rest/projects", http.Header{}, http.StatusUnauthorized, ""},
+		{"oauth2 proxy user", http.MethodGet, "/projects", http.Header{"X-Forwarded-User": {"alice"}}, http.StatusOK, "alice"},
+		{"anonymous user", http.MethodGet, "/projects", http.Header{}, http.StatusUnauthorized, ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			req := httptest.NewRequest(c.method, c.path, nil)
+			req.Header = c.header
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+			assert.Equal(t, c.status, w.Code)
+			if c.status == http.StatusOK {
+				assert.Equal(t, c.user, w.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,205 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notifications
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/server/api/shared"
+	"github.com/apache/incubator-devlake/server/services"
+	"github.com/gin-gonic/gin"
+)
+
+type PaginatedNotificationChannels struct {
+	Channels []*models.NotificationChannel `json:"channels"`
+	Count    int64                         `json:"count"`
+}
+
+type PaginatedNotifications struct {
+	Notifications []*models.Notification `json:"notifications"`
+	Count         int64                  `json:"count"`
+}
+
+// @Summary Get list of notification channels
+// @Description GET /notification-channels?page=1&pageSize=10&projectName=demo
+// @Tags framework/notifications
+// @Param page query int false "query"
+// @Param pageSize query int false "query"
+// @Param projectName query string false "project name"
+// @Param blueprintId query int false "blueprint id"
+// @Param type query string false "webhook, slack, email or teams"
+// @Success 200  {object} PaginatedNotificationChannels
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-channels [get]
+func GetNotificationChannels(c *gin.Context) {
+	var query services.NotificationChannelQuery
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	channels, count, err := services.GetNotificationChannels(&query)
+	if err != nil {
+		shared.ApiOutputAbort(c, errors.Default.Wrap(err, "error getting notification channels"))
+		return
+	}
+	shared.ApiOutputSuccess(c, PaginatedNotificationChannels{
+		Channels: channels,
+		Count:    count,
+	}, http.StatusOK)
+}
+
+// @Summary Get a notification channel
+// @Description Get a notification channel
+// @Tags framework/notifications
+// @Param channelId path int true "channel id"
+// @Success 200  {object} models.NotificationChannel
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-channels/{channelId} [get]
+func GetNotificationChannel(c *gin.Context) {
+	id, err := getChannelId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	channel, err := services.GetNotificationChannel(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting notification channel"))
+		return
+	}
+	shared.ApiOutputSuccess(c, channel, http.StatusOK)
+}
+
+// @Summary Create a notification channel
+// @Description Create a notification channel of a blueprint or a project
+// @Tags framework/notifications
+// @Accept application/json
+// @Param channel body models.NotificationChannel true "json"
+// @Success 200  {object} models.NotificationChannel
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-channels [post]
+func PostNotificationChannel(c *gin.Context) {
+	channel := &models.NotificationChannel{}
+	err := c.ShouldBind(channel)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	user, _ := shared.GetUser(c)
+	channel, err = services.CreateNotificationChannel(user, channel)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error creating notification channel"))
+		return
+	}
+	shared.ApiOutputSuccess(c, channel, http.StatusCreated)
+}
+
+// @Summary Patch a notification channel
+// @Description Patch a notification channel
+// @Tags framework/notifications
+// @Accept application/json
+// @Param channelId path int true "channel id"
+// @Param channel body models.NotificationChannel true "json"
+// @Success 200  {object} models.NotificationChannel
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-channels/{channelId} [patch]
+func PatchNotificationChannel(c *gin.Context) {
+	id, err := getChannelId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	var body map[string]interface{}
+	if e := c.ShouldBind(&body); e != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(e, shared.BadRequestBody))
+		return
+	}
+	channel, err := services.PatchNotificationChannel(id, body)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error patching notification channel"))
+		return
+	}
+	shared.ApiOutputSuccess(c, channel, http.StatusOK)
+}
+
+// @Summary Delete a notification channel
+// @Description Delete a notification channel
+// @Tags framework/notifications
+// @Param channelId path int true "channel id"
+// @Success 200
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notification-channels/{channelId} [delete]
+func DeleteNotificationChannel(c *gin.Context) {
+	id, err := getChannelId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	err = services.DeleteNotificationChannel(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error deleting notification channel"))
+		return
+	}
+	shared.ApiOutputSuccess(c, nil, http.StatusOK)
+}
+
+// @Summary Get list of delivered notifications
+// @Description GET /notifications?page=1&pageSize=10&channelId=1&status=FAILED
+// @Tags framework/notifications
+// @Param page query int false "query"
+// @Param pageSize query int false "query"
+// @Param channelId query int false "channel id"
+// @Param pipelineId query int false "pipeline id"
+// @Param status query string false "PENDING, SENT or FAILED"
+// @Success 200  {object} PaginatedNotifications
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /notifications [get]
+func GetNotifications(c *gin.Context) {
+	var query services.NotificationQuery
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	notifications, count, err := services.GetNotifications(&query)
+	if err != nil {
+		shared.ApiOutputAbort(c, errors.Default.Wrap(err, "error getting notifications"))
+		return
+	}
+	shared.ApiOutputSuccess(c, PaginatedNotifications{
+		Notifications: notifications,
+		Count:         count,
+	}, http.StatusOK)
+}
+
+func getChannelId(c *gin.Context) (uint64, errors.Error) {
+	id, err := strconv.ParseUint(c.Param("channelId"), 10, 64)
+	if err != nil {
+		return 0, errors.BadInput.Wrap(err, "bad channelId format supplied")
+	}
+	return id, nil
+}
@@ -173,6 +173,7 @@ func DownloadLogs(c *gin.Context) {
 // @Tags framework/pipelines
 // @Accept application/json
 // @Param pipelineId path int true "pipelineId"
+// @Param mode query string false "resume: skip the subtasks finished successfully in the previous attempt"
 // @Success 200  {object} []models.Task
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
@@ -184,7 +185,12 @@ func PostRerun(c *gin.Context) {
 		shared.ApiOutputError(c, errors.BadInput.Wrap(err, "bad pipelineID format supplied"))
 		return
 	}
-	rerunTasks, err := services.RerunPipeline(id, nil)
+	resume, err := services.ParseRerunMode(c.Query("mode"))
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	rerunTasks, err := services.RerunPipeline(id, nil, resume)
 	if err != nil {
 		shared.ApiOutputError(c, errors.Default.Wrap(err, "failed to rerun pipeline"))
 		return
@@ -200,3 +206,32 @@ func PostRerun(c *gin.Context) {
 	}
 	shared.ApiOutputSuccess(c, rerunTasks, http.StatusOK)
 }
+
+// @Summary Get performance stats of pipelines
+// @Description Aggregate the tasks began in the time window by plugin, subtask, connection or scope, with the p50/p95
+// @Description duration in seconds, failure rate, records processed and api requests made by the subtasks
+// @Tags framework/pipelines
+// @Param groupBy path string true "plugins, subtasks, connections or scopes"
+// @Param startDate query string false "start date of the time window, e.g. 2024-01-01, defaults to 30 days before endDate"
+// @Param endDate query string false "end date of the time window, e.g. 2024-01-31, defaults to today"
+// @Param plugin query string false "plugin name"
+// @Param blueprint_id query int false "blueprint id"
+// @Param interval query string false "day, week or month, split the stats into a time-series"
+// @Success 200  {object} []services.PipelineStats
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /pipelines/stats/{groupBy} [get]
+func GetStats(c *gin.Context) {
+	var query services.PipelineStatsQuery
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	stats, err := services.GetPipelineStats(c.Param("groupBy"), &query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting pipeline stats"))
+		return
+	}
+	shared.ApiOutputSuccess(c, stats, http.StatusOK)
+}
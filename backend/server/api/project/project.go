@@ -125,7 +125,8 @@ func PostProject(c *gin.Context) {
 		return
 	}
 
-	projectOutput, err := services.CreateProject(projectInput)
+	user, _ := shared.GetUser(c)
+	projectOutput, err := services.CreateProject(user, projectInput)
 	if err != nil {
 		shared.ApiOutputError(c, errors.Default.Wrap(err, "error creating project"))
 		return
@@ -153,7 +154,8 @@ func PatchProject(c *gin.Context) {
 		return
 	}
 
-	projectOutput, err := services.PatchProject(projectName, body)
+	user, _ := shared.GetUser(c)
+	projectOutput, err := services.PatchProject(user, projectName, body)
 	if err != nil {
 		shared.ApiOutputError(c, errors.Default.Wrap(err, "error patch project"))
 		return
@@ -172,10 +174,102 @@ func PatchProject(c *gin.Context) {
 // @Router /projects/:projectName [delete]
 func DeleteProject(c *gin.Context) {
 	projectName := c.Param("projectName")
-	err := services.DeleteProject(projectName)
+	user, _ := shared.GetUser(c)
+	err := services.DeleteProject(user, projectName)
 	if err != nil {
 		shared.ApiOutputError(c, errors.Default.Wrap(err, "error deleting project"))
 		return
 	}
 	shared.ApiOutputSuccess(c, nil, http.StatusOK)
 }
+
+// @Summary Get the dora metrics of a project
+// @Description Get deployment frequency, lead time for changes, change failure rate and failed deployment recovery time of a project
+// @Tags framework/projects
+// @Accept application/json
+// @Param projectName path string true "project name"
+// @Param startDate query string false "start date of the time window, e.g. 2024-01-01, defaults to 6 months before endDate"
+// @Param endDate query string false "end date of the time window, e.g. 2024-06-30, defaults to today"
+// @Param environment query string false "deployment environment, defaults to PRODUCTION"
+// @Success 200  {object} services.ProjectDoraMetrics
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /projects/{projectName}/metrics/dora [get]
+func GetProjectDoraMetrics(c *gin.Context) {
+	projectName := c.Param("projectName")
+
+	var query services.DoraQuery
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	metrics, err := services.GetProjectDoraMetrics(projectName, &query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting dora metrics of project"))
+		return
+	}
+	shared.ApiOutputSuccess(c, metrics, http.StatusOK)
+}
+
+// @Summary Export a project
+// @Description Export the configuration of a project, including its metrics, blueprint, connections without secrets,
+// @Description scopes and scope configs, which can be applied by the import api
+// @Tags framework/projects
+// @Param projectName path string true "project name"
+// @Param format query string false "json (default) or yaml"
+// @Success 200  {object} services.ProjectDocument
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /projects/{projectName}/export [get]
+func ExportProject(c *gin.Context) {
+	projectName := c.Param("projectName")
+	doc, err := services.ExportProject(projectName)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error exporting project"))
+		return
+	}
+	format := c.Query("format")
+	if format == "" || format == services.PROJECT_DOCUMENT_FORMAT_JSON {
+		shared.ApiOutputSuccess(c, doc, http.StatusOK)
+		return
+	}
+	data, err := services.MarshalProjectDocument(doc, format)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	c.Data(http.StatusOK, "application/yaml", data)
+}
+
+// @Summary Import a project
+// @Description Create or update a project and its blueprint, connections, scopes and scope configs from an exported
+// @Description document in json or yaml. Importing the same document again changes nothing
+// @Tags framework/projects
+// @Accept application/json
+// @Accept application/yaml
+// @Param document body services.ProjectDocument true "json or yaml"
+// @Param dryRun query bool false "list the changes without saving them"
+// @Success 200  {object} services.ProjectImportResult
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /projects/import [post]
+func ImportProject(c *gin.Context) {
+	data, e := c.GetRawData()
+	if e != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(e, shared.BadRequestBody))
+		return
+	}
+	doc, err := services.UnmarshalProjectDocument(data)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	user, _ := shared.GetUser(c)
+	result, err := services.ImportProject(user, doc, c.Query("dryRun") == "true")
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error importing project"))
+		return
+	}
+	shared.ApiOutputSuccess(c, result, http.StatusOK)
+}
@@ -0,0 +1,209 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rawdataretention
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/server/api/shared"
+	"github.com/apache/incubator-devlake/server/services"
+	"github.com/gin-gonic/gin"
+)
+
+type PaginatedRawDataRetentionPolicies struct {
+	Policies []*models.RawDataRetentionPolicy `json:"policies"`
+	Count    int64                            `json:"count"`
+}
+
+// @Summary Get list of raw data retention policies
+// @Description GET /raw-data-retention-policies?page=1&pageSize=10&plugin=github
+// @Tags framework/rawdataretention
+// @Param page query int false "query"
+// @Param pageSize query int false "query"
+// @Param plugin query string false "plugin name"
+// @Success 200  {object} PaginatedRawDataRetentionPolicies
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies [get]
+func GetPolicies(c *gin.Context) {
+	var query services.RawDataRetentionPolicyQuery
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	policies, count, err := services.GetRawDataRetentionPolicies(&query)
+	if err != nil {
+		shared.ApiOutputAbort(c, errors.Default.Wrap(err, "error getting raw data retention policies"))
+		return
+	}
+	shared.ApiOutputSuccess(c, PaginatedRawDataRetentionPolicies{
+		Policies: policies,
+		Count:    count,
+	}, http.StatusOK)
+}
+
+// @Summary Get a raw data retention policy
+// @Description Get a raw data retention policy
+// @Tags framework/rawdataretention
+// @Param policyId path int true "policy id"
+// @Success 200  {object} models.RawDataRetentionPolicy
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies/{policyId} [get]
+func GetPolicy(c *gin.Context) {
+	id, err := getPolicyId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	policy, err := services.GetRawDataRetentionPolicy(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error getting raw data retention policy"))
+		return
+	}
+	shared.ApiOutputSuccess(c, policy, http.StatusOK)
+}
+
+// @Summary Create a raw data retention policy
+// @Description Create a raw data retention policy of a plugin or a connection of the plugin
+// @Tags framework/rawdataretention
+// @Accept application/json
+// @Param policy body models.RawDataRetentionPolicy true "json"
+// @Success 200  {object} models.RawDataRetentionPolicy
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies [post]
+func PostPolicy(c *gin.Context) {
+	policy := &models.RawDataRetentionPolicy{}
+	err := c.ShouldBind(policy)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	user, _ := shared.GetUser(c)
+	policy, err = services.CreateRawDataRetentionPolicy(user, policy)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error creating raw data retention policy"))
+		return
+	}
+	shared.ApiOutputSuccess(c, policy, http.StatusCreated)
+}
+
+// @Summary Patch a raw data retention policy
+// @Description Patch a raw data retention policy
+// @Tags framework/rawdataretention
+// @Accept application/json
+// @Param policyId path int true "policy id"
+// @Param policy body models.RawDataRetentionPolicy true "json"
+// @Success 200  {object} models.RawDataRetentionPolicy
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies/{policyId} [patch]
+func PatchPolicy(c *gin.Context) {
+	id, err := getPolicyId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	var body map[string]interface{}
+	if e := c.ShouldBind(&body); e != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(e, shared.BadRequestBody))
+		return
+	}
+	policy, err := services.PatchRawDataRetentionPolicy(id, body)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error patching raw data retention policy"))
+		return
+	}
+	shared.ApiOutputSuccess(c, policy, http.StatusOK)
+}
+
+// @Summary Delete a raw data retention policy
+// @Description Delete a raw data retention policy
+// @Tags framework/rawdataretention
+// @Param policyId path int true "policy id"
+// @Success 200
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies/{policyId} [delete]
+func DeletePolicy(c *gin.Context) {
+	id, err := getPolicyId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	err = services.DeleteRawDataRetentionPolicy(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error deleting raw data retention policy"))
+		return
+	}
+	shared.ApiOutputSuccess(c, nil, http.StatusOK)
+}
+
+// @Summary Purge the raw tables by all the enabled policies
+// @Description POST /raw-data-retention-policies/purge?dryRun=true, the number of the deleted rows and the size of
+// @Description their payloads are reported, nothing is deleted in the dry-run mode
+// @Tags framework/rawdataretention
+// @Param dryRun query bool false "report what would be purged without deleting"
+// @Success 200  {object} services.RawDataPurgeSummary
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies/purge [post]
+func PurgeAll(c *gin.Context) {
+	purge(c, 0)
+}
+
+// @Summary Purge the raw tables by a policy
+// @Description POST /raw-data-retention-policies/{policyId}/purge?dryRun=true, the policy is applied even if it is
+// @Description disabled
+// @Tags framework/rawdataretention
+// @Param policyId path int true "policy id"
+// @Param dryRun query bool false "report what would be purged without deleting"
+// @Success 200  {object} services.RawDataPurgeSummary
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /raw-data-retention-policies/{policyId}/purge [post]
+func PurgePolicy(c *gin.Context) {
+	id, err := getPolicyId(c)
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	purge(c, id)
+}
+
+func purge(c *gin.Context, policyId uint64) {
+	summary, err := services.PurgeRawData(policyId, c.Query("dryRun") == "true")
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error purging raw data"))
+		return
+	}
+	shared.ApiOutputSuccess(c, summary, http.StatusOK)
+}
+
+func getPolicyId(c *gin.Context) (uint64, errors.Error) {
+	id, err := strconv.ParseUint(c.Param("policyId"), 10, 64)
+	if err != nil {
+		return 0, errors.BadInput.Wrap(err, "bad policyId format supplied")
+	}
+	return id, nil
+}
@@ -0,0 +1,116 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rolebindings
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/impls/logruslog"
+	"github.com/apache/incubator-devlake/server/api/shared"
+	"github.com/apache/incubator-devlake/server/services"
+	"github.com/gin-gonic/gin"
+)
+
+type PaginatedRoleBindings struct {
+	RoleBindings []*models.RoleBinding `json:"roleBindings"`
+	Count        int64                 `json:"count"`
+}
+
+// @Summary Get list of role bindings
+// @Description GET /role-bindings?page=1&pageSize=10&subject=alice&projectName=demo
+// @Tags framework/role-bindings
+// @Param page query int false "query"
+// @Param pageSize query int false "query"
+// @Param subject query string false "user name or email"
+// @Param projectName query string false "project name"
+// @Success 200  {object} PaginatedRoleBindings
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /role-bindings [get]
+func GetRoleBindings(c *gin.Context) {
+	var query services.RoleBindingQuery
+	err := c.ShouldBindQuery(&query)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	roleBindings, count, err := services.GetRoleBindings(&query)
+	if err != nil {
+		shared.ApiOutputAbort(c, errors.Default.Wrap(err, "error getting role bindings"))
+		return
+	}
+	shared.ApiOutputSuccess(c, PaginatedRoleBindings{
+		RoleBindings: roleBindings,
+		Count:        count,
+	}, http.StatusOK)
+}
+
+// @Summary Grant a role to a user
+// @Description Grant a role to a user globally, or on a project if projectName is set. The previous role of the
+// @Description user on the same project is replaced.
+// @Tags framework/role-bindings
+// @Accept application/json
+// @Param roleBinding body models.ApiInputRoleBinding true "json"
+// @Success 200  {object} models.RoleBinding
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /role-bindings [put]
+func PutRoleBinding(c *gin.Context) {
+	roleBindingInput := &models.ApiInputRoleBinding{}
+	err := c.ShouldBind(roleBindingInput)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, shared.BadRequestBody))
+		return
+	}
+	user, exist := shared.GetUser(c)
+	if !exist {
+		logruslog.Global.Warn(nil, "user doesn't exist")
+	}
+	roleBinding, err := services.PutRoleBinding(user, roleBindingInput)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error saving role binding"))
+		return
+	}
+	shared.ApiOutputSuccess(c, roleBinding, http.StatusOK)
+}
+
+// @Summary Revoke a role binding
+// @Description Revoke a role binding
+// @Tags framework/role-bindings
+// @Param roleBindingId path int true "role binding id"
+// @Success 200
+// @Failure 400  {string} errcode.Error "Bad Request"
+// @Failure 404  {string} errcode.Error "Not Found"
+// @Failure 500  {string} errcode.Error "Internal Error"
+// @Router /role-bindings/{roleBindingId} [delete]
+func DeleteRoleBinding(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("roleBindingId"), 10, 64)
+	if err != nil {
+		shared.ApiOutputError(c, errors.BadInput.Wrap(err, "bad roleBindingId format supplied"))
+		return
+	}
+	err = services.DeleteRoleBinding(id)
+	if err != nil {
+		shared.ApiOutputError(c, errors.Default.Wrap(err, "error deleting role binding"))
+		return
+	}
+	shared.ApiOutputSuccess(c, nil, http.StatusOK)
+}
@@ -18,7 +18,9 @@ limitations under the License.
 package api
 
 import (
+	"bytes"
 	"fmt"
+	"io"
 	"net/http"
 	"strings"
 
@@ -26,15 +28,19 @@ import (
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/impls/logruslog"
 	"github.com/apache/incubator-devlake/server/api/apikeys"
+	"github.com/apache/incubator-devlake/server/api/auditlogs"
 	"github.com/apache/incubator-devlake/server/api/store"
 
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/server/api/blueprints"
 	"github.com/apache/incubator-devlake/server/api/domainlayer"
+	"github.com/apache/incubator-devlake/server/api/notifications"
 	"github.com/apache/incubator-devlake/server/api/pipelines"
 	"github.com/apache/incubator-devlake/server/api/plugininfo"
 	"github.com/apache/incubator-devlake/server/api/project"
 	"github.com/apache/incubator-devlake/server/api/push"
+	"github.com/apache/incubator-devlake/server/api/rawdataretention"
+	"github.com/apache/incubator-devlake/server/api/rolebindings"
 	"github.com/apache/incubator-devlake/server/api/shared"
 	"github.com/apache/incubator-devlake/server/api/task"
 	"github.com/apache/incubator-devlake/server/services"
@@ -45,6 +51,7 @@ import (
 func RegisterRouter(r *gin.Engine, basicRes context.BasicRes) {
 	r.GET("/pipelines", pipelines.Index)
 	r.POST("/pipelines", pipelines.Post)
+	r.GET("/pipelines/stats/:groupBy", pipelines.GetStats)
 	r.GET("/pipelines/:pipelineId", pipelines.Get)
 	r.DELETE("/pipelines/:pipelineId", pipelines.Delete)
 	r.GET("/pipelines/:pipelineId/tasks", task.GetTaskByPipeline)
@@ -59,6 +66,7 @@ func RegisterRouter(r *gin.Engine, basicRes context.BasicRes) {
 	r.GET("/blueprints/:blueprintId", blueprints.Get)
 	r.POST("/blueprints/:blueprintId/trigger", blueprints.Trigger)
 	r.GET("/blueprints/:blueprintId/pipelines", blueprints.GetBlueprintPipelines)
+	r.GET("/blueprints/:blueprintId/plan-preview", blueprints.GetPlanPreview)
 
 	r.POST("/tasks/:taskId/rerun", task.PostRerun)
 
@@ -72,9 +80,12 @@ func RegisterRouter(r *gin.Engine, basicRes context.BasicRes) {
 	// project api
 	r.GET("/projects/:projectName", project.GetProject)
 	r.GET("/projects/:projectName/check", project.GetProjectCheck)
+	r.GET("/projects/:projectName/metrics/dora", project.GetProjectDoraMetrics)
+	r.GET("/projects/:projectName/export", project.ExportProject)
 	r.PATCH("/projects/:projectName", project.PatchProject)
 	r.DELETE("/projects/:projectName", project.DeleteProject)
 	r.POST("/projects", project.PostProject)
+	r.POST("/projects/import", project.ImportProject)
 	r.GET("/projects", project.GetProjects)
 	// on board api
 	r.GET("/store/:storeKey", store.GetStore)
@@ -86,6 +97,31 @@ func RegisterRouter(r *gin.Engine, basicRes context.BasicRes) {
 	r.PUT("/api-keys/:apiKeyId", apikeys.PutApiKey)
 	r.DELETE("/api-keys/:apiKeyId", apikeys.DeleteApiKey)
 
+	// role bindings api
+	r.GET("/role-bindings", rolebindings.GetRoleBindings)
+	r.PUT("/role-bindings", rolebindings.PutRoleBinding)
+	r.DELETE("/role-bindings/:roleBindingId", rolebindings.DeleteRoleBinding)
+
+	// audit logs api
+	r.GET("/audit-logs", auditlogs.GetAuditLogs)
+
+	// notification channels api
+	r.GET("/notification-channels", notifications.GetNotificationChannels)
+	r.POST("/notification-channels", notifications.PostNotificationChannel)
+	r.GET("/notification-channels/:channelId", notifications.GetNotificationChannel)
+	r.PATCH("/notification-channels/:channelId", notifications.PatchNotificationChannel)
+	r.DELETE("/notification-channels/:channelId", notifications.DeleteNotificationChannel)
+	r.GET("/notifications", notifications.GetNotifications)
+
+	// raw data retention api
+	r.GET("/raw-data-retention-policies", rawdataretention.GetPolicies)
+	r.POST("/raw-data-retention-policies", rawdataretention.PostPolicy)
+	r.POST("/raw-data-retention-policies/purge", rawdataretention.PurgeAll)
+	r.GET("/raw-data-retention-policies/:policyId", rawdataretention.GetPolicy)
+	r.PATCH("/raw-data-retention-policies/:policyId", rawdataretention.PatchPolicy)
+	r.DELETE("/raw-data-retention-policies/:policyId", rawdataretention.DeletePolicy)
+	r.POST("/raw-data-retention-policies/:policyId/purge", rawdataretention.PurgePolicy)
+
 	// mount all api resources for all plugins
 	resources, err := services.GetPluginsApiResources()
 	if err != nil {
@@ -131,6 +167,14 @@ func handlePluginCall(basicRes context.BasicRes, pluginName string, handler plug
 			if strings.HasPrefix(c.Request.Header.Get("Content-Type"), "multipart/form-data;") {
 				input.Request = c.Request
 			} else {
+				rawBody, getRawDataErr := c.GetRawData()
+				if getRawDataErr != nil {
+					shared.ApiOutputError(c, getRawDataErr)
+					return
+				}
+				input.RawBody = rawBody
+				input.Request = c.Request
+				c.Request.Body = io.NopCloser(bytes.NewReader(rawBody))
 				shouldBindJSONErr := c.ShouldBindJSON(&input.Body)
 				if shouldBindJSONErr != nil && shouldBindJSONErr.Error() != "EOF" {
 					shared.ApiOutputError(c, shouldBindJSONErr)
@@ -100,6 +100,7 @@ func GetSubtaskByPipeline(c *gin.Context) {
 // @Summary rerun task
 // @Tags framework/tasks
 // @Accept application/json
+// @Param mode query string false "resume: skip the subtasks finished successfully in the previous attempt"
 // @Success 200  {object} models.Task
 // @Failure 400  {object} shared.ApiBody "Bad Request"
 // @Failure 500  {object} shared.ApiBody "Internal Error"
@@ -111,7 +112,12 @@ func PostRerun(c *gin.Context) {
 		shared.ApiOutputError(c, errors.BadInput.Wrap(err, "bad taskId format supplied"))
 		return
 	}
-	task, err := services.RerunTask(id)
+	resume, err := services.ParseRerunMode(c.Query("mode"))
+	if err != nil {
+		shared.ApiOutputError(c, err)
+		return
+	}
+	task, err := services.RerunTask(id, resume)
 	if err != nil {
 		shared.ApiOutputError(c, err)
 		return
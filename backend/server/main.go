@@ -18,10 +18,13 @@ limitations under the License.
 package main
 
 import (
+	"os"
+
 	"github.com/apache/incubator-devlake/core/config"
 	"github.com/apache/incubator-devlake/core/plugin"
 	_ "github.com/apache/incubator-devlake/core/version"
 	"github.com/apache/incubator-devlake/server/api"
+	"github.com/apache/incubator-devlake/server/services"
 )
 
 func main() {
@@ -30,5 +33,10 @@ func main() {
 	if encryptionSecret == "" {
 		panic("ENCRYPTION_SECRET must be set in environment variable or .env file")
 	}
+	// `lake worker` only executes the pipelines scheduled by the api server
+	if len(os.Args) > 1 && os.Args[1] == "worker" {
+		services.RunWorker()
+		return
+	}
 	api.CreateAndRunApiServer()
 }
@@ -18,6 +18,8 @@ limitations under the License.
 package services
 
 import (
+	"fmt"
+
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models"
@@ -64,19 +66,27 @@ func GetApiKeys(query *ApiKeysQuery) ([]*models.ApiKey, int64, errors.Error) {
 	return apiKeys, count, nil
 }
 
-func DeleteApiKey(id uint64) errors.Error {
+func DeleteApiKey(user *common.User, id uint64) errors.Error {
 	// verify input
 	if id == 0 {
 		return errors.BadInput.New("api key's id is missing")
 	}
 
 	apiKeyHelper := apikeyhelper.NewApiKeyHelper(basicRes, logger)
-	err := apiKeyHelper.Delete(id)
+	before, err := apiKeyHelper.GetApiKey(nil, dal.Where("id = ?", id))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return errors.NotFound.Wrap(err, "api key not found")
+		}
+		return errors.Default.Wrap(err, "error finding DB api key")
+	}
+	before.RemoveHashedApiKey()
+	err = apiKeyHelper.Delete(id)
 	if err != nil {
 		logger.Error(err, "api key helper delete: %d", id)
 		return err
 	}
-	return nil
+	return recordAudit(nil, user, models.AUDIT_ACTION_DELETE, models.AUDIT_TARGET_API_KEY, fmt.Sprintf("%d", id), before, nil)
 }
 
 func PutApiKey(user *common.User, id uint64) (*models.ApiOutputApiKey, errors.Error) {
@@ -90,6 +100,10 @@ func PutApiKey(user *common.User, id uint64) (*models.ApiOutputApiKey, errors.Er
 		logger.Error(err, "api key helper put: %d", id)
 		return nil, err
 	}
+	err = recordAudit(nil, user, models.AUDIT_ACTION_ROTATE, models.AUDIT_TARGET_API_KEY, fmt.Sprintf("%d", id), nil, nil)
+	if err != nil {
+		return nil, err
+	}
 	return apiKey, nil
 }
 
@@ -111,6 +125,16 @@ func CreateApiKey(user *common.User, apiKeyInput *models.ApiInputApiKey) (*model
 		logger.Error(err, "api key helper create")
 		return nil, errors.Default.Wrap(err, "random letters")
 	}
+	// never record the api key itself
+	after := *apiKey
+	after.RemoveHashedApiKey()
+	err = recordAudit(tx, user, models.AUDIT_ACTION_CREATE, models.AUDIT_TARGET_API_KEY, fmt.Sprintf("%d", apiKey.ID), nil, &after)
+	if err != nil {
+		if err := tx.Rollback(); err != nil {
+			logger.Error(err, "transaction Rollback")
+		}
+		return nil, err
+	}
 	if err := tx.Commit(); err != nil {
 		logger.Info("transaction commit: %s", err)
 	}
@@ -0,0 +1,85 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/helpers/audithelper"
+)
+
+// AuditLogQuery used to query audit logs as the api input
+type AuditLogQuery struct {
+	Pagination
+	User         string `form:"user"`
+	Action       string `form:"action"`
+	TargetType   string `form:"targetType"`
+	TargetPlugin string `form:"targetPlugin"`
+	TargetId     string `form:"targetId"`
+}
+
+// GetAuditLogs returns a paginated list of audit logs based on `query`, the latest first
+func GetAuditLogs(query *AuditLogQuery) ([]*models.AuditLog, int64, errors.Error) {
+	clauses := []dal.Clause{
+		dal.From(&models.AuditLog{}),
+	}
+	if query.User != "" {
+		clauses = append(clauses, dal.Where("user = ? OR user_email = ?", query.User, query.User))
+	}
+	if query.Action != "" {
+		clauses = append(clauses, dal.Where("action = ?", query.Action))
+	}
+	if query.TargetType != "" {
+		clauses = append(clauses, dal.Where("target_type = ?", query.TargetType))
+	}
+	if query.TargetPlugin != "" {
+		clauses = append(clauses, dal.Where("target_plugin = ?", query.TargetPlugin))
+	}
+	if query.TargetId != "" {
+		clauses = append(clauses, dal.Where("target_id = ?", query.TargetId))
+	}
+	count, err := db.Count(clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error getting DB count of audit logs")
+	}
+	clauses = append(clauses,
+		dal.Orderby("id DESC"),
+		dal.Offset(query.GetSkip()),
+		dal.Limit(query.GetPageSize()),
+	)
+	auditLogs := make([]*models.AuditLog, 0)
+	err = db.All(&auditLogs, clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error finding DB audit logs")
+	}
+	return auditLogs, count, nil
+}
+
+// recordAudit writes an audit log of the change made by the user, before and after must be sanitized
+func recordAudit(tx dal.Dal, user *common.User, action, targetType, targetId string, before, after interface{}) errors.Error {
+	return audithelper.NewAuditHelper(basicRes).Record(
+		tx,
+		user,
+		action,
+		audithelper.AuditTarget{Type: targetType, Id: targetId},
+		before,
+		after,
+	)
+}
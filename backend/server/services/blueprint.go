@@ -28,6 +28,7 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 	"github.com/apache/incubator-devlake/impls/logruslog"
 	"github.com/robfig/cron/v3"
@@ -67,9 +68,16 @@ func (bj BlueprintJob) Run() {
 }
 
 // CreateBlueprint accepts a Blueprint instance and insert it to database
-func CreateBlueprint(blueprint *models.Blueprint) errors.Error {
+func CreateBlueprint(user *common.User, blueprint *models.Blueprint) errors.Error {
 	_, err := saveBlueprint(blueprint)
-	return err
+	if err != nil {
+		return err
+	}
+	after, err := GetBlueprint(blueprint.ID, true)
+	if err != nil {
+		return err
+	}
+	return recordAudit(nil, user, models.AUDIT_ACTION_CREATE, models.AUDIT_TARGET_BLUEPRINT, fmt.Sprintf("%d", blueprint.ID), nil, after)
 }
 
 // GetBlueprints returns a paginated list of Blueprints based on `query`
@@ -131,10 +139,9 @@ func GetBlueprintByProjectName(projectName string) (*models.Blueprint, errors.Er
 }
 
 func validateBlueprintAndMakePlan(blueprint *models.Blueprint) errors.Error {
-	// validation
-	err := vld.Struct(blueprint)
+	err := validateBlueprint(blueprint)
 	if err != nil {
-		return errors.BadInput.WrapRaw(err)
+		return err
 	}
 
 	// checking if the project exist
@@ -155,6 +162,28 @@ func validateBlueprintAndMakePlan(blueprint *models.Blueprint) errors.Error {
 		}
 	}
 
+	if blueprint.Mode == models.BLUEPRINT_MODE_NORMAL {
+		var e errors.Error
+		blueprint.Plan, e = MakePlanForBlueprint(blueprint, &blueprint.SyncPolicy)
+		if e != nil {
+			return e
+		}
+	}
+	return nil
+}
+
+// validateBlueprint checks the blueprint itself without looking into the project and the scopes
+func validateBlueprint(blueprint *models.Blueprint) errors.Error {
+	err := vld.Struct(blueprint)
+	if err != nil {
+		return errors.BadInput.WrapRaw(err)
+	}
+
+	// older blueprints were migrated to the stages format
+	if blueprint.PlanFormat == "" {
+		blueprint.PlanFormat = models.PLAN_FORMAT_DAG
+	}
+
 	if strings.ToLower(blueprint.CronConfig) == "manual" {
 		blueprint.IsManual = true
 	}
@@ -168,11 +197,8 @@ func validateBlueprintAndMakePlan(blueprint *models.Blueprint) errors.Error {
 		if len(blueprint.Plan) == 0 {
 			return errors.BadInput.New("invalid plan")
 		}
-	} else if blueprint.Mode == models.BLUEPRINT_MODE_NORMAL {
-		var e errors.Error
-		blueprint.Plan, e = MakePlanForBlueprint(blueprint, &blueprint.SyncPolicy)
-		if e != nil {
-			return e
+		if err := blueprint.Plan.ValidateDag(); err != nil {
+			return err
 		}
 	}
 	return nil
@@ -199,12 +225,17 @@ func saveBlueprint(blueprint *models.Blueprint) (*models.Blueprint, errors.Error
 }
 
 // PatchBlueprint FIXME ...
-func PatchBlueprint(id uint64, body map[string]interface{}) (*models.Blueprint, errors.Error) {
+func PatchBlueprint(user *common.User, id uint64, body map[string]interface{}) (*models.Blueprint, errors.Error) {
 	// load record from db
 	blueprint, err := GetBlueprint(id, false)
 	if err != nil {
 		return nil, err
 	}
+	// keep the original state for auditing
+	before, err := GetBlueprint(id, true)
+	if err != nil {
+		return nil, err
+	}
 
 	originMode := blueprint.Mode
 	err = helper.DecodeMapStruct(body, blueprint, true)
@@ -232,12 +263,16 @@ func PatchBlueprint(id uint64, body map[string]interface{}) (*models.Blueprint,
 	if err := SanitizeBlueprint(blueprint); err != nil {
 		return nil, errors.Convert(err)
 	}
+	err = recordAudit(nil, user, models.AUDIT_ACTION_UPDATE, models.AUDIT_TARGET_BLUEPRINT, fmt.Sprintf("%d", id), before, blueprint)
+	if err != nil {
+		return nil, err
+	}
 	return blueprint, nil
 }
 
 // DeleteBlueprint FIXME ...
-func DeleteBlueprint(id uint64) errors.Error {
-	bp, err := bpManager.GetDbBlueprint(id)
+func DeleteBlueprint(user *common.User, id uint64) errors.Error {
+	bp, err := GetBlueprint(id, true)
 	if err != nil {
 		return err
 	}
@@ -252,7 +287,7 @@ func DeleteBlueprint(id uint64) errors.Error {
 	if err != nil {
 		return errors.Default.Wrap(err, "Failed to delete the blueprint")
 	}
-	return nil
+	return recordAudit(nil, user, models.AUDIT_ACTION_DELETE, models.AUDIT_TARGET_BLUEPRINT, fmt.Sprintf("%d", id), bp, nil)
 }
 
 var blueprintReloadLock sync.Mutex
@@ -374,6 +409,16 @@ func MakePlanForBlueprint(blueprint *models.Blueprint, syncPolicy *models.SyncPo
 	if syncPolicy != nil && syncPolicy.SkipCollectors {
 		skipCollectors = true
 	}
+	if blueprint.PlanFormat == models.PLAN_FORMAT_DAG {
+		return GenerateDagPlanJsonV200(
+			blueprint.ProjectName,
+			blueprint.Connections,
+			metrics,
+			skipCollectors,
+			blueprint.BeforePlan,
+			blueprint.AfterPlan,
+		)
+	}
 	plan, err := GeneratePlanJsonV200(blueprint.ProjectName, blueprint.Connections, metrics, skipCollectors)
 	if err != nil {
 		return nil, err
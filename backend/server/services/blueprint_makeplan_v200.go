@@ -27,6 +27,13 @@ import (
 	"github.com/apache/incubator-devlake/core/plugin"
 )
 
+// planPartsV200 holds the plans generated by the plugins, which are merged into the final plan
+type planPartsV200 struct {
+	projectMapping coreModels.PipelinePlan
+	sources        []coreModels.PipelinePlan
+	metrics        []coreModels.PipelinePlan
+}
+
 // GeneratePlanJsonV200 generates pipeline plan according v2.0.0 definition
 func GeneratePlanJsonV200(
 	projectName string,
@@ -34,6 +41,101 @@ func GeneratePlanJsonV200(
 	metrics map[string]json.RawMessage,
 	skipCollectors bool,
 ) (coreModels.PipelinePlan, errors.Error) {
+	parts, err := generatePlanPartsV200(projectName, connections, metrics, skipCollectors)
+	if err != nil {
+		return nil, err
+	}
+	plan := SequentializePipelinePlans(
+		parts.projectMapping,
+		ParallelizePipelinePlans(parts.sources...),
+		ParallelizePipelinePlans(parts.metrics...),
+	)
+	return plan, nil
+}
+
+// GenerateDagPlanJsonV200 generates the same plan as GeneratePlanJsonV200 but with task dependencies, so that the
+// stages of a connection only wait for the previous stages of the same connection. The metric plugins still wait for
+// all data sources, and the beforePlan and afterPlan are executed before and after everything else.
+func GenerateDagPlanJsonV200(
+	projectName string,
+	connections []*coreModels.BlueprintConnection,
+	metrics map[string]json.RawMessage,
+	skipCollectors bool,
+	beforePlan coreModels.PipelinePlan,
+	afterPlan coreModels.PipelinePlan,
+) (coreModels.PipelinePlan, errors.Error) {
+	parts, err := generatePlanPartsV200(projectName, connections, metrics, skipCollectors)
+	if err != nil {
+		return nil, err
+	}
+	builder := &dagPlanBuilder{}
+	before, leaves := builder.chain(beforePlan, nil)
+	projectMapping, leaves := builder.chain(parts.projectMapping, leaves)
+	sources, leaves := builder.fork(parts.sources, leaves)
+	metricPlans, leaves := builder.fork(parts.metrics, leaves)
+	after, _ := builder.chain(afterPlan, leaves)
+	return SequentializePipelinePlans(
+		before,
+		projectMapping,
+		ParallelizePipelinePlans(sources...),
+		ParallelizePipelinePlans(metricPlans...),
+		after,
+	), nil
+}
+
+// dagPlanBuilder assigns ids and dependencies to the tasks of the stage plans
+type dagPlanBuilder struct {
+	count int
+}
+
+// chain copies the plan with ids assigned to its tasks, the tasks depend on the tasks of the previous stage while
+// the first stage depends on `dependsOn`. It returns the copy and the ids of the tasks the next plan should wait for
+func (b *dagPlanBuilder) chain(plan coreModels.PipelinePlan, dependsOn []string) (coreModels.PipelinePlan, []string) {
+	copied := make(coreModels.PipelinePlan, 0, len(plan))
+	for _, stage := range plan {
+		if len(stage) == 0 {
+			continue
+		}
+		copiedStage := make(coreModels.PipelineStage, 0, len(stage))
+		ids := make([]string, 0, len(stage))
+		for _, task := range stage {
+			b.count++
+			copiedTask := *task
+			copiedTask.Id = fmt.Sprintf("%s-%d", task.Plugin, b.count)
+			copiedTask.DependsOn = dependsOn
+			copiedStage = append(copiedStage, &copiedTask)
+			ids = append(ids, copiedTask.Id)
+		}
+		copied = append(copied, copiedStage)
+		dependsOn = ids
+	}
+	return copied, dependsOn
+}
+
+// fork chains the plans independently of each other, returns the ids of the last stage of all plans
+func (b *dagPlanBuilder) fork(plans []coreModels.PipelinePlan, dependsOn []string) ([]coreModels.PipelinePlan, []string) {
+	copied := make([]coreModels.PipelinePlan, 0, len(plans))
+	var leaves []string
+	for _, plan := range plans {
+		copiedPlan, planLeaves := b.chain(plan, dependsOn)
+		if len(copiedPlan) == 0 {
+			continue
+		}
+		copied = append(copied, copiedPlan)
+		leaves = append(leaves, planLeaves...)
+	}
+	if len(copied) == 0 {
+		return copied, dependsOn
+	}
+	return copied, leaves
+}
+
+func generatePlanPartsV200(
+	projectName string,
+	connections []*coreModels.BlueprintConnection,
+	metrics map[string]json.RawMessage,
+	skipCollectors bool,
+) (*planPartsV200, errors.Error) {
 	var err errors.Error
 	// make plan for data-source coreModels fist. generate plan for each
 	// connection, then merge them into one legitimate plan and collect the
@@ -132,12 +234,11 @@ func GeneratePlanJsonV200(
 			}
 		}
 	}
-	plan := SequentializePipelinePlans(
-		planForProjectMapping,
-		ParallelizePipelinePlans(sourcePlans...),
-		ParallelizePipelinePlans(metricPlans...),
-	)
-	return plan, err
+	return &planPartsV200{
+		projectMapping: planForProjectMapping,
+		sources:        sourcePlans,
+		metrics:        metricPlans,
+	}, err
 }
 
 func removeCollectorTasks(plan coreModels.PipelinePlan) coreModels.PipelinePlan {
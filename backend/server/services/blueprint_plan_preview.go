@@ -0,0 +1,265 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/core/runner"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+)
+
+// BlueprintPlanPreview is the plan which would be executed if the blueprint was triggered now
+type BlueprintPlanPreview struct {
+	BlueprintId       uint64               `json:"blueprintId"`
+	PlanFormat        string               `json:"planFormat"`
+	SyncPolicy        models.SyncPolicy    `json:"syncPolicy"`
+	Plan              [][]*PlanPreviewTask `json:"plan"`
+	Scopes            []*PlanPreviewScope  `json:"scopes"`
+	EstimatedApiCalls int64                `json:"estimatedApiCalls"`
+}
+
+// PlanPreviewTask is a task of the expanded plan along with the subtasks it would execute in order
+type PlanPreviewTask struct {
+	Id        string                 `json:"id,omitempty"`
+	DependsOn []string               `json:"dependsOn,omitempty"`
+	Plugin    string                 `json:"plugin"`
+	Options   map[string]interface{} `json:"options"`
+	Subtasks  []string               `json:"subtasks"`
+}
+
+// PlanPreviewScope is a scope of the blueprint with its resolved scope config and collector states
+type PlanPreviewScope struct {
+	PluginName        string                  `json:"pluginName"`
+	ConnectionId      uint64                  `json:"connectionId"`
+	ScopeId           string                  `json:"scopeId"`
+	ScopeName         string                  `json:"scopeName"`
+	ScopeConfig       interface{}             `json:"scopeConfig"`
+	Incremental       bool                    `json:"incremental"`
+	Collectors        []*PlanPreviewCollector `json:"collectors"`
+	EstimatedApiCalls int64                   `json:"estimatedApiCalls"`
+}
+
+// PlanPreviewCollector tells whether the collector of a raw table would collect incrementally, and how many api calls
+// it made in the latest successful run
+type PlanPreviewCollector struct {
+	RawDataTable       string     `json:"rawDataTable"`
+	Incremental        bool       `json:"incremental"`
+	Since              *time.Time `json:"since"`
+	LatestSuccessStart *time.Time `json:"latestSuccessStart"`
+	EstimatedApiCalls  int64      `json:"estimatedApiCalls"`
+}
+
+// PreviewBlueprintPlan expands the plan of the blueprint with its SyncPolicy without creating any pipeline
+func PreviewBlueprintPlan(blueprintId uint64) (*BlueprintPlanPreview, errors.Error) {
+	blueprint, err := GetBlueprint(blueprintId, false)
+	if err != nil {
+		return nil, err
+	}
+	syncPolicy := &blueprint.SyncPolicy
+	plan := blueprint.Plan
+	if blueprint.Mode == models.BLUEPRINT_MODE_NORMAL {
+		plan, err = MakePlanForBlueprint(blueprint, syncPolicy)
+		if err != nil {
+			return nil, err
+		}
+	}
+	preview := &BlueprintPlanPreview{
+		BlueprintId: blueprint.ID,
+		PlanFormat:  blueprint.PlanFormat,
+		SyncPolicy:  blueprint.SyncPolicy,
+		Plan:        make([][]*PlanPreviewTask, 0, len(plan)),
+		Scopes:      make([]*PlanPreviewScope, 0),
+	}
+	for _, stage := range plan {
+		previewStage := make([]*PlanPreviewTask, 0, len(stage))
+		for _, task := range stage {
+			previewTask, err := previewPlanTask(task, syncPolicy)
+			if err != nil {
+				return nil, err
+			}
+			previewStage = append(previewStage, previewTask)
+		}
+		preview.Plan = append(preview.Plan, previewStage)
+	}
+	for _, connection := range blueprint.Connections {
+		for _, bpScope := range connection.Scopes {
+			previewScope, err := previewPlanScope(connection, bpScope.ScopeId, syncPolicy)
+			if err != nil {
+				return nil, err
+			}
+			if previewScope == nil {
+				continue
+			}
+			preview.Scopes = append(preview.Scopes, previewScope)
+			preview.EstimatedApiCalls += previewScope.EstimatedApiCalls
+		}
+	}
+	return preview, nil
+}
+
+func previewPlanTask(task *models.PipelineTask, syncPolicy *models.SyncPolicy) (*PlanPreviewTask, errors.Error) {
+	options, e := SanitizePluginOption(task.Plugin, task.Options)
+	if e != nil {
+		return nil, errors.Convert(e)
+	}
+	previewTask := &PlanPreviewTask{
+		Id:        task.Id,
+		DependsOn: task.DependsOn,
+		Plugin:    task.Plugin,
+		Options:   options,
+		Subtasks:  make([]string, 0),
+	}
+	pluginMeta, err := plugin.GetPlugin(task.Plugin)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, fmt.Sprintf("plugin %s is not loaded", task.Plugin))
+	}
+	pluginTask, ok := pluginMeta.(plugin.PluginTask)
+	if !ok {
+		return previewTask, nil
+	}
+	subtaskMetas := pluginTask.SubTaskMetas()
+	subtasksFlag, err := runner.GetSubtasksFlag(subtaskMetas, task.Subtasks, syncPolicy)
+	if err != nil {
+		return nil, err
+	}
+	for _, subtaskMeta := range subtaskMetas {
+		if subtasksFlag[subtaskMeta.Name] {
+			previewTask.Subtasks = append(previewTask.Subtasks, subtaskMeta.Name)
+		}
+	}
+	return previewTask, nil
+}
+
+// previewPlanScope loads the scope and its scope config, and checks the collector states of its raw tables. nil is
+// returned for the plugins without scopes
+func previewPlanScope(connection *models.BlueprintConnection, scopeId string, syncPolicy *models.SyncPolicy) (*PlanPreviewScope, errors.Error) {
+	pluginMeta, err := plugin.GetPlugin(connection.PluginName)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, fmt.Sprintf("plugin %s is not loaded", connection.PluginName))
+	}
+	pluginSrc, ok := pluginMeta.(plugin.PluginSource)
+	if !ok || pluginSrc.Scope() == nil {
+		return nil, nil
+	}
+	scope, err := findScope(db, pluginSrc, connection.ConnectionId, scopeId)
+	if err != nil {
+		return nil, err
+	}
+	if scope == nil {
+		return nil, errors.NotFound.New(fmt.Sprintf("scope %s of %s connection %d not found", scopeId, connection.PluginName, connection.ConnectionId))
+	}
+	previewScope := &PlanPreviewScope{
+		PluginName:   connection.PluginName,
+		ConnectionId: connection.ConnectionId,
+		ScopeId:      scopeId,
+		ScopeName:    scope.ScopeFullName(),
+		Collectors:   make([]*PlanPreviewCollector, 0),
+	}
+	if scopeConfigId := scope.ScopeScopeConfigId(); scopeConfigId != 0 && pluginSrc.ScopeConfig() != nil {
+		scopeConfig := pluginSrc.ScopeConfig()
+		err = db.First(scopeConfig, dal.Where("id = ?", scopeConfigId))
+		if err != nil && !db.IsErrorNotFound(err) {
+			return nil, errors.Default.Wrap(err, "error finding DB scope config")
+		}
+		if err == nil {
+			previewScope.ScopeConfig = scopeConfig
+		}
+	}
+
+	rawDataParams := plugin.MarshalScopeParams(scope.ScopeParams())
+	states := make([]*models.CollectorLatestState, 0)
+	err = db.All(&states, dal.Where("raw_data_params = ?", rawDataParams), dal.Orderby("raw_data_table"))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error finding DB collector states")
+	}
+	for _, state := range states {
+		stateManager, err := helper.NewCollectorStateManager(basicRes, syncPolicy, state.RawDataTable, state.RawDataParams)
+		if err != nil {
+			return nil, err
+		}
+		collector := &PlanPreviewCollector{
+			RawDataTable:       state.RawDataTable,
+			Incremental:        stateManager.IsIncremental(),
+			Since:              stateManager.GetSince(),
+			LatestSuccessStart: state.LatestSuccessStart,
+		}
+		collector.EstimatedApiCalls, err = estimateApiCalls(state)
+		if err != nil {
+			return nil, err
+		}
+		previewScope.Collectors = append(previewScope.Collectors, collector)
+		previewScope.EstimatedApiCalls += collector.EstimatedApiCalls
+	}
+	// the scope is collected incrementally only if all of its collectors are
+	previewScope.Incremental = len(previewScope.Collectors) > 0
+	for _, collector := range previewScope.Collectors {
+		previewScope.Incremental = previewScope.Incremental && collector.Incremental
+	}
+	return previewScope, nil
+}
+
+// findScope loads the scope by its connection and id, nil is returned if it does not exist
+func findScope(tx dal.Dal, pluginSrc plugin.PluginSource, connectionId uint64, scopeId string) (plugin.ToolLayerScope, errors.Error) {
+	scope := pluginSrc.Scope()
+	pkNames, err := dal.GetPrimarykeyColumnNames(tx, scope)
+	if err != nil {
+		return nil, err
+	}
+	clauses := []dal.Clause{dal.Where("connection_id = ?", connectionId)}
+	for _, pkName := range pkNames {
+		if pkName != "connection_id" {
+			clauses = append(clauses, dal.Where(fmt.Sprintf("%s = ?", pkName), scopeId))
+		}
+	}
+	err = tx.First(scope, clauses...)
+	if err != nil {
+		if tx.IsErrorNotFound(err) {
+			return nil, nil
+		}
+		return nil, errors.Default.Wrap(err, "error finding DB scope")
+	}
+	return scope, nil
+}
+
+// estimateApiCalls counts the distinct urls requested by the latest successful run of the collector, which is the
+// number of api calls it made as each response is saved to the raw table along with the url
+func estimateApiCalls(state *models.CollectorLatestState) (int64, errors.Error) {
+	if state.LatestSuccessStart == nil || !db.HasTable(state.RawDataTable) {
+		return 0, nil
+	}
+	var counts []int64
+	err := db.Pluck(
+		"COUNT(DISTINCT url)",
+		&counts,
+		dal.From(state.RawDataTable),
+		dal.Where("params = ? AND created_at >= ?", state.RawDataParams, state.LatestSuccessStart),
+	)
+	if err != nil {
+		return 0, errors.Default.Wrap(err, fmt.Sprintf("error counting api calls in %s", state.RawDataTable))
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+	return counts[0], nil
+}
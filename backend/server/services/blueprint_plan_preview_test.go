@@ -0,0 +1,212 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+	pluginServices "github.com/apache/incubator-devlake/helpers/pluginhelper/services"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	mockcontext "github.com/apache/incubator-devlake/mocks/core/context"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+const previewTestPluginName = "TestPreviewBlueprintPlan-plugin"
+
+type previewTestScope struct {
+	common.Scope
+	Id   string
+	Name string
+}
+
+func (s previewTestScope) TableName() string          { return "_tool_preview_test_scopes" }
+func (s previewTestScope) ScopeId() string            { return s.Id }
+func (s previewTestScope) ScopeName() string          { return s.Name }
+func (s previewTestScope) ScopeFullName() string      { return "group/" + s.Name }
+func (s previewTestScope) ScopeParams() interface{}   { return map[string]string{"scopeId": s.Id} }
+func (s previewTestScope) ScopeConnectionId() uint64  { return s.ConnectionId }
+func (s previewTestScope) ScopeScopeConfigId() uint64 { return s.ScopeConfigId }
+
+type previewTestScopeConfig struct {
+	common.ScopeConfig
+	Pattern string
+}
+
+func (previewTestScopeConfig) TableName() string { return "_tool_preview_test_scope_configs" }
+
+type previewTestPlugin struct{}
+
+func (previewTestPlugin) Description() string          { return "plugin for previewing plans" }
+func (previewTestPlugin) RootPkgPath() string          { return previewTestPluginName }
+func (previewTestPlugin) Name() string                 { return previewTestPluginName }
+func (previewTestPlugin) Connection() dal.Tabler       { return nil }
+func (previewTestPlugin) Scope() plugin.ToolLayerScope { return &previewTestScope{} }
+func (previewTestPlugin) ScopeConfig() dal.Tabler      { return &previewTestScopeConfig{} }
+func (previewTestPlugin) SubTaskMetas() []plugin.SubTaskMeta {
+	return []plugin.SubTaskMeta{
+		{Name: "collectIssues", EnabledByDefault: true},
+		{Name: "extractIssues", EnabledByDefault: true},
+		{Name: "extractComments", EnabledByDefault: false},
+		{Name: "convertIssues", EnabledByDefault: true},
+	}
+}
+func (previewTestPlugin) PrepareTaskData(taskCtx plugin.TaskContext, options map[string]interface{}) (interface{}, errors.Error) {
+	return nil, nil
+}
+
+// mockPreviewDal serves the blueprint, its scope and the collector state from a mock which only allows reading
+func mockPreviewDal(t *testing.T, syncPolicy models.SyncPolicy, latestSuccessStart time.Time) *mockdal.Dal {
+	mockDal := mockdal.NewDal(t)
+	mockRes := new(mockcontext.BasicRes)
+	mockRes.On("GetDal").Return(mockDal)
+	mockRes.On("GetLogger").Return(unithelper.DummyLogger())
+	oldDb, oldBpManager, oldBasicRes := db, bpManager, basicRes
+	db, bpManager, basicRes = mockDal, pluginServices.NewBlueprintManager(mockDal), mockRes
+	t.Cleanup(func() {
+		db, bpManager, basicRes = oldDb, oldBpManager, oldBasicRes
+	})
+
+	state := models.CollectorLatestState{
+		RawDataTable:       "_raw_preview_test_issues",
+		RawDataParams:      `{"scopeId":"10"}`,
+		LatestSuccessStart: &latestSuccessStart,
+	}
+	mockDal.On("First", mock.AnythingOfType("*models.Blueprint"), mock.Anything).Run(func(args mock.Arguments) {
+		blueprint := args.Get(0).(*models.Blueprint)
+		blueprint.ID = 1
+		blueprint.Mode = models.BLUEPRINT_MODE_ADVANCED
+		blueprint.SyncPolicy = syncPolicy
+		blueprint.Plan = models.PipelinePlan{{{Plugin: previewTestPluginName, Options: map[string]interface{}{"scopeId": "10"}}}}
+	}).Return(nil)
+	mockDal.On("Pluck", "name", mock.Anything, mock.Anything).Return(nil)
+	mockDal.On("All", mock.AnythingOfType("*[]*models.BlueprintConnection"), mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]*models.BlueprintConnection) = []*models.BlueprintConnection{
+			{BlueprintId: 1, PluginName: previewTestPluginName, ConnectionId: 2},
+		}
+	}).Return(nil)
+	mockDal.On("All", mock.AnythingOfType("*[]*models.BlueprintScope"), mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]*models.BlueprintScope) = []*models.BlueprintScope{{ScopeId: "10"}}
+	}).Return(nil)
+	idColumn := mockdal.NewColumnMeta(t)
+	idColumn.On("Name").Return("id")
+	mockDal.On("GetColumns", mock.Anything, mock.Anything).Return([]dal.ColumnMeta{idColumn}, nil)
+	mockDal.On("First", mock.AnythingOfType("*services.previewTestScope"), mock.Anything).Run(func(args mock.Arguments) {
+		scope := args.Get(0).(*previewTestScope)
+		scope.Id = "10"
+		scope.Name = "demo"
+		scope.ConnectionId = 2
+		scope.ScopeConfigId = 3
+	}).Return(nil)
+	mockDal.On("First", mock.AnythingOfType("*services.previewTestScopeConfig"), mock.Anything).Run(func(args mock.Arguments) {
+		scopeConfig := args.Get(0).(*previewTestScopeConfig)
+		scopeConfig.ID = 3
+		scopeConfig.Pattern = "bug"
+	}).Return(nil)
+	mockDal.On("All", mock.AnythingOfType("*[]*models.CollectorLatestState"), mock.Anything).Run(func(args mock.Arguments) {
+		s := state
+		*args.Get(0).(*[]*models.CollectorLatestState) = []*models.CollectorLatestState{&s}
+	}).Return(nil)
+	mockDal.On("First", mock.AnythingOfType("*models.CollectorLatestState"), mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*models.CollectorLatestState) = state
+	}).Return(nil)
+	mockDal.On("HasTable", "_raw_preview_test_issues").Return(true)
+	mockDal.On("Pluck", "COUNT(DISTINCT url)", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]int64) = []int64{42}
+	}).Return(nil)
+	return mockDal
+}
+
+func TestPreviewBlueprintPlan(t *testing.T) {
+	errors.Must(plugin.RegisterPlugin(previewTestPluginName, previewTestPlugin{}))
+	latestSuccessStart := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	timeAfter := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name        string
+		syncPolicy  models.SyncPolicy
+		subtasks    []string
+		incremental bool
+		since       *time.Time
+	}{
+		{
+			name:        "incremental",
+			syncPolicy:  models.SyncPolicy{TimeAfter: &timeAfter},
+			subtasks:    []string{"collectIssues", "extractIssues", "convertIssues"},
+			incremental: true,
+			since:       &latestSuccessStart,
+		},
+		{
+			name:        "full sync",
+			syncPolicy:  models.SyncPolicy{TimeAfter: &timeAfter, TriggerSyncPolicy: models.TriggerSyncPolicy{FullSync: true}},
+			subtasks:    []string{"collectIssues", "extractIssues", "convertIssues"},
+			incremental: false,
+			since:       &timeAfter,
+		},
+		{
+			name:        "skip collectors",
+			syncPolicy:  models.SyncPolicy{TimeAfter: &timeAfter, TriggerSyncPolicy: models.TriggerSyncPolicy{SkipCollectors: true}},
+			subtasks:    []string{"extractIssues", "convertIssues"},
+			incremental: true,
+			since:       &latestSuccessStart,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			mockDal := mockPreviewDal(t, c.syncPolicy, latestSuccessStart)
+
+			preview, err := PreviewBlueprintPlan(1)
+			assert.Nil(t, err)
+			assert.Equal(t, uint64(1), preview.BlueprintId)
+			assert.Len(t, preview.Plan, 1)
+			assert.Len(t, preview.Plan[0], 1)
+			assert.Equal(t, previewTestPluginName, preview.Plan[0][0].Plugin)
+			assert.Equal(t, c.subtasks, preview.Plan[0][0].Subtasks)
+
+			assert.Len(t, preview.Scopes, 1)
+			scope := preview.Scopes[0]
+			assert.Equal(t, "10", scope.ScopeId)
+			assert.Equal(t, "group/demo", scope.ScopeName)
+			assert.Equal(t, "bug", scope.ScopeConfig.(*previewTestScopeConfig).Pattern)
+			assert.Equal(t, c.incremental, scope.Incremental)
+			assert.Len(t, scope.Collectors, 1)
+			assert.Equal(t, c.incremental, scope.Collectors[0].Incremental)
+			assert.Equal(t, c.since, scope.Collectors[0].Since)
+			assert.Equal(t, int64(42), scope.EstimatedApiCalls)
+			assert.Equal(t, int64(42), preview.EstimatedApiCalls)
+
+			// previewing must not change anything, i.e. pipelines or collector states
+			for _, method := range []string{"Create", "CreateOrUpdate", "CreateIfNotExist", "Update", "Delete", "UpdateAllColumn"} {
+				mockDal.AssertNotCalled(t, method, mock.Anything, mock.Anything)
+			}
+			for _, method := range []string{"UpdateColumns", "UpdateColumnsAffected"} {
+				mockDal.AssertNotCalled(t, method, mock.Anything, mock.Anything, mock.Anything)
+			}
+			mockDal.AssertNotCalled(t, "UpdateColumn", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+			mockDal.AssertNotCalled(t, "Exec", mock.Anything, mock.Anything)
+			mockDal.AssertNotCalled(t, "Begin")
+		})
+	}
+}
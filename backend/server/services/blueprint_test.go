@@ -147,3 +147,31 @@ func TestRemoveCollectorTasks(t *testing.T) {
 		},
 	}, removeCollectorTasks(plan1))
 }
+
+func TestDagPlanBuilder(t *testing.T) {
+	builder := &dagPlanBuilder{}
+	mapping, leaves := builder.chain(coreModels.PipelinePlan{{{Plugin: "org"}}}, nil)
+	sources, leaves := builder.fork([]coreModels.PipelinePlan{
+		{{{Plugin: "github"}}, {{Plugin: "gitextractor"}}},
+		{{{Plugin: "jira"}}},
+	}, leaves)
+	metrics, _ := builder.fork([]coreModels.PipelinePlan{{{{Plugin: "dora"}}}}, leaves)
+	plan := SequentializePipelinePlans(mapping, ParallelizePipelinePlans(sources...), ParallelizePipelinePlans(metrics...))
+
+	assert.Equal(t, coreModels.PipelinePlan{
+		{
+			{Plugin: "org", Id: "org-1"},
+		},
+		{
+			{Plugin: "github", Id: "github-2", DependsOn: []string{"org-1"}},
+			{Plugin: "jira", Id: "jira-4", DependsOn: []string{"org-1"}},
+		},
+		{
+			{Plugin: "gitextractor", Id: "gitextractor-3", DependsOn: []string{"github-2"}},
+		},
+		{
+			{Plugin: "dora", Id: "dora-5", DependsOn: []string{"gitextractor-3", "jira-4"}},
+		},
+	}, plan)
+	assert.Nil(t, plan.ValidateDag())
+}
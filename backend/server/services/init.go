@@ -26,6 +26,7 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/log"
+	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/models/migrationscripts"
 	"github.com/apache/incubator-devlake/core/plugin"
 	"github.com/apache/incubator-devlake/core/runner"
@@ -123,6 +124,9 @@ func Init() {
 	errors.Must(runner.LoadPlugins(basicRes))
 	logger.Info("all plugins have been loaded")
 	registerPluginsMigrationScripts()
+	plugin.RegisterPipelineEnqueuer(func(newPipeline *models.NewPipeline) (*models.Pipeline, errors.Error) {
+		return CreatePipeline(newPipeline, false)
+	})
 }
 
 func InjectCustomService(customPipelineNotifier PipelineNotificationService, customProjectService ProjectService) errors.Error {
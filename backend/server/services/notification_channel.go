@@ -0,0 +1,234 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+)
+
+var notificationEvents = map[models.NotificationType]bool{
+	models.NotificationPipelineStatusChanged: true,
+	models.NotificationPipelineFailed:        true,
+	models.NotificationTaskFailed:            true,
+	models.NotificationPipelineTookTooLong:   true,
+	models.NotificationBlueprintNotSucceeded: true,
+}
+
+// NotificationChannelQuery used to query notification channels as the api input
+type NotificationChannelQuery struct {
+	Pagination
+	ProjectName string `form:"projectName"`
+	BlueprintId uint64 `form:"blueprintId"`
+	Type        string `form:"type"`
+}
+
+// NotificationQuery used to query the delivered notifications as the api input
+type NotificationQuery struct {
+	Pagination
+	ChannelId  uint64 `form:"channelId"`
+	PipelineId uint64 `form:"pipelineId"`
+	Status     string `form:"status"`
+}
+
+// GetNotificationChannels returns a paginated list of notification channels based on `query`
+func GetNotificationChannels(query *NotificationChannelQuery) ([]*models.NotificationChannel, int64, errors.Error) {
+	clauses := []dal.Clause{
+		dal.From(&models.NotificationChannel{}),
+	}
+	if query.ProjectName != "" {
+		clauses = append(clauses, dal.Where("project_name = ?", query.ProjectName))
+	}
+	if query.BlueprintId != 0 {
+		clauses = append(clauses, dal.Where("blueprint_id = ?", query.BlueprintId))
+	}
+	if query.Type != "" {
+		clauses = append(clauses, dal.Where("type = ?", query.Type))
+	}
+	count, err := db.Count(clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error getting DB count of notification channels")
+	}
+	clauses = append(clauses,
+		dal.Orderby("id"),
+		dal.Offset(query.GetSkip()),
+		dal.Limit(query.GetPageSize()),
+	)
+	channels := make([]*models.NotificationChannel, 0)
+	err = db.All(&channels, clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error finding DB notification channels")
+	}
+	for idx, channel := range channels {
+		sanitized := channel.Sanitize()
+		channels[idx] = &sanitized
+	}
+	return channels, count, nil
+}
+
+// GetNotificationChannel returns the sanitized notification channel
+func GetNotificationChannel(id uint64) (*models.NotificationChannel, errors.Error) {
+	channel, err := getNotificationChannel(id)
+	if err != nil {
+		return nil, err
+	}
+	sanitized := channel.Sanitize()
+	return &sanitized, nil
+}
+
+// CreateNotificationChannel accepts a notification channel and insert it to database
+func CreateNotificationChannel(user *common.User, channel *models.NotificationChannel) (*models.NotificationChannel, errors.Error) {
+	channel.ID = 0
+	if user != nil {
+		channel.Creator = common.Creator{Creator: user.Name, CreatorEmail: user.Email}
+	}
+	err := validateNotificationChannel(channel)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Create(channel)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error creating DB notification channel")
+	}
+	sanitized := channel.Sanitize()
+	return &sanitized, nil
+}
+
+// PatchNotificationChannel updates the notification channel, the sanitized secret and endpoint sent back are ignored
+func PatchNotificationChannel(id uint64, body map[string]interface{}) (*models.NotificationChannel, errors.Error) {
+	channel, err := getNotificationChannel(id)
+	if err != nil {
+		return nil, err
+	}
+	sanitized := channel.Sanitize()
+	if body["secret"] == sanitized.Secret {
+		delete(body, "secret")
+	}
+	if body["endpoint"] == sanitized.Endpoint {
+		delete(body, "endpoint")
+	}
+	err = helper.DecodeMapStruct(body, channel, true)
+	if err != nil {
+		return nil, err
+	}
+	channel.ID = id
+	err = validateNotificationChannel(channel)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(channel)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error updating DB notification channel")
+	}
+	sanitized = channel.Sanitize()
+	return &sanitized, nil
+}
+
+// DeleteNotificationChannel deletes the notification channel
+func DeleteNotificationChannel(id uint64) errors.Error {
+	channel, err := getNotificationChannel(id)
+	if err != nil {
+		return err
+	}
+	return db.Delete(channel)
+}
+
+// GetNotifications returns a paginated list of the notifications based on `query`, the latest first
+func GetNotifications(query *NotificationQuery) ([]*models.Notification, int64, errors.Error) {
+	clauses := []dal.Clause{
+		dal.From(&models.Notification{}),
+	}
+	if query.ChannelId != 0 {
+		clauses = append(clauses, dal.Where("channel_id = ?", query.ChannelId))
+	}
+	if query.PipelineId != 0 {
+		clauses = append(clauses, dal.Where("pipeline_id = ?", query.PipelineId))
+	}
+	if query.Status != "" {
+		clauses = append(clauses, dal.Where("status = ?", query.Status))
+	}
+	count, err := db.Count(clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error getting DB count of notifications")
+	}
+	clauses = append(clauses,
+		dal.Orderby("id DESC"),
+		dal.Offset(query.GetSkip()),
+		dal.Limit(query.GetPageSize()),
+	)
+	notifications := make([]*models.Notification, 0)
+	err = db.All(&notifications, clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error finding DB notifications")
+	}
+	return notifications, count, nil
+}
+
+func getNotificationChannel(id uint64) (*models.NotificationChannel, errors.Error) {
+	if id == 0 {
+		return nil, errors.BadInput.New("notification channel's id is missing")
+	}
+	channel := &models.NotificationChannel{}
+	err := db.First(channel, dal.Where("id = ?", id))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.New(fmt.Sprintf("notification channel %d not found", id))
+		}
+		return nil, errors.Default.Wrap(err, "error finding DB notification channel")
+	}
+	return channel, nil
+}
+
+func validateNotificationChannel(channel *models.NotificationChannel) errors.Error {
+	if err := VerifyStruct(channel); err != nil {
+		return err
+	}
+	if channel.ProjectName == "" && channel.BlueprintId == 0 {
+		return errors.BadInput.New("either projectName or blueprintId is required")
+	}
+	if channel.ProjectName != "" {
+		if _, err := getProjectByName(db, channel.ProjectName); err != nil {
+			return err
+		}
+	}
+	if channel.BlueprintId != 0 {
+		if _, err := GetBlueprint(channel.BlueprintId, false); err != nil {
+			return err
+		}
+	}
+	if len(channel.Events) == 0 {
+		return errors.BadInput.New("at least one event is required")
+	}
+	for _, event := range channel.Events {
+		if !notificationEvents[event] {
+			return errors.BadInput.New(fmt.Sprintf("unknown event %s", event))
+		}
+	}
+	if channel.Subscribes(models.NotificationPipelineTookTooLong) && channel.DurationThresholdMinutes <= 0 {
+		return errors.BadInput.New("durationThresholdMinutes is required by the PipelineTookTooLong event")
+	}
+	if channel.Subscribes(models.NotificationBlueprintNotSucceeded) && channel.StaleDays <= 0 {
+		return errors.BadInput.New("staleDays is required by the BlueprintNotSucceeded event")
+	}
+	return nil
+}
@@ -83,11 +83,9 @@ func pipelineServiceInit() {
 	plugin.InitPlugins(basicRes)
 
 	// notification
-	var notificationEndpoint = cfg.GetString("NOTIFICATION_ENDPOINT")
-	var notificationSecret = cfg.GetString("NOTIFICATION_SECRET")
-	if strings.TrimSpace(notificationEndpoint) != "" {
-		defaultNotificationService = NewDefaultPipelineNotificationService(notificationEndpoint, notificationSecret)
-	}
+	initPipelineNotification()
+
+	registerPipelineMetrics()
 
 	// standalone mode: reset pipeline status
 	if cfg.GetBool("RESUME_PIPELINES") {
@@ -99,6 +97,30 @@ func pipelineServiceInit() {
 	// load cronjobs for blueprints
 	errors.Must(ReloadBlueprints())
 
+	// keep the lease of running pipelines and requeue the ones lost by other workers
+	go keepPipelineLeases()
+
+	// check the notification channels for long running pipelines and stale blueprints
+	go watchNotificationChannels()
+
+	// purge the raw tables by the retention policies
+	scheduleRawDataPurge()
+
+	// run pipeline with independent goroutine
+	if cfg.GetBool("CONSUME_PIPELINES") {
+		go RunPipelineInQueue(getPipelineMaxParallel())
+	}
+}
+
+func initPipelineNotification() {
+	var notificationEndpoint = cfg.GetString("NOTIFICATION_ENDPOINT")
+	var notificationSecret = cfg.GetString("NOTIFICATION_SECRET")
+	if strings.TrimSpace(notificationEndpoint) != "" {
+		defaultNotificationService = NewDefaultPipelineNotificationService(notificationEndpoint, notificationSecret)
+	}
+}
+
+func getPipelineMaxParallel() int64 {
 	var pipelineMaxParallel = cfg.GetInt64("PIPELINE_MAX_PARALLEL")
 	if pipelineMaxParallel < 0 {
 		panic(errors.BadInput.New(`PIPELINE_MAX_PARALLEL should be a positive integer`))
@@ -107,31 +129,39 @@ func pipelineServiceInit() {
 		globalPipelineLog.Warn(nil, `pipelineMaxParallel=0 means pipeline will be run No Limit`)
 		pipelineMaxParallel = 10000
 	}
-	// run pipeline with independent goroutine
-	if cfg.GetBool("CONSUME_PIPELINES") {
-		go RunPipelineInQueue(pipelineMaxParallel)
-	}
+	return pipelineMaxParallel
 }
 
+// markInterruptedPipelineAs updates the status of the running pipelines which are not held by a live worker
 func markInterruptedPipelineAs(status string) {
+	leaseExpiredAt := time.Now().Add(-getPipelineLeaseTimeout())
 	errors.Must(db.UpdateColumns(
-		&models.Pipeline{},
+		&models.Task{},
 		[]dal.DalSet{
 			{ColumnName: "status", Value: status},
 		},
-		dal.Where("status = ?", models.TASK_RUNNING),
+		dal.Where(
+			`status = ? AND pipeline_id IN (
+				SELECT id FROM _devlake_pipelines WHERE status = ? AND (heartbeat_at IS NULL OR heartbeat_at < ?)
+			)`,
+			models.TASK_RUNNING, models.TASK_RUNNING, leaseExpiredAt,
+		),
 	))
 	errors.Must(db.UpdateColumns(
-		&models.Task{},
+		&models.Pipeline{},
 		[]dal.DalSet{
 			{ColumnName: "status", Value: status},
+			{ColumnName: "worker_id", Value: ""},
 		},
-		dal.Where("status = ?", models.TASK_RUNNING),
+		dal.Where("status = ? AND (heartbeat_at IS NULL OR heartbeat_at < ?)", models.TASK_RUNNING, leaseExpiredAt),
 	))
 }
 
 // CreatePipeline and return the model
 func CreatePipeline(newPipeline *models.NewPipeline, shouldSanitize bool) (*models.Pipeline, errors.Error) {
+	if err := newPipeline.Plan.ValidateDag(); err != nil {
+		return nil, err
+	}
 	pipeline, err := CreateDbPipeline(newPipeline)
 	if err != nil {
 		return nil, errors.Convert(err)
@@ -297,14 +327,24 @@ func dequeuePipeline(runningParallelLabels []string) (pipeline *models.Pipeline,
 			globalPipelineLog.Info("resumed pipeline #%d", pipeline.ID)
 		}
 		errors.Must(tx.LockTables(dal.LockTables{{Table: "_devlake_pipelines", Exclusive: true}}))
-		err = tx.UpdateColumns(&models.Pipeline{}, []dal.DalSet{
+		// the table locks are not honored by every database, claim the pipeline only if it is still queued so
+		// that it would never be picked up by 2 workers
+		var claimed int64
+		claimed, err = tx.UpdateColumnsAffected(&models.Pipeline{}, []dal.DalSet{
 			{ColumnName: "status", Value: models.TASK_RUNNING},
 			{ColumnName: "message", Value: ""},
 			{ColumnName: "began_at", Value: pipeline.BeganAt},
-		}, dal.Where("id = ?", pipeline.ID))
+			{ColumnName: "worker_id", Value: getWorkerId()},
+			{ColumnName: "heartbeat_at", Value: time.Now()},
+			{ColumnName: "cancel_requested", Value: false},
+		}, dal.Where("id = ?", pipeline.ID), where_status)
 		if err != nil {
 			panic(err)
 		}
+		if claimed != 1 {
+			globalPipelineLog.Info("pipeline #%d was claimed by another worker", pipeline.ID)
+			pipeline = nil
+		}
 
 		return
 	}
@@ -353,9 +393,11 @@ func RunPipelineInQueue(pipelineMaxParallel int64) {
 		runningParallelLabelLock.Lock()
 		runningParallelLabels = append(runningParallelLabels, pipelineParallelLabels...)
 		runningParallelLabelLock.Unlock()
+		addLocalPipeline(dbPipeline.ID)
 
 		go func(pipelineId uint64, parallelLabels []string) {
 			defer sema.Release(1)
+			defer removeLocalPipeline(pipelineId)
 			defer func() {
 				runningParallelLabelLock.Lock()
 				runningParallelLabels = utils.SliceRemove(runningParallelLabels, parallelLabels...)
@@ -396,12 +438,8 @@ func getProjectName(pipeline *models.Pipeline) (string, errors.Error) {
 	return dbBlueprint.ProjectName, nil
 }
 
-// NotifyExternal FIXME ...
+// NotifyExternal sends the status change of the pipeline to the global endpoint and the notification channels
 func NotifyExternal(pipelineId uint64) errors.Error {
-	notification := GetPipelineNotificationService()
-	if notification == nil {
-		return nil
-	}
 	// send notification to an external web endpoint
 	pipeline, err := GetPipeline(pipelineId, true)
 	if err != nil {
@@ -411,6 +449,14 @@ func NotifyExternal(pipelineId uint64) errors.Error {
 	if err != nil {
 		return err
 	}
+	err = notifyChannels(pipeline, projectName)
+	if err != nil {
+		globalPipelineLog.Error(err, "failed to notify channels of pipeline #%d", pipelineId)
+	}
+	notification := GetPipelineNotificationService()
+	if notification == nil {
+		return nil
+	}
 	err = notification.PipelineStatusChanged(PipelineNotificationParam{
 		ProjectName: projectName,
 		PipelineID:  pipeline.ID,
@@ -454,6 +500,19 @@ func CancelPipeline(pipelineId uint64) errors.Error {
 		// the target pipeline is pending, no running, no need to perform the actual cancel operation
 		return nil
 	}
+	if pipeline.Status == models.TASK_RUNNING && pipeline.WorkerId != "" && pipeline.WorkerId != getWorkerId() {
+		// the pipeline is running on another worker, ask it to cancel the tasks on its next heartbeat
+		err = db.UpdateColumn(&models.Pipeline{}, "cancel_requested", true, dal.Where("id = ?", pipelineId))
+		if err != nil {
+			return errors.Default.Wrap(err, "failed to request cancellation of the pipeline")
+		}
+		return nil
+	}
+	return cancelRunningTasks(pipelineId)
+}
+
+// cancelRunningTasks cancels the tasks of the pipeline running in the current process
+func cancelRunningTasks(pipelineId uint64) errors.Error {
 	pendingTasks, count, err := GetTasks(&TaskQuery{PipelineId: pipelineId, Pending: 1, Pagination: Pagination{PageSize: -1}})
 	if err != nil {
 		return errors.Convert(err)
@@ -481,8 +540,24 @@ func getPipelineLogsPath(pipeline *models.Pipeline) (string, errors.Error) {
 	return "", errors.Default.Wrap(err, fmt.Sprintf("error validating logs path for pipeline #%d", pipeline.ID))
 }
 
-// RerunPipeline would rerun all failed tasks or specified task
-func RerunPipeline(pipelineId uint64, task *models.Task) (tasks []*models.Task, err errors.Error) {
+// RERUN_MODE_RESUME skips the subtasks which were finished successfully in the previous attempt of the rerun tasks
+const RERUN_MODE_RESUME = "resume"
+
+// ParseRerunMode returns whether the rerun `mode` given by the user resumes the failed tasks
+func ParseRerunMode(mode string) (bool, errors.Error) {
+	switch mode {
+	case "":
+		return false, nil
+	case RERUN_MODE_RESUME:
+		return true, nil
+	}
+	return false, errors.BadInput.New(fmt.Sprintf("unsupported rerun mode %s", mode))
+}
+
+// RerunPipeline would rerun all failed tasks or specified task. With `resume`, the new tasks skip the subtasks which
+// were finished successfully in the previous attempts and restart from the failed one, reusing the raw data and the
+// collector states left by them
+func RerunPipeline(pipelineId uint64, task *models.Task, resume bool) (tasks []*models.Task, err errors.Error) {
 	// prevent pipeline executor from doing anything that might jeopardize the integrity
 	pipeline := &models.Pipeline{}
 	txHelper := dbhelper.NewTxHelper(basicRes, &err)
@@ -545,6 +620,10 @@ func RerunPipeline(pipelineId uint64, task *models.Task) (tasks []*models.Task,
 			return nil, err
 		}
 		// create new task
+		var resumedFromTaskId uint64
+		if resume {
+			resumedFromTaskId = t.ID
+		}
 		rerunTask, err := createTask(&models.NewTask{
 			PipelineTask: &models.PipelineTask{
 				Plugin:   t.Plugin,
@@ -555,6 +634,8 @@ func RerunPipeline(pipelineId uint64, task *models.Task) (tasks []*models.Task,
 			PipelineRow: t.PipelineRow,
 			PipelineCol: t.PipelineCol,
 			IsRerun:     true,
+
+			ResumedFromTaskId: resumedFromTaskId,
 		}, tx)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,55 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// registerPipelineMetrics exposes the pipelines waiting in the queue and the running ones, which are counted from the
+// database when scraped since the queue is shared by all workers
+func registerPipelineMetrics() {
+	metricshelper.Registry.MustRegister(
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "devlake",
+			Name:      "pipeline_queue_depth",
+			Help:      "Number of pipelines waiting to be run.",
+		}, func() float64 {
+			return countPipelines(models.TASK_CREATED, models.TASK_RERUN, models.TASK_RESUME)
+		}),
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: "devlake",
+			Name:      "pipelines_running",
+			Help:      "Number of running pipelines.",
+		}, func() float64 {
+			return countPipelines(models.TASK_RUNNING)
+		}),
+	)
+}
+
+func countPipelines(status ...string) float64 {
+	count, err := db.Count(dal.From(&models.Pipeline{}), dal.Where("status IN ?", status))
+	if err != nil {
+		logger.Error(err, "failed to count pipelines for metrics")
+		return 0
+	}
+	return float64(count)
+}
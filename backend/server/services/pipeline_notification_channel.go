@@ -0,0 +1,297 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+)
+
+// NotificationEvent is the content delivered to the notification channels
+type NotificationEvent struct {
+	Type        models.NotificationType `json:"type"`
+	ProjectName string                  `json:"projectName"`
+	BlueprintId uint64                  `json:"blueprintId"`
+	PipelineId  uint64                  `json:"pipelineId"`
+	TaskId      uint64                  `json:"taskId,omitempty"`
+	Status      string                  `json:"status,omitempty"`
+	Message     string                  `json:"message"`
+	Time        time.Time               `json:"time"`
+}
+
+// Title returns a one line summary of the event
+func (e *NotificationEvent) Title() string {
+	if e.ProjectName != "" {
+		return fmt.Sprintf("[DevLake] %s: %s", e.ProjectName, e.Type)
+	}
+	return fmt.Sprintf("[DevLake] %s", e.Type)
+}
+
+// pipelineEvents returns the events raised by the status change of the pipeline, tasks are only used when the
+// pipeline is finished
+func pipelineEvents(pipeline *models.Pipeline, projectName string, tasks []*models.Task) []*NotificationEvent {
+	now := time.Now()
+	newEvent := func(eventType models.NotificationType, message string) *NotificationEvent {
+		return &NotificationEvent{
+			Type:        eventType,
+			ProjectName: projectName,
+			BlueprintId: pipeline.BlueprintId,
+			PipelineId:  pipeline.ID,
+			Status:      pipeline.Status,
+			Message:     message,
+			Time:        now,
+		}
+	}
+	events := []*NotificationEvent{
+		newEvent(models.NotificationPipelineStatusChanged, fmt.Sprintf("pipeline #%d is %s", pipeline.ID, pipeline.Status)),
+	}
+	if pipeline.FinishedAt == nil {
+		return events
+	}
+	if pipeline.Status == models.TASK_FAILED {
+		events = append(events, newEvent(
+			models.NotificationPipelineFailed,
+			fmt.Sprintf("pipeline #%d failed: %s", pipeline.ID, pipeline.Message),
+		))
+	}
+	for _, task := range tasks {
+		if task.Status != models.TASK_FAILED {
+			continue
+		}
+		event := newEvent(
+			models.NotificationTaskFailed,
+			fmt.Sprintf("task #%d of plugin %s in pipeline #%d failed: %s", task.ID, task.Plugin, pipeline.ID, task.Message),
+		)
+		event.TaskId = task.ID
+		event.Status = task.Status
+		events = append(events, event)
+	}
+	return events
+}
+
+// findNotificationChannels returns the enabled channels of the blueprint or the project
+func findNotificationChannels(blueprintId uint64, projectName string) ([]*models.NotificationChannel, errors.Error) {
+	channels := make([]*models.NotificationChannel, 0)
+	clauses := []dal.Clause{dal.Where("enable = ?", true)}
+	if projectName != "" {
+		clauses = append(clauses, dal.Where("blueprint_id = ? OR project_name = ?", blueprintId, projectName))
+	} else {
+		clauses = append(clauses, dal.Where("blueprint_id = ?", blueprintId))
+	}
+	err := db.All(&channels, clauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error finding DB notification channels")
+	}
+	return channels, nil
+}
+
+// notifyChannels delivers the events raised by the status change of the pipeline to the subscribed channels
+func notifyChannels(pipeline *models.Pipeline, projectName string) errors.Error {
+	if pipeline.BlueprintId == 0 {
+		return nil
+	}
+	channels, err := findNotificationChannels(pipeline.BlueprintId, projectName)
+	if err != nil || len(channels) == 0 {
+		return err
+	}
+	var tasks []*models.Task
+	if pipeline.FinishedAt != nil {
+		tasks, err = GetLatestTasksOfPipeline(pipeline)
+		if err != nil {
+			return err
+		}
+	}
+	events := pipelineEvents(pipeline, projectName, tasks)
+	// a broken channel must not stop the others from being notified
+	var errs []error
+	for _, channel := range channels {
+		for _, event := range events {
+			if channel.Subscribes(event.Type) {
+				if err := deliverNotification(channel, event); err != nil {
+					errs = append(errs, errors.Default.Wrap(err, fmt.Sprintf("failed to notify channel #%d", channel.ID)))
+				}
+			}
+		}
+		// the pipeline might finish before the watcher detects it was running for too long
+		if pipeline.FinishedAt != nil {
+			if err := notifyLongRunningPipeline(channel, pipeline, projectName); err != nil {
+				errs = append(errs, errors.Default.Wrap(err, fmt.Sprintf("failed to notify channel #%d", channel.ID)))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Default.Combine(errs)
+	}
+	return nil
+}
+
+// watchNotificationChannels checks the conditions which are not triggered by the status change of the pipelines
+// periodically, i.e. long running pipelines and blueprints without successful pipelines, and retries the
+// notifications which were left undelivered
+func watchNotificationChannels() {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for range ticker.C {
+		channels := make([]*models.NotificationChannel, 0)
+		err := db.All(&channels, dal.Where("enable = ?", true))
+		if err != nil {
+			globalPipelineLog.Error(err, "failed to find notification channels")
+			continue
+		}
+		for _, channel := range channels {
+			if err := checkNotificationChannel(channel); err != nil {
+				globalPipelineLog.Error(err, "failed to check notification channel #%d", channel.ID)
+			}
+		}
+		if err := sweepNotifications(); err != nil {
+			globalPipelineLog.Error(err, "failed to retry notifications")
+		}
+	}
+}
+
+func checkNotificationChannel(channel *models.NotificationChannel) errors.Error {
+	watchLongRunning := channel.Subscribes(models.NotificationPipelineTookTooLong)
+	watchStale := channel.Subscribes(models.NotificationBlueprintNotSucceeded)
+	if !watchLongRunning && !watchStale {
+		return nil
+	}
+	blueprints, err := getChannelBlueprints(channel)
+	if err != nil {
+		return err
+	}
+	for _, blueprint := range blueprints {
+		if watchLongRunning {
+			pipelines := make([]*models.Pipeline, 0)
+			err = db.All(&pipelines, dal.Where("blueprint_id = ? AND status = ?", blueprint.ID, models.TASK_RUNNING))
+			if err != nil {
+				return errors.Default.Wrap(err, "error finding DB running pipelines")
+			}
+			for _, pipeline := range pipelines {
+				if err := notifyLongRunningPipeline(channel, pipeline, blueprint.ProjectName); err != nil {
+					return err
+				}
+			}
+		}
+		if watchStale {
+			if err := notifyStaleBlueprint(channel, blueprint); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func getChannelBlueprints(channel *models.NotificationChannel) ([]*models.Blueprint, errors.Error) {
+	blueprints := make([]*models.Blueprint, 0)
+	if channel.BlueprintId != 0 {
+		blueprint, err := bpManager.GetDbBlueprint(channel.BlueprintId)
+		if err != nil {
+			if db.IsErrorNotFound(err) {
+				return blueprints, nil
+			}
+			return nil, err
+		}
+		blueprints = append(blueprints, blueprint)
+	}
+	if channel.ProjectName != "" {
+		blueprint, err := GetBlueprintByProjectName(channel.ProjectName)
+		if err != nil {
+			return nil, err
+		}
+		if blueprint != nil && blueprint.ID != channel.BlueprintId {
+			blueprints = append(blueprints, blueprint)
+		}
+	}
+	return blueprints, nil
+}
+
+// notifyLongRunningPipeline sends the PipelineTookTooLong event once per pipeline
+func notifyLongRunningPipeline(channel *models.NotificationChannel, pipeline *models.Pipeline, projectName string) errors.Error {
+	if !channel.Subscribes(models.NotificationPipelineTookTooLong) || pipeline.BeganAt == nil {
+		return nil
+	}
+	threshold := time.Duration(channel.DurationThresholdMinutes) * time.Minute
+	end := time.Now()
+	if pipeline.FinishedAt != nil {
+		end = *pipeline.FinishedAt
+	}
+	if end.Sub(*pipeline.BeganAt) <= threshold {
+		return nil
+	}
+	notified, err := hasNotified(channel, models.NotificationPipelineTookTooLong, dal.Where("pipeline_id = ?", pipeline.ID))
+	if err != nil || notified {
+		return err
+	}
+	return deliverNotification(channel, &NotificationEvent{
+		Type:        models.NotificationPipelineTookTooLong,
+		ProjectName: projectName,
+		BlueprintId: pipeline.BlueprintId,
+		PipelineId:  pipeline.ID,
+		Status:      pipeline.Status,
+		Message:     fmt.Sprintf("pipeline #%d has been running for more than %d minutes", pipeline.ID, channel.DurationThresholdMinutes),
+		Time:        time.Now(),
+	})
+}
+
+// notifyStaleBlueprint sends the BlueprintNotSucceeded event at most once every StaleDays
+func notifyStaleBlueprint(channel *models.NotificationChannel, blueprint *models.Blueprint) errors.Error {
+	staleSince := time.Now().AddDate(0, 0, -channel.StaleDays)
+	if blueprint.CreatedAt.After(staleSince) {
+		return nil
+	}
+	succeeded, err := db.Count(
+		dal.From(&models.Pipeline{}),
+		dal.Where("blueprint_id = ? AND status = ? AND finished_at >= ?", blueprint.ID, models.TASK_COMPLETED, staleSince),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "error counting DB successful pipelines")
+	}
+	if succeeded > 0 {
+		return nil
+	}
+	notified, err := hasNotified(
+		channel,
+		models.NotificationBlueprintNotSucceeded,
+		dal.Where("blueprint_id = ? AND created_at >= ?", blueprint.ID, staleSince),
+	)
+	if err != nil || notified {
+		return err
+	}
+	return deliverNotification(channel, &NotificationEvent{
+		Type:        models.NotificationBlueprintNotSucceeded,
+		ProjectName: blueprint.ProjectName,
+		BlueprintId: blueprint.ID,
+		Message:     fmt.Sprintf("blueprint %s has not succeeded in the last %d days", blueprint.Name, channel.StaleDays),
+		Time:        time.Now(),
+	})
+}
+
+func hasNotified(channel *models.NotificationChannel, event models.NotificationType, clauses ...dal.Clause) (bool, errors.Error) {
+	count, err := db.Count(append([]dal.Clause{
+		dal.From(&models.Notification{}),
+		dal.Where("channel_id = ? AND type = ?", channel.ID, event),
+	}, clauses...)...)
+	if err != nil {
+		return false, errors.Default.Wrap(err, "error counting DB notifications")
+	}
+	return count > 0, nil
+}
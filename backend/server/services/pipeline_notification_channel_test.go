@@ -0,0 +1,89 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPipelineEvents(t *testing.T) {
+	pipeline := &models.Pipeline{
+		Model:       common.Model{ID: 3},
+		BlueprintId: 2,
+		Status:      models.TASK_RUNNING,
+	}
+	events := pipelineEvents(pipeline, "demo", nil)
+	assert.Len(t, events, 1)
+	assert.Equal(t, models.NotificationPipelineStatusChanged, events[0].Type)
+	assert.Equal(t, "demo", events[0].ProjectName)
+	assert.Equal(t, uint64(2), events[0].BlueprintId)
+
+	finishedAt := time.Now()
+	pipeline.FinishedAt = &finishedAt
+	pipeline.Status = models.TASK_FAILED
+	tasks := []*models.Task{
+		{Model: common.Model{ID: 5}, Plugin: "github", Status: models.TASK_COMPLETED},
+		{Model: common.Model{ID: 6}, Plugin: "jira", Status: models.TASK_FAILED, Message: "401"},
+	}
+	events = pipelineEvents(pipeline, "demo", tasks)
+	assert.Len(t, events, 3)
+	assert.Equal(t, models.NotificationPipelineFailed, events[1].Type)
+	assert.Equal(t, models.NotificationTaskFailed, events[2].Type)
+	assert.Equal(t, uint64(6), events[2].TaskId)
+	assert.Contains(t, events[2].Message, "jira")
+}
+
+func TestNotificationPayloads(t *testing.T) {
+	event := &NotificationEvent{
+		Type:        models.NotificationPipelineFailed,
+		ProjectName: "demo",
+		PipelineId:  3,
+		Status:      models.TASK_FAILED,
+		Message:     "pipeline #3 failed",
+	}
+
+	var slack map[string]interface{}
+	assert.Nil(t, json.Unmarshal(slackPayload(event), &slack))
+	assert.Equal(t, "*[DevLake] demo: PipelineFailed*\npipeline #3 failed", slack["text"])
+
+	var teams map[string]interface{}
+	assert.Nil(t, json.Unmarshal(teamsPayload(event), &teams))
+	assert.Equal(t, "MessageCard", teams["@type"])
+	assert.Equal(t, "D70000", teams["themeColor"])
+	assert.Equal(t, "pipeline #3 failed", teams["text"])
+}
+
+func TestNotificationChannelSanitize(t *testing.T) {
+	channel := models.NotificationChannel{
+		Type:     models.NOTIFICATION_CHANNEL_SLACK,
+		Endpoint: "https://hooks.slack.com/services/T000/B000/XXXX",
+		Secret:   "secret",
+		Events:   []models.NotificationType{models.NotificationTaskFailed},
+	}
+	sanitized := channel.Sanitize()
+	assert.Equal(t, "https://hooks.slack.com/********", sanitized.Endpoint)
+	assert.Equal(t, "******", sanitized.Secret)
+	assert.True(t, channel.Subscribes(models.NotificationTaskFailed))
+	assert.False(t, channel.Subscribes(models.NotificationPipelineFailed))
+}
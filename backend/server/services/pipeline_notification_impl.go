@@ -64,6 +64,7 @@ func (n *DefaultPipelineNotificationService) sendNotification(notificationType m
 		return err1
 	}
 	notification.Nonce = nonce
+	notification.Status = models.NOTIFICATION_STATUS_PENDING
 
 	err = db.Create(&notification)
 	if err != nil {
@@ -73,11 +74,16 @@ func (n *DefaultPipelineNotificationService) sendNotification(notificationType m
 	sign := n.signature(notification.Data, fmt.Sprintf("%d-%s", notification.ID, nonce))
 	url := fmt.Sprintf("%s?nouce=%d-%s&sign=%s", n.EndPoint, notification.ID, nonce, sign)
 
+	notification.Attempts = 1
 	resp, err := http.Post(url, "application/json", strings.NewReader(notification.Data))
 	if err != nil {
+		notification.Status = models.NOTIFICATION_STATUS_FAILED
+		notification.Error = err.Error()
+		_ = db.Update(notification)
 		return errors.Convert(err)
 	}
 
+	notification.Status = models.NOTIFICATION_STATUS_SENT
 	notification.ResponseCode = resp.StatusCode
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
@@ -88,6 +94,10 @@ func (n *DefaultPipelineNotificationService) sendNotification(notificationType m
 }
 
 func (n *DefaultPipelineNotificationService) signature(input, nouce string) string {
-	sum := sha256.Sum256([]byte(input + n.Secret + nouce))
+	return signNotification(input, n.Secret, nouce)
+}
+
+func signNotification(input, secret, nouce string) string {
+	sum := sha256.Sum256([]byte(input + secret + nouce))
 	return hex.EncodeToString(sum[:])
 }
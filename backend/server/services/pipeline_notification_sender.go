@@ -0,0 +1,272 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/smtp"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/utils"
+)
+
+var notificationHttpClient = &http.Client{Timeout: 30 * time.Second}
+
+// deliverNotification records the notification and sends it to the channel in background, failed deliveries are
+// retried up to NOTIFICATION_MAX_ATTEMPTS times
+func deliverNotification(channel *models.NotificationChannel, event *NotificationEvent) errors.Error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	nonce, err1 := utils.RandLetterBytes(16)
+	if err1 != nil {
+		return err1
+	}
+	notification := &models.Notification{
+		Type:        event.Type,
+		Endpoint:    channel.Sanitize().Endpoint,
+		Nonce:       nonce,
+		Data:        string(data),
+		ChannelId:   channel.ID,
+		BlueprintId: event.BlueprintId,
+		PipelineId:  event.PipelineId,
+		Status:      models.NOTIFICATION_STATUS_PENDING,
+	}
+	err = db.Create(notification)
+	if err != nil {
+		return errors.Default.Wrap(err, "error creating DB notification")
+	}
+	go sendNotificationWithRetry(*channel, notification, event)
+	return nil
+}
+
+func getNotificationMaxAttempts() int {
+	maxAttempts := cfg.GetInt("NOTIFICATION_MAX_ATTEMPTS")
+	if maxAttempts <= 0 {
+		maxAttempts = 1
+	}
+	return maxAttempts
+}
+
+func getNotificationRetryInterval() time.Duration {
+	return time.Duration(cfg.GetInt("NOTIFICATION_RETRY_INTERVAL")) * time.Second
+}
+
+// getNotificationStaleTimeout returns how long a notification could stay untouched while it is being sent, the ones
+// untouched for longer were abandoned, i.e. the process was restarted before the delivery finished
+func getNotificationStaleTimeout() time.Duration {
+	maxAttempts := getNotificationMaxAttempts()
+	return getNotificationRetryInterval()*time.Duration(maxAttempts) + notificationHttpClient.Timeout + time.Minute
+}
+
+func sendNotificationWithRetry(channel models.NotificationChannel, notification *models.Notification, event *NotificationEvent) {
+	maxAttempts := getNotificationMaxAttempts()
+	retryInterval := getNotificationRetryInterval()
+	for notification.Attempts < maxAttempts {
+		if notification.Attempts > 0 {
+			// linear backoff
+			time.Sleep(retryInterval * time.Duration(notification.Attempts))
+		}
+		notification.Attempts++
+		code, response, err := sendToChannel(&channel, notification, event)
+		notification.ResponseCode = code
+		notification.Response = response
+		if err == nil {
+			notification.Status = models.NOTIFICATION_STATUS_SENT
+			notification.Error = ""
+			break
+		}
+		notification.Status = models.NOTIFICATION_STATUS_FAILED
+		notification.Error = err.Error()
+		globalPipelineLog.Warn(err, "attempt %d of notification #%d to channel #%d failed", notification.Attempts, notification.ID, channel.ID)
+		// keep the progress so the sweeper would pick it up if the process was stopped before the next attempt
+		if notification.Attempts < maxAttempts {
+			if err := db.Update(notification); err != nil {
+				globalPipelineLog.Error(err, "failed to update notification #%d", notification.ID)
+			}
+		}
+	}
+	if err := db.Update(notification); err != nil {
+		globalPipelineLog.Error(err, "failed to update notification #%d", notification.ID)
+	}
+}
+
+// sweepNotifications resends the notifications which were abandoned before all attempts were made, e.g. because
+// the process was restarted
+func sweepNotifications() errors.Error {
+	staleBefore := time.Now().Add(-getNotificationStaleTimeout())
+	notifications := make([]*models.Notification, 0)
+	err := db.All(&notifications,
+		dal.Where(
+			"channel_id > 0 AND status IN ? AND attempts < ? AND updated_at < ?",
+			[]string{models.NOTIFICATION_STATUS_PENDING, models.NOTIFICATION_STATUS_FAILED},
+			getNotificationMaxAttempts(),
+			staleBefore,
+		),
+		dal.Orderby("id ASC"),
+		dal.Limit(100),
+	)
+	if err != nil {
+		return err
+	}
+	for _, notification := range notifications {
+		// claim the notification so it would not be resent by another process
+		claimed, err := db.UpdateColumnsAffected(
+			&models.Notification{},
+			[]dal.DalSet{{ColumnName: "updated_at", Value: time.Now()}},
+			dal.Where("id = ? AND attempts = ? AND updated_at < ?", notification.ID, notification.Attempts, staleBefore),
+		)
+		if err != nil {
+			return err
+		}
+		if claimed != 1 {
+			continue
+		}
+		channel := &models.NotificationChannel{}
+		err = db.First(channel, dal.Where("id = ? AND enable = ?", notification.ChannelId, true))
+		if err != nil {
+			if !db.IsErrorNotFound(err) {
+				return err
+			}
+			// the channel was deleted or disabled in the meantime, give up
+			notification.Status = models.NOTIFICATION_STATUS_FAILED
+			notification.Error = fmt.Sprintf("notification channel #%d is not available", notification.ChannelId)
+			notification.Attempts = getNotificationMaxAttempts()
+			if err := db.Update(notification); err != nil {
+				return err
+			}
+			continue
+		}
+		event := &NotificationEvent{}
+		if err := json.Unmarshal([]byte(notification.Data), event); err != nil {
+			return errors.Convert(err)
+		}
+		globalPipelineLog.Info("resending notification #%d to channel #%d", notification.ID, channel.ID)
+		go sendNotificationWithRetry(*channel, notification, event)
+	}
+	return nil
+}
+
+// sendToChannel sends the event in the format of the channel type, returns the response of the endpoint
+func sendToChannel(channel *models.NotificationChannel, notification *models.Notification, event *NotificationEvent) (int, string, errors.Error) {
+	switch channel.Type {
+	case models.NOTIFICATION_CHANNEL_WEBHOOK:
+		sign := signNotification(notification.Data, channel.Secret, fmt.Sprintf("%d-%s", notification.ID, notification.Nonce))
+		endpoint := fmt.Sprintf("%s?nouce=%d-%s&sign=%s", channel.Endpoint, notification.ID, notification.Nonce, sign)
+		return postJson(endpoint, []byte(notification.Data))
+	case models.NOTIFICATION_CHANNEL_SLACK:
+		return postJson(channel.Endpoint, slackPayload(event))
+	case models.NOTIFICATION_CHANNEL_TEAMS:
+		return postJson(channel.Endpoint, teamsPayload(event))
+	case models.NOTIFICATION_CHANNEL_EMAIL:
+		return 0, "", sendEmail(strings.Split(channel.Endpoint, ","), event)
+	}
+	return 0, "", errors.BadInput.New(fmt.Sprintf("unsupported notification channel type %s", channel.Type))
+}
+
+func postJson(endpoint string, body []byte) (int, string, errors.Error) {
+	resp, err := notificationHttpClient.Post(endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return 0, "", errors.Convert(err)
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, "", errors.Convert(err)
+	}
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode, string(respBody), errors.HttpStatus(resp.StatusCode).New(fmt.Sprintf("unexpected status code %d", resp.StatusCode))
+	}
+	return resp.StatusCode, string(respBody), nil
+}
+
+// slackPayload formats the event for the slack-compatible incoming webhooks
+func slackPayload(event *NotificationEvent) []byte {
+	payload, _ := json.Marshal(map[string]interface{}{
+		"text": fmt.Sprintf("*%s*\n%s", event.Title(), event.Message),
+	})
+	return payload
+}
+
+// teamsPayload formats the event as a MS Teams message card
+func teamsPayload(event *NotificationEvent) []byte {
+	themeColor := "0076D7"
+	if event.Type != models.NotificationPipelineStatusChanged || event.Status == models.TASK_FAILED {
+		themeColor = "D70000"
+	}
+	facts := []map[string]string{}
+	if event.ProjectName != "" {
+		facts = append(facts, map[string]string{"name": "Project", "value": event.ProjectName})
+	}
+	if event.PipelineId != 0 {
+		facts = append(facts, map[string]string{"name": "Pipeline", "value": fmt.Sprintf("#%d", event.PipelineId)})
+	}
+	if event.Status != "" {
+		facts = append(facts, map[string]string{"name": "Status", "value": event.Status})
+	}
+	payload, _ := json.Marshal(map[string]interface{}{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"summary":    event.Title(),
+		"themeColor": themeColor,
+		"title":      event.Title(),
+		"text":       event.Message,
+		"sections":   []map[string]interface{}{{"facts": facts}},
+	})
+	return payload
+}
+
+// sendEmail sends the event through the SMTP server configured by SMTP_HOST and friends
+func sendEmail(recipients []string, event *NotificationEvent) errors.Error {
+	host := cfg.GetString("SMTP_HOST")
+	if host == "" {
+		return errors.BadInput.New("SMTP_HOST is not configured")
+	}
+	to := make([]string, 0, len(recipients))
+	for _, recipient := range recipients {
+		if recipient = strings.TrimSpace(recipient); recipient != "" {
+			to = append(to, recipient)
+		}
+	}
+	if len(to) == 0 {
+		return errors.BadInput.New("no recipient")
+	}
+	from := cfg.GetString("SMTP_FROM")
+	var auth smtp.Auth
+	if username := cfg.GetString("SMTP_USERNAME"); username != "" {
+		auth = smtp.PlainAuth("", username, cfg.GetString("SMTP_PASSWORD"), host)
+	}
+	msg := fmt.Sprintf(
+		"From: %s\r\nTo: %s\r\nSubject: %s\r\nContent-Type: text/plain; charset=UTF-8\r\n\r\n%s\r\n",
+		from, strings.Join(to, ", "), event.Title(), event.Message,
+	)
+	err := smtp.SendMail(fmt.Sprintf("%s:%d", host, cfg.GetInt("SMTP_PORT")), auth, from, to, []byte(msg))
+	if err != nil {
+		return errors.Convert(err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/config"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/spf13/viper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockNotificationSender(t *testing.T) (*mockdal.Dal, *httptest.Server, *int32) {
+	mockDal := mockdal.NewDal(t)
+	v := viper.New()
+	v.Set("NOTIFICATION_MAX_ATTEMPTS", 3)
+	v.Set("NOTIFICATION_RETRY_INTERVAL", 0)
+	oldDb, oldCfg := db, cfg
+	db, cfg = mockDal, config.ConfigReader(v)
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(func() {
+		server.Close()
+		db, cfg = oldDb, oldCfg
+	})
+	return mockDal, server, &requests
+}
+
+// expectNotificationSent returns a channel receiving the notification once its delivery is finished
+func expectNotificationSent(mockDal *mockdal.Dal) chan *models.Notification {
+	sent := make(chan *models.Notification, 1)
+	mockDal.On("Update", mock.AnythingOfType("*models.Notification"), mock.Anything).Run(func(args mock.Arguments) {
+		sent <- args.Get(0).(*models.Notification)
+	}).Return(nil).Once()
+	return sent
+}
+
+func waitNotificationSent(t *testing.T, sent chan *models.Notification) *models.Notification {
+	select {
+	case notification := <-sent:
+		return notification
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "notification was not sent")
+		return nil
+	}
+}
+
+func TestSweepNotificationsResendsAbandonedNotifications(t *testing.T) {
+	mockDal, server, requests := mockNotificationSender(t)
+	data, _ := json.Marshal(&NotificationEvent{Type: models.NotificationPipelineFailed, PipelineId: 3})
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]*models.Notification) = []*models.Notification{{
+			Model:     common.Model{ID: 1},
+			Type:      models.NotificationPipelineFailed,
+			Data:      string(data),
+			ChannelId: 2,
+			Status:    models.NOTIFICATION_STATUS_FAILED,
+			Attempts:  1,
+		}}
+	}).Return(nil).Once()
+	mockDal.On("UpdateColumnsAffected", mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+	mockDal.On("First", mock.AnythingOfType("*models.NotificationChannel"), mock.Anything).Run(func(args mock.Arguments) {
+		channel := args.Get(0).(*models.NotificationChannel)
+		channel.ID = 2
+		channel.Type = models.NOTIFICATION_CHANNEL_WEBHOOK
+		channel.Endpoint = server.URL
+	}).Return(nil).Once()
+	sent := expectNotificationSent(mockDal)
+
+	assert.Nil(t, sweepNotifications())
+	notification := waitNotificationSent(t, sent)
+	if assert.NotNil(t, notification) {
+		assert.Equal(t, models.NOTIFICATION_STATUS_SENT, notification.Status)
+		assert.Equal(t, 2, notification.Attempts)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
+
+func TestSweepNotificationsSkipsClaimedNotifications(t *testing.T) {
+	mockDal, _, requests := mockNotificationSender(t)
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]*models.Notification) = []*models.Notification{{
+			Model:     common.Model{ID: 1},
+			ChannelId: 2,
+			Status:    models.NOTIFICATION_STATUS_PENDING,
+		}}
+	}).Return(nil).Once()
+	// resent by another process already
+	mockDal.On("UpdateColumnsAffected", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+
+	assert.Nil(t, sweepNotifications())
+	assert.Equal(t, int32(0), atomic.LoadInt32(requests))
+}
+
+func TestNotifyChannelsKeepsNotifyingOtherChannels(t *testing.T) {
+	mockDal, server, requests := mockNotificationSender(t)
+	events := []models.NotificationType{models.NotificationPipelineStatusChanged}
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*[]*models.NotificationChannel) = []*models.NotificationChannel{
+			{Model: common.Model{ID: 1}, Type: models.NOTIFICATION_CHANNEL_WEBHOOK, Endpoint: server.URL, Events: events},
+			{Model: common.Model{ID: 2}, Type: models.NOTIFICATION_CHANNEL_WEBHOOK, Endpoint: server.URL, Events: events},
+		}
+	}).Return(nil).Once()
+	mockDal.On("Create", mock.MatchedBy(func(n *models.Notification) bool { return n.ChannelId == 1 }), mock.Anything).
+		Return(errors.Default.New("database is gone")).Once()
+	mockDal.On("Create", mock.MatchedBy(func(n *models.Notification) bool { return n.ChannelId == 2 }), mock.Anything).
+		Return(nil).Once()
+	sent := expectNotificationSent(mockDal)
+
+	err := notifyChannels(&models.Pipeline{Model: common.Model{ID: 3}, BlueprintId: 1, Status: models.TASK_RUNNING}, "")
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "channel #1")
+	notification := waitNotificationSent(t, sent)
+	if assert.NotNil(t, notification) {
+		assert.Equal(t, uint64(2), notification.ChannelId)
+		assert.Equal(t, models.NOTIFICATION_STATUS_SENT, notification.Status)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(requests))
+}
@@ -26,6 +26,9 @@ import (
 	"github.com/apache/incubator-devlake/core/models"
 	"github.com/apache/incubator-devlake/core/runner"
 	"github.com/apache/incubator-devlake/impls/logruslog"
+	"github.com/apache/incubator-devlake/impls/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"time"
 )
 
@@ -34,12 +37,12 @@ type pipelineRunner struct {
 	pipeline *models.Pipeline
 }
 
-func (p *pipelineRunner) runPipelineStandalone() errors.Error {
+func (p *pipelineRunner) runPipelineStandalone(ctx context.Context) errors.Error {
 	return runner.RunPipeline(
 		basicRes.ReplaceLogger(p.logger),
 		p.pipeline.ID,
 		func(taskIds []uint64) errors.Error {
-			return RunTasksStandalone(p.logger, taskIds)
+			return RunTasksStandalone(ctx, p.logger, taskIds)
 		},
 	)
 }
@@ -68,16 +71,26 @@ func runPipeline(pipelineId uint64) errors.Error {
 	if err != nil {
 		return err
 	}
+	ctx, span := tracing.Tracer().Start(context.Background(), "pipeline", trace.WithAttributes(
+		attribute.Int64("devlake.pipeline_id", int64(pipelineId)),
+		attribute.Int64("devlake.blueprint_id", int64(ppl.BlueprintId)),
+	))
 	pipelineRun := pipelineRunner{
-		logger:   GetPipelineLogger(ppl),
+		logger:   logruslog.WithTraceContext(GetPipelineLogger(ppl), ctx),
 		pipeline: ppl,
 	}
 	// run
-	err = pipelineRun.runPipelineStandalone()
+	err = pipelineRun.runPipelineStandalone(ctx)
+	tracing.EndSpan(span, err)
 	isCancelled := errors.Is(err, context.Canceled)
 	if err != nil {
 		err = errors.Default.Wrap(err, fmt.Sprintf("Error running pipeline %d.", pipelineId))
 	}
+	if isPipelineLeaseLost(pipelineId) {
+		// the pipeline belongs to another worker now, leave its status alone
+		globalPipelineLog.Warn(err, "pipeline #%d was taken over by another worker", pipelineId)
+		return nil
+	}
 	dbPipeline, e := GetDbPipeline(pipelineId)
 	if e != nil {
 		return errors.Default.Wrap(err, fmt.Sprintf("Unable to get pipeline %d.", pipelineId))
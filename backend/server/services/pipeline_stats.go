@@ -0,0 +1,253 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+)
+
+const (
+	PIPELINE_STATS_BY_PLUGIN     = "plugins"
+	PIPELINE_STATS_BY_SUBTASK    = "subtasks"
+	PIPELINE_STATS_BY_CONNECTION = "connections"
+	PIPELINE_STATS_BY_SCOPE      = "scopes"
+)
+
+// scopeIdOptionKeys are the task options identifying the scope of a task, plugins name them differently
+var scopeIdOptionKeys = []string{"scopeId", "fullName", "projectId", "boardId", "name"}
+
+// PipelineStatsQuery filters the tasks the pipeline stats are computed from, Interval splits them into a time-series
+type PipelineStatsQuery struct {
+	StartDate   *time.Time `form:"startDate" time_format:"2006-01-02"`
+	EndDate     *time.Time `form:"endDate" time_format:"2006-01-02"`
+	Plugin      string     `form:"plugin"`
+	BlueprintId uint64     `form:"blueprint_id"`
+	Interval    string     `form:"interval"`
+}
+
+// PipelineStats is the performance of a group of tasks, or subtasks when grouped by subtask.
+// Durations are in seconds and only count the finished runs
+type PipelineStats struct {
+	Plugin           string     `json:"plugin"`
+	Subtask          string     `json:"subtask,omitempty"`
+	ConnectionId     uint64     `json:"connectionId,omitempty"`
+	ScopeId          string     `json:"scopeId,omitempty"`
+	Period           *time.Time `json:"period,omitempty"`
+	Runs             int        `json:"runs"`
+	Failures         int        `json:"failures"`
+	FailureRate      float64    `json:"failureRate"`
+	P50Duration      *float64   `json:"p50Duration"`
+	P95Duration      *float64   `json:"p95Duration"`
+	RecordsProcessed int64      `json:"recordsProcessed"`
+	HttpRequests     int64      `json:"httpRequests"`
+	HttpRetries      int64      `json:"httpRetries"`
+	HttpRateLimited  int64      `json:"httpRateLimited"`
+	durations        []float64
+}
+
+// GetPipelineStats aggregates the tasks began in the time window by the given dimension
+func GetPipelineStats(groupBy string, query *PipelineStatsQuery) ([]*PipelineStats, errors.Error) {
+	switch groupBy {
+	case PIPELINE_STATS_BY_PLUGIN, PIPELINE_STATS_BY_SUBTASK, PIPELINE_STATS_BY_CONNECTION, PIPELINE_STATS_BY_SCOPE:
+	default:
+		return nil, errors.BadInput.New(fmt.Sprintf("unknown dimension %s", groupBy))
+	}
+	periodOf, err := getStatsPeriodOf(query.Interval)
+	if err != nil {
+		return nil, err
+	}
+	endDate := time.Now()
+	if query.EndDate != nil {
+		// the end date is inclusive
+		endDate = query.EndDate.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	startDate := endDate.AddDate(0, 0, -30)
+	if query.StartDate != nil {
+		startDate = *query.StartDate
+	}
+	if !startDate.Before(endDate) {
+		return nil, errors.BadInput.New("startDate must be earlier than endDate")
+	}
+
+	clauses := []dal.Clause{dal.Where("began_at BETWEEN ? AND ?", startDate, endDate)}
+	if query.Plugin != "" {
+		clauses = append(clauses, dal.Where("plugin = ?", query.Plugin))
+	}
+	if query.BlueprintId > 0 {
+		clauses = append(clauses, dal.Where("pipeline_id IN (SELECT id FROM _devlake_pipelines WHERE blueprint_id = ?)", query.BlueprintId))
+	}
+	var tasks []*models.Task
+	err = db.All(&tasks, clauses...)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error getting tasks")
+	}
+	var subtasks []*models.Subtask
+	if len(tasks) > 0 {
+		taskIds := make([]uint64, len(tasks))
+		for i, task := range tasks {
+			taskIds[i] = task.ID
+		}
+		err = db.All(&subtasks, dal.Where("task_id IN ?", taskIds))
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "error getting subtasks")
+		}
+	}
+	return aggregatePipelineStats(groupBy, periodOf, tasks, subtasks), nil
+}
+
+func getStatsPeriodOf(interval string) (func(time.Time) time.Time, errors.Error) {
+	switch interval {
+	case "":
+		return nil, nil
+	case "day":
+		return dayOf, nil
+	case "week":
+		return weekOf, nil
+	case "month":
+		return monthOf, nil
+	}
+	return nil, errors.BadInput.New(fmt.Sprintf("interval must be one of day, week or month, got %s", interval))
+}
+
+// aggregatePipelineStats groups the tasks, or their subtasks, by the dimension and the period they began in
+func aggregatePipelineStats(
+	groupBy string,
+	periodOf func(time.Time) time.Time,
+	tasks []*models.Task,
+	subtasks []*models.Subtask,
+) []*PipelineStats {
+	groups := make(map[string]*PipelineStats)
+	var result []*PipelineStats
+	getGroup := func(task *models.Task, subtaskName string) *PipelineStats {
+		key := &PipelineStats{Plugin: task.Plugin, Subtask: subtaskName}
+		if groupBy == PIPELINE_STATS_BY_CONNECTION || groupBy == PIPELINE_STATS_BY_SCOPE {
+			key.ConnectionId = getConnectionIdOption(task.Options)
+		}
+		if groupBy == PIPELINE_STATS_BY_SCOPE {
+			key.ScopeId = getScopeIdOption(task.Options)
+		}
+		if periodOf != nil && task.BeganAt != nil {
+			period := periodOf(*task.BeganAt)
+			key.Period = &period
+		}
+		id := fmt.Sprintf("%s/%s/%d/%s", key.Plugin, key.Subtask, key.ConnectionId, key.ScopeId)
+		if key.Period != nil {
+			id += "/" + key.Period.Format(time.RFC3339)
+		}
+		if group, ok := groups[id]; ok {
+			return group
+		}
+		groups[id] = key
+		result = append(result, key)
+		return key
+	}
+
+	taskById := make(map[uint64]*models.Task, len(tasks))
+	for _, task := range tasks {
+		taskById[task.ID] = task
+		if groupBy == PIPELINE_STATS_BY_SUBTASK {
+			continue
+		}
+		group := getGroup(task, "")
+		if task.FinishedAt == nil {
+			continue
+		}
+		group.Runs++
+		if task.Status == models.TASK_FAILED {
+			group.Failures++
+		}
+		group.durations = append(group.durations, float64(task.SpentSeconds))
+	}
+	for _, subtask := range subtasks {
+		task := taskById[subtask.TaskID]
+		if task == nil {
+			continue
+		}
+		var group *PipelineStats
+		if groupBy == PIPELINE_STATS_BY_SUBTASK {
+			group = getGroup(task, subtask.Name)
+			if subtask.FinishedAt != nil {
+				group.Runs++
+				if subtask.IsFailed {
+					group.Failures++
+				}
+				group.durations = append(group.durations, float64(subtask.SpentSeconds))
+			}
+		} else {
+			group = getGroup(task, "")
+		}
+		group.RecordsProcessed += subtask.RecordsWritten
+		group.HttpRequests += subtask.HttpRequests
+		group.HttpRetries += subtask.HttpRetries
+		group.HttpRateLimited += subtask.HttpRateLimited
+	}
+
+	for _, group := range result {
+		if group.Runs > 0 {
+			group.FailureRate = float64(group.Failures) / float64(group.Runs)
+		}
+		group.P50Duration = percentile(group.durations, 0.5)
+		group.P95Duration = percentile(group.durations, 0.95)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.Plugin != b.Plugin {
+			return a.Plugin < b.Plugin
+		}
+		if a.Subtask != b.Subtask {
+			return a.Subtask < b.Subtask
+		}
+		if a.ConnectionId != b.ConnectionId {
+			return a.ConnectionId < b.ConnectionId
+		}
+		if a.ScopeId != b.ScopeId {
+			return a.ScopeId < b.ScopeId
+		}
+		return a.Period != nil && b.Period != nil && a.Period.Before(*b.Period)
+	})
+	return result
+}
+
+func getConnectionIdOption(options map[string]interface{}) uint64 {
+	if value, ok := options["connectionId"].(float64); ok && value > 0 {
+		return uint64(value)
+	}
+	return 0
+}
+
+func getScopeIdOption(options map[string]interface{}) string {
+	for _, key := range scopeIdOptionKeys {
+		switch value := options[key].(type) {
+		case string:
+			if strings.TrimSpace(value) != "" {
+				return value
+			}
+		case float64:
+			return strconv.FormatFloat(value, 'f', -1, 64)
+		}
+	}
+	return ""
+}
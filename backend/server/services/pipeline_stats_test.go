@@ -0,0 +1,101 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	assert.Nil(t, percentile(nil, 0.5))
+	values := make([]float64, 0, 21)
+	for i := 20; i >= 0; i-- {
+		values = append(values, float64(i))
+	}
+	assert.Equal(t, 10.0, *percentile(values, 0.5))
+	assert.Equal(t, 19.0, *percentile(values, 0.95))
+	assert.Equal(t, 20.0, *percentile(values, 1))
+}
+
+func TestAggregatePipelineStats(t *testing.T) {
+	day1 := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	day2 := day1.AddDate(0, 0, 1)
+	tasks := []*models.Task{
+		{Model: common.Model{ID: 1}, Plugin: "github", Status: models.TASK_COMPLETED, SpentSeconds: 10, BeganAt: &day1, FinishedAt: &day1,
+			Options: map[string]interface{}{"connectionId": float64(1), "fullName": "apache/incubator-devlake"}},
+		{Model: common.Model{ID: 2}, Plugin: "github", Status: models.TASK_FAILED, SpentSeconds: 30, BeganAt: &day2, FinishedAt: &day2,
+			Options: map[string]interface{}{"connectionId": float64(1), "fullName": "apache/incubator-devlake"}},
+		{Model: common.Model{ID: 3}, Plugin: "jira", Status: models.TASK_COMPLETED, SpentSeconds: 5, BeganAt: &day1, FinishedAt: &day1,
+			Options: map[string]interface{}{"connectionId": float64(2), "boardId": float64(8)}},
+		{Model: common.Model{ID: 4}, Plugin: "jira", Status: models.TASK_RUNNING, BeganAt: &day2,
+			Options: map[string]interface{}{"connectionId": float64(2), "boardId": float64(8)}},
+	}
+	subtasks := []*models.Subtask{
+		{TaskID: 1, Name: "collectIssues", SpentSeconds: 8, FinishedAt: &day1,
+			SubtaskCounters: models.SubtaskCounters{RecordsWritten: 100, HttpRequests: 10, HttpRetries: 1}},
+		{TaskID: 2, Name: "collectIssues", SpentSeconds: 20, FinishedAt: &day2, IsFailed: true,
+			SubtaskCounters: models.SubtaskCounters{RecordsWritten: 50, HttpRequests: 5, HttpRateLimited: 2}},
+		{TaskID: 3, Name: "collectIssues", SpentSeconds: 4, FinishedAt: &day1,
+			SubtaskCounters: models.SubtaskCounters{RecordsWritten: 7, HttpRequests: 1}},
+		{TaskID: 4, Name: "collectIssues"},
+	}
+
+	stats := aggregatePipelineStats(PIPELINE_STATS_BY_PLUGIN, nil, tasks, subtasks)
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, "github", stats[0].Plugin)
+		assert.Equal(t, 2, stats[0].Runs)
+		assert.Equal(t, 0.5, stats[0].FailureRate)
+		assert.Equal(t, 10.0, *stats[0].P50Duration)
+		assert.Equal(t, 10.0, *stats[0].P95Duration)
+		assert.Equal(t, int64(150), stats[0].RecordsProcessed)
+		assert.Equal(t, int64(15), stats[0].HttpRequests)
+		assert.Equal(t, int64(1), stats[0].HttpRetries)
+		assert.Equal(t, int64(2), stats[0].HttpRateLimited)
+		assert.Equal(t, "jira", stats[1].Plugin)
+		assert.Equal(t, 1, stats[1].Runs)
+	}
+
+	stats = aggregatePipelineStats(PIPELINE_STATS_BY_SUBTASK, nil, tasks, subtasks)
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, "collectIssues", stats[0].Subtask)
+		assert.Equal(t, 2, stats[0].Runs)
+		assert.Equal(t, 1, stats[0].Failures)
+		assert.Equal(t, 8.0, *stats[0].P50Duration)
+	}
+
+	stats = aggregatePipelineStats(PIPELINE_STATS_BY_SCOPE, weekOf, tasks, subtasks)
+	if assert.Len(t, stats, 2) {
+		assert.Equal(t, uint64(1), stats[0].ConnectionId)
+		assert.Equal(t, "apache/incubator-devlake", stats[0].ScopeId)
+		assert.Equal(t, "8", stats[1].ScopeId)
+		assert.Equal(t, weekOf(day1), *stats[0].Period)
+	}
+
+	stats = aggregatePipelineStats(PIPELINE_STATS_BY_CONNECTION, dayOf, tasks, subtasks)
+	if assert.Len(t, stats, 4) {
+		assert.Equal(t, dayOf(day1), *stats[0].Period)
+		assert.Equal(t, dayOf(day2), *stats[1].Period)
+		assert.Equal(t, 1.0, stats[1].FailureRate)
+		assert.Equal(t, "", stats[0].ScopeId)
+	}
+}
@@ -27,6 +27,7 @@ import (
 	"github.com/apache/incubator-devlake/core/dal"
 	"github.com/apache/incubator-devlake/core/errors"
 	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
 	"github.com/apache/incubator-devlake/core/models/domainlayer/crossdomain"
 	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
 )
@@ -100,7 +101,7 @@ func GetProjects(query *ProjectQuery) ([]*models.ApiOutputProject, int64, errors
 }
 
 // CreateProject accepts a project instance and insert it to database
-func CreateProject(projectInput *models.ApiInputProject) (*models.ApiOutputProject, errors.Error) {
+func CreateProject(user *common.User, projectInput *models.ApiInputProject) (*models.ApiOutputProject, errors.Error) {
 	// verify input
 	if err := VerifyStruct(projectInput); err != nil {
 		return nil, err
@@ -174,7 +175,15 @@ func CreateProject(projectInput *models.ApiInputProject) (*models.ApiOutputProje
 		return nil, err
 	}
 
-	return makeProjectOutput(project, false)
+	projectOutput, err := makeProjectOutput(project, false)
+	if err != nil {
+		return nil, err
+	}
+	err = recordAudit(nil, user, models.AUDIT_ACTION_CREATE, models.AUDIT_TARGET_PROJECT, project.Name, nil, projectOutput)
+	if err != nil {
+		return nil, err
+	}
+	return projectOutput, nil
 }
 
 // GetProject returns a Project
@@ -193,7 +202,7 @@ func GetProject(name string) (*models.ApiOutputProject, errors.Error) {
 }
 
 // PatchProject FIXME ...
-func PatchProject(name string, body map[string]interface{}) (*models.ApiOutputProject, errors.Error) {
+func PatchProject(user *common.User, name string, body map[string]interface{}) (*models.ApiOutputProject, errors.Error) {
 	projectInput := &models.ApiInputProject{}
 
 	// load input
@@ -202,6 +211,12 @@ func PatchProject(name string, body map[string]interface{}) (*models.ApiOutputPr
 		return nil, err
 	}
 
+	// keep the original state for auditing
+	before, err := GetProject(name)
+	if err != nil {
+		return nil, err
+	}
+
 	// wrap all operation inside a transaction
 	tx := db.Begin()
 	defer func() {
@@ -275,6 +290,16 @@ func PatchProject(name string, body map[string]interface{}) (*models.ApiOutputPr
 		if err != nil {
 			return nil, err
 		}
+
+		// RoleBinding
+		err = tx.UpdateColumn(
+			&models.RoleBinding{},
+			"project_name", project.Name,
+			dal.Where("project_name = ?", name),
+		)
+		if err != nil {
+			return nil, err
+		}
 		if projectService != nil {
 			if err := projectService.RenameProject(tx, name, project.Name); err != nil {
 				return nil, err
@@ -319,7 +344,15 @@ func PatchProject(name string, body map[string]interface{}) (*models.ApiOutputPr
 	}
 
 	// all good, render output
-	return makeProjectOutput(project, false)
+	projectOutput, err := makeProjectOutput(project, false)
+	if err != nil {
+		return nil, err
+	}
+	err = recordAudit(nil, user, models.AUDIT_ACTION_UPDATE, models.AUDIT_TARGET_PROJECT, project.Name, before, projectOutput)
+	if err != nil {
+		return nil, err
+	}
+	return projectOutput, nil
 }
 
 func thereAreUnfinishedPipelinesUnderProject(projectName string) (bool, errors.Error) {
@@ -348,13 +381,13 @@ func thereAreUnfinishedPipelinesUnderBlueprint(blueprintID uint64) (bool, errors
 }
 
 // DeleteProject FIXME ...
-func DeleteProject(name string) errors.Error {
+func DeleteProject(user *common.User, name string) errors.Error {
 	// verify input
 	if name == "" {
 		return errors.BadInput.New("project name is missing")
 	}
 	// verify exists
-	_, err := getProjectByName(db, name)
+	before, err := GetProject(name)
 	if err != nil {
 		return err
 	}
@@ -399,6 +432,14 @@ func DeleteProject(name string) errors.Error {
 	if err != nil {
 		return errors.Default.Wrap(err, "error deleting project Issue metric")
 	}
+	err = tx.Delete(&models.RoleBinding{}, dal.Where("project_name = ?", name))
+	if err != nil {
+		return errors.Default.Wrap(err, "error deleting project role bindings")
+	}
+	err = recordAudit(tx, user, models.AUDIT_ACTION_DELETE, models.AUDIT_TARGET_PROJECT, name, before, nil)
+	if err != nil {
+		return err
+	}
 	return tx.Commit()
 }
 
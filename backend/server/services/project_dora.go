@@ -0,0 +1,373 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"sort"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models/domainlayer/devops"
+)
+
+const (
+	DORA_BENCHMARK_ELITE  = "elite"
+	DORA_BENCHMARK_HIGH   = "high"
+	DORA_BENCHMARK_MEDIUM = "medium"
+	DORA_BENCHMARK_LOW    = "low"
+)
+
+// DoraQuery describes the time window and environment the dora metrics are computed for
+type DoraQuery struct {
+	StartDate   *time.Time `form:"startDate" time_format:"2006-01-02"`
+	EndDate     *time.Time `form:"endDate" time_format:"2006-01-02"`
+	Environment string     `form:"environment"`
+}
+
+// DoraMetric is a single dora metric with its benchmark bucket of the 2023 DORA report,
+// Value and Benchmark are empty when there is no data to compute the metric from
+type DoraMetric struct {
+	Value     *float64 `json:"value"`
+	Unit      string   `json:"unit"`
+	Benchmark string   `json:"benchmark"`
+}
+
+// DoraWeek holds the dora numbers of a calendar week starting on Monday
+type DoraWeek struct {
+	Week                         time.Time `json:"week"`
+	DeploymentCount              int       `json:"deploymentCount"`
+	DeploymentDays               int       `json:"deploymentDays"`
+	MedianLeadTimeForChanges     *float64  `json:"medianLeadTimeForChanges"`
+	ChangeFailureRate            *float64  `json:"changeFailureRate"`
+	FailedDeploymentRecoveryTime *float64  `json:"failedDeploymentRecoveryTime"`
+}
+
+// ProjectDoraMetrics is the api output of the dora metrics of a project
+type ProjectDoraMetrics struct {
+	ProjectName                  string      `json:"projectName"`
+	Environment                  string      `json:"environment"`
+	StartDate                    time.Time   `json:"startDate"`
+	EndDate                      time.Time   `json:"endDate"`
+	DeploymentFrequency          DoraMetric  `json:"deploymentFrequency"`
+	LeadTimeForChanges           DoraMetric  `json:"leadTimeForChanges"`
+	ChangeFailureRate            DoraMetric  `json:"changeFailureRate"`
+	FailedDeploymentRecoveryTime DoraMetric  `json:"failedDeploymentRecoveryTime"`
+	Weekly                       []*DoraWeek `json:"weekly"`
+}
+
+type doraDeployment struct {
+	DeploymentId string
+	FinishedDate *time.Time
+}
+
+type doraPrCycleTime struct {
+	Id           string
+	PrCycleTime  *int64
+	FinishedDate *time.Time
+}
+
+type doraIncident struct {
+	Id             string
+	DeploymentId   string
+	ResolutionDate *time.Time
+}
+
+// GetProjectDoraMetrics computes the four dora metrics of the project from the data produced by the dora plugin,
+// the same way the DORA dashboard of grafana does
+func GetProjectDoraMetrics(projectName string, query *DoraQuery) (*ProjectDoraMetrics, errors.Error) {
+	if projectName == "" {
+		return nil, errors.BadInput.New("project name is missing")
+	}
+	_, err := getProjectByName(db, projectName)
+	if err != nil {
+		return nil, err
+	}
+	endDate := time.Now()
+	if query.EndDate != nil {
+		// the end date is inclusive
+		endDate = query.EndDate.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	startDate := endDate.AddDate(0, -6, 0)
+	if query.StartDate != nil {
+		startDate = *query.StartDate
+	}
+	if !startDate.Before(endDate) {
+		return nil, errors.BadInput.New("startDate must be earlier than endDate")
+	}
+	environment := query.Environment
+	if environment == "" {
+		environment = devops.PRODUCTION
+	}
+
+	// When deploying multiple commits in one pipeline, GitLab and BitBucket may generate more than one deployment.
+	// DevLake considers them as ONE deployment and use the last one's finished_date as the finished date.
+	var deployments []*doraDeployment
+	err = db.All(
+		&deployments,
+		dal.Select("cdc.cicd_deployment_id AS deployment_id, MAX(cdc.finished_date) AS finished_date"),
+		dal.From("cicd_deployment_commits cdc"),
+		dal.Join("JOIN project_mapping pm ON cdc.cicd_scope_id = pm.row_id AND pm.table = ?", "cicd_scopes"),
+		dal.Where(
+			"pm.project_name = ? AND cdc.result = ? AND cdc.environment = ?",
+			projectName, devops.RESULT_SUCCESS, environment,
+		),
+		dal.Groupby("cdc.cicd_deployment_id"),
+		dal.Having("MAX(cdc.finished_date) BETWEEN ? AND ?", startDate, endDate),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error getting deployments of the project")
+	}
+
+	var prCycleTimes []*doraPrCycleTime
+	err = db.All(
+		&prCycleTimes,
+		dal.Select("DISTINCT ppm.id, ppm.pr_cycle_time, cdc.finished_date"),
+		dal.From("project_pr_metrics ppm"),
+		dal.Join("JOIN pull_requests pr ON pr.id = ppm.id"),
+		dal.Join("JOIN cicd_deployment_commits cdc ON ppm.deployment_commit_id = cdc.id"),
+		dal.Where(
+			`ppm.project_name = ? AND pr.merged_date IS NOT NULL AND ppm.pr_cycle_time IS NOT NULL
+			AND cdc.finished_date BETWEEN ? AND ?`,
+			projectName, startDate, endDate,
+		),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error getting pr cycle time of the project")
+	}
+
+	var incidents []*doraIncident
+	err = db.All(
+		&incidents,
+		dal.Select("i.id, pim.deployment_id, i.resolution_date"),
+		dal.From("incidents i"),
+		dal.Join("JOIN project_incident_deployment_relationships pim ON i.id = pim.id"),
+		dal.Where("pim.project_name = ?", projectName),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error getting incidents of the project")
+	}
+
+	metrics := calculateDoraMetrics(startDate, endDate, deployments, prCycleTimes, incidents)
+	metrics.ProjectName = projectName
+	metrics.Environment = environment
+	return metrics, nil
+}
+
+func calculateDoraMetrics(
+	startDate, endDate time.Time,
+	deployments []*doraDeployment,
+	prCycleTimes []*doraPrCycleTime,
+	incidents []*doraIncident,
+) *ProjectDoraMetrics {
+	startDate = startDate.UTC()
+	endDate = endDate.UTC()
+	metrics := &ProjectDoraMetrics{
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+	weeks := make(map[time.Time]*DoraWeek)
+	for w := weekOf(startDate); !w.After(endDate); w = w.AddDate(0, 0, 7) {
+		week := &DoraWeek{Week: w}
+		weeks[w] = week
+		metrics.Weekly = append(metrics.Weekly, week)
+	}
+
+	// Metric 1: deployment frequency, based on the number of days with at least one deployment
+	deployedDays := make(map[time.Time]bool)
+	finishedDates := make(map[string]time.Time)
+	for _, d := range deployments {
+		if d.FinishedDate == nil {
+			continue
+		}
+		finishedDates[d.DeploymentId] = *d.FinishedDate
+		day := dayOf(*d.FinishedDate)
+		if week, ok := weeks[weekOf(day)]; ok {
+			week.DeploymentCount++
+			if !deployedDays[day] {
+				week.DeploymentDays++
+			}
+		}
+		deployedDays[day] = true
+	}
+	daysPerWeek := make([]float64, 0, len(metrics.Weekly))
+	for _, week := range metrics.Weekly {
+		daysPerWeek = append(daysPerWeek, float64(week.DeploymentDays))
+	}
+	daysPerMonth := make([]float64, 0)
+	for m := monthOf(startDate); !m.After(endDate); m = m.AddDate(0, 1, 0) {
+		days := 0
+		for day := range deployedDays {
+			if monthOf(day).Equal(m) {
+				days++
+			}
+		}
+		daysPerMonth = append(daysPerMonth, float64(days))
+	}
+	metrics.DeploymentFrequency = deploymentFrequency(len(finishedDates) > 0, median(daysPerWeek), median(daysPerMonth))
+
+	// Metric 2: lead time for changes, using the median pr cycle time of the deployed PRs
+	leadTimes := make([]float64, 0, len(prCycleTimes))
+	weeklyLeadTimes := make(map[time.Time][]float64)
+	for _, pr := range prCycleTimes {
+		if pr.PrCycleTime == nil || pr.FinishedDate == nil {
+			continue
+		}
+		hours := float64(*pr.PrCycleTime) / 60
+		leadTimes = append(leadTimes, hours)
+		w := weekOf(*pr.FinishedDate)
+		weeklyLeadTimes[w] = append(weeklyLeadTimes[w], hours)
+	}
+	metrics.LeadTimeForChanges = leadTimeForChanges(median(leadTimes))
+
+	// Metric 3: change failure rate, the percentage of deployments causing at least one incident
+	// Metric 4: failed deployment recovery time, from the deployment to the resolution of the incident it caused
+	failedDeployments := make(map[string]bool)
+	recoveryTimes := make([]float64, 0)
+	weeklyRecoveryTimes := make(map[time.Time][]float64)
+	for _, incident := range incidents {
+		finishedDate, ok := finishedDates[incident.DeploymentId]
+		if !ok {
+			continue
+		}
+		failedDeployments[incident.DeploymentId] = true
+		if incident.ResolutionDate == nil || incident.ResolutionDate.Before(startDate) || incident.ResolutionDate.After(endDate) {
+			continue
+		}
+		hours := incident.ResolutionDate.Sub(finishedDate).Hours()
+		recoveryTimes = append(recoveryTimes, hours)
+		w := weekOf(*incident.ResolutionDate)
+		weeklyRecoveryTimes[w] = append(weeklyRecoveryTimes[w], hours)
+	}
+	var changeFailureRate *float64
+	if len(finishedDates) > 0 {
+		rate := float64(len(failedDeployments)) / float64(len(finishedDates))
+		changeFailureRate = &rate
+	}
+	metrics.ChangeFailureRate = changeFailureRateMetric(changeFailureRate)
+	metrics.FailedDeploymentRecoveryTime = failedDeploymentRecoveryTime(median(recoveryTimes))
+
+	weeklyDeployments := make(map[time.Time]int)
+	weeklyFailedDeployments := make(map[time.Time]int)
+	for id, finishedDate := range finishedDates {
+		w := weekOf(finishedDate)
+		weeklyDeployments[w]++
+		if failedDeployments[id] {
+			weeklyFailedDeployments[w]++
+		}
+	}
+	for _, week := range metrics.Weekly {
+		week.MedianLeadTimeForChanges = median(weeklyLeadTimes[week.Week])
+		week.FailedDeploymentRecoveryTime = median(weeklyRecoveryTimes[week.Week])
+		if total := weeklyDeployments[week.Week]; total > 0 {
+			rate := float64(weeklyFailedDeployments[week.Week]) / float64(total)
+			week.ChangeFailureRate = &rate
+		}
+	}
+	return metrics
+}
+
+func deploymentFrequency(collected bool, daysPerWeek, daysPerMonth *float64) DoraMetric {
+	if !collected || daysPerWeek == nil || daysPerMonth == nil {
+		return DoraMetric{Unit: "days/week"}
+	}
+	switch {
+	case *daysPerWeek >= 5:
+		return DoraMetric{Value: daysPerWeek, Unit: "days/week", Benchmark: DORA_BENCHMARK_ELITE}
+	case *daysPerWeek >= 1:
+		return DoraMetric{Value: daysPerWeek, Unit: "days/week", Benchmark: DORA_BENCHMARK_HIGH}
+	case *daysPerMonth >= 1:
+		return DoraMetric{Value: daysPerMonth, Unit: "days/month", Benchmark: DORA_BENCHMARK_MEDIUM}
+	default:
+		return DoraMetric{Value: daysPerMonth, Unit: "days/month", Benchmark: DORA_BENCHMARK_LOW}
+	}
+}
+
+func leadTimeForChanges(hours *float64) DoraMetric {
+	return DoraMetric{Value: hours, Unit: "hours", Benchmark: benchmarkByThresholds(hours, 24, 7*24, 30*24)}
+}
+
+func failedDeploymentRecoveryTime(hours *float64) DoraMetric {
+	return DoraMetric{Value: hours, Unit: "hours", Benchmark: benchmarkByThresholds(hours, 1, 24, 7*24)}
+}
+
+func changeFailureRateMetric(rate *float64) DoraMetric {
+	metric := DoraMetric{Value: rate, Unit: "ratio"}
+	if rate == nil {
+		return metric
+	}
+	switch {
+	case *rate <= .05:
+		metric.Benchmark = DORA_BENCHMARK_ELITE
+	case *rate <= .10:
+		metric.Benchmark = DORA_BENCHMARK_HIGH
+	case *rate <= .15:
+		metric.Benchmark = DORA_BENCHMARK_MEDIUM
+	default:
+		metric.Benchmark = DORA_BENCHMARK_LOW
+	}
+	return metric
+}
+
+// benchmarkByThresholds returns the bucket of a metric which is better when smaller
+func benchmarkByThresholds(value *float64, elite, high, medium float64) string {
+	switch {
+	case value == nil:
+		return ""
+	case *value < elite:
+		return DORA_BENCHMARK_ELITE
+	case *value < high:
+		return DORA_BENCHMARK_HIGH
+	case *value < medium:
+		return DORA_BENCHMARK_MEDIUM
+	default:
+		return DORA_BENCHMARK_LOW
+	}
+}
+
+// median follows the definition used by the grafana dashboards: the largest value whose percent_rank <= 0.5
+func median(values []float64) *float64 {
+	return percentile(values, 0.5)
+}
+
+// percentile returns the largest value whose percent_rank <= p
+func percentile(values []float64, p float64) *float64 {
+	if len(values) == 0 {
+		return nil
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	// the epsilon keeps rank like 20 * 0.95 from being truncated to 18
+	m := sorted[int(float64(len(sorted)-1)*p+1e-9)]
+	return &m
+}
+
+func dayOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func weekOf(t time.Time) time.Time {
+	day := dayOf(t)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func monthOf(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,99 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMedian(t *testing.T) {
+	assert.Nil(t, median(nil))
+	assert.Equal(t, 3.0, *median([]float64{3}))
+	assert.Equal(t, 2.0, *median([]float64{4, 1, 2, 3}))
+	assert.Equal(t, 3.0, *median([]float64{5, 1, 3, 2, 4}))
+}
+
+func TestWeekOf(t *testing.T) {
+	monday := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, monday, weekOf(time.Date(2024, 1, 1, 13, 0, 0, 0, time.UTC)))
+	assert.Equal(t, monday, weekOf(time.Date(2024, 1, 7, 23, 0, 0, 0, time.UTC)))
+	assert.Equal(t, monday.AddDate(0, 0, 7), weekOf(time.Date(2024, 1, 8, 0, 0, 0, 0, time.UTC)))
+}
+
+func TestCalculateDoraMetrics(t *testing.T) {
+	date := func(day, hour int) *time.Time {
+		t := time.Date(2024, 1, day, hour, 0, 0, 0, time.UTC)
+		return &t
+	}
+	int64Ptr := func(v int64) *int64 { return &v }
+	startDate := *date(1, 0)
+	endDate := *date(14, 23)
+
+	deployments := []*doraDeployment{
+		{DeploymentId: "d1", FinishedDate: date(1, 10)},
+		{DeploymentId: "d2", FinishedDate: date(2, 10)},
+		{DeploymentId: "d3", FinishedDate: date(2, 12)},
+		{DeploymentId: "d4", FinishedDate: date(9, 10)},
+	}
+	prCycleTimes := []*doraPrCycleTime{
+		{Id: "pr1", PrCycleTime: int64Ptr(60), FinishedDate: date(1, 10)},
+		{Id: "pr2", PrCycleTime: int64Ptr(120), FinishedDate: date(2, 10)},
+		{Id: "pr3", PrCycleTime: int64Ptr(30 * 60), FinishedDate: date(9, 10)},
+	}
+	incidents := []*doraIncident{
+		{Id: "i1", DeploymentId: "d2", ResolutionDate: date(2, 14)},
+		{Id: "i2", DeploymentId: "unknown", ResolutionDate: date(3, 14)},
+	}
+
+	metrics := calculateDoraMetrics(startDate, endDate, deployments, prCycleTimes, incidents)
+
+	assert.Equal(t, 2, len(metrics.Weekly))
+	assert.Equal(t, 4, metrics.Weekly[0].DeploymentCount+metrics.Weekly[1].DeploymentCount)
+	assert.Equal(t, 2, metrics.Weekly[0].DeploymentDays)
+	assert.Equal(t, 1, metrics.Weekly[1].DeploymentDays)
+
+	assert.Equal(t, 1.0, *metrics.DeploymentFrequency.Value)
+	assert.Equal(t, DORA_BENCHMARK_HIGH, metrics.DeploymentFrequency.Benchmark)
+
+	assert.Equal(t, 2.0, *metrics.LeadTimeForChanges.Value)
+	assert.Equal(t, DORA_BENCHMARK_ELITE, metrics.LeadTimeForChanges.Benchmark)
+	assert.Equal(t, 1.0, *metrics.Weekly[0].MedianLeadTimeForChanges)
+	assert.Equal(t, 30.0, *metrics.Weekly[1].MedianLeadTimeForChanges)
+
+	assert.Equal(t, 0.25, *metrics.ChangeFailureRate.Value)
+	assert.Equal(t, DORA_BENCHMARK_LOW, metrics.ChangeFailureRate.Benchmark)
+	assert.Equal(t, 1.0/3, *metrics.Weekly[0].ChangeFailureRate)
+	assert.Equal(t, 0.0, *metrics.Weekly[1].ChangeFailureRate)
+
+	assert.Equal(t, 4.0, *metrics.FailedDeploymentRecoveryTime.Value)
+	assert.Equal(t, DORA_BENCHMARK_HIGH, metrics.FailedDeploymentRecoveryTime.Benchmark)
+}
+
+func TestCalculateDoraMetricsWithoutData(t *testing.T) {
+	startDate := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	metrics := calculateDoraMetrics(startDate, startDate.AddDate(0, 1, 0), nil, nil, nil)
+
+	assert.Nil(t, metrics.DeploymentFrequency.Value)
+	assert.Empty(t, metrics.DeploymentFrequency.Benchmark)
+	assert.Nil(t, metrics.LeadTimeForChanges.Value)
+	assert.Nil(t, metrics.ChangeFailureRate.Value)
+	assert.Empty(t, metrics.FailedDeploymentRecoveryTime.Benchmark)
+}
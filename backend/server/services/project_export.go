@@ -0,0 +1,836 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/helpers/audithelper"
+	"github.com/apache/incubator-devlake/helpers/pluginhelper/services"
+	"github.com/spf13/cast"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	PROJECT_DOCUMENT_FORMAT_JSON = "json"
+	PROJECT_DOCUMENT_FORMAT_YAML = "yaml"
+	// PROJECT_IMPORT_UNCHANGED is the action of the entities which are identical to the document
+	PROJECT_IMPORT_UNCHANGED = "unchanged"
+)
+
+// the key of the scope config name in the scopes of the document, which replaces the scope config id
+const documentScopeConfigKey = "scopeConfigName"
+
+// ProjectDocument is the self-contained configuration of a project, it is produced by ExportProject and applied by
+// ImportProject so that the configuration can be kept in git. The connections are identified by their names, the
+// scope configs by their names within the connections, and the secrets of the connections are stripped
+type ProjectDocument struct {
+	Project     models.BaseProject           `json:"project"`
+	Metrics     []*models.BaseMetric         `json:"metrics"`
+	Blueprint   *ProjectDocumentBlueprint    `json:"blueprint"`
+	Connections []*ProjectDocumentConnection `json:"connections"`
+}
+
+// ProjectDocumentBlueprint is the blueprint of the project, the scopes are those listed in the connections
+type ProjectDocumentBlueprint struct {
+	Name       string              `json:"name"`
+	Mode       string              `json:"mode"`
+	Plan       models.PipelinePlan `json:"plan,omitempty"`
+	Enable     bool                `json:"enable"`
+	CronConfig string              `json:"cronConfig"`
+	IsManual   bool                `json:"isManual"`
+	BeforePlan models.PipelinePlan `json:"beforePlan,omitempty"`
+	AfterPlan  models.PipelinePlan `json:"afterPlan,omitempty"`
+	Labels     []string            `json:"labels"`
+	Priority   int                 `json:"priority"`
+	PlanFormat string              `json:"planFormat"`
+	SyncPolicy models.SyncPolicy   `json:"syncPolicy"`
+}
+
+// ProjectDocumentConnection is a connection used by the blueprint along with its scopes and their scope configs
+type ProjectDocumentConnection struct {
+	PluginName   string                   `json:"pluginName"`
+	Connection   map[string]interface{}   `json:"connection"`
+	ScopeConfigs []map[string]interface{} `json:"scopeConfigs"`
+	Scopes       []map[string]interface{} `json:"scopes"`
+}
+
+// ProjectImportResult lists the changes made by ImportProject, or to be made if it is a dry run
+type ProjectImportResult struct {
+	DryRun  bool                   `json:"dryRun"`
+	Changes []*ProjectImportChange `json:"changes"`
+}
+
+// ProjectImportChange is the change of an entity, the diff is in the same form as the audit logs
+type ProjectImportChange struct {
+	TargetType   string          `json:"targetType"`
+	TargetPlugin string          `json:"targetPlugin,omitempty"`
+	Name         string          `json:"name"`
+	Action       string          `json:"action"`
+	Diff         json.RawMessage `json:"diff,omitempty"`
+}
+
+// ExportProject returns the configuration of the project as a document
+func ExportProject(name string) (*ProjectDocument, errors.Error) {
+	project, err := getProjectByName(db, name)
+	if err != nil {
+		return nil, err
+	}
+	doc := &ProjectDocument{
+		Project:     project.BaseProject,
+		Connections: make([]*ProjectDocumentConnection, 0),
+	}
+	doc.Metrics, err = getProjectMetrics(db, name)
+	if err != nil {
+		return nil, err
+	}
+	blueprint, err := GetBlueprintByProjectName(name)
+	if err != nil {
+		return nil, err
+	}
+	if blueprint == nil {
+		return doc, nil
+	}
+	doc.Blueprint = makeDocumentBlueprint(blueprint)
+	for _, bpConnection := range blueprint.Connections {
+		docConnection, err := exportConnection(bpConnection)
+		if err != nil {
+			return nil, err
+		}
+		doc.Connections = append(doc.Connections, docConnection)
+	}
+	sort.Slice(doc.Connections, func(i, j int) bool {
+		if doc.Connections[i].PluginName != doc.Connections[j].PluginName {
+			return doc.Connections[i].PluginName < doc.Connections[j].PluginName
+		}
+		return cast.ToString(doc.Connections[i].Connection["name"]) < cast.ToString(doc.Connections[j].Connection["name"])
+	})
+	return doc, nil
+}
+
+// MarshalProjectDocument encodes the document in the format of json or yaml
+func MarshalProjectDocument(doc *ProjectDocument, format string) ([]byte, errors.Error) {
+	data, err := json.Marshal(doc)
+	if err != nil {
+		return nil, errors.Convert(err)
+	}
+	switch format {
+	case "", PROJECT_DOCUMENT_FORMAT_JSON:
+		return data, nil
+	case PROJECT_DOCUMENT_FORMAT_YAML:
+		var content interface{}
+		err = json.Unmarshal(data, &content)
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+		data, err = yaml.Marshal(content)
+		if err != nil {
+			return nil, errors.Convert(err)
+		}
+		return data, nil
+	}
+	return nil, errors.BadInput.New(fmt.Sprintf("unsupported format %s", format))
+}
+
+// UnmarshalProjectDocument decodes the document in either json or yaml since the former is a subset of the latter
+func UnmarshalProjectDocument(data []byte) (*ProjectDocument, errors.Error) {
+	var content interface{}
+	err := yaml.Unmarshal(data, &content)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid project document")
+	}
+	data, err = json.Marshal(content)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid project document")
+	}
+	doc := &ProjectDocument{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	err = decoder.Decode(doc)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, "invalid project document")
+	}
+	return doc, nil
+}
+
+// ImportProject creates or updates the project and its blueprint, connections, scope configs and scopes according to
+// the document, the entities identical to the document are left untouched so that importing the same document again
+// changes nothing. Nothing is saved if dryRun is true
+func ImportProject(user *common.User, doc *ProjectDocument, dryRun bool) (*ProjectImportResult, errors.Error) {
+	if doc.Project.Name == "" {
+		return nil, errors.BadInput.New("project name is missing")
+	}
+	tx := db.Begin()
+	importer := &projectImporter{tx: tx, user: user, changes: make([]*ProjectImportChange, 0)}
+	blueprint, err := importer.apply(doc)
+	if err != nil || dryRun {
+		if e := tx.Rollback(); e != nil {
+			logger.Error(e, "ImportProject: failed to rollback")
+		}
+		if err != nil {
+			return nil, err
+		}
+		return &ProjectImportResult{DryRun: true, Changes: importer.changes}, nil
+	}
+	err = tx.Commit()
+	if err != nil {
+		return nil, err
+	}
+	// the plan is made by the plugins, which only see the connections and scopes once they are committed. the import
+	// is reverted if the plan can not be made, so that the document is never applied partially
+	if blueprint != nil && importer.changed() {
+		if _, err = saveBlueprint(blueprint); err != nil {
+			if e := importer.revert(); e != nil {
+				logger.Error(e, "ImportProject: failed to revert")
+			}
+			return nil, err
+		}
+	}
+	return &ProjectImportResult{Changes: importer.changes}, nil
+}
+
+type projectImporter struct {
+	tx      dal.Transaction
+	user    *common.User
+	changes []*ProjectImportChange
+	// reverts restore the saved entities as they were before the import
+	reverts []func(tx dal.Transaction) errors.Error
+}
+
+// revert restores the entities saved by the committed import in reverse order, in a new transaction
+func (i *projectImporter) revert() errors.Error {
+	tx := db.Begin()
+	for j := len(i.reverts) - 1; j >= 0; j-- {
+		err := i.reverts[j](tx)
+		if err != nil {
+			if e := tx.Rollback(); e != nil {
+				logger.Error(e, "ImportProject: failed to rollback the revert")
+			}
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// entitySnapshot is the state of an entity used for comparison, the sanitized one is reported
+type entitySnapshot struct {
+	raw       map[string]interface{}
+	sanitized map[string]interface{}
+}
+
+func (i *projectImporter) apply(doc *ProjectDocument) (*models.Blueprint, errors.Error) {
+	err := i.importProject(doc)
+	if err != nil {
+		return nil, err
+	}
+	bpConnections := make([]*models.BlueprintConnection, 0, len(doc.Connections))
+	for _, docConnection := range doc.Connections {
+		bpConnection, err := i.importConnection(docConnection)
+		if err != nil {
+			return nil, err
+		}
+		bpConnections = append(bpConnections, bpConnection)
+	}
+	if doc.Blueprint == nil {
+		return nil, nil
+	}
+	return i.importBlueprint(doc.Project.Name, doc.Blueprint, bpConnections)
+}
+
+// compare records the change between the snapshots, nil `before` means the entity is to be created
+func (i *projectImporter) compare(targetType, pluginName, name string, before, after *entitySnapshot) (*ProjectImportChange, errors.Error) {
+	change := &ProjectImportChange{
+		TargetType:   targetType,
+		TargetPlugin: pluginName,
+		Name:         name,
+		Action:       models.AUDIT_ACTION_CREATE,
+	}
+	if before != nil {
+		change.Action = PROJECT_IMPORT_UNCHANGED
+		if !reflect.DeepEqual(before.raw, after.raw) {
+			change.Action = models.AUDIT_ACTION_UPDATE
+		}
+	}
+	var err errors.Error
+	if change.Action != PROJECT_IMPORT_UNCHANGED {
+		var sanitizedBefore map[string]interface{}
+		if before != nil {
+			sanitizedBefore = before.sanitized
+		}
+		change.Diff, err = audithelper.Diff(sanitizedBefore, after.sanitized)
+		if err != nil {
+			return nil, err
+		}
+	}
+	i.changes = append(i.changes, change)
+	return change, nil
+}
+
+// record writes the audit log of the change with the sanitized snapshots, and keeps the function restoring the entity
+// in case the import is reverted, which is audited as well
+func (i *projectImporter) record(change *ProjectImportChange, targetId string, before, after *entitySnapshot, restore func(tx dal.Transaction) errors.Error) errors.Error {
+	var sanitizedBefore map[string]interface{}
+	if before != nil {
+		sanitizedBefore = before.sanitized
+	}
+	target := audithelper.AuditTarget{Type: change.TargetType, Plugin: change.TargetPlugin, Id: targetId}
+	i.reverts = append(i.reverts, func(tx dal.Transaction) errors.Error {
+		err := restore(tx)
+		if err != nil {
+			return err
+		}
+		action := models.AUDIT_ACTION_UPDATE
+		if before == nil {
+			action = models.AUDIT_ACTION_DELETE
+		}
+		return audithelper.NewAuditHelper(basicRes).Record(tx, i.user, action, target, after.sanitized, sanitizedBefore)
+	})
+	return audithelper.NewAuditHelper(basicRes).Record(i.tx, i.user, change.Action, target, sanitizedBefore, after.sanitized)
+}
+
+func (i *projectImporter) changed() bool {
+	for _, change := range i.changes {
+		if change.Action != PROJECT_IMPORT_UNCHANGED {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *projectImporter) importProject(doc *ProjectDocument) errors.Error {
+	name := doc.Project.Name
+	project, err := getProjectByName(i.tx, name)
+	if err != nil && !i.tx.IsErrorNotFound(err) {
+		return err
+	}
+	var before *entitySnapshot
+	var original models.Project
+	var originalMetrics []*models.BaseMetric
+	if project != nil {
+		original = *project
+		originalMetrics, err = getProjectMetrics(i.tx, name)
+		if err != nil {
+			return err
+		}
+		before, err = snapshotProject(&project.BaseProject, originalMetrics)
+		if err != nil {
+			return err
+		}
+	} else {
+		project = &models.Project{}
+	}
+	metrics := doc.Metrics
+	if metrics == nil {
+		metrics = make([]*models.BaseMetric, 0)
+	}
+	sort.Slice(metrics, func(a, b int) bool { return metrics[a].PluginName < metrics[b].PluginName })
+	after, err := snapshotProject(&doc.Project, metrics)
+	if err != nil {
+		return err
+	}
+	change, err := i.compare(models.AUDIT_TARGET_PROJECT, "", name, before, after)
+	if err != nil || change.Action == PROJECT_IMPORT_UNCHANGED {
+		return err
+	}
+	project.BaseProject = doc.Project
+	if before == nil {
+		err = i.tx.Create(project)
+	} else {
+		err = i.tx.Update(project)
+	}
+	if err != nil {
+		return errors.Default.Wrap(err, "error saving DB project")
+	}
+	err = refreshProjectMetrics(i.tx, &models.ApiInputProject{BaseProject: doc.Project, Metrics: metrics})
+	if err != nil {
+		return err
+	}
+	return i.record(change, name, before, after, func(tx dal.Transaction) errors.Error {
+		if before == nil {
+			err := tx.Delete(&models.ProjectMetricSetting{}, dal.Where("project_name = ?", name))
+			if err != nil {
+				return err
+			}
+			return tx.Delete(&models.Project{}, dal.Where("name = ?", name))
+		}
+		err := tx.Update(&original)
+		if err != nil {
+			return err
+		}
+		return refreshProjectMetrics(tx, &models.ApiInputProject{BaseProject: original.BaseProject, Metrics: originalMetrics})
+	})
+}
+
+// importConnection saves the connection along with its scope configs and scopes, and returns the blueprint connection
+// referencing them
+func (i *projectImporter) importConnection(docConnection *ProjectDocumentConnection) (*models.BlueprintConnection, errors.Error) {
+	pluginSrc, err := getPluginSource(docConnection.PluginName)
+	if err != nil {
+		return nil, err
+	}
+	name := cast.ToString(docConnection.Connection["name"])
+	if name == "" {
+		return nil, errors.BadInput.New(fmt.Sprintf("the name of the %s connection is missing", docConnection.PluginName))
+	}
+	connection, original := pluginSrc.Connection(), pluginSrc.Connection()
+	before, err := i.findEntity(connection, original, dal.Where("name = ?", name))
+	if err != nil {
+		return nil, err
+	}
+	fields := copyDocumentMap(docConnection.Connection)
+	if before != nil {
+		// secrets are stripped from the exported documents, keep the existing ones unless given explicitly
+		for field, value := range before.raw {
+			if !reflect.DeepEqual(value, before.sanitized[field]) && cast.ToString(fields[field]) == "" {
+				delete(fields, field)
+			}
+		}
+	} else {
+		connection = pluginSrc.Connection()
+	}
+	connectionId, err := i.importEntity(
+		models.AUDIT_TARGET_CONNECTION, docConnection.PluginName, name, connection, original, before, fields,
+		func() errors.Error {
+			target := models.UnwrapObject(connection)
+			if connectionValidator, ok := target.(plugin.ConnectionValidator); ok {
+				return connectionValidator.ValidateConnection(target, vld)
+			}
+			if e := vld.Struct(target); e != nil {
+				return errors.BadInput.Wrap(e, fmt.Sprintf("invalid %s connection %s", docConnection.PluginName, name))
+			}
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	scopeConfigIds := make(map[string]uint64)
+	for _, docScopeConfig := range docConnection.ScopeConfigs {
+		scopeConfigName := cast.ToString(docScopeConfig["name"])
+		if scopeConfigName == "" || pluginSrc.ScopeConfig() == nil {
+			return nil, errors.BadInput.New(fmt.Sprintf("invalid scope config of %s connection %s", docConnection.PluginName, name))
+		}
+		scopeConfig, original := pluginSrc.ScopeConfig(), pluginSrc.ScopeConfig()
+		before, err := i.findEntity(scopeConfig, original, dal.Where("connection_id = ? AND name = ?", connectionId, scopeConfigName))
+		if err != nil {
+			return nil, err
+		}
+		if before == nil {
+			scopeConfig = pluginSrc.ScopeConfig()
+		}
+		fields := copyDocumentMap(docScopeConfig)
+		fields["connectionId"] = connectionId
+		scopeConfigIds[scopeConfigName], err = i.importEntity(
+			models.AUDIT_TARGET_SCOPE_CONFIG, docConnection.PluginName, scopeConfigName, scopeConfig, original, before, fields, nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	bpConnection := &models.BlueprintConnection{
+		PluginName:   docConnection.PluginName,
+		ConnectionId: connectionId,
+		Scopes:       make([]*models.BlueprintScope, 0, len(docConnection.Scopes)),
+	}
+	for _, docScope := range docConnection.Scopes {
+		fields := copyDocumentMap(docScope)
+		fields["connectionId"] = connectionId
+		fields["scopeConfigId"] = uint64(0)
+		if scopeConfigName := cast.ToString(fields[documentScopeConfigKey]); scopeConfigName != "" {
+			scopeConfigId, ok := scopeConfigIds[scopeConfigName]
+			if !ok {
+				return nil, errors.BadInput.New(fmt.Sprintf("scope config %s of %s connection %s not found in the document", scopeConfigName, docConnection.PluginName, name))
+			}
+			fields["scopeConfigId"] = scopeConfigId
+		}
+		delete(fields, documentScopeConfigKey)
+		// decode into a new scope to find out its id
+		scope := pluginSrc.Scope()
+		err = decodeDocumentMap(fields, scope)
+		if err != nil {
+			return nil, err
+		}
+		scopeId := scope.ScopeId()
+		existing, err := findScope(i.tx, pluginSrc, connectionId, scopeId)
+		if err != nil {
+			return nil, err
+		}
+		var before *entitySnapshot
+		var original plugin.ToolLayerScope
+		if existing != nil {
+			scope = existing
+			before, err = snapshotEntity(scope)
+			if err != nil {
+				return nil, err
+			}
+			// load the scope again to keep it as it was, since the existing one is modified by the document
+			original, err = findScope(i.tx, pluginSrc, connectionId, scopeId)
+			if err != nil {
+				return nil, err
+			}
+		}
+		_, err = i.importEntity(models.AUDIT_TARGET_SCOPE, docConnection.PluginName, scopeId, scope, original, before, fields, nil)
+		if err != nil {
+			return nil, err
+		}
+		bpConnection.Scopes = append(bpConnection.Scopes, &models.BlueprintScope{ScopeId: scopeId})
+	}
+	return bpConnection, nil
+}
+
+// findEntity loads the entity and returns its snapshot, nil is returned if it does not exist. The entity is loaded
+// into original as well, which is kept as it was to revert the import
+func (i *projectImporter) findEntity(entity, original interface{}, clauses ...dal.Clause) (*entitySnapshot, errors.Error) {
+	for _, dst := range []interface{}{entity, original} {
+		err := i.tx.First(dst, clauses...)
+		if err != nil {
+			if i.tx.IsErrorNotFound(err) {
+				return nil, nil
+			}
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("error finding DB %T", dst))
+		}
+	}
+	return snapshotEntity(entity)
+}
+
+// importEntity applies the fields of the document to the entity and saves it if anything changed, the id of the
+// entity is returned. The original is the entity as it was before, which is restored if the import is reverted
+func (i *projectImporter) importEntity(
+	targetType, pluginName, name string,
+	entity interface{},
+	original interface{},
+	before *entitySnapshot,
+	fields map[string]interface{},
+	validate func() errors.Error,
+) (uint64, errors.Error) {
+	err := decodeDocumentMap(fields, entity)
+	if err != nil {
+		return 0, err
+	}
+	after, err := snapshotEntity(entity)
+	if err != nil {
+		return 0, err
+	}
+	change, err := i.compare(targetType, pluginName, name, before, after)
+	if err != nil {
+		return 0, err
+	}
+	if change.Action != PROJECT_IMPORT_UNCHANGED {
+		if validate != nil {
+			if err = validate(); err != nil {
+				return 0, err
+			}
+		}
+		err = i.tx.CreateOrUpdate(entity)
+		if err != nil {
+			return 0, errors.Default.Wrap(err, fmt.Sprintf("error saving %s %s", targetType, name))
+		}
+		after, err = snapshotEntity(entity)
+		if err != nil {
+			return 0, err
+		}
+		err = i.record(change, audithelper.TargetId(models.UnwrapObject(entity)), before, after, func(tx dal.Transaction) errors.Error {
+			if before == nil {
+				return tx.Delete(entity)
+			}
+			return tx.CreateOrUpdate(original)
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return cast.ToUint64(after.raw["id"]), nil
+}
+
+func (i *projectImporter) importBlueprint(projectName string, docBlueprint *ProjectDocumentBlueprint, bpConnections []*models.BlueprintConnection) (*models.Blueprint, errors.Error) {
+	bpManager := services.NewBlueprintManager(i.tx)
+	blueprint, err := bpManager.GetDbBlueprintByProjectName(projectName)
+	if err != nil && !i.tx.IsErrorNotFound(err) {
+		return nil, err
+	}
+	var before *entitySnapshot
+	var original *models.Blueprint
+	if blueprint != nil {
+		before, err = snapshotBlueprint(blueprint)
+		if err != nil {
+			return nil, err
+		}
+		original, err = bpManager.GetDbBlueprintByProjectName(projectName)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		blueprint = &models.Blueprint{ProjectName: projectName}
+	}
+	// keep the time as it is if only the time zone differs
+	timeAfter := docBlueprint.SyncPolicy.TimeAfter
+	if timeAfter != nil && blueprint.TimeAfter != nil && timeAfter.Equal(*blueprint.TimeAfter) {
+		timeAfter = blueprint.TimeAfter
+	}
+	blueprint.Name = docBlueprint.Name
+	blueprint.Mode = docBlueprint.Mode
+	blueprint.Enable = docBlueprint.Enable
+	blueprint.CronConfig = docBlueprint.CronConfig
+	blueprint.IsManual = docBlueprint.IsManual
+	blueprint.BeforePlan = docBlueprint.BeforePlan
+	blueprint.AfterPlan = docBlueprint.AfterPlan
+	blueprint.Labels = docBlueprint.Labels
+	blueprint.Priority = docBlueprint.Priority
+	blueprint.PlanFormat = docBlueprint.PlanFormat
+	blueprint.SyncPolicy = docBlueprint.SyncPolicy
+	blueprint.SyncPolicy.TimeAfter = timeAfter
+	blueprint.Connections = bpConnections
+	if blueprint.Mode == models.BLUEPRINT_MODE_ADVANCED {
+		blueprint.Plan = docBlueprint.Plan
+	}
+	err = validateBlueprint(blueprint)
+	if err != nil {
+		return nil, err
+	}
+	after, err := snapshotBlueprint(blueprint)
+	if err != nil {
+		return nil, err
+	}
+	change, err := i.compare(models.AUDIT_TARGET_BLUEPRINT, "", blueprint.Name, before, after)
+	if err != nil {
+		return nil, err
+	}
+	if change.Action != PROJECT_IMPORT_UNCHANGED {
+		err = bpManager.SaveDbBlueprint(blueprint)
+		if err != nil {
+			return nil, err
+		}
+		blueprintId := blueprint.ID
+		err = i.record(change, fmt.Sprintf("%d", blueprintId), before, after, func(tx dal.Transaction) errors.Error {
+			if before != nil {
+				return services.NewBlueprintManager(tx).SaveDbBlueprint(original)
+			}
+			for _, table := range []dal.Tabler{&models.BlueprintLabel{}, &models.BlueprintConnection{}, &models.BlueprintScope{}} {
+				err := tx.Delete(table, dal.Where("blueprint_id = ?", blueprintId))
+				if err != nil {
+					return err
+				}
+			}
+			return tx.Delete(&models.Blueprint{}, dal.Where("id = ?", blueprintId))
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return blueprint, nil
+}
+
+func exportConnection(bpConnection *models.BlueprintConnection) (*ProjectDocumentConnection, errors.Error) {
+	pluginSrc, err := getPluginSource(bpConnection.PluginName)
+	if err != nil {
+		return nil, err
+	}
+	connection := pluginSrc.Connection()
+	err = db.First(connection, dal.Where("id = ?", bpConnection.ConnectionId))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("error finding DB %s connection %d", bpConnection.PluginName, bpConnection.ConnectionId))
+	}
+	docConnection := &ProjectDocumentConnection{
+		PluginName:   bpConnection.PluginName,
+		ScopeConfigs: make([]map[string]interface{}, 0),
+		Scopes:       make([]map[string]interface{}, 0, len(bpConnection.Scopes)),
+	}
+	docConnection.Connection, err = makeDocumentMap(connection, "id")
+	if err != nil {
+		return nil, err
+	}
+	bpScopes := bpConnection.Scopes
+	sort.Slice(bpScopes, func(i, j int) bool { return bpScopes[i].ScopeId < bpScopes[j].ScopeId })
+	scopeConfigNames := make(map[uint64]string)
+	for _, bpScope := range bpScopes {
+		scope, err := findScope(db, pluginSrc, bpConnection.ConnectionId, bpScope.ScopeId)
+		if err != nil {
+			return nil, err
+		}
+		if scope == nil {
+			return nil, errors.NotFound.New(fmt.Sprintf("scope %s of %s connection %d not found", bpScope.ScopeId, bpConnection.PluginName, bpConnection.ConnectionId))
+		}
+		docScope, err := makeDocumentMap(scope, "connectionId", "scopeConfigId")
+		if err != nil {
+			return nil, err
+		}
+		if scopeConfigId := scope.ScopeScopeConfigId(); scopeConfigId != 0 && pluginSrc.ScopeConfig() != nil {
+			scopeConfigName, ok := scopeConfigNames[scopeConfigId]
+			if !ok {
+				scopeConfig := pluginSrc.ScopeConfig()
+				err = db.First(scopeConfig, dal.Where("id = ?", scopeConfigId))
+				if err != nil && !db.IsErrorNotFound(err) {
+					return nil, errors.Default.Wrap(err, "error finding DB scope config")
+				}
+				if err == nil {
+					docScopeConfig, err := makeDocumentMap(scopeConfig, "id", "connectionId")
+					if err != nil {
+						return nil, err
+					}
+					scopeConfigName = cast.ToString(docScopeConfig["name"])
+					docConnection.ScopeConfigs = append(docConnection.ScopeConfigs, docScopeConfig)
+				}
+				scopeConfigNames[scopeConfigId] = scopeConfigName
+			}
+			if scopeConfigName != "" {
+				docScope[documentScopeConfigKey] = scopeConfigName
+			}
+		}
+		docConnection.Scopes = append(docConnection.Scopes, docScope)
+	}
+	sort.Slice(docConnection.ScopeConfigs, func(i, j int) bool {
+		return cast.ToString(docConnection.ScopeConfigs[i]["name"]) < cast.ToString(docConnection.ScopeConfigs[j]["name"])
+	})
+	return docConnection, nil
+}
+
+func makeDocumentBlueprint(blueprint *models.Blueprint) *ProjectDocumentBlueprint {
+	docBlueprint := &ProjectDocumentBlueprint{
+		Name:       blueprint.Name,
+		Mode:       blueprint.Mode,
+		Enable:     blueprint.Enable,
+		CronConfig: blueprint.CronConfig,
+		IsManual:   blueprint.IsManual,
+		BeforePlan: blueprint.BeforePlan,
+		AfterPlan:  blueprint.AfterPlan,
+		Labels:     blueprint.Labels,
+		Priority:   blueprint.Priority,
+		PlanFormat: blueprint.PlanFormat,
+		SyncPolicy: blueprint.SyncPolicy,
+	}
+	if docBlueprint.Labels == nil {
+		docBlueprint.Labels = make([]string, 0)
+	}
+	// the plan of normal blueprints is generated from the scopes
+	if blueprint.Mode == models.BLUEPRINT_MODE_ADVANCED {
+		docBlueprint.Plan = blueprint.Plan
+	}
+	return docBlueprint
+}
+
+// makeDocumentMap converts the entity to its json fields, the fields changed by its `Sanitize()` method are secrets
+// and stripped along with the timestamps, the raw data origin and the `omitted` fields
+func makeDocumentMap(entity interface{}, omitted ...string) (map[string]interface{}, errors.Error) {
+	snapshot, err := snapshotEntity(entity)
+	if err != nil {
+		return nil, err
+	}
+	fields := snapshot.raw
+	for name, value := range fields {
+		if !reflect.DeepEqual(value, snapshot.sanitized[name]) || strings.HasPrefix(name, "_raw_data_") {
+			delete(fields, name)
+		}
+	}
+	for _, name := range omitted {
+		delete(fields, name)
+	}
+	return fields, nil
+}
+
+// decodeDocumentMap applies the fields to the entity, the fields absent from the map are kept as they are
+func decodeDocumentMap(fields map[string]interface{}, entity interface{}) errors.Error {
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return errors.Convert(err)
+	}
+	err = json.Unmarshal(data, models.UnwrapObject(entity))
+	if err != nil {
+		return errors.BadInput.Wrap(err, fmt.Sprintf("invalid fields for %T", entity))
+	}
+	return nil
+}
+
+func copyDocumentMap(fields map[string]interface{}) map[string]interface{} {
+	result := make(map[string]interface{}, len(fields))
+	for name, value := range fields {
+		result[name] = value
+	}
+	return result
+}
+
+func snapshotEntity(entity interface{}) (*entitySnapshot, errors.Error) {
+	raw, err := audithelper.Snapshot(models.UnwrapObject(entity))
+	if err != nil {
+		return nil, err
+	}
+	sanitized, err := audithelper.Snapshot(audithelper.Sanitize(models.UnwrapObject(entity)))
+	if err != nil {
+		return nil, err
+	}
+	return &entitySnapshot{raw: raw, sanitized: sanitized}, nil
+}
+
+func snapshotProject(project *models.BaseProject, metrics []*models.BaseMetric) (*entitySnapshot, errors.Error) {
+	return snapshotEntity(&struct {
+		models.BaseProject
+		Metrics []*models.BaseMetric `json:"metrics"`
+	}{*project, metrics})
+}
+
+// snapshotBlueprint includes the scopes of the blueprint, so that adding or removing scopes is a change as well
+func snapshotBlueprint(blueprint *models.Blueprint) (*entitySnapshot, errors.Error) {
+	scopes := make([]string, 0)
+	for _, connection := range blueprint.Connections {
+		for _, scope := range connection.Scopes {
+			scopes = append(scopes, fmt.Sprintf("%s:%d:%s", connection.PluginName, connection.ConnectionId, scope.ScopeId))
+		}
+	}
+	sort.Strings(scopes)
+	return snapshotEntity(&struct {
+		*ProjectDocumentBlueprint
+		Scopes []string `json:"scopes"`
+	}{makeDocumentBlueprint(blueprint), scopes})
+}
+
+func getProjectMetrics(tx dal.Dal, projectName string) ([]*models.BaseMetric, errors.Error) {
+	projectMetrics := make([]models.ProjectMetricSetting, 0)
+	err := tx.All(&projectMetrics, dal.Where("project_name = ?", projectName), dal.Orderby("plugin_name"))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "failed to load project metrics")
+	}
+	metrics := make([]*models.BaseMetric, len(projectMetrics))
+	for i, projectMetric := range projectMetrics {
+		metric := projectMetric.BaseMetric
+		metrics[i] = &metric
+	}
+	return metrics, nil
+}
+
+func getPluginSource(pluginName string) (plugin.PluginSource, errors.Error) {
+	pluginMeta, err := plugin.GetPlugin(pluginName)
+	if err != nil {
+		return nil, errors.BadInput.Wrap(err, fmt.Sprintf("plugin %s is not loaded", pluginName))
+	}
+	pluginSrc, ok := pluginMeta.(plugin.PluginSource)
+	if !ok || pluginSrc.Scope() == nil {
+		return nil, errors.BadInput.New(fmt.Sprintf("plugin %s doesn't support data connections", pluginName))
+	}
+	return pluginSrc, nil
+}
@@ -0,0 +1,111 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type testExportConnection struct {
+	ID        uint64    `json:"id"`
+	Name      string    `json:"name"`
+	Endpoint  string    `json:"endpoint"`
+	Token     string    `json:"token"`
+	Secret    string    `json:"secret"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func (c testExportConnection) Sanitize() testExportConnection {
+	if c.Token != "" {
+		c.Token = "****"
+	}
+	c.Secret = ""
+	return c
+}
+
+func TestMakeDocumentMap(t *testing.T) {
+	fields, err := makeDocumentMap(&testExportConnection{
+		ID:        1,
+		Name:      "github",
+		Endpoint:  "https://api.github.com/",
+		Token:     "ghp_xxx",
+		Secret:    "",
+		UpdatedAt: time.Now(),
+	}, "id")
+	assert.Nil(t, err)
+	// the token is masked by Sanitize() thus stripped, while the empty secret is left unchanged
+	assert.Equal(t, map[string]interface{}{
+		"name":     "github",
+		"endpoint": "https://api.github.com/",
+		"secret":   "",
+	}, fields)
+
+	connection := &testExportConnection{ID: 1, Name: "github", Token: "ghp_xxx"}
+	err = decodeDocumentMap(map[string]interface{}{"endpoint": "https://github.example.com/api/v3/"}, connection)
+	assert.Nil(t, err)
+	assert.Equal(t, "https://github.example.com/api/v3/", connection.Endpoint)
+	assert.Equal(t, "ghp_xxx", connection.Token)
+	assert.Equal(t, uint64(1), connection.ID)
+}
+
+func TestProjectDocumentRoundTrip(t *testing.T) {
+	timeAfter := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	doc := &ProjectDocument{
+		Project: models.BaseProject{Name: "demo", Description: "yes"},
+		Metrics: []*models.BaseMetric{
+			{PluginName: "dora", PluginOption: json.RawMessage(`{"deploymentPattern":"deploy"}`), Enable: true},
+		},
+		Blueprint: &ProjectDocumentBlueprint{
+			Name:       "demo-Blueprint",
+			Mode:       models.BLUEPRINT_MODE_NORMAL,
+			CronConfig: "0 0 * * *",
+			Labels:     []string{"team-a"},
+			PlanFormat: models.PLAN_FORMAT_DAG,
+			SyncPolicy: models.SyncPolicy{TimeAfter: &timeAfter},
+		},
+		Connections: []*ProjectDocumentConnection{
+			{
+				PluginName:   "github",
+				Connection:   map[string]interface{}{"name": "github", "rateLimitPerHour": float64(4500)},
+				ScopeConfigs: []map[string]interface{}{{"name": "default", "prType": "type/(.*)$"}},
+				Scopes:       []map[string]interface{}{{"githubId": float64(1), "name": "apache/devlake", documentScopeConfigKey: "default"}},
+			},
+		},
+	}
+	for _, format := range []string{PROJECT_DOCUMENT_FORMAT_JSON, PROJECT_DOCUMENT_FORMAT_YAML} {
+		data, err := MarshalProjectDocument(doc, format)
+		assert.Nil(t, err)
+		decoded, err := UnmarshalProjectDocument(data)
+		assert.Nil(t, err, format)
+		assert.Equal(t, "demo", decoded.Project.Name)
+		assert.JSONEq(t, `{"deploymentPattern":"deploy"}`, string(decoded.Metrics[0].PluginOption))
+		assert.True(t, timeAfter.Equal(*decoded.Blueprint.SyncPolicy.TimeAfter), format)
+		assert.Equal(t, doc.Connections[0].Scopes, decoded.Connections[0].Scopes)
+		assert.Equal(t, doc.Connections[0].Connection, decoded.Connections[0].Connection)
+	}
+
+	_, err := MarshalProjectDocument(doc, "xml")
+	assert.NotNil(t, err)
+	_, err = UnmarshalProjectDocument([]byte("project:\n  name: demo\nunknown: 1\n"))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,542 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/core/plugin"
+	helper "github.com/apache/incubator-devlake/helpers/pluginhelper/api"
+	"github.com/robfig/cron/v3"
+)
+
+// rawDataPurgeBatchSize limits the rows deleted by a statement to keep the raw tables available to the collectors
+const rawDataPurgeBatchSize = 1000
+
+var rawDataPurgeCron *cron.Cron
+
+// RawDataRetentionPolicyQuery used to query raw data retention policies as the api input
+type RawDataRetentionPolicyQuery struct {
+	Pagination
+	Plugin string `form:"plugin"`
+}
+
+// RawDataPurgeResult tells how many raw rows of a scope were purged, or would be purged in the dry-run mode
+type RawDataPurgeResult struct {
+	PolicyId       uint64 `json:"policyId"`
+	Table          string `json:"table,omitempty"`
+	Params         string `json:"params,omitempty"`
+	DeletedRows    int64  `json:"deletedRows"`
+	ReclaimedBytes int64  `json:"reclaimedBytes"`
+	// Skipped explains why the raw rows were left untouched
+	Skipped string `json:"skipped,omitempty"`
+}
+
+// RawDataPurgeSummary sums up the purge of the raw tables, ReclaimedBytes is the size of the deleted payloads, the
+// database might need `OPTIMIZE TABLE` or `VACUUM` to return the space to the file system
+type RawDataPurgeSummary struct {
+	DryRun         bool                  `json:"dryRun"`
+	DeletedRows    int64                 `json:"deletedRows"`
+	ReclaimedBytes int64                 `json:"reclaimedBytes"`
+	Results        []*RawDataPurgeResult `json:"results"`
+}
+
+// GetRawDataRetentionPolicies returns a paginated list of raw data retention policies based on `query`
+func GetRawDataRetentionPolicies(query *RawDataRetentionPolicyQuery) ([]*models.RawDataRetentionPolicy, int64, errors.Error) {
+	clauses := []dal.Clause{
+		dal.From(&models.RawDataRetentionPolicy{}),
+	}
+	if query.Plugin != "" {
+		clauses = append(clauses, dal.Where("plugin = ?", query.Plugin))
+	}
+	count, err := db.Count(clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error getting DB count of raw data retention policies")
+	}
+	clauses = append(clauses,
+		dal.Orderby("id"),
+		dal.Offset(query.GetSkip()),
+		dal.Limit(query.GetPageSize()),
+	)
+	policies := make([]*models.RawDataRetentionPolicy, 0)
+	err = db.All(&policies, clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error finding DB raw data retention policies")
+	}
+	return policies, count, nil
+}
+
+// GetRawDataRetentionPolicy returns the raw data retention policy
+func GetRawDataRetentionPolicy(id uint64) (*models.RawDataRetentionPolicy, errors.Error) {
+	if id == 0 {
+		return nil, errors.BadInput.New("raw data retention policy's id is missing")
+	}
+	policy := &models.RawDataRetentionPolicy{}
+	err := db.First(policy, dal.Where("id = ?", id))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return nil, errors.NotFound.New(fmt.Sprintf("raw data retention policy %d not found", id))
+		}
+		return nil, errors.Default.Wrap(err, "error finding DB raw data retention policy")
+	}
+	return policy, nil
+}
+
+// CreateRawDataRetentionPolicy accepts a raw data retention policy and insert it to database
+func CreateRawDataRetentionPolicy(user *common.User, policy *models.RawDataRetentionPolicy) (*models.RawDataRetentionPolicy, errors.Error) {
+	policy.ID = 0
+	policy.LastPurgedAt = nil
+	policy.LastDeletedRows = 0
+	policy.LastReclaimedBytes = 0
+	if user != nil {
+		policy.Creator = common.Creator{Creator: user.Name, CreatorEmail: user.Email}
+	}
+	err := validateRawDataRetentionPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Create(policy)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error creating DB raw data retention policy")
+	}
+	return policy, nil
+}
+
+// PatchRawDataRetentionPolicy updates the raw data retention policy, the result of the latest purge is kept
+func PatchRawDataRetentionPolicy(id uint64, body map[string]interface{}) (*models.RawDataRetentionPolicy, errors.Error) {
+	policy, err := GetRawDataRetentionPolicy(id)
+	if err != nil {
+		return nil, err
+	}
+	lastPurgedAt, lastDeletedRows, lastReclaimedBytes := policy.LastPurgedAt, policy.LastDeletedRows, policy.LastReclaimedBytes
+	err = helper.DecodeMapStruct(body, policy, true)
+	if err != nil {
+		return nil, err
+	}
+	policy.ID = id
+	policy.LastPurgedAt, policy.LastDeletedRows, policy.LastReclaimedBytes = lastPurgedAt, lastDeletedRows, lastReclaimedBytes
+	err = validateRawDataRetentionPolicy(policy)
+	if err != nil {
+		return nil, err
+	}
+	err = db.Update(policy)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error updating DB raw data retention policy")
+	}
+	return policy, nil
+}
+
+// DeleteRawDataRetentionPolicy deletes the raw data retention policy
+func DeleteRawDataRetentionPolicy(id uint64) errors.Error {
+	policy, err := GetRawDataRetentionPolicy(id)
+	if err != nil {
+		return err
+	}
+	return db.Delete(policy)
+}
+
+func validateRawDataRetentionPolicy(policy *models.RawDataRetentionPolicy) errors.Error {
+	if err := VerifyStruct(policy); err != nil {
+		return err
+	}
+	if _, err := plugin.GetPlugin(policy.Plugin); err != nil {
+		return errors.BadInput.Wrap(err, fmt.Sprintf("unknown plugin %s", policy.Plugin))
+	}
+	if policy.Mode == models.RAW_DATA_RETENTION_KEEP_DAYS && policy.Days <= 0 {
+		return errors.BadInput.New("days is required by the KEEP_DAYS mode")
+	}
+	return nil
+}
+
+// PurgeRawData purges the raw tables by the policy, or by all the enabled policies if policyId is 0. Nothing is
+// deleted in the dry-run mode, the summary tells what would be purged instead.
+func PurgeRawData(policyId uint64, dryRun bool) (*RawDataPurgeSummary, errors.Error) {
+	var policies []*models.RawDataRetentionPolicy
+	if policyId != 0 {
+		policy, err := GetRawDataRetentionPolicy(policyId)
+		if err != nil {
+			return nil, err
+		}
+		policies = append(policies, policy)
+	} else {
+		err := db.All(&policies, dal.Where("enable = ?", true), dal.Orderby("id"))
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "error finding DB raw data retention policies")
+		}
+	}
+	summary := &RawDataPurgeSummary{
+		DryRun:  dryRun,
+		Results: make([]*RawDataPurgeResult, 0),
+	}
+	for _, policy := range policies {
+		now := time.Now()
+		results, err := purgeRawDataByPolicy(policy, dryRun, now)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("error purging raw data by policy %d", policy.ID))
+		}
+		var deletedRows, reclaimedBytes int64
+		for _, result := range results {
+			deletedRows += result.DeletedRows
+			reclaimedBytes += result.ReclaimedBytes
+		}
+		summary.DeletedRows += deletedRows
+		summary.ReclaimedBytes += reclaimedBytes
+		summary.Results = append(summary.Results, results...)
+		if dryRun {
+			continue
+		}
+		err = db.UpdateColumns(policy, []dal.DalSet{
+			{ColumnName: "last_purged_at", Value: now},
+			{ColumnName: "last_deleted_rows", Value: deletedRows},
+			{ColumnName: "last_reclaimed_bytes", Value: reclaimedBytes},
+		})
+		if err != nil {
+			return nil, errors.Default.Wrap(err, "error updating DB raw data retention policy")
+		}
+	}
+	return summary, nil
+}
+
+func purgeRawDataByPolicy(policy *models.RawDataRetentionPolicy, dryRun bool, now time.Time) ([]*RawDataPurgeResult, errors.Error) {
+	// the extractors of a running task might be reading the rows to be purged
+	running, err := db.Count(
+		dal.From(&models.Task{}),
+		dal.Where("plugin = ? AND status = ?", policy.Plugin, models.TASK_RUNNING),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error counting running tasks")
+	}
+	if running > 0 {
+		return []*RawDataPurgeResult{{PolicyId: policy.ID, Skipped: "tasks of the plugin are running"}}, nil
+	}
+	tables, err := db.AllTables()
+	if err != nil {
+		return nil, err
+	}
+	pluginNames := make([]string, 0)
+	for name := range plugin.AllPlugins() {
+		pluginNames = append(pluginNames, name)
+	}
+	results := make([]*RawDataPurgeResult, 0)
+	for _, table := range rawTablesOfPlugin(tables, policy.Plugin, pluginNames) {
+		var paramsList []string
+		err = db.Pluck("DISTINCT params", &paramsList, dal.From(table))
+		if err != nil {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("error getting params of %s", table))
+		}
+		sort.Strings(paramsList)
+		for _, params := range paramsList {
+			if policy.ConnectionId != 0 && getParamsConnectionId(params) != policy.ConnectionId {
+				continue
+			}
+			result, err := purgeRawDataOfScope(policy, table, params, dryRun, now)
+			if err != nil {
+				return nil, err
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+// purgeRawDataOfScope purges the raw rows of the same RawDataParams, the rows created after the last run of the
+// stateful extractors are kept for their next incremental run. The stateful extractors are told about the purge by
+// the watermark of their states, so they wait for a full collection instead of re-extracting the remaining rows.
+func purgeRawDataOfScope(policy *models.RawDataRetentionPolicy, table, params string, dryRun bool, now time.Time) (*RawDataPurgeResult, errors.Error) {
+	result := &RawDataPurgeResult{PolicyId: policy.ID, Table: table, Params: params}
+	extractedBefore, err := getExtractedBefore(policy.Plugin, table, params)
+	if err != nil {
+		return nil, err
+	}
+	clauses := []dal.Clause{
+		dal.From(table),
+		dal.Where("params = ?", params),
+	}
+	if extractedBefore != nil {
+		clauses = append(clauses, dal.Where("created_at < ?", extractedBefore))
+	} else if !policy.IncludeFullExtraction {
+		result.Skipped = "the raw rows are extracted all over again by each run"
+		return result, nil
+	} else if policy.Mode == models.RAW_DATA_RETENTION_AFTER_CONVERSION {
+		result.Skipped = "the raw rows are not extracted by a stateful extractor"
+		return result, nil
+	}
+	marked := false
+	purge := func(ids []uint64) errors.Error {
+		if !dryRun && !marked && extractedBefore != nil {
+			err := markRawDataPurged(policy.Plugin, table, params, *extractedBefore)
+			if err != nil {
+				return err
+			}
+			marked = true
+		}
+		reclaimedBytes, err := purgeRawRowsById(table, ids, dryRun)
+		if err != nil {
+			return err
+		}
+		result.DeletedRows += int64(len(ids))
+		result.ReclaimedBytes += reclaimedBytes
+		return nil
+	}
+	switch policy.Mode {
+	case models.RAW_DATA_RETENTION_KEEP_DAYS:
+		clauses = append(clauses, dal.Where("created_at < ?", now.AddDate(0, 0, -policy.Days)))
+	case models.RAW_DATA_RETENTION_AFTER_CONVERSION:
+		convertedBefore, err := getConvertedBefore(policy.Plugin, table, params)
+		if err != nil {
+			return nil, err
+		}
+		if convertedBefore == nil {
+			result.Skipped = "the raw rows are not converted by a stateful convertor"
+			return result, nil
+		}
+		clauses = append(clauses, dal.Where("created_at < ?", convertedBefore))
+	case models.RAW_DATA_RETENTION_KEEP_LATEST:
+		ids, err := findSupersededRawRows(table, params, extractedBefore)
+		if err != nil {
+			return nil, err
+		}
+		for len(ids) > 0 {
+			batch := ids
+			if len(batch) > rawDataPurgeBatchSize {
+				batch = batch[:rawDataPurgeBatchSize]
+			}
+			ids = ids[len(batch):]
+			err = purge(batch)
+			if err != nil {
+				return nil, err
+			}
+		}
+		return result, nil
+	}
+	if dryRun {
+		result.DeletedRows, err = db.Count(clauses...)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("error counting rows of %s", table))
+		}
+		result.ReclaimedBytes, err = measureRawRows(clauses...)
+		if err != nil {
+			return nil, err
+		}
+		return result, nil
+	}
+	for {
+		var ids []uint64
+		err = db.Pluck("id", &ids, append(clauses, dal.Orderby("id"), dal.Limit(rawDataPurgeBatchSize))...)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("error getting ids of %s", table))
+		}
+		if len(ids) == 0 {
+			return result, nil
+		}
+		err = purge(ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+// getExtractedBefore returns the time before which the raw rows were extracted by all the stateful extractors of the
+// scope reading the table, or nil if the table is not read by a stateful extractor
+func getExtractedBefore(pluginName, table, params string) (*time.Time, errors.Error) {
+	return getStartedBefore(pluginName, "extracted_table", table, params)
+}
+
+// getConvertedBefore returns the time before which the records extracted from the raw rows were converted by all the
+// stateful convertors of the scope, or nil if they are not converted by a stateful convertor
+func getConvertedBefore(pluginName, table, params string) (*time.Time, errors.Error) {
+	return getStartedBefore(pluginName, "converted_table", table, params)
+}
+
+func getStartedBefore(pluginName, tableColumn, table, params string) (*time.Time, errors.Error) {
+	var states []*models.SubtaskState
+	err := db.All(&states, dal.Where(fmt.Sprintf("plugin = ? AND params = ? AND %s = ?", tableColumn), pluginName, params, table))
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error finding DB subtask states")
+	}
+	var startedBefore *time.Time
+	for _, state := range states {
+		if state.PrevStartedAt == nil {
+			continue
+		}
+		if startedBefore == nil || state.PrevStartedAt.Before(*startedBefore) {
+			startedBefore = state.PrevStartedAt
+		}
+	}
+	return startedBefore, nil
+}
+
+// markRawDataPurged records the purge watermark to the states of the stateful extractors reading the table, it is
+// written before the rows get deleted so an interrupted purge is never missed by the extractors
+func markRawDataPurged(pluginName, table, params string, purgedBefore time.Time) errors.Error {
+	err := db.UpdateColumn(
+		&models.SubtaskState{},
+		"purged_before", purgedBefore,
+		dal.Where(
+			"plugin = ? AND params = ? AND extracted_table = ? AND (purged_before IS NULL OR purged_before < ?)",
+			pluginName, params, table, purgedBefore,
+		),
+	)
+	if err != nil {
+		return errors.Default.Wrap(err, "error marking DB subtask states purged")
+	}
+	return nil
+}
+
+// findSupersededRawRows returns the ids of the raw rows whose input was requested again later, created before
+// extractedBefore if it is not nil
+func findSupersededRawRows(table, params string, extractedBefore *time.Time) ([]uint64, errors.Error) {
+	cursor, err := db.Cursor(
+		dal.Select("id, input, created_at"),
+		dal.From(table),
+		dal.Where("params = ? AND input IS NOT NULL", params),
+		dal.Orderby("id DESC"),
+	)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, fmt.Sprintf("error reading inputs of %s", table))
+	}
+	defer cursor.Close()
+	seen := make(map[string]bool)
+	ids := make([]uint64, 0)
+	for cursor.Next() {
+		row := &helper.RawData{}
+		err = db.Fetch(cursor, row)
+		if err != nil {
+			return nil, errors.Default.Wrap(err, fmt.Sprintf("error fetching input of %s", table))
+		}
+		input := string(row.Input)
+		if input == "" || input == "null" {
+			continue
+		}
+		if seen[input] && (extractedBefore == nil || row.CreatedAt.Before(*extractedBefore)) {
+			ids = append(ids, row.ID)
+		}
+		seen[input] = true
+	}
+	return ids, nil
+}
+
+// purgeRawRowsById deletes the raw rows unless in the dry-run mode and returns the size of their payloads
+func purgeRawRowsById(table string, ids []uint64, dryRun bool) (int64, errors.Error) {
+	reclaimedBytes, err := measureRawRows(dal.From(table), dal.Where("id IN ?", ids))
+	if err != nil || dryRun {
+		return reclaimedBytes, err
+	}
+	err = db.Delete(&helper.RawData{}, dal.From(table), dal.Where("id IN ?", ids))
+	if err != nil {
+		return 0, errors.Default.Wrap(err, fmt.Sprintf("error deleting rows of %s", table))
+	}
+	return reclaimedBytes, nil
+}
+
+func measureRawRows(clauses ...dal.Clause) (int64, errors.Error) {
+	input := "CAST(input AS CHAR)"
+	if db.Dialect() == "postgres" {
+		input = "CAST(input AS TEXT)"
+	}
+	var sizes []int64
+	err := db.Pluck(
+		fmt.Sprintf(
+			"COALESCE(SUM(COALESCE(OCTET_LENGTH(data), 0) + COALESCE(OCTET_LENGTH(url), 0) + COALESCE(OCTET_LENGTH(params), 0) + COALESCE(OCTET_LENGTH(%s), 0)), 0)",
+			input,
+		),
+		&sizes,
+		clauses...,
+	)
+	if err != nil {
+		return 0, errors.Default.Wrap(err, "error measuring raw rows")
+	}
+	if len(sizes) == 0 {
+		return 0, nil
+	}
+	return sizes[0], nil
+}
+
+// rawTablesOfPlugin returns the `_raw_<plugin>_*` tables, excluding the ones of the other plugins sharing the prefix,
+// i.e. `_raw_github_graphql_*` are not the raw tables of github
+func rawTablesOfPlugin(tables []string, pluginName string, pluginNames []string) []string {
+	prefix := fmt.Sprintf("_raw_%s_", pluginName)
+	excluded := make([]string, 0)
+	for _, name := range pluginNames {
+		otherPrefix := fmt.Sprintf("_raw_%s_", name)
+		if name != pluginName && strings.HasPrefix(otherPrefix, prefix) {
+			excluded = append(excluded, otherPrefix)
+		}
+	}
+	rawTables := make([]string, 0)
+	for _, table := range tables {
+		if !strings.HasPrefix(table, prefix) {
+			continue
+		}
+		isExcluded := false
+		for _, otherPrefix := range excluded {
+			if strings.HasPrefix(table, otherPrefix) {
+				isExcluded = true
+				break
+			}
+		}
+		if !isExcluded {
+			rawTables = append(rawTables, table)
+		}
+	}
+	sort.Strings(rawTables)
+	return rawTables
+}
+
+// getParamsConnectionId returns the connection id of the RawDataParams, or 0 if it is not found
+func getParamsConnectionId(params string) uint64 {
+	var values map[string]interface{}
+	if json.Unmarshal([]byte(params), &values) != nil {
+		return 0
+	}
+	for key, value := range values {
+		if id, ok := value.(float64); ok && strings.EqualFold(key, "connectionId") {
+			return uint64(id)
+		}
+	}
+	return 0
+}
+
+// scheduleRawDataPurge runs the enabled raw data retention policies by RAW_DATA_PURGE_CRON
+func scheduleRawDataPurge() {
+	cronConfig := strings.TrimSpace(cfg.GetString("RAW_DATA_PURGE_CRON"))
+	if cronConfig == "" {
+		return
+	}
+	rawDataPurgeCron = cron.New(cron.WithLocation(time.UTC))
+	_, err := rawDataPurgeCron.AddFunc(cronConfig, func() {
+		summary, err := PurgeRawData(0, false)
+		if err != nil {
+			logger.Error(err, "failed to purge raw data")
+			return
+		}
+		logger.Info("purged %d raw rows, %d bytes reclaimed", summary.DeletedRows, summary.ReclaimedBytes)
+	})
+	if err != nil {
+		panic(errors.BadInput.Wrap(err, "invalid RAW_DATA_PURGE_CRON"))
+	}
+	rawDataPurgeCron.Start()
+}
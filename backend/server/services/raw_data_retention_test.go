@@ -0,0 +1,128 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/models"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRawTablesOfPlugin(t *testing.T) {
+	tables := []string{
+		"_raw_github_api_issues",
+		"_raw_github_graphql_prs",
+		"_raw_github_api_comments",
+		"_raw_gitlab_api_merge_requests",
+		"_devlake_subtask_states",
+		"github_issues",
+	}
+	pluginNames := []string{"github", "github_graphql", "gitlab"}
+	assert.Equal(t, []string{"_raw_github_api_comments", "_raw_github_api_issues"}, rawTablesOfPlugin(tables, "github", pluginNames))
+	assert.Equal(t, []string{"_raw_github_graphql_prs"}, rawTablesOfPlugin(tables, "github_graphql", pluginNames))
+	assert.Empty(t, rawTablesOfPlugin(tables, "jira", pluginNames))
+}
+
+func TestGetParamsConnectionId(t *testing.T) {
+	assert.Equal(t, uint64(3), getParamsConnectionId(`{"ConnectionId":3,"Name":"apache/incubator-devlake"}`))
+	assert.Equal(t, uint64(5), getParamsConnectionId(`{"connectionId":5,"boardId":1}`))
+	assert.Equal(t, uint64(0), getParamsConnectionId(`{"Name":"apache/incubator-devlake"}`))
+	assert.Equal(t, uint64(0), getParamsConnectionId(`not json`))
+}
+
+func mockRawDataRetentionDal(t *testing.T, extractedBefore, convertedBefore *time.Time) *mockdal.Dal {
+	mockDal := mockdal.NewDal(t)
+	oldDb := db
+	db = mockDal
+	t.Cleanup(func() {
+		db = oldDb
+	})
+	mockDal.On("All", mock.AnythingOfType("*[]*models.SubtaskState"), mock.Anything).Run(func(args mock.Arguments) {
+		states := args.Get(0).(*[]*models.SubtaskState)
+		where := args.Get(1).([]dal.Clause)[0].Data.(dal.DalClause).Expr
+		if strings.Contains(where, "extracted_table") && extractedBefore != nil {
+			*states = append(*states, &models.SubtaskState{PrevStartedAt: extractedBefore})
+		}
+		if strings.Contains(where, "converted_table") && convertedBefore != nil {
+			*states = append(*states, &models.SubtaskState{PrevStartedAt: convertedBefore})
+		}
+	}).Return(nil).Maybe()
+	return mockDal
+}
+
+func TestPurgeRawDataOfScopeMarksWatermarkBeforeDeleting(t *testing.T) {
+	extractedBefore := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	mockDal := mockRawDataRetentionDal(t, &extractedBefore, nil)
+	calls := make([]string, 0)
+	mockDal.On("Pluck", "id", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]uint64) = []uint64{1, 2}
+	}).Return(nil).Once()
+	mockDal.On("Pluck", "id", mock.Anything, mock.Anything).Return(nil).Once()
+	mockDal.On("UpdateColumn", mock.AnythingOfType("*models.SubtaskState"), "purged_before", extractedBefore, mock.Anything).Run(func(args mock.Arguments) {
+		calls = append(calls, "mark")
+	}).Return(nil).Once()
+	mockDal.On("Dialect").Return("mysql").Once()
+	mockDal.On("Pluck", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+	mockDal.On("Delete", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		calls = append(calls, "delete")
+	}).Return(nil).Once()
+
+	policy := &models.RawDataRetentionPolicy{Plugin: "github", Mode: models.RAW_DATA_RETENTION_KEEP_DAYS, Days: 30}
+	result, err := purgeRawDataOfScope(policy, "_raw_github_api_issues", `{"ConnectionId":1}`, false, time.Now())
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), result.DeletedRows)
+	assert.Equal(t, []string{"mark", "delete"}, calls)
+}
+
+func TestPurgeRawDataOfScopeAfterConversion(t *testing.T) {
+	extractedBefore := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	convertedBefore := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+	policy := &models.RawDataRetentionPolicy{Plugin: "github", Mode: models.RAW_DATA_RETENTION_AFTER_CONVERSION}
+
+	t.Run("not converted by a stateful convertor", func(t *testing.T) {
+		mockRawDataRetentionDal(t, &extractedBefore, nil)
+		result, err := purgeRawDataOfScope(policy, "_raw_github_api_issues", `{"ConnectionId":1}`, false, time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, "the raw rows are not converted by a stateful convertor", result.Skipped)
+	})
+
+	t.Run("converted by a stateful convertor", func(t *testing.T) {
+		mockDal := mockRawDataRetentionDal(t, &extractedBefore, &convertedBefore)
+		var createdBefore []interface{}
+		mockDal.On("Count", mock.Anything).Run(func(args mock.Arguments) {
+			for _, clause := range args.Get(0).([]dal.Clause) {
+				if where, ok := clause.Data.(dal.DalClause); ok && where.Expr == "created_at < ?" {
+					createdBefore = append(createdBefore, where.Params...)
+				}
+			}
+		}).Return(int64(3), nil).Once()
+		mockDal.On("Dialect").Return("mysql").Once()
+		mockDal.On("Pluck", mock.Anything, mock.Anything, mock.Anything).Return(nil).Once()
+
+		result, err := purgeRawDataOfScope(policy, "_raw_github_api_issues", `{"ConnectionId":1}`, true, time.Now())
+		assert.Nil(t, err)
+		assert.Equal(t, int64(3), result.DeletedRows)
+		assert.Equal(t, []interface{}{&extractedBefore, &convertedBefore}, createdBefore)
+	})
+}
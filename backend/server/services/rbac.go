@@ -0,0 +1,190 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+)
+
+// RoleBindingQuery used to query role bindings as the api input
+type RoleBindingQuery struct {
+	Pagination
+	Subject     string `form:"subject"`
+	ProjectName string `form:"projectName"`
+}
+
+// IsRbacEnabled returns whether the role bindings are enforced on the api
+func IsRbacEnabled() bool {
+	return cfg.GetBool("RBAC_ENABLED")
+}
+
+// GetRoleBindings returns a paginated list of role bindings based on `query`
+func GetRoleBindings(query *RoleBindingQuery) ([]*models.RoleBinding, int64, errors.Error) {
+	clauses := []dal.Clause{
+		dal.From(&models.RoleBinding{}),
+	}
+	if query.Subject != "" {
+		clauses = append(clauses, dal.Where("subject = ?", query.Subject))
+	}
+	if query.ProjectName != "" {
+		clauses = append(clauses, dal.Where("project_name = ?", query.ProjectName))
+	}
+	count, err := db.Count(clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error getting DB count of role bindings")
+	}
+	clauses = append(clauses,
+		dal.Orderby("id"),
+		dal.Offset(query.GetSkip()),
+		dal.Limit(query.GetPageSize()),
+	)
+	roleBindings := make([]*models.RoleBinding, 0)
+	err = db.All(&roleBindings, clauses...)
+	if err != nil {
+		return nil, 0, errors.Default.Wrap(err, "error finding DB role bindings")
+	}
+	return roleBindings, count, nil
+}
+
+// PutRoleBinding grants the role to the subject, the previous role of the subject on the same project is replaced
+func PutRoleBinding(user *common.User, input *models.ApiInputRoleBinding) (*models.RoleBinding, errors.Error) {
+	if err := VerifyStruct(input); err != nil {
+		return nil, err
+	}
+	if input.ProjectName != "" {
+		if input.Role == models.ROLE_ADMIN {
+			return nil, errors.BadInput.New("admin role can not be bound to a project")
+		}
+		if _, err := getProjectByName(db, input.ProjectName); err != nil {
+			return nil, err
+		}
+	}
+	roleBinding := &models.RoleBinding{}
+	err := db.First(roleBinding, dal.Where("subject = ? AND project_name = ?", input.Subject, input.ProjectName))
+	if err != nil && !db.IsErrorNotFound(err) {
+		return nil, errors.Default.Wrap(err, "error finding DB role binding")
+	}
+	roleBinding.Subject = input.Subject
+	roleBinding.ProjectName = input.ProjectName
+	roleBinding.Role = input.Role
+	if user != nil {
+		roleBinding.Creator = common.Creator{Creator: user.Name, CreatorEmail: user.Email}
+	}
+	err = db.CreateOrUpdate(roleBinding)
+	if err != nil {
+		return nil, errors.Default.Wrap(err, "error saving role binding")
+	}
+	return roleBinding, nil
+}
+
+// DeleteRoleBinding revokes the role binding
+func DeleteRoleBinding(id uint64) errors.Error {
+	if id == 0 {
+		return errors.BadInput.New("role binding's id is missing")
+	}
+	roleBinding := &models.RoleBinding{}
+	err := db.First(roleBinding, dal.Where("id = ?", id))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return errors.NotFound.New(fmt.Sprintf("role binding %d not found", id))
+		}
+		return errors.Default.Wrap(err, "error finding DB role binding")
+	}
+	return db.Delete(roleBinding)
+}
+
+// AuthorizeUser checks whether the user is granted the role, on the project if projectName is not empty. The users
+// listed in RBAC_ADMINS are always admins so that the first role bindings can be created
+func AuthorizeUser(user *common.User, role string, projectName string) (bool, errors.Error) {
+	if user == nil {
+		return false, nil
+	}
+	subjects := make([]string, 0, 2)
+	for _, subject := range []string{user.Name, user.Email} {
+		if subject != "" {
+			subjects = append(subjects, subject)
+		}
+	}
+	if len(subjects) == 0 {
+		return false, nil
+	}
+	for _, admin := range strings.Split(cfg.GetString("RBAC_ADMINS"), ",") {
+		admin = strings.TrimSpace(admin)
+		for _, subject := range subjects {
+			if admin != "" && admin == subject {
+				return true, nil
+			}
+		}
+	}
+	roleBindings := make([]*models.RoleBinding, 0)
+	err := db.All(&roleBindings, dal.Where("subject IN ?", subjects))
+	if err != nil {
+		return false, errors.Default.Wrap(err, "error finding DB role bindings")
+	}
+	return hasRole(roleBindings, role, projectName), nil
+}
+
+// hasRole returns true if any of the bindings grants the role. The global bindings apply to all projects while the
+// project bindings apply to their projects only, so the routes not operating on a project, i.e. listing the projects,
+// pipelines or connections of all projects, require a global binding
+func hasRole(roleBindings []*models.RoleBinding, role string, projectName string) bool {
+	required := models.RoleLevels[role]
+	for _, roleBinding := range roleBindings {
+		level := models.RoleLevels[roleBinding.Role]
+		if roleBinding.ProjectName == "" || roleBinding.ProjectName == projectName {
+			if level >= required {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// GetProjectNameByBlueprintId returns the name of the project which the blueprint belongs to
+func GetProjectNameByBlueprintId(blueprintId uint64) (string, errors.Error) {
+	blueprint, err := bpManager.GetDbBlueprint(blueprintId)
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return blueprint.ProjectName, nil
+}
+
+// GetProjectNameByPipelineId returns the name of the project which the pipeline was created for
+func GetProjectNameByPipelineId(pipelineId uint64) (string, errors.Error) {
+	pipeline := &models.Pipeline{}
+	err := db.First(pipeline, dal.Where("id = ?", pipelineId))
+	if err != nil {
+		if db.IsErrorNotFound(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	if pipeline.BlueprintId == 0 {
+		return "", nil
+	}
+	return GetProjectNameByBlueprintId(pipeline.BlueprintId)
+}
@@ -0,0 +1,62 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasRole(t *testing.T) {
+	globalViewer := []*models.RoleBinding{
+		{Subject: "alice", Role: models.ROLE_VIEWER},
+	}
+	assert.True(t, hasRole(globalViewer, models.ROLE_VIEWER, ""))
+	assert.True(t, hasRole(globalViewer, models.ROLE_VIEWER, "p1"))
+	assert.False(t, hasRole(globalViewer, models.ROLE_PROJECT_MAINTAINER, "p1"))
+
+	maintainerOfP1 := []*models.RoleBinding{
+		{Subject: "bob", ProjectName: "p1", Role: models.ROLE_PROJECT_MAINTAINER},
+	}
+	assert.True(t, hasRole(maintainerOfP1, models.ROLE_PROJECT_MAINTAINER, "p1"))
+	assert.True(t, hasRole(maintainerOfP1, models.ROLE_VIEWER, "p1"))
+	assert.False(t, hasRole(maintainerOfP1, models.ROLE_VIEWER, ""), "the resources of all projects require a global binding")
+	assert.False(t, hasRole(maintainerOfP1, models.ROLE_VIEWER, "p2"))
+	assert.False(t, hasRole(maintainerOfP1, models.ROLE_PROJECT_MAINTAINER, ""))
+	assert.False(t, hasRole(maintainerOfP1, models.ROLE_ADMIN, "p1"))
+
+	viewerOfP1AndP2 := []*models.RoleBinding{
+		{Subject: "erin", ProjectName: "p1", Role: models.ROLE_VIEWER},
+		{Subject: "erin", ProjectName: "p2", Role: models.ROLE_PROJECT_MAINTAINER},
+	}
+	assert.True(t, hasRole(viewerOfP1AndP2, models.ROLE_VIEWER, "p1"))
+	assert.False(t, hasRole(viewerOfP1AndP2, models.ROLE_PROJECT_MAINTAINER, "p1"))
+	assert.False(t, hasRole(viewerOfP1AndP2, models.ROLE_VIEWER, ""))
+	assert.False(t, hasRole(viewerOfP1AndP2, models.ROLE_VIEWER, "p3"))
+
+	admin := []*models.RoleBinding{
+		{Subject: "carol", Role: models.ROLE_ADMIN},
+	}
+	assert.True(t, hasRole(admin, models.ROLE_ADMIN, ""))
+	assert.True(t, hasRole(admin, models.ROLE_PROJECT_MAINTAINER, "p2"))
+
+	assert.False(t, hasRole(nil, models.ROLE_VIEWER, ""))
+	assert.False(t, hasRole([]*models.RoleBinding{{Subject: "dave", Role: "unknown"}}, models.ROLE_VIEWER, ""))
+}
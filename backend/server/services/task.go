@@ -51,6 +51,8 @@ func createTask(newTask *models.NewTask, tx dal.Transaction) (*models.Task, erro
 		PipelineId:  newTask.PipelineId,
 		PipelineRow: newTask.PipelineRow,
 		PipelineCol: newTask.PipelineCol,
+
+		ResumedFromTaskId: newTask.ResumedFromTaskId,
 	}
 	if newTask.IsRerun {
 		task.Status = models.TASK_RERUN
@@ -175,7 +177,7 @@ func CancelTask(taskId uint64) errors.Error {
 }
 
 // RunTasksStandalone run tasks in parallel
-func RunTasksStandalone(parentLogger log.Logger, taskIds []uint64) errors.Error {
+func RunTasksStandalone(ctx context.Context, parentLogger log.Logger, taskIds []uint64) errors.Error {
 	if len(taskIds) == 0 {
 		return nil
 	}
@@ -184,7 +186,7 @@ func RunTasksStandalone(parentLogger log.Logger, taskIds []uint64) errors.Error
 		go func(id uint64) {
 			taskLog.Info("run task #%d in background ", id)
 			var err errors.Error
-			taskErr := runTaskStandalone(parentLogger, id)
+			taskErr := runTaskStandalone(ctx, parentLogger, id)
 			if taskErr != nil {
 				err = errors.Default.Wrap(taskErr, fmt.Sprintf("Error running task %d.", id))
 			}
@@ -219,13 +221,13 @@ func RunTasksStandalone(parentLogger log.Logger, taskIds []uint64) errors.Error
 	return errors.Convert(err)
 }
 
-// RerunTask reruns specified task
-func RerunTask(taskId uint64) (*models.Task, errors.Error) {
+// RerunTask reruns specified task, the subtasks finished successfully in the previous attempt are skipped if `resume` is true
+func RerunTask(taskId uint64, resume bool) (*models.Task, errors.Error) {
 	task, err := GetTask(taskId)
 	if err != nil {
 		return nil, err
 	}
-	rerunTasks, err := RerunPipeline(task.PipelineId, task)
+	rerunTasks, err := RerunPipeline(task.PipelineId, task, resume)
 	if err != nil {
 		return nil, err
 	}
@@ -301,6 +303,7 @@ func GetSubTasksInfo(pipelineId uint64, shouldSanitize bool, tx dal.Dal) (*model
 				IsCollector:     subtask.IsCollector,
 				IsFailed:        subtask.IsFailed,
 				Message:         subtask.Message,
+				SubtaskCounters: subtask.SubtaskCounters,
 			}
 			subTaskResult.SubtaskDetails = append(subTaskResult.SubtaskDetails, t)
 		}
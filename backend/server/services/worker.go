@@ -0,0 +1,280 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/migration"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/migrationscripts"
+	"github.com/apache/incubator-devlake/core/plugin"
+	"github.com/apache/incubator-devlake/core/runner"
+	"github.com/apache/incubator-devlake/helpers/dbhelper"
+	"github.com/apache/incubator-devlake/helpers/metricshelper"
+)
+
+var workerId string
+var workerIdOnce sync.Once
+
+// getWorkerId returns the id of the current process, which is used to lease pipelines
+func getWorkerId() string {
+	workerIdOnce.Do(func() {
+		hostName, err := os.Hostname()
+		if err != nil {
+			hostName = "unknown"
+		}
+		workerId = fmt.Sprintf("%s-%d-%d", hostName, os.Getpid(), time.Now().UnixNano())
+	})
+	return workerId
+}
+
+func getPipelineHeartbeatInterval() time.Duration {
+	return time.Duration(cfg.GetInt64("PIPELINE_HEARTBEAT_INTERVAL")) * time.Second
+}
+
+func getPipelineLeaseTimeout() time.Duration {
+	return time.Duration(cfg.GetInt64("PIPELINE_LEASE_TIMEOUT")) * time.Second
+}
+
+// RunWorker runs the current process as a worker, which executes pipelines scheduled by the api server.
+// Multiple workers may share the same database, pipelines are leased by the workers and requeued automatically
+// once their worker stops sending heartbeats.
+func RunWorker() {
+	InitResources()
+	errors.Must(runner.LoadPlugins(basicRes))
+	logger.Info("all plugins have been loaded")
+
+	// migrations are performed by the api server, wait for it to finish
+	for hasPendingMigrationScripts() {
+		logger.Info("waiting for the api server to execute the pending migration scripts")
+		time.Sleep(getPipelineHeartbeatInterval())
+	}
+	plugin.InitPlugins(basicRes)
+	initPipelineNotification()
+	statusLock.Lock()
+	serviceStatus = SERVICE_STATUS_READY
+	statusLock.Unlock()
+
+	logger.Info("worker %s started", getWorkerId())
+	serveWorkerMetrics()
+	go keepPipelineLeases()
+	RunPipelineInQueue(getPipelineMaxParallel())
+}
+
+// serveWorkerMetrics exposes the metrics of the tasks and api clients running in the worker on WORKER_METRICS_PORT,
+// since the /metrics endpoint of the api server only covers its own process
+func serveWorkerMetrics() {
+	port := strings.TrimLeft(cfg.GetString("WORKER_METRICS_PORT"), ":")
+	if port == "" {
+		return
+	}
+	portNum, err := strconv.Atoi(port)
+	if err != nil {
+		panic(fmt.Errorf("WORKER_METRICS_PORT [%s] must be int: %s", port, err.Error()))
+	}
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", metricshelper.Handler())
+	logger.Info("serving metrics of worker %s on port %d", getWorkerId(), portNum)
+	go func() {
+		if err := http.ListenAndServe(fmt.Sprintf(":%d", portNum), mux); err != nil {
+			logger.Error(err, "metrics listener of the worker stopped")
+		}
+	}()
+}
+
+// hasPendingMigrationScripts checks the migration history with a fresh migrator since it is updated by another process
+func hasPendingMigrationScripts() bool {
+	if !db.HasTable(&models.Pipeline{}) {
+		return true
+	}
+	m := errors.Must1(migration.NewMigrator(basicRes))
+	m.Register(migrationscripts.All(), "Framework")
+	for _, pluginInst := range plugin.AllPlugins() {
+		if migratable, ok := pluginInst.(plugin.PluginMigration); ok {
+			m.Register(migratable.MigrationScripts(), pluginInst.Name())
+		}
+	}
+	return m.HasPendingScripts()
+}
+
+// keepPipelineLeases sends heartbeats for the pipelines running in the current process, handles the cancellation
+// requested by other processes, and requeues the pipelines whose lease have expired
+func keepPipelineLeases() {
+	ticker := time.NewTicker(getPipelineHeartbeatInterval())
+	defer ticker.Stop()
+	for range ticker.C {
+		if err := heartbeatPipelines(); err != nil {
+			globalPipelineLog.Error(err, "failed to send heartbeat of pipelines")
+		}
+		if err := cancelRequestedPipelines(); err != nil {
+			globalPipelineLog.Error(err, "failed to cancel pipelines")
+		}
+		if err := requeueExpiredPipelines(); err != nil {
+			globalPipelineLog.Error(err, "failed to requeue pipelines")
+		}
+	}
+}
+
+// localPipelines tracks the pipelines running in the current process, the value tells whether the lease was lost
+var localPipelines = map[uint64]bool{}
+var localPipelinesLock sync.Mutex
+
+func addLocalPipeline(pipelineId uint64) {
+	localPipelinesLock.Lock()
+	defer localPipelinesLock.Unlock()
+	localPipelines[pipelineId] = false
+}
+
+func removeLocalPipeline(pipelineId uint64) {
+	localPipelinesLock.Lock()
+	defer localPipelinesLock.Unlock()
+	delete(localPipelines, pipelineId)
+}
+
+// isPipelineLeaseLost returns true if the pipeline running in the current process has been taken over by another one
+func isPipelineLeaseLost(pipelineId uint64) bool {
+	localPipelinesLock.Lock()
+	defer localPipelinesLock.Unlock()
+	return localPipelines[pipelineId]
+}
+
+func getLeasedPipelineIds() []uint64 {
+	localPipelinesLock.Lock()
+	defer localPipelinesLock.Unlock()
+	pipelineIds := make([]uint64, 0, len(localPipelines))
+	for pipelineId, leaseLost := range localPipelines {
+		if !leaseLost {
+			pipelineIds = append(pipelineIds, pipelineId)
+		}
+	}
+	return pipelineIds
+}
+
+// heartbeatPipelines renews the leases of the pipelines running in the current process, the local run is cancelled
+// once the lease is lost, i.e. the pipeline was requeued and may be running by another worker now
+func heartbeatPipelines() errors.Error {
+	for _, pipelineId := range getLeasedPipelineIds() {
+		updated, err := db.UpdateColumnsAffected(
+			&models.Pipeline{},
+			[]dal.DalSet{{ColumnName: "heartbeat_at", Value: time.Now()}},
+			dal.Where("id = ? AND worker_id = ? AND status = ?", pipelineId, getWorkerId(), models.TASK_RUNNING),
+		)
+		if err != nil {
+			return err
+		}
+		if updated > 0 {
+			continue
+		}
+		localPipelinesLock.Lock()
+		_, running := localPipelines[pipelineId]
+		if running {
+			localPipelines[pipelineId] = true
+		}
+		localPipelinesLock.Unlock()
+		if !running {
+			// finished in the meantime
+			continue
+		}
+		globalPipelineLog.Warn(nil, "lease of pipeline #%d was lost, cancelling the local run", pipelineId)
+		err = cancelRunningTasks(pipelineId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func cancelRequestedPipelines() errors.Error {
+	var pipelineIds []uint64
+	err := db.Pluck("id", &pipelineIds,
+		dal.From(&models.Pipeline{}),
+		dal.Where("worker_id = ? AND status = ? AND cancel_requested = ?", getWorkerId(), models.TASK_RUNNING, true),
+	)
+	if err != nil {
+		return err
+	}
+	for _, pipelineId := range pipelineIds {
+		globalPipelineLog.Info("cancelling pipeline #%d as requested", pipelineId)
+		err = cancelRunningTasks(pipelineId)
+		if err != nil {
+			return err
+		}
+		err = db.UpdateColumn(&models.Pipeline{}, "cancel_requested", false, dal.Where("id = ?", pipelineId))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requeueExpiredPipelines puts the running pipelines which lost their worker back to the queue, they would be
+// resumed by the next available worker
+func requeueExpiredPipelines() errors.Error {
+	leaseExpiredAt := time.Now().Add(-getPipelineLeaseTimeout())
+	var pipelines []*models.Pipeline
+	err := db.All(&pipelines,
+		dal.Select("id, worker_id"),
+		dal.Where("status = ? AND heartbeat_at < ?", models.TASK_RUNNING, leaseExpiredAt),
+	)
+	if err != nil {
+		return err
+	}
+	for _, pipeline := range pipelines {
+		globalPipelineLog.Warn(nil, "lease of pipeline #%d held by worker %s expired, requeueing", pipeline.ID, pipeline.WorkerId)
+		err = requeueExpiredPipeline(pipeline.ID, leaseExpiredAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requeueExpiredPipeline requeues the pipeline and its running tasks at once, the tasks are left alone if the
+// pipeline was renewed or picked up by another process in the meantime
+func requeueExpiredPipeline(pipelineId uint64, leaseExpiredAt time.Time) (err errors.Error) {
+	txHelper := dbhelper.NewTxHelper(basicRes, &err)
+	defer txHelper.End()
+	tx := txHelper.Begin()
+	requeued, err := tx.UpdateColumnsAffected(
+		&models.Pipeline{},
+		[]dal.DalSet{
+			{ColumnName: "status", Value: models.TASK_RESUME},
+			{ColumnName: "worker_id", Value: ""},
+		},
+		dal.Where("id = ? AND status = ? AND heartbeat_at < ?", pipelineId, models.TASK_RUNNING, leaseExpiredAt),
+	)
+	if err != nil || requeued != 1 {
+		return err
+	}
+	return tx.UpdateColumns(
+		&models.Task{},
+		[]dal.DalSet{
+			{ColumnName: "status", Value: models.TASK_RESUME},
+		},
+		dal.Where("pipeline_id = ? AND status = ?", pipelineId, models.TASK_RUNNING),
+	)
+}
@@ -0,0 +1,195 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/config"
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/errors"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	"github.com/apache/incubator-devlake/helpers/unithelper"
+	mockcontext "github.com/apache/incubator-devlake/mocks/core/context"
+	mockdal "github.com/apache/incubator-devlake/mocks/core/dal"
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func mockWorkerDal(t *testing.T) *mockdal.Dal {
+	mockDal := mockdal.NewDal(t)
+	oldDb, oldVld := db, vld
+	db, vld = mockDal, validator.New()
+	t.Cleanup(func() {
+		db, vld = oldDb, oldVld
+	})
+	return mockDal
+}
+
+func TestHeartbeatPipelinesRenewsLeases(t *testing.T) {
+	mockDal := mockWorkerDal(t)
+	addLocalPipeline(1)
+	defer removeLocalPipeline(1)
+	mockDal.On("UpdateColumnsAffected", mock.Anything, mock.Anything, mock.Anything).Return(int64(1), nil).Once()
+
+	assert.Nil(t, heartbeatPipelines())
+	assert.False(t, isPipelineLeaseLost(1))
+}
+
+func TestHeartbeatPipelinesCancelsLostLeases(t *testing.T) {
+	mockDal := mockWorkerDal(t)
+	addLocalPipeline(2)
+	defer removeLocalPipeline(2)
+	cancelled := false
+	_, cancel := context.WithCancel(context.Background())
+	errors.Must(runningTasks.Add(20, func() {
+		cancelled = true
+		cancel()
+	}))
+	mockDal.On("UpdateColumnsAffected", mock.Anything, mock.Anything, mock.Anything).Return(int64(0), nil).Once()
+	mockDal.On("Count", mock.Anything).Return(int64(1), nil).Once()
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		tasks := args.Get(0).(*[]*models.Task)
+		*tasks = append(*tasks, &models.Task{Model: common.Model{ID: 20}, PipelineId: 2})
+	}).Return(nil).Once()
+
+	assert.Nil(t, heartbeatPipelines())
+	assert.True(t, isPipelineLeaseLost(2))
+	assert.True(t, cancelled)
+
+	// the lost lease is not renewed anymore
+	assert.Nil(t, heartbeatPipelines())
+}
+
+func TestHeartbeatPipelinesSkipsFinishedPipelines(t *testing.T) {
+	mockDal := mockWorkerDal(t)
+	addLocalPipeline(3)
+	mockDal.On("UpdateColumnsAffected", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		// the pipeline finished before its lease was renewed
+		removeLocalPipeline(3)
+	}).Return(int64(0), nil).Once()
+
+	assert.Nil(t, heartbeatPipelines())
+	assert.False(t, isPipelineLeaseLost(3))
+}
+
+func mockDequeueTx(t *testing.T, claimed int64) *mockdal.Transaction {
+	mockTx := mockdal.NewTransaction(t)
+	mockDal := mockdal.NewDal(t)
+	mockDal.On("Begin").Return(mockTx)
+	mockRes := new(mockcontext.BasicRes)
+	mockRes.On("GetDal").Return(mockDal)
+	mockRes.On("GetLogger").Return(unithelper.DummyLogger())
+	oldBasicRes := basicRes
+	basicRes = mockRes
+	t.Cleanup(func() {
+		basicRes = oldBasicRes
+	})
+
+	mockTx.On("LockTables", mock.Anything).Return(nil)
+	mockTx.On("Pluck", "priority", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*[]int) = []int{0}
+	}).Return(nil)
+	mockTx.On("First", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.Pipeline).ID = 7
+	}).Return(nil)
+	mockTx.On("UpdateColumnsAffected", mock.Anything, mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		// the pipeline must be claimed only if it is still queued
+		clauses := args.Get(2).([]dal.Clause)
+		statusGuarded := false
+		for _, clause := range clauses {
+			if where, ok := clause.Data.(dal.DalClause); ok && where.Expr == "status IN ?" {
+				statusGuarded = true
+			}
+		}
+		assert.True(t, statusGuarded)
+	}).Return(claimed, nil)
+	mockTx.On("UnlockTables").Return(nil)
+	mockTx.On("Commit").Return(nil)
+	return mockTx
+}
+
+func TestDequeuePipelineClaimsQueuedPipeline(t *testing.T) {
+	mockDequeueTx(t, 1)
+
+	pipeline, err := dequeuePipeline(nil)
+	assert.Nil(t, err)
+	if assert.NotNil(t, pipeline) {
+		assert.Equal(t, uint64(7), pipeline.ID)
+	}
+}
+
+func TestDequeuePipelineSkipsPipelineClaimedByOthers(t *testing.T) {
+	mockDequeueTx(t, 0)
+
+	pipeline, err := dequeuePipeline(nil)
+	assert.Nil(t, err)
+	assert.Nil(t, pipeline)
+}
+
+func mockRequeueTx(t *testing.T, requeued int64) (*mockdal.Dal, *mockdal.Transaction) {
+	mockDal := mockWorkerDal(t)
+	mockTx := mockdal.NewTransaction(t)
+	mockDal.On("Begin").Return(mockTx).Maybe()
+	mockRes := new(mockcontext.BasicRes)
+	mockRes.On("GetDal").Return(mockDal)
+	mockRes.On("GetLogger").Return(unithelper.DummyLogger())
+	oldBasicRes, oldCfg := basicRes, cfg
+	basicRes, cfg = mockRes, config.GetConfig()
+	t.Cleanup(func() {
+		basicRes, cfg = oldBasicRes, oldCfg
+	})
+	mockDal.On("All", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		pipelines := args.Get(0).(*[]*models.Pipeline)
+		*pipelines = append(*pipelines, &models.Pipeline{Model: common.Model{ID: 5}, WorkerId: "worker-1"})
+	}).Return(nil).Once()
+	mockTx.On("UpdateColumnsAffected", mock.AnythingOfType("*models.Pipeline"), mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		// the pipeline must be requeued only if its lease is still expired
+		where := args.Get(2).([]dal.Clause)[0].Data.(dal.DalClause)
+		assert.Equal(t, "id = ? AND status = ? AND heartbeat_at < ?", where.Expr)
+	}).Return(requeued, nil).Once()
+	mockTx.On("UnlockTables").Return(nil)
+	return mockDal, mockTx
+}
+
+func TestRequeueExpiredPipelinesRequeuesTasks(t *testing.T) {
+	_, mockTx := mockRequeueTx(t, 1)
+	mockTx.On("UpdateColumns", mock.AnythingOfType("*models.Task"), mock.Anything, mock.Anything).Return(nil).Once()
+	mockTx.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, requeueExpiredPipelines())
+}
+
+func TestRequeueExpiredPipelinesSkipsRenewedPipelines(t *testing.T) {
+	_, mockTx := mockRequeueTx(t, 0)
+	mockTx.On("Commit").Return(nil).Once()
+
+	assert.Nil(t, requeueExpiredPipelines())
+	mockTx.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRequeueExpiredPipelinesRollsBackOnFailure(t *testing.T) {
+	_, mockTx := mockRequeueTx(t, 1)
+	mockTx.On("UpdateColumns", mock.AnythingOfType("*models.Task"), mock.Anything, mock.Anything).Return(errors.Default.New("lost connection")).Once()
+	mockTx.On("Rollback").Return(nil).Once()
+
+	assert.NotNil(t, requeueExpiredPipelines())
+}
@@ -0,0 +1,101 @@
+/*
+Licensed to the Apache Software Foundation (ASF) under one or more
+contributor license agreements.  See the NOTICE file distributed with
+this work for additional information regarding copyright ownership.
+The ASF licenses this file to You under the Apache License, Version 2.0
+(the "License"); you may not use this file except in compliance with
+the License.  You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package services
+
+import (
+	"testing"
+
+	"github.com/apache/incubator-devlake/core/dal"
+	"github.com/apache/incubator-devlake/core/models"
+	"github.com/apache/incubator-devlake/core/models/common"
+	gitlabModels "github.com/apache/incubator-devlake/plugins/gitlab/models"
+	"github.com/apache/incubator-devlake/server/services"
+	"github.com/apache/incubator-devlake/test/helper"
+	"github.com/stretchr/testify/assert"
+)
+
+func makeImportDocument(description string, connections ...*services.ProjectDocumentConnection) *services.ProjectDocument {
+	return &services.ProjectDocument{
+		Project: models.BaseProject{Name: "import-demo", Description: description},
+		Blueprint: &services.ProjectDocumentBlueprint{
+			Name:       "import-demo-Blueprint",
+			Mode:       models.BLUEPRINT_MODE_NORMAL,
+			CronConfig: "0 0 * * *",
+			IsManual:   true,
+		},
+		Connections: connections,
+	}
+}
+
+func makeImportConnection(name string, scopes ...map[string]interface{}) *services.ProjectDocumentConnection {
+	return &services.ProjectDocumentConnection{
+		PluginName: "gitlab",
+		Connection: map[string]interface{}{
+			"name":     name,
+			"endpoint": "https://gitlab.example.com/api/v4/",
+			"token":    "glpat-xxx",
+		},
+		Scopes: scopes,
+	}
+}
+
+func TestImportProjectRevertsOnPlanFailure(t *testing.T) {
+	client := helper.StartDevLakeServer(t, loadGoPlugins())
+	db := client.GetDal()
+	user := &common.User{Name: "importer"}
+
+	// a fresh import fails as the connection has no scopes to make the plan with, nothing is left behind
+	_, err := services.ImportProject(user, makeImportDocument("original", makeImportConnection("import-empty")), false)
+	assert.NotNil(t, err)
+	count, err := db.Count(dal.From(&models.Project{}), dal.Where("name = ?", "import-demo"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+	count, err = db.Count(dal.From(&gitlabModels.GitlabConnection{}), dal.Where("name = ?", "import-empty"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+
+	scope := map[string]interface{}{"gitlabId": 1, "name": "demo", "pathWithNamespace": "group/demo"}
+	result, err := services.ImportProject(user, makeImportDocument("original", makeImportConnection("import-gitlab", scope)), false)
+	if !assert.Nil(t, err) {
+		return
+	}
+	assert.NotEmpty(t, result.Changes)
+	blueprint, err := services.GetBlueprintByProjectName("import-demo")
+	assert.Nil(t, err)
+	assert.Len(t, blueprint.Connections, 1)
+
+	// the import is reverted as the plan can not be made with the new connection
+	_, err = services.ImportProject(
+		user,
+		makeImportDocument("changed", makeImportConnection("import-gitlab", scope), makeImportConnection("import-empty")),
+		false,
+	)
+	assert.NotNil(t, err)
+	project := &models.Project{}
+	assert.Nil(t, db.First(project, dal.Where("name = ?", "import-demo")))
+	assert.Equal(t, "original", project.Description)
+	count, err = db.Count(dal.From(&gitlabModels.GitlabConnection{}), dal.Where("name = ?", "import-empty"))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(0), count)
+	reverted, err := services.GetBlueprintByProjectName("import-demo")
+	assert.Nil(t, err)
+	assert.Equal(t, blueprint.ID, reverted.ID)
+	if assert.Len(t, reverted.Connections, 1) {
+		assert.Equal(t, blueprint.Connections[0].ConnectionId, reverted.Connections[0].ConnectionId)
+	}
+}
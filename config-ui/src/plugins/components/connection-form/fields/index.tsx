@@ -25,6 +25,7 @@ import { ConnectionProxy } from './proxy';
 import { ConnectionRateLimit } from './rate-limit';
 import { ConnectionAppId } from './app-id';
 import { ConnectionSecretKey } from './secret-key';
+import { ConnectionWebhookSecret } from './webhook-secret';
 
 interface Props {
   type: 'create' | 'update';
@@ -82,6 +83,8 @@ export const Form = ({ type, name, fields, initialValues, values, errors, setVal
           return <ConnectionAppId key={key} {...getProps('appId')} {...field} />;
         case 'secretKey':
           return <ConnectionSecretKey key={key} type={type} {...getProps('secretKey')} {...field} />;
+        case 'webhookSecret':
+          return <ConnectionWebhookSecret key={key} type={type} {...getProps('webhookSecret')} {...field} />;
         case 'proxy':
           return <ConnectionProxy key={key} {...getProps('proxy')} {...field} />;
         case 'rateLimitPerHour':
@@ -59,8 +59,10 @@ export const BitbucketServerConfig: IPluginConfig = {
     },
   },
   scopeConfig: {
-    entities: ['CODEREVIEW', 'CROSS', 'CODE'],
+    entities: ['CODEREVIEW', 'CROSS', 'CODE', 'CICD'],
     transformation: {
+      deploymentPattern: '',
+      productionPattern: '',
       refdiff: {
         tagsLimit: 10,
         tagsPattern: '/v\\d+\\.\\d+(\\.\\d+(-rc)*\\d*)*$/',
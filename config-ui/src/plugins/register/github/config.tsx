@@ -79,6 +79,7 @@ export const GitHubConfig: IPluginConfig = {
           />
         ),
       'proxy',
+      'webhookSecret',
       {
         key: 'rateLimitPerHour',
         subLabel:
@@ -53,6 +53,7 @@ export const GitLabConfig: IPluginConfig = {
         ),
       },
       'proxy',
+      'webhookSecret',
       {
         key: 'rateLimitPerHour',
         subLabel: